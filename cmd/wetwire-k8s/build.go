@@ -0,0 +1,376 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	coredomain "github.com/lex00/wetwire-core-go/domain"
+	"github.com/lex00/wetwire-k8s-go/domain"
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/lex00/wetwire-k8s-go/internal/serialize"
+	"github.com/spf13/cobra"
+)
+
+// configureBuildCmd extends the auto-generated build command with
+// k8s-specific behavior that the core command does not provide.
+func configureBuildCmd(rootCmd *cobra.Command, d *domain.K8sDomain) {
+	buildCmd := findSubcommand(rootCmd, "build")
+	if buildCmd == nil {
+		return
+	}
+
+	buildCmd.Long += `
+
+The manifests are written as YAML by default; use --format json for a JSON
+array, or add --json-list for a v1 List object that kubectl apply accepts,
+including with --server-side. Use --output to write them to a file and --dry-run to print them
+without writing.
+
+Use - as the path to build a single Go file read from stdin, e.g.
+cat resources.go | wetwire-k8s build -. Errors refer to it as stdin.go.
+
+Use --format helm with --output <dir> to export the resources as a Helm chart
+(Chart.yaml, values.yaml and templates/) instead of plain manifests.
+
+Resources are ordered by their Go references. Use --apply-order to also place
+the ConfigMaps, Secrets, PersistentVolumeClaims and ServiceAccounts that a
+resource refers to by name before it, so that the output is safe to apply with
+kubectl apply -f in order.
+
+YAML is indented by 2 spaces per level; use --indent to change it (2-9) and
+--leading-separator to start the output with "---" even for a single document.
+Use --provenance to add a generated-by header and a "# source: file.go:line"
+comment above each document.
+
+Use --prune-helpers to emit only API objects, leaving out helper values such as
+a Container or PodSpec declared as its own variable.
+
+Use --namespace to set metadata.namespace on every namespaced resource,
+replacing the namespace set in the code, so the same package can be built for
+different environments. Cluster-scoped kinds such as Namespace, ClusterRole and
+PersistentVolume are left unchanged.
+
+Use --owner-references to set ownerReferences on resources that refer to
+exactly one workload (Deployment, StatefulSet, DaemonSet, ReplicaSet, Job or
+CronJob) in their namespace, such as a Service selecting a Deployment's pods,
+so that they are garbage collected with it. The garbage collector deletes
+resources whose owner UID does not exist, so give the UID of each live owner
+with --owner-uid [namespace/]kind/name=uid, as printed by
+kubectl get deployment web -o jsonpath='{.metadata.uid}'. The build fails if
+an owner's UID is not given.
+
+Use --overlay <dir> to build the package with an overlay package on top, for
+example the settings of one environment. Overlay resources with the same kind
+and name as a base resource are merged into it like a strategic merge patch:
+labels and annotations are merged, containers and other named lists are
+merged by name, and scalars such as replicas and images are replaced. Other
+overlay resources are added to the output.
+
+Use --set-image [workload/]container=image to set the image of the containers
+with that name, in every workload or only in the named one, and
+--set-replicas name=count to set the replicas of a Deployment, StatefulSet or
+ReplicaSet, without editing the code, for example to pin the images a CI
+pipeline pushed. Both can be repeated. An override that matches nothing is an
+error.
+
+Use --config-hash to annotate the pod template of each workload with
+wetwire-k8s/config-hash, a hash of the content of the ConfigMaps and Secrets
+it mounts or reads environment variables from, so that applying a changed
+ConfigMap rolls the workload out.
+
+Use --kube-version <version>, e.g. 1.22, to build for an older cluster:
+resources are moved to the older apiVersion the cluster serves, such as
+autoscaling/v2beta2 for a HorizontalPodAutoscaler before 1.23, and fields it
+does not support are dropped, with a warning on stderr for each change.
+
+Without a path argument, the source directory, output format and path, and
+build settings are read from the .wetwire.yaml of the current directory or
+its parents, as written by init, if there is one. Use --config <file> to read
+them from another file, such as one per environment or CI profile. Paths in
+the file are relative to its directory, and the path argument and flags given
+on the command line take precedence over it. Use
+--skip-validation, or build.skip_validation in the file, to skip the checks
+that references point at declared resources and form no cycle.
+
+Use --watch to rebuild whenever a .go file under the path changes, until
+interrupted with Ctrl+C.`
+
+	buildCmd.Flags().Bool("apply-order", false, "Order resources after the objects they refer to by name (configMapRef, secretKeyRef, volumes, serviceAccountName)")
+	buildCmd.Flags().Bool("json-list", false, "With --format json, wrap the resources in a v1 List object instead of an array")
+	buildCmd.Flags().Int("indent", serialize.DefaultYAMLIndent, "Spaces per indentation level in YAML output (2-9)")
+	buildCmd.Flags().Bool("leading-separator", false, "Start YAML output with a \"---\" document separator")
+	buildCmd.Flags().Bool("provenance", false, "Comment YAML output with its generator and the source position of each resource")
+	buildCmd.Flags().Bool("prune-helpers", false, "Leave helper values (Containers, PodSpecs, ...) declared as variables out of the output")
+	buildCmd.Flags().StringP("namespace", "n", "", "Set the namespace of every namespaced resource, overriding the code")
+	buildCmd.Flags().Bool("owner-references", false, "Set ownerReferences on resources that refer to a workload in the package")
+	buildCmd.Flags().StringArray("owner-uid", nil, "UID of a live owner for --owner-references, as [namespace/]kind/name=uid (repeatable)")
+	buildCmd.Flags().String("overlay", "", "Directory of a package whose resources patch or add to the built resources")
+	buildCmd.Flags().StringArray("set-image", nil, "Set the image of containers, as [workload/]container=image (repeatable)")
+	buildCmd.Flags().StringArray("set-replicas", nil, "Set the replicas of a workload, as name=count (repeatable)")
+	buildCmd.Flags().Bool("config-hash", false, "Annotate pod templates with a hash of the ConfigMaps and Secrets they refer to")
+	buildCmd.Flags().String("kube-version", "", "Kubernetes version to build for, e.g. 1.22; older apiVersions are used and unsupported fields dropped")
+	buildCmd.Flags().Bool("skip-validation", false, "Skip the reference and dependency cycle checks")
+	buildCmd.Flags().String("config", "", "Read source, output and build settings from this .wetwire.yaml")
+	buildCmd.Flags().Bool("watch", false, "Rebuild when .go files change")
+
+	buildCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadBuildConfig(cmd, args)
+		if err != nil {
+			return err
+		}
+		if cfg != nil {
+			if args, err = applyBuildConfig(cmd, args, cfg); err != nil {
+				return err
+			}
+		}
+
+		format, _ := cmd.Flags().GetString("format")
+		var run func() error
+		switch format {
+		case "helm":
+			run = func() error { return runHelmBuild(cmd, args, d) }
+		case "text", "yaml":
+			run = func() error { return runManifestBuild(cmd, args, d, "yaml") }
+		case "json":
+			run = func() error { return runManifestBuild(cmd, args, d, "json") }
+		default:
+			return fmt.Errorf("unsupported format: %s (supported: yaml, json, helm)", format)
+		}
+
+		if watch, _ := cmd.Flags().GetBool("watch"); watch {
+			if pathArg(args) == "-" {
+				return fmt.Errorf("--watch cannot be used when reading from stdin")
+			}
+			return runWatched(cmd, pathArg(args), run)
+		}
+		return run()
+	}
+}
+
+// buildSource returns the path to build and, when path is "-", the Go
+// source read from stdin, which is built as a file named stdin.go.
+func buildSource(cmd *cobra.Command, path string) (string, []byte, error) {
+	if path != "-" {
+		return path, nil, nil
+	}
+	source, err := io.ReadAll(cmd.InOrStdin())
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return "stdin.go", source, nil
+}
+
+// pathArg returns the path argument of a command, defaulting to the current
+// directory.
+func pathArg(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return "."
+}
+
+// outputFlag returns the --output path, or "" for stdout when it is empty or
+// "-", so that "-" overrides an output path set in .wetwire.yaml.
+func outputFlag(cmd *cobra.Command) string {
+	output, _ := cmd.Flags().GetString("output")
+	if output == "-" {
+		return ""
+	}
+	return output
+}
+
+// runManifestBuild runs the builder and prints the generated manifests as-is,
+// rather than wrapping them in a formatted result. When the manifests are
+// written to --output only a confirmation is printed.
+func runManifestBuild(cmd *cobra.Command, args []string, d *domain.K8sDomain, format string) error {
+	path, source, err := buildSource(cmd, pathArg(args))
+	if err != nil {
+		return err
+	}
+
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	output := outputFlag(cmd)
+	buildType, _ := cmd.Flags().GetString("type")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	applyOrder, _ := cmd.Flags().GetBool("apply-order")
+	pruneHelpers, _ := cmd.Flags().GetBool("prune-helpers")
+	namespace, _ := cmd.Flags().GetString("namespace")
+	ownerReferences, _ := cmd.Flags().GetBool("owner-references")
+	overlay, _ := cmd.Flags().GetString("overlay")
+	indent, _ := cmd.Flags().GetInt("indent")
+	leadingSeparator, _ := cmd.Flags().GetBool("leading-separator")
+	provenance, _ := cmd.Flags().GetBool("provenance")
+	jsonList, _ := cmd.Flags().GetBool("json-list")
+	kubeVersion, _ := cmd.Flags().GetString("kube-version")
+	configHash, _ := cmd.Flags().GetBool("config-hash")
+	skipValidation, _ := cmd.Flags().GetBool("skip-validation")
+	overrides, err := buildOverrides(cmd)
+	if err != nil {
+		return err
+	}
+	ownerUIDs, err := buildOwnerUIDs(cmd)
+	if err != nil {
+		return err
+	}
+
+	log := newLogger(cmd, cmd.OutOrStdout())
+	log.Debugf("Building %s as %s", path, format)
+
+	ctx := coredomain.NewContextWithVerbose(context.Background(), path, verbose)
+	result, err := d.BuildWithOptions(ctx, path, domain.K8sBuildOpts{
+		BuildOpts: coredomain.BuildOpts{
+			Format: format,
+			Type:   buildType,
+			Output: output,
+			DryRun: dryRun,
+		},
+		Source:           source,
+		ApplyOrder:       applyOrder,
+		Indent:           indent,
+		LeadingSeparator: leadingSeparator,
+		Provenance:       provenance,
+		PruneHelpers:     pruneHelpers,
+		Namespace:        namespace,
+		OwnerReferences:  ownerReferences,
+		OwnerUIDs:        ownerUIDs,
+		Overlay:          overlay,
+		Overrides:        overrides,
+		JSONList:         jsonList,
+		KubeVersion:      kubeVersion,
+		ConfigHash:       configHash,
+		SkipValidation:   skipValidation,
+	})
+	if err != nil {
+		return fmt.Errorf("build failed: %w", err)
+	}
+
+	if !result.Success {
+		text, err := coredomain.FormatResult(result, "text")
+		if err != nil {
+			return fmt.Errorf("failed to format result: %w", err)
+		}
+		log.Errorf("%s", text)
+		// Build failures are not usage errors
+		cmd.SilenceUsage = true
+		return errOperationFailed
+	}
+
+	// Warnings go to stderr, so that the manifests on stdout stay usable
+	warn := newLogger(cmd, cmd.ErrOrStderr())
+	for _, e := range result.Errors {
+		warn.Infof("warning: %s", e.Message)
+	}
+
+	if manifests, ok := result.Data.(string); ok {
+		fmt.Fprintln(cmd.OutOrStdout(), manifests)
+		return nil
+	}
+	log.Infof("%s", result.Message)
+	return nil
+}
+
+// runHelmBuild runs the builder in helm mode. The core command formats its
+// result using the --format value, which has no "helm" formatter, so the
+// result is reported as text here instead.
+func runHelmBuild(cmd *cobra.Command, args []string, d *domain.K8sDomain) error {
+	path, source, err := buildSource(cmd, pathArg(args))
+	if err != nil {
+		return err
+	}
+
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	output := outputFlag(cmd)
+	buildType, _ := cmd.Flags().GetString("type")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	applyOrder, _ := cmd.Flags().GetBool("apply-order")
+	pruneHelpers, _ := cmd.Flags().GetBool("prune-helpers")
+	namespace, _ := cmd.Flags().GetString("namespace")
+	ownerReferences, _ := cmd.Flags().GetBool("owner-references")
+	overlay, _ := cmd.Flags().GetString("overlay")
+	skipValidation, _ := cmd.Flags().GetBool("skip-validation")
+	overrides, err := buildOverrides(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := coredomain.NewContextWithVerbose(context.Background(), path, verbose)
+	result, err := d.BuildWithOptions(ctx, path, domain.K8sBuildOpts{
+		BuildOpts: coredomain.BuildOpts{
+			Format: "helm",
+			Type:   buildType,
+			Output: output,
+			DryRun: dryRun,
+		},
+		Source:          source,
+		ApplyOrder:      applyOrder,
+		PruneHelpers:    pruneHelpers,
+		Namespace:       namespace,
+		OwnerReferences: ownerReferences,
+		Overlay:         overlay,
+		Overrides:       overrides,
+		SkipValidation:  skipValidation,
+	})
+	if err != nil {
+		return fmt.Errorf("build failed: %w", err)
+	}
+
+	text, err := coredomain.FormatResult(result, "text")
+	if err != nil {
+		return fmt.Errorf("failed to format result: %w", err)
+	}
+
+	if !result.Success {
+		fmt.Fprint(cmd.OutOrStdout(), text)
+		cmd.SilenceUsage = true
+		return errOperationFailed
+	}
+	newLogger(cmd, cmd.OutOrStdout()).Infof("%s", text)
+	return nil
+}
+
+// buildOverrides parses the --set-image and --set-replicas flags.
+func buildOverrides(cmd *cobra.Command) (build.Overrides, error) {
+	var overrides build.Overrides
+	images, _ := cmd.Flags().GetStringArray("set-image")
+	for _, s := range images {
+		o, err := build.ParseImageOverride(s)
+		if err != nil {
+			return build.Overrides{}, err
+		}
+		overrides.Images = append(overrides.Images, o)
+	}
+	replicas, _ := cmd.Flags().GetStringArray("set-replicas")
+	for _, s := range replicas {
+		o, err := build.ParseReplicasOverride(s)
+		if err != nil {
+			return build.Overrides{}, err
+		}
+		overrides.Replicas = append(overrides.Replicas, o)
+	}
+	return overrides, nil
+}
+
+// buildOwnerUIDs parses the --owner-uid flags.
+func buildOwnerUIDs(cmd *cobra.Command) ([]build.OwnerUID, error) {
+	values, _ := cmd.Flags().GetStringArray("owner-uid")
+	var uids []build.OwnerUID
+	for _, s := range values {
+		o, err := build.ParseOwnerUID(s)
+		if err != nil {
+			return nil, err
+		}
+		uids = append(uids, o)
+	}
+	return uids, nil
+}
+
+// findSubcommand returns the direct subcommand of root with the given name.
+func findSubcommand(root *cobra.Command, name string) *cobra.Command {
+	for _, cmd := range root.Commands() {
+		if cmd.Name() == name {
+			return cmd
+		}
+	}
+	return nil
+}
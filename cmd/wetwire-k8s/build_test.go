@@ -0,0 +1,444 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/domain"
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runBuildCommand runs the domain build command with the k8s extensions
+// applied, as main() configures it.
+func runBuildCommand(args []string) (*bytes.Buffer, error) {
+	stdout, _, err := runBuildCommandOutput(args)
+	return stdout, err
+}
+
+// runBuildCommandOutput is runBuildCommand also returning what the command
+// printed to stderr.
+func runBuildCommandOutput(args []string) (*bytes.Buffer, *bytes.Buffer, error) {
+	return runBuildCommandInput(args, nil)
+}
+
+// runBuildCommandInput is runBuildCommandOutput with the command reading
+// its input from stdin, or from os.Stdin when it is nil.
+func runBuildCommandInput(args []string, stdin io.Reader) (*bytes.Buffer, *bytes.Buffer, error) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	d := &domain.K8sDomain{}
+	rootCmd := domain.CreateRootCommand(d)
+	addQuietFlag(rootCmd)
+	configureBuildCmd(rootCmd, d)
+	rootCmd.SetOut(stdout)
+	rootCmd.SetErr(stderr)
+	rootCmd.SetIn(stdin)
+	rootCmd.SetArgs(append([]string{"build"}, args...))
+
+	err := rootCmd.Execute()
+	return stdout, stderr, err
+}
+
+// guestbookResources are the metadata names of the guestbook resources, a
+// Deployment and a Service for each tier.
+var guestbookResources = []string{
+	"redis-leader",
+	"redis-leader",
+	"redis-follower",
+	"redis-follower",
+	"frontend",
+	"frontend",
+}
+
+func TestBuildCommand_Guestbook(t *testing.T) {
+	stdout, err := runBuildCommand([]string{"../../examples/guestbook"})
+	require.NoError(t, err)
+
+	out := stdout.String()
+	for _, name := range guestbookResources {
+		assert.Contains(t, out, "name: "+name)
+	}
+	assert.Contains(t, out, "kind: Deployment")
+	assert.Contains(t, out, "kind: Service")
+}
+
+func TestBuildCommand_GuestbookJSON(t *testing.T) {
+	stdout, err := runBuildCommand([]string{"../../examples/guestbook", "--format", "json"})
+	require.NoError(t, err)
+
+	var manifests []map[string]interface{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &manifests))
+	assert.Len(t, manifests, len(guestbookResources))
+}
+
+func TestBuildCommand_JSONList(t *testing.T) {
+	stdout, err := runBuildCommand([]string{"../../examples/guestbook", "--format", "json", "--json-list"})
+	require.NoError(t, err)
+
+	var list struct {
+		APIVersion string                   `json:"apiVersion"`
+		Kind       string                   `json:"kind"`
+		Items      []map[string]interface{} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &list))
+	assert.Equal(t, "v1", list.APIVersion)
+	assert.Equal(t, "List", list.Kind)
+	assert.Len(t, list.Items, len(guestbookResources))
+
+	_, err = runBuildCommand([]string{"../../examples/guestbook", "--json-list"})
+	assert.ErrorContains(t, err, "requires the json format")
+}
+
+func TestBuildCommand_Output(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "guestbook.yaml")
+
+	stdout, err := runBuildCommand([]string{"../../examples/guestbook", "-o", output})
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), "Wrote "+output)
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	for _, name := range guestbookResources {
+		assert.Contains(t, string(data), "name: "+name)
+	}
+}
+
+func TestBuildCommand_Quiet(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "guestbook.yaml")
+
+	stdout, stderr, err := runBuildCommandOutput([]string{"../../examples/guestbook", "-o", output, "--quiet"})
+	require.NoError(t, err)
+	assert.Empty(t, stdout.String(), "--quiet should suppress the success message")
+	assert.Empty(t, stderr.String())
+	assert.FileExists(t, output)
+
+	// Errors are still printed
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(`package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var BadConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "Bad_Config"},
+}
+`), 0644))
+
+	stdout, stderr, err = runBuildCommandOutput([]string{dir, "--quiet"})
+	assert.Error(t, err)
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "not a valid Kubernetes name")
+}
+
+func TestBuildCommand_QuietAndVerbose(t *testing.T) {
+	_, _, err := runBuildCommandOutput([]string{"../../examples/guestbook", "--quiet", "--verbose"})
+	assert.Error(t, err, "--quiet and --verbose are mutually exclusive")
+}
+
+func TestBuildCommand_SetOverrides(t *testing.T) {
+	stdout, err := runBuildCommand([]string{"../../examples/web-service", "--set-replicas", "webapp=5"})
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), "replicas: 5")
+
+	_, stderr, err := runBuildCommandOutput([]string{"../../examples/web-service", "--set-image", "sidecar=envoy:1.31"})
+	assert.Error(t, err)
+	assert.Contains(t, stderr.String(), `no container named "sidecar"`)
+
+	_, err = runBuildCommand([]string{"../../examples/web-service", "--set-replicas", "webapp"})
+	assert.ErrorContains(t, err, "expected name=count")
+}
+
+func TestBuildCommand_SetOverridesGuestbook(t *testing.T) {
+	stdout, err := runBuildCommand([]string{"../../examples/guestbook", "--format", "json",
+		"--set-image", "php-redis=nginx:1.27", "--set-replicas", "frontend=5"})
+	require.NoError(t, err)
+
+	var manifests []struct {
+		Kind     string
+		Metadata struct{ Name string }
+		Spec     struct {
+			Replicas int
+			Template struct {
+				Spec struct {
+					Containers []struct{ Name, Image string }
+				}
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &manifests))
+
+	deployments := make(map[string]int)
+	for i, m := range manifests {
+		if m.Kind == "Deployment" {
+			deployments[m.Metadata.Name] = i
+		}
+	}
+	require.Contains(t, deployments, "frontend")
+	frontend := manifests[deployments["frontend"]].Spec
+	assert.Equal(t, 5, frontend.Replicas)
+	require.Len(t, frontend.Template.Spec.Containers, 1)
+	assert.Equal(t, "php-redis", frontend.Template.Spec.Containers[0].Name)
+	assert.Equal(t, "nginx:1.27", frontend.Template.Spec.Containers[0].Image)
+
+	// Other workloads keep the values in the code
+	require.Contains(t, deployments, "redis-follower")
+	follower := manifests[deployments["redis-follower"]].Spec
+	assert.Equal(t, 2, follower.Replicas)
+	assert.Equal(t, "us-docker.pkg.dev/google-samples/containers/gke/gb-redis-follower:v2", follower.Template.Spec.Containers[0].Image)
+}
+
+func TestBuildCommand_OwnerUID(t *testing.T) {
+	stdout, err := runBuildCommand([]string{"../../examples/web-service", "--format", "json",
+		"--owner-references", "--owner-uid", "deployment/webapp=6f1f5c1e-3c4b-4f7e-9a51-2d0e8b7c9a10"})
+	require.NoError(t, err)
+
+	var manifests []struct {
+		Kind     string
+		Metadata struct {
+			OwnerReferences []struct{ Kind, Name, UID string } `json:"ownerReferences"`
+		}
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &manifests))
+	var owned int
+	for _, m := range manifests {
+		for _, ref := range m.Metadata.OwnerReferences {
+			owned++
+			assert.Equal(t, "Service", m.Kind)
+			assert.Equal(t, "webapp", ref.Name)
+			assert.Equal(t, "6f1f5c1e-3c4b-4f7e-9a51-2d0e8b7c9a10", ref.UID)
+		}
+	}
+	assert.Equal(t, 1, owned)
+
+	// Without the owner's UID the build fails
+	_, stderr, err := runBuildCommandOutput([]string{"../../examples/web-service", "--owner-references"})
+	require.Error(t, err)
+	assert.Contains(t, stderr.String(), "whose UID is unknown")
+
+	_, err = runBuildCommand([]string{"../../examples/web-service", "--owner-references", "--owner-uid", "webapp"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected [namespace/]kind/name=uid")
+}
+
+func TestBuildCommand_KubeVersion(t *testing.T) {
+	stdout, stderr, err := runBuildCommandOutput([]string{"../../examples/hpa", "--kube-version", "1.22"})
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), "apiVersion: autoscaling/v2beta2")
+	assert.NotContains(t, stdout.String(), "warning")
+	assert.Contains(t, stderr.String(), "warning: HorizontalPodAutoscaler web-app-hpa: Kubernetes 1.22 does not serve autoscaling/v2")
+
+	_, stderr, err = runBuildCommandOutput([]string{"../../examples/hpa", "--kube-version", "1.22", "--quiet"})
+	require.NoError(t, err)
+	assert.Empty(t, stderr.String())
+}
+
+// configHashAnnotation builds the configmap-secret example at dir with
+// --config-hash and returns the config hash annotation of its Deployment.
+func configHashAnnotation(t *testing.T, dir string) string {
+	t.Helper()
+	stdout, err := runBuildCommand([]string{dir, "--format", "json", "--config-hash"})
+	require.NoError(t, err)
+
+	var manifests []struct {
+		Kind string
+		Spec struct {
+			Template struct {
+				Metadata struct{ Annotations map[string]string }
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &manifests))
+	for _, m := range manifests {
+		if m.Kind == "Deployment" {
+			return m.Spec.Template.Metadata.Annotations[build.ConfigHashAnnotation]
+		}
+	}
+	t.Fatal("no Deployment in the output")
+	return ""
+}
+
+func TestBuildCommand_ConfigHash(t *testing.T) {
+	hash := configHashAnnotation(t, "../../examples/configmap-secret")
+	assert.Regexp(t, "^[0-9a-f]{64}$", hash)
+
+	// The hash covers the data in the code, so changing a value changes it
+	src, err := os.ReadFile("../../examples/configmap-secret/main.go")
+	require.NoError(t, err)
+	changed := strings.Replace(string(src), `"LOG_LEVEL":    "info"`, `"LOG_LEVEL":    "debug"`, 1)
+	require.NotEqual(t, string(src), changed)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(changed), 0644))
+	assert.NotEqual(t, hash, configHashAnnotation(t, dir))
+
+	stdout, err := runBuildCommand([]string{"../../examples/configmap-secret"})
+	require.NoError(t, err)
+	assert.NotContains(t, stdout.String(), build.ConfigHashAnnotation)
+}
+
+func TestBuildCommand_DryRun(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "guestbook.yaml")
+
+	stdout, err := runBuildCommand([]string{"../../examples/guestbook", "-o", output, "--dry-run"})
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), "name: frontend")
+
+	_, err = os.Stat(output)
+	assert.True(t, os.IsNotExist(err), "dry run should not write output")
+}
+
+func TestBuildCommand_UnsupportedFormat(t *testing.T) {
+	_, err := runBuildCommand([]string{"../../examples/guestbook", "--format", "xml"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported format")
+}
+
+func TestBuildCommand_ApplyOrder(t *testing.T) {
+	stdout, err := runBuildCommand([]string{"../../examples/configmap-secret", "--apply-order", "--format", "json"})
+	require.NoError(t, err)
+
+	var manifests []map[string]interface{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &manifests))
+
+	var kinds []string
+	for _, m := range manifests {
+		kinds = append(kinds, m["kind"].(string))
+	}
+	assert.Equal(t, []string{"ConfigMap", "Secret", "ConfigMap", "Secret", "Deployment"}, kinds)
+}
+
+func TestBuildCommand_YAMLLayout(t *testing.T) {
+	stdout, err := runBuildCommand([]string{"../../examples/guestbook"})
+	require.NoError(t, err)
+	assert.False(t, strings.HasPrefix(stdout.String(), "---"))
+	assert.Contains(t, stdout.String(), "\nmetadata:\n  name: ")
+
+	stdout, err = runBuildCommand([]string{"../../examples/guestbook", "--indent", "4", "--leading-separator"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stdout.String(), "---\napiVersion: "))
+	assert.Contains(t, stdout.String(), "\nmetadata:\n    name: ")
+	assert.Equal(t, len(guestbookResources), strings.Count(stdout.String(), "---\n"))
+
+	_, err = runBuildCommand([]string{"../../examples/guestbook", "--indent", "1"})
+	assert.ErrorContains(t, err, "invalid YAML indent 1")
+}
+
+func TestBuildCommand_Stdin(t *testing.T) {
+	source := `package piped
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var AppConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+}
+`
+
+	stdout, _, err := runBuildCommandInput([]string{"-"}, strings.NewReader(source))
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), "kind: ConfigMap")
+	assert.Contains(t, stdout.String(), "name: app-config")
+
+	t.Run("errors name stdin.go", func(t *testing.T) {
+		stdout, stderr, err := runBuildCommandInput([]string{"-"}, strings.NewReader("package piped\n\nvar {{{"))
+		require.Error(t, err)
+		assert.Contains(t, stdout.String()+stderr.String(), "stdin.go:3")
+	})
+
+	t.Run("watch is rejected", func(t *testing.T) {
+		_, _, err := runBuildCommandInput([]string{"-", "--watch"}, strings.NewReader(source))
+		assert.ErrorContains(t, err, "--watch cannot be used when reading from stdin")
+	})
+}
+
+func TestBuildCommand_Config(t *testing.T) {
+	// An alternate config for CI, next to no project: its source is the
+	// guestbook example, relative to the config file
+	guestbook, err := filepath.Abs("../../examples/guestbook")
+	require.NoError(t, err)
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "ci.wetwire.yaml")
+	relSource, err := filepath.Rel(dir, guestbook)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(configPath, []byte("source: "+relSource+"\noutput:\n  format: json\n"), 0644))
+
+	stdout, err := runBuildCommand([]string{"--config", configPath})
+	require.NoError(t, err)
+	var manifests []map[string]interface{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &manifests), "the config selects JSON output")
+	assert.Len(t, manifests, len(guestbookResources))
+
+	t.Run("flags take precedence", func(t *testing.T) {
+		stdout, err := runBuildCommand([]string{"--config", configPath, "--format", "yaml"})
+		require.NoError(t, err)
+		assert.Contains(t, stdout.String(), "kind: Deployment")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := runBuildCommand([]string{"--config", filepath.Join(dir, "missing.yaml")})
+		assert.ErrorContains(t, err, "failed to read config")
+	})
+}
+
+func TestBuildCommand_ProjectConfig(t *testing.T) {
+	// A project whose .wetwire.yaml builds the guestbook example as JSON
+	// into manifests.json; the build runs without arguments from a
+	// subdirectory of the project
+	guestbook, err := filepath.Abs("../../examples/guestbook")
+	require.NoError(t, err)
+	project := t.TempDir()
+	relSource, err := filepath.Rel(project, guestbook)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(project, ".wetwire.yaml"), []byte(`source: `+relSource+`
+output:
+  format: json
+  path: manifests.json
+build:
+  skip_validation: true
+`), 0644))
+	subdir := filepath.Join(project, "docs")
+	require.NoError(t, os.Mkdir(subdir, 0755))
+	t.Chdir(subdir)
+	outputPath := filepath.Join(project, "manifests.json")
+
+	stdout, err := runBuildCommand(nil)
+	require.NoError(t, err)
+	assert.NotContains(t, stdout.String(), "kind: Deployment", "the manifests go to the configured file")
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	var manifests []map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &manifests), "the config selects JSON output")
+	assert.Len(t, manifests, len(guestbookResources))
+
+	t.Run("flags take precedence", func(t *testing.T) {
+		_, err := runBuildCommand([]string{"--format", "yaml"})
+		require.NoError(t, err)
+		data, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "kind: Deployment")
+	})
+
+	t.Run("- writes to stdout", func(t *testing.T) {
+		stdout, err := runBuildCommand([]string{"--output", "-"})
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &manifests))
+		assert.NoFileExists(t, "-")
+	})
+
+	t.Run("a path argument ignores the project config", func(t *testing.T) {
+		stdout, err := runBuildCommand([]string{guestbook})
+		require.NoError(t, err)
+		assert.Contains(t, stdout.String(), "kind: Deployment")
+	})
+}
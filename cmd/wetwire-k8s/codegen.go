@@ -56,7 +56,7 @@ For example:
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			source := args[0]
-			errWriter := cmd.ErrOrStderr()
+			log := newLogger(cmd, cmd.ErrOrStderr())
 
 			// Parse the source
 			crdSource := crd.ParseCRDSource(source)
@@ -73,13 +73,13 @@ For example:
 				}
 
 			case "github":
-				fmt.Fprintf(errWriter, "Fetching CRDs from GitHub...\n")
+				log.Infof("Fetching CRDs from GitHub...")
 				fetcher := crd.NewFetcher("")
 				crdDir, err = fetcher.FetchConfigConnector(cmd.Context())
 				if err != nil {
 					return fmt.Errorf("fetch CRDs: %w", err)
 				}
-				fmt.Fprintf(errWriter, "Downloaded CRDs to %s\n", crdDir)
+				log.Infof("Downloaded CRDs to %s", crdDir)
 
 			case "url":
 				return fmt.Errorf("URL source not yet implemented: %s", source)
@@ -102,13 +102,13 @@ For example:
 			}
 
 			// Generate code
-			fmt.Fprintf(errWriter, "Generating Go types from CRDs in %s...\n", crdDir)
+			log.Infof("Generating Go types from CRDs in %s...", crdDir)
 			generator := generate.NewCRDGenerator(absOutput, domain)
 			if err := generator.GenerateFromCRDDirectory(crdDir); err != nil {
 				return fmt.Errorf("generate code: %w", err)
 			}
 
-			fmt.Fprintf(errWriter, "Output written to %s\n", absOutput)
+			log.Infof("Output written to %s", absOutput)
 			return nil
 		},
 	}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/lex00/wetwire-k8s-go/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// loadConfig loads the project configuration named by --config, or returns
+// nil if the flag is not set.
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	path, _ := cmd.Flags().GetString("config")
+	if path == "" {
+		return nil, nil
+	}
+	return config.Load(path)
+}
+
+// loadBuildConfig loads the project configuration of the build command: the
+// file named by --config or, when no path argument is given, the
+// .wetwire.yaml of the current directory or its parents. It returns nil if
+// there is none.
+func loadBuildConfig(cmd *cobra.Command, args []string) (*config.Config, error) {
+	if cmd.Flags().Changed("config") || len(args) > 0 {
+		return loadConfig(cmd)
+	}
+	path := config.Find(".")
+	if path == "" {
+		return nil, nil
+	}
+	newLogger(cmd, cmd.ErrOrStderr()).Debugf("Using config %s", path)
+	return config.Load(path)
+}
+
+// applyBuildConfig sets the build flags that were not given on the command
+// line from cfg, and returns the arguments with the configured source
+// directory when no path was given. Flags and arguments take precedence
+// over the configuration.
+func applyBuildConfig(cmd *cobra.Command, args []string, cfg *config.Config) ([]string, error) {
+	if len(args) == 0 && cfg.Source != "" {
+		args = []string{cfg.Source}
+	}
+	settings := map[string]string{
+		"format":          cfg.Output.Format,
+		"output":          cfg.Output.Path,
+		"skip-validation": strconv.FormatBool(cfg.Build.SkipValidation),
+	}
+	for flag, value := range settings {
+		// "-" is stdout, which is already the default output
+		if value == "" || value == "-" || cmd.Flags().Changed(flag) {
+			continue
+		}
+		if err := cmd.Flags().Set(flag, value); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
@@ -5,13 +5,17 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/lex00/wetwire-core-go/agent/agents"
 	"github.com/lex00/wetwire-core-go/agent/orchestrator"
 	"github.com/lex00/wetwire-core-go/agent/results"
+	"github.com/lex00/wetwire-core-go/providers"
+	anthropicprovider "github.com/lex00/wetwire-core-go/providers/anthropic"
 	"github.com/lex00/wetwire-k8s-go/internal/kiro"
 	"github.com/spf13/cobra"
 )
@@ -44,6 +48,8 @@ func newDesignCmd() *cobra.Command {
 	var maxLintCycles int
 	var stream bool
 	var provider string
+	var model string
+	var interactive bool
 
 	cmd := &cobra.Command{
 		Use:   "design",
@@ -52,24 +58,33 @@ func newDesignCmd() *cobra.Command {
 natural language descriptions.
 
 The AI agent will:
-1. Ask clarifying questions if needed
+1. Ask clarifying questions if needed (with --interactive; otherwise it is
+   told to use sensible defaults)
 2. Generate Go code using wetwire-k8s patterns
 3. Run lint and fix any issues
 4. Build the final YAML manifests
 
 Examples:
   wetwire-k8s design --prompt "Create a web app with 3 replicas"
+  wetwire-k8s design --interactive --prompt "Create a database for my app"
   wetwire-k8s design --output-dir ./infra --prompt "Full microservice stack"
-  wetwire-k8s design --provider kiro --prompt "Create nginx deployment"`,
+  wetwire-k8s design --provider anthropic --prompt "Create a CronJob"
+  wetwire-k8s design --provider kiro --prompt "Create nginx deployment"
+
+Providers:
+  core       Claude CLI if installed, otherwise the Anthropic API (default)
+  anthropic  Anthropic API, requires ANTHROPIC_API_KEY
+  kiro       Kiro CLI (kiro-cli), driven by the same agent loop`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if prompt == "" {
 				return fmt.Errorf("--prompt flag is required")
 			}
 
-			// Handle kiro provider
-			if provider == "kiro" {
-				return runDesignKiro(prompt)
+			aiProvider, err := newDesignProvider(provider, outputDir)
+			if err != nil {
+				return err
 			}
+			out := cmd.OutOrStdout()
 
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
@@ -77,32 +92,36 @@ Examples:
 			// Handle interrupt
 			sigCh := make(chan os.Signal, 1)
 			signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+			log := newLogger(cmd, out)
 			go func() {
 				<-sigCh
-				fmt.Println("\nInterrupted, cleaning up...")
+				log.Infof("\nInterrupted, cleaning up...")
 				cancel()
 			}()
 
 			// Create session for tracking
 			session := results.NewSession("human", "design")
 
-			// Create human developer (reads from stdin)
-			reader := bufio.NewReader(os.Stdin)
-			developer := orchestrator.NewHumanDeveloper(func() (string, error) {
-				return reader.ReadString('\n')
-			})
+			// Answer the agent's questions from the terminal, or with
+			// defaults so that batch runs do not wait for input
+			var developer orchestrator.Developer = defaultsDeveloper{}
+			if interactive {
+				developer = newPromptDeveloper(cmd.InOrStdin(), out)
+			}
 
 			// Create stream handler if streaming enabled
 			var streamHandler agents.StreamHandler
 			if stream {
 				streamHandler = func(text string) {
-					fmt.Print(text)
+					fmt.Fprint(out, text)
 				}
 			}
 
 			// Create runner agent with K8s domain config
 			runner, err := agents.NewRunnerAgent(agents.RunnerConfig{
 				Domain:        K8sDomain(),
+				Provider:      aiProvider,
+				Model:         model,
 				WorkDir:       outputDir,
 				MaxLintCycles: maxLintCycles,
 				Session:       session,
@@ -113,10 +132,9 @@ Examples:
 				return fmt.Errorf("creating runner: %w", err)
 			}
 
-			fmt.Println("Starting AI-assisted design session...")
-			fmt.Println("The AI will ask questions and generate infrastructure code.")
-			fmt.Println("Press Ctrl+C to stop.")
-			fmt.Println()
+			log.Infof("Starting AI-assisted design session...")
+			log.Infof("The AI will ask questions and generate infrastructure code.")
+			log.Infof("Press Ctrl+C to stop.\n")
 
 			// Run the agent
 			if err := runner.Run(ctx, prompt); err != nil {
@@ -124,13 +142,13 @@ Examples:
 			}
 
 			// Print summary
-			fmt.Println("\n--- Session Summary ---")
-			fmt.Printf("Generated files: %d\n", len(runner.GetGeneratedFiles()))
+			log.Infof("\n--- Session Summary ---")
+			log.Infof("Generated files: %d", len(runner.GetGeneratedFiles()))
 			for _, f := range runner.GetGeneratedFiles() {
-				fmt.Printf("  - %s\n", f)
+				log.Infof("  - %s", f)
 			}
-			fmt.Printf("Lint cycles: %d\n", runner.GetLintCycles())
-			fmt.Printf("Lint passed: %v\n", runner.LintPassed())
+			log.Infof("Lint cycles: %d", runner.GetLintCycles())
+			log.Infof("Lint passed: %v", runner.LintPassed())
 
 			return nil
 		},
@@ -140,14 +158,62 @@ Examples:
 	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", ".", "Output directory for generated code")
 	cmd.Flags().IntVar(&maxLintCycles, "max-lint-cycles", 3, "Maximum lint/fix cycles")
 	cmd.Flags().BoolVar(&stream, "stream", true, "Stream AI responses")
-	cmd.Flags().StringVar(&provider, "provider", "core", "AI provider to use (core, kiro)")
+	cmd.Flags().StringVar(&provider, "provider", "core", "AI provider to use (core, anthropic, kiro)")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Answer the agent's clarifying questions on the terminal")
+	cmd.Flags().StringVar(&model, "model", "", "Model to use (default: the provider's default model)")
 	_ = cmd.MarkFlagRequired("prompt")
 
 	return cmd
 }
 
-// runDesignKiro runs the design command using the Kiro provider.
-func runDesignKiro(prompt string) error {
-	fmt.Println("Launching Kiro design session...")
-	return kiro.LaunchChat(kiro.AgentName, prompt)
+// newDesignProvider returns the AI provider for a runner agent working in
+// workDir. The core provider is nil, which lets the runner choose the Claude
+// CLI when it is installed and the Anthropic API otherwise.
+func newDesignProvider(name, workDir string) (providers.Provider, error) {
+	switch name {
+	case "core", "":
+		return nil, nil
+	case "kiro":
+		return kiro.NewProvider(workDir), nil
+	case "anthropic":
+		provider, err := anthropicprovider.New(anthropicprovider.Config{})
+		if err != nil {
+			return nil, fmt.Errorf("anthropic provider: %w", err)
+		}
+		return provider, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q (supported: core, anthropic, kiro)", name)
+	}
+}
+
+// defaultsAnswer is the answer to every question when not interactive.
+const defaultsAnswer = "No developer is available to answer questions. " +
+	"Use sensible defaults and state your assumptions in code comments."
+
+// defaultsDeveloper answers every question with defaultsAnswer.
+type defaultsDeveloper struct{}
+
+func (defaultsDeveloper) Respond(ctx context.Context, question string) (string, error) {
+	return defaultsAnswer, nil
+}
+
+// promptDeveloper answers questions by printing them and reading a line
+// from the user.
+type promptDeveloper struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newPromptDeveloper(in io.Reader, out io.Writer) *promptDeveloper {
+	return &promptDeveloper{in: bufio.NewReader(in), out: out}
+}
+
+func (d *promptDeveloper) Respond(ctx context.Context, question string) (string, error) {
+	fmt.Fprintf(d.out, "\n[Runner asks]: %s\n\n[Your answer]: ", question)
+
+	answer, err := d.in.ReadString('\n')
+	if err != nil && (err != io.EOF || answer == "") {
+		return "", fmt.Errorf("read answer: %w", err)
+	}
+	return strings.TrimSpace(answer), nil
 }
@@ -2,9 +2,12 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDesignCommand_Help(t *testing.T) {
@@ -20,6 +23,7 @@ func TestDesignCommand_Help(t *testing.T) {
 	assert.Contains(t, helpOutput, "design")
 	assert.Contains(t, helpOutput, "--prompt")
 	assert.Contains(t, helpOutput, "--output-dir")
+	assert.Contains(t, helpOutput, "--interactive")
 }
 
 func TestDesignCommand_MissingPrompt(t *testing.T) {
@@ -44,3 +48,69 @@ func TestK8sDomain(t *testing.T) {
 	assert.Contains(t, domain.SystemPrompt, "Kubernetes")
 	assert.Contains(t, domain.SystemPrompt, "wetwire-k8s")
 }
+
+func TestNewDesignProvider(t *testing.T) {
+	t.Run("core lets the runner choose", func(t *testing.T) {
+		provider, err := newDesignProvider("core", ".")
+		require.NoError(t, err)
+		assert.Nil(t, provider)
+	})
+
+	t.Run("anthropic requires an API key", func(t *testing.T) {
+		t.Setenv("ANTHROPIC_API_KEY", "")
+		_, err := newDesignProvider("anthropic", ".")
+		assert.ErrorContains(t, err, "ANTHROPIC_API_KEY")
+	})
+
+	t.Run("anthropic uses the API key from the environment", func(t *testing.T) {
+		t.Setenv("ANTHROPIC_API_KEY", "test-key")
+		provider, err := newDesignProvider("anthropic", ".")
+		require.NoError(t, err)
+		assert.Equal(t, "anthropic", provider.Name())
+	})
+
+	t.Run("kiro runs the Kiro CLI in the output directory", func(t *testing.T) {
+		provider, err := newDesignProvider("kiro", "./infra")
+		require.NoError(t, err)
+		assert.Equal(t, "kiro", provider.Name())
+	})
+
+	t.Run("unknown providers are rejected", func(t *testing.T) {
+		_, err := newDesignProvider("openai", ".")
+		assert.ErrorContains(t, err, `unknown provider "openai"`)
+	})
+}
+
+func TestDesignCommand_UnknownProvider(t *testing.T) {
+	rootCmd := newDesignCmd()
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"--prompt", "Create nginx", "--provider", "openai"})
+
+	err := rootCmd.Execute()
+	assert.ErrorContains(t, err, "unknown provider")
+}
+
+func TestPromptDeveloper(t *testing.T) {
+	out := &bytes.Buffer{}
+	developer := newPromptDeveloper(strings.NewReader("3 replicas\nno ingress"), out)
+
+	answer, err := developer.Respond(context.Background(), "How many replicas?")
+	require.NoError(t, err)
+	assert.Equal(t, "3 replicas", answer)
+	assert.Contains(t, out.String(), "[Runner asks]: How many replicas?")
+
+	// The last answer may end without a newline
+	answer, err = developer.Respond(context.Background(), "Expose it publicly?")
+	require.NoError(t, err)
+	assert.Equal(t, "no ingress", answer)
+
+	_, err = developer.Respond(context.Background(), "Anything else?")
+	assert.ErrorContains(t, err, "read answer")
+}
+
+func TestDefaultsDeveloper(t *testing.T) {
+	answer, err := defaultsDeveloper{}.Respond(context.Background(), "Which namespace?")
+	require.NoError(t, err)
+	assert.Contains(t, answer, "sensible defaults")
+}
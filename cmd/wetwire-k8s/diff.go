@@ -1,14 +1,15 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
 
-	"github.com/lex00/wetwire-k8s-go/internal/build"
-	"github.com/lex00/wetwire-k8s-go/internal/roundtrip"
+	coredomain "github.com/lex00/wetwire-core-go/domain"
+	"github.com/lex00/wetwire-k8s-go/domain"
 	"github.com/spf13/cobra"
 )
 
@@ -24,24 +25,33 @@ const (
 // newDiffCmd creates the diff subcommand
 func newDiffCmd() *cobra.Command {
 	var against string
-	var semantic bool
-	var useColor bool
+	var live bool
+	var kubeconfig string
+	var ignoreOrder bool
+	var color string
+	var format string
 
 	cmd := &cobra.Command{
 		Use:   "diff [PATH]",
-		Short: "Compare generated manifests against existing manifests",
-		Long: `Diff compares the generated Kubernetes manifests from Go code
-against an existing manifest file.
+		Short: "Compare generated manifests against existing manifests or a live cluster",
+		Long: `Diff compares the generated Kubernetes manifests from Go code against
+a target, showing field-level changes grouped by resource.
 
 If PATH is not specified, the current directory is used.
 
-The --against flag is required and specifies the existing manifest to compare against.
+The target is either a manifest file or directory (--against), or the live
+objects in the cluster (--live, requires kubectl and a kubeconfig). Both sides
+are normalized before comparison, so status, server-populated metadata and
+zero values are ignored. Changes are listed field by field.
+
+Text output is colorized when stdout is a terminal; use --color=always or
+--color=never to override. --semantic is a deprecated alias of --ignore-order.
 
 Examples:
-  wetwire-k8s diff ./k8s --against manifest.yaml           # Compare generated output vs existing
-  wetwire-k8s diff --against manifest.yaml                 # Compare from current directory
-  wetwire-k8s diff ./k8s --against manifest.yaml --semantic # Use semantic comparison
-  wetwire-k8s diff ./k8s --against manifest.yaml --color   # Colorized output`,
+  wetwire-k8s diff ./k8s --against manifest.yaml      # Compare against a file
+  wetwire-k8s diff ./k8s --against ./manifests        # Compare against a directory
+  wetwire-k8s diff ./k8s --live                       # Compare against the cluster
+  wetwire-k8s diff ./k8s --against manifest.yaml --format json`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Determine source path
@@ -61,257 +71,159 @@ Examples:
 				return fmt.Errorf("source path does not exist: %s", absPath)
 			}
 
-			// Get the --against manifest file
-			if against == "" {
-				return fmt.Errorf("--against flag is required")
+			if against == "" && !live {
+				return fmt.Errorf("either --against or --live is required")
 			}
-
-			// Resolve against path
-			againstPath, err := filepath.Abs(against)
-			if err != nil {
-				return fmt.Errorf("failed to resolve against path: %w", err)
+			if against != "" && live {
+				return fmt.Errorf("--against and --live cannot be used together")
 			}
 
-			// Read the existing manifest
-			existingData, err := os.ReadFile(againstPath)
-			if err != nil {
-				return fmt.Errorf("failed to read manifest file: %w", err)
+			if format != "text" && format != "json" {
+				return fmt.Errorf("unsupported format: %s (supported: text, json)", format)
 			}
-
-			// Run the build pipeline
-			result, err := build.Build(absPath, build.Options{
-				OutputMode: build.SingleFile,
-			})
+			writer := cmd.OutOrStdout()
+			useColor, err := colorEnabled(color, writer)
 			if err != nil {
-				return fmt.Errorf("build failed: %w", err)
+				return err
 			}
 
-			// Generate YAML output from build
-			var generatedData []byte
-			if len(result.OrderedResources) > 0 {
-				generatedData, err = generateOutput(result.OrderedResources, "yaml")
-				if err != nil {
-					return fmt.Errorf("failed to generate output: %w", err)
+			if against != "" {
+				if against, err = filepath.Abs(against); err != nil {
+					return fmt.Errorf("failed to resolve against path: %w", err)
+				}
+				if _, err := os.Stat(against); err != nil {
+					return fmt.Errorf("failed to read manifest: %w", err)
 				}
 			}
 
-			// Get output writer
-			writer := cmd.OutOrStdout()
-
-			if semantic {
-				return runSemanticDiff(writer, existingData, generatedData, useColor)
+			d := &domain.K8sDomain{}
+			ctx := coredomain.NewContext(context.Background(), absPath)
+			result, err := d.DiffAgainst(ctx, absPath, domain.K8sDiffOpts{
+				DiffOpts:   coredomain.DiffOpts{IgnoreOrder: ignoreOrder},
+				Against:    against,
+				Live:       live,
+				Kubeconfig: kubeconfig,
+			})
+			if err != nil {
+				return fmt.Errorf("diff failed: %w", err)
 			}
 
-			return runTextDiff(writer, existingData, generatedData, useColor)
+			if format == "json" {
+				return writeDiffJSON(writer, result)
+			}
+			writeDiffText(writer, result, useColor)
+			return nil
 		},
 	}
 
-	cmd.Flags().StringVarP(&against, "against", "a", "", "Existing manifest file to compare against (required)")
-	cmd.Flags().BoolVar(&semantic, "semantic", false, "Use semantic comparison (ignores ordering and whitespace)")
-	cmd.Flags().BoolVar(&useColor, "color", false, "Enable colorized output")
-	_ = cmd.MarkFlagRequired("against")
+	cmd.Flags().StringVarP(&against, "against", "a", "", "Existing manifest file or directory to compare against")
+	cmd.Flags().BoolVar(&live, "live", false, "Compare against the live objects in the cluster")
+	cmd.Flags().StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig file for --live")
+	cmd.Flags().BoolVar(&ignoreOrder, "ignore-order", false, "Ignore ordering of list elements")
+	cmd.Flags().StringVar(&color, "color", "auto", "Colorize text output: auto, always or never")
+	cmd.Flags().Lookup("color").NoOptDefVal = "always"
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format (text, json)")
+
+	// --semantic predates --ignore-order, which it now stands for
+	cmd.Flags().BoolVar(&ignoreOrder, "semantic", false, "Ignore ordering of list elements")
+	_ = cmd.Flags().MarkDeprecated("semantic", "use --ignore-order instead")
 
 	return cmd
 }
 
-// runSemanticDiff performs semantic comparison of YAML documents
-func runSemanticDiff(writer io.Writer, existing, generated []byte, useColor bool) error {
-	// Parse both YAML documents
-	existingDocs, err := roundtrip.ParseMultiDocYAML(existing)
-	if err != nil {
-		return fmt.Errorf("failed to parse existing manifest: %w", err)
+// colorEnabled reports whether text output to writer is colorized for a
+// --color mode: always, never, or auto to colorize only a terminal. true and
+// false are accepted for always and never.
+func colorEnabled(mode string, writer io.Writer) (bool, error) {
+	switch mode {
+	case "always", "true":
+		return true, nil
+	case "never", "false":
+		return false, nil
+	case "auto":
+		return isTerminal(writer), nil
 	}
-
-	generatedDocs, err := roundtrip.ParseMultiDocYAML(generated)
-	if err != nil {
-		return fmt.Errorf("failed to parse generated manifest: %w", err)
-	}
-
-	// Compare documents semantically
-	equivalent, differences := roundtrip.Compare(existingDocs, generatedDocs, true)
-
-	if equivalent {
-		fmt.Fprintln(writer, "No differences found")
-		return nil
-	}
-
-	// Format and output differences
-	formatSemanticDiff(writer, differences, useColor)
-	return nil
+	return false, fmt.Errorf("invalid --color %q (supported: auto, always, never)", mode)
 }
 
-// formatSemanticDiff formats semantic differences for output
-func formatSemanticDiff(writer io.Writer, differences []roundtrip.Difference, useColor bool) {
-	fmt.Fprintf(writer, "Found %d difference(s):\n\n", len(differences))
-
-	for _, diff := range differences {
-		switch diff.Type {
-		case roundtrip.DiffTypeMissing:
-			if useColor {
-				fmt.Fprintf(writer, "%s- MISSING:%s %s\n", colorRed, colorReset, diff.Path)
-				fmt.Fprintf(writer, "  was: %v\n", diff.Original)
-			} else {
-				fmt.Fprintf(writer, "- MISSING: %s\n", diff.Path)
-				fmt.Fprintf(writer, "  was: %v\n", diff.Original)
-			}
-		case roundtrip.DiffTypeAdded:
-			if useColor {
-				fmt.Fprintf(writer, "%s+ ADDED:%s %s\n", colorGreen, colorReset, diff.Path)
-				fmt.Fprintf(writer, "  now: %v\n", diff.Result)
-			} else {
-				fmt.Fprintf(writer, "+ ADDED: %s\n", diff.Path)
-				fmt.Fprintf(writer, "  now: %v\n", diff.Result)
-			}
-		case roundtrip.DiffTypeModified:
-			if useColor {
-				fmt.Fprintf(writer, "%s~ MODIFIED:%s %s\n", colorYellow, colorReset, diff.Path)
-				fmt.Fprintf(writer, "  %swas:%s %v\n", colorRed, colorReset, diff.Original)
-				fmt.Fprintf(writer, "  %snow:%s %v\n", colorGreen, colorReset, diff.Result)
-			} else {
-				fmt.Fprintf(writer, "~ MODIFIED: %s\n", diff.Path)
-				fmt.Fprintf(writer, "  was: %v\n", diff.Original)
-				fmt.Fprintf(writer, "  now: %v\n", diff.Result)
-			}
-		}
-		fmt.Fprintln(writer)
+// isTerminal reports whether writer is a terminal.
+func isTerminal(writer io.Writer) bool {
+	f, ok := writer.(*os.File)
+	if !ok {
+		return false
 	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
-// runTextDiff performs line-by-line text comparison
-func runTextDiff(writer io.Writer, existing, generated []byte, useColor bool) error {
-	existingLines := strings.Split(string(existing), "\n")
-	generatedLines := strings.Split(string(generated), "\n")
-
-	// Simple line-by-line diff using LCS algorithm
-	diff := computeLineDiff(existingLines, generatedLines)
-
-	if len(diff) == 0 {
-		fmt.Fprintln(writer, "No differences found")
-		return nil
-	}
-
-	// Output diff
-	for _, line := range diff {
-		if useColor {
-			switch {
-			case strings.HasPrefix(line, "-"):
-				fmt.Fprintf(writer, "%s%s%s\n", colorRed, line, colorReset)
-			case strings.HasPrefix(line, "+"):
-				fmt.Fprintf(writer, "%s%s%s\n", colorGreen, line, colorReset)
-			case strings.HasPrefix(line, "@"):
-				fmt.Fprintf(writer, "%s%s%s\n", colorCyan, line, colorReset)
-			default:
-				fmt.Fprintln(writer, line)
-			}
-		} else {
-			fmt.Fprintln(writer, line)
-		}
-	}
-
-	return nil
-}
-
-// computeLineDiff computes a unified diff between two sets of lines
-func computeLineDiff(a, b []string) []string {
-	// Use a simple diff algorithm
-	lcs := computeLCS(a, b)
-	return formatUnifiedDiff(a, b, lcs)
+// diffEntryJSON is the JSON form of a single resource difference.
+type diffEntryJSON struct {
+	Resource string   `json:"resource"`
+	Type     string   `json:"type"`
+	Action   string   `json:"action"`
+	Changes  []string `json:"changes,omitempty"`
 }
 
-// computeLCS computes the Longest Common Subsequence indices
-func computeLCS(a, b []string) [][2]int {
-	m, n := len(a), len(b)
-
-	// Build LCS table
-	dp := make([][]int, m+1)
-	for i := range dp {
-		dp[i] = make([]int, n+1)
+// writeDiffJSON writes the diff result as JSON.
+func writeDiffJSON(writer io.Writer, result *coredomain.DiffResult) error {
+	out := struct {
+		Entries []diffEntryJSON `json:"entries"`
+		Summary struct {
+			Added    int `json:"added"`
+			Removed  int `json:"removed"`
+			Modified int `json:"modified"`
+			Total    int `json:"total"`
+		} `json:"summary"`
+	}{Entries: []diffEntryJSON{}}
+
+	for _, e := range result.Entries {
+		out.Entries = append(out.Entries, diffEntryJSON{
+			Resource: e.Resource,
+			Type:     e.Type,
+			Action:   e.Action,
+			Changes:  e.Changes,
+		})
 	}
+	out.Summary.Added = result.Summary.Added
+	out.Summary.Removed = result.Summary.Removed
+	out.Summary.Modified = result.Summary.Modified
+	out.Summary.Total = result.Summary.Total
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
 
-	for i := 1; i <= m; i++ {
-		for j := 1; j <= n; j++ {
-			if a[i-1] == b[j-1] {
-				dp[i][j] = dp[i-1][j-1] + 1
-			} else {
-				if dp[i-1][j] > dp[i][j-1] {
-					dp[i][j] = dp[i-1][j]
-				} else {
-					dp[i][j] = dp[i][j-1]
-				}
-			}
-		}
+// writeDiffText writes the diff result grouped by resource, with added
+// resources in green, removed in red and modified in yellow.
+func writeDiffText(writer io.Writer, result *coredomain.DiffResult, useColor bool) {
+	if result.Summary.Total == 0 {
+		fmt.Fprintln(writer, "No differences found")
+		return
 	}
 
-	// Backtrack to find LCS indices
-	var result [][2]int
-	i, j := m, n
-	for i > 0 && j > 0 {
-		if a[i-1] == b[j-1] {
-			result = append([][2]int{{i - 1, j - 1}}, result...)
-			i--
-			j--
-		} else if dp[i-1][j] > dp[i][j-1] {
-			i--
-		} else {
-			j--
+	paint := func(color, s string) string {
+		if !useColor {
+			return s
 		}
+		return color + s + colorReset
 	}
 
-	return result
-}
-
-// formatUnifiedDiff formats the diff in unified diff style
-func formatUnifiedDiff(a, b []string, lcs [][2]int) []string {
-	var result []string
-
-	// Track whether there are any actual differences
-	hasDiff := false
-
-	aIdx, bIdx := 0, 0
-	lcsIdx := 0
-
-	for aIdx < len(a) || bIdx < len(b) {
-		// Find the next common line
-		var nextACommon, nextBCommon int
-		if lcsIdx < len(lcs) {
-			nextACommon = lcs[lcsIdx][0]
-			nextBCommon = lcs[lcsIdx][1]
-		} else {
-			nextACommon = len(a)
-			nextBCommon = len(b)
-		}
-
-		// Output lines only in a (removed)
-		for aIdx < nextACommon {
-			if strings.TrimSpace(a[aIdx]) != "" {
-				result = append(result, "- "+a[aIdx])
-				hasDiff = true
+	for _, e := range result.Entries {
+		switch e.Action {
+		case "added":
+			fmt.Fprintln(writer, paint(colorGreen, "+ "+e.Resource))
+		case "removed":
+			fmt.Fprintln(writer, paint(colorRed, "- "+e.Resource))
+		default:
+			fmt.Fprintln(writer, paint(colorYellow, "~ "+e.Resource))
+			for _, change := range e.Changes {
+				fmt.Fprintf(writer, "    %s\n", change)
 			}
-			aIdx++
 		}
-
-		// Output lines only in b (added)
-		for bIdx < nextBCommon {
-			if strings.TrimSpace(b[bIdx]) != "" {
-				result = append(result, "+ "+b[bIdx])
-				hasDiff = true
-			}
-			bIdx++
-		}
-
-		// Skip the common line
-		if lcsIdx < len(lcs) {
-			// Optionally include context lines
-			// result = append(result, "  "+a[aIdx])
-			aIdx++
-			bIdx++
-			lcsIdx++
-		}
-	}
-
-	if !hasDiff {
-		return nil
 	}
 
-	return result
+	fmt.Fprintln(writer)
+	fmt.Fprintln(writer, paint(colorCyan, fmt.Sprintf("%d added, %d modified, %d removed",
+		result.Summary.Added, result.Summary.Modified, result.Summary.Removed)))
 }
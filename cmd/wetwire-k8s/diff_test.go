@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -15,6 +16,8 @@ func TestDiffCommand_Help(t *testing.T) {
 	assert.Contains(t, stdout.String(), "compares the generated Kubernetes manifests")
 	assert.Contains(t, stdout.String(), "--against")
 	assert.Contains(t, stdout.String(), "--semantic")
+	assert.Contains(t, stdout.String(), "--live")
+	assert.Contains(t, stdout.String(), "--format")
 }
 
 func TestDiffCommand_MissingAgainst(t *testing.T) {
@@ -24,7 +27,7 @@ func TestDiffCommand_MissingAgainst(t *testing.T) {
 
 	_, _, err = runTestCommand([]string{"diff", tmpDir})
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "against")
+	assert.Contains(t, err.Error(), "--against or --live")
 }
 
 func TestDiffCommand_NonExistentPath(t *testing.T) {
@@ -42,3 +45,187 @@ func TestDiffCommand_NonExistentAgainst(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to read")
 }
+
+const diffSource = `package main
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var WebDeployment = appsv1.Deployment{}
+
+var WebService = corev1.Service{}
+`
+
+func TestDiffCommand_AgainstFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte(diffSource), 0644))
+
+	manifest := filepath.Join(t.TempDir(), "manifest.yaml")
+	require.NoError(t, os.WriteFile(manifest, []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-deployment
+  uid: 1234
+spec:
+  replicas: 2
+status:
+  readyReplicas: 2
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: old-config
+`), 0644))
+
+	stdout, _, err := runTestCommand([]string{"diff", tmpDir, "--against", manifest, "--color=false"})
+	require.NoError(t, err)
+
+	out := stdout.String()
+	assert.Contains(t, out, "+ Service/web-service")
+	assert.Contains(t, out, "- ConfigMap/old-config")
+	assert.Contains(t, out, "~ Deployment/web-deployment")
+	assert.Contains(t, out, "spec.replicas: removed (2)")
+	// status and server-populated metadata are normalized away
+	assert.NotContains(t, out, "status")
+	assert.NotContains(t, out, "uid")
+	assert.Contains(t, out, "1 added, 1 modified, 1 removed")
+}
+
+func TestDiffCommand_JSONFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte(diffSource), 0644))
+
+	manifestDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(manifestDir, "deployment.yaml"), []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-deployment
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(manifestDir, "service.yml"), []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: web-service
+`), 0644))
+
+	stdout, _, err := runTestCommand([]string{"diff", tmpDir, "--against", manifestDir, "--format", "json"})
+	require.NoError(t, err)
+
+	var result struct {
+		Entries []map[string]interface{} `json:"entries"`
+		Summary map[string]int           `json:"summary"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+	assert.Empty(t, result.Entries)
+	assert.Equal(t, 0, result.Summary["total"])
+}
+
+func TestDiffCommand_AgainstAndLiveExclusive(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte(diffSource), 0644))
+
+	_, _, err := runTestCommand([]string{"diff", tmpDir, "--against", "manifest.yaml", "--live"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be used together")
+}
+
+func TestDiffCommand_FieldChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte(`package main
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+var Web = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web"},
+	Spec: appsv1.DeploymentSpec{
+		Replicas: ptr(int32(3)),
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "web", Image: "nginx:1.27"}},
+			},
+		},
+	},
+}
+`), 0644))
+
+	manifest := filepath.Join(t.TempDir(), "manifest.yaml")
+	require.NoError(t, os.WriteFile(manifest, []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+        - name: web
+          image: nginx:1.25
+`), 0644))
+
+	stdout, _, err := runTestCommand([]string{"diff", tmpDir, "--against", manifest})
+	require.NoError(t, err)
+
+	assert.Equal(t, `~ Deployment/web
+    metadata.labels.app: removed (web)
+    spec.replicas: 2 -> 3
+    spec.template.spec.containers[0].image: nginx:1.25 -> nginx:1.27
+
+0 added, 1 modified, 0 removed
+`, stdout.String(), "output that is not a terminal is not colorized")
+
+	stdout, _, err = runTestCommand([]string{"diff", tmpDir, "--against", manifest, "--color"})
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), colorYellow+"~ Deployment/web"+colorReset)
+
+	_, _, err = runTestCommand([]string{"diff", tmpDir, "--against", manifest, "--color=sometimes"})
+	assert.ErrorContains(t, err, "invalid --color")
+}
+
+func TestDiffCommand_Semantic(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte(`package main
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var Web = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "web"},
+	Spec: corev1.ServiceSpec{
+		Ports: []corev1.ServicePort{{Name: "http", Port: 80}, {Name: "https", Port: 443}},
+	},
+}
+`), 0644))
+
+	manifest := filepath.Join(t.TempDir(), "manifest.yaml")
+	require.NoError(t, os.WriteFile(manifest, []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+    - name: https
+      port: 443
+    - name: http
+      port: 80
+`), 0644))
+
+	// --semantic still works, as the deprecated spelling of --ignore-order
+	stdout, _, err := runTestCommand([]string{"diff", tmpDir, "--against", manifest, "--semantic"})
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), "No differences found")
+
+	stdout, _, err = runTestCommand([]string{"diff", tmpDir, "--against", manifest})
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), "~ Service/web")
+}
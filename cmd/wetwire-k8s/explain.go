@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lex00/wetwire-k8s-go/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+// newExplainCmd creates the explain subcommand
+func newExplainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain [RULE]",
+		Short: "Explain a lint rule",
+		Long: `Explain prints the documentation of a lint rule: its name, description,
+severity, why it matters and an example of code that violates and satisfies it.
+
+Without a rule ID, explain lists all rules.
+
+Examples:
+  wetwire-k8s explain           # List all rules
+  wetwire-k8s explain WK8006    # Explain the image tag rule`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return writeRuleTable(cmd.OutOrStdout(), lint.AllRules())
+			}
+
+			rule, ok := lint.RuleByID(args[0])
+			if !ok {
+				return fmt.Errorf("unknown rule %q (run 'wetwire-k8s explain' to list rules)", args[0])
+			}
+			writeRuleExplanation(cmd.OutOrStdout(), rule)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// writeRuleTable writes the ID, severity and name of each rule as a table.
+func writeRuleTable(w io.Writer, rules []lint.Rule) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RULE\tSEVERITY\tNAME")
+	for _, rule := range rules {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", rule.ID, rule.Severity, rule.Name)
+	}
+	return tw.Flush()
+}
+
+// writeRuleExplanation writes the documentation of a rule.
+func writeRuleExplanation(w io.Writer, rule lint.Rule) {
+	info := lint.Catalog([]lint.Rule{rule})[0]
+	fixable := "no"
+	if info.Fixable {
+		fixable = "yes (wetwire-k8s lint --fix)"
+	}
+
+	fmt.Fprintf(w, "%s: %s\n\n", rule.ID, rule.Name)
+	fmt.Fprintf(w, "Description: %s\n", rule.Description)
+	fmt.Fprintf(w, "Severity:    %s\n", info.Severity)
+	fmt.Fprintf(w, "Category:    %s\n", info.Category)
+	fmt.Fprintf(w, "Auto-fix:    %s\n", fixable)
+
+	if rule.Rationale != "" {
+		fmt.Fprintf(w, "\nWhy:\n  %s\n", rule.Rationale)
+	}
+	if rule.Example.Bad != "" {
+		fmt.Fprintf(w, "\nBad:\n%s\n", indentLines(rule.Example.Bad))
+	}
+	if rule.Example.Good != "" {
+		fmt.Fprintf(w, "\nGood:\n%s\n", indentLines(rule.Example.Good))
+	}
+}
+
+// indentLines indents each line of code by four spaces, expanding tabs so
+// the example lines up in a terminal.
+func indentLines(code string) string {
+	lines := strings.Split(strings.ReplaceAll(code, "\t", "    "), "\n")
+	for i, line := range lines {
+		lines[i] = "    " + line
+	}
+	return strings.Join(lines, "\n")
+}
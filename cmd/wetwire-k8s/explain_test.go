@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainCommand_KnownRule(t *testing.T) {
+	stdout, _, err := runTestCommand([]string{"explain", "WK8006"})
+	require.NoError(t, err)
+
+	output := stdout.String()
+	assert.Contains(t, output, "WK8006: Flag :latest image tags")
+	assert.Contains(t, output, "Severity:    error")
+	assert.Contains(t, output, "Why:")
+	assert.Contains(t, output, `Image: "nginx:latest"`)
+	assert.Contains(t, output, `Image: "nginx:1.25.3"`)
+}
+
+func TestExplainCommand_CaseInsensitive(t *testing.T) {
+	stdout, _, err := runTestCommand([]string{"explain", "wk8006"})
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), "WK8006: Flag :latest image tags")
+}
+
+func TestExplainCommand_UnknownRule(t *testing.T) {
+	_, _, err := runTestCommand([]string{"explain", "WK9999"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown rule "WK9999"`)
+}
+
+func TestExplainCommand_ListsRules(t *testing.T) {
+	stdout, _, err := runTestCommand([]string{"explain"})
+	require.NoError(t, err)
+
+	output := stdout.String()
+	assert.Contains(t, output, "RULE")
+	assert.Contains(t, output, "WK8001")
+	assert.Contains(t, output, "WK8401")
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lex00/wetwire-k8s-go/internal/format"
+	"github.com/spf13/cobra"
+)
+
+// newFmtCmd creates the fmt subcommand
+func newFmtCmd() *cobra.Command {
+	var list bool
+	var diff bool
+
+	cmd := &cobra.Command{
+		Use:   "fmt [PATH]",
+		Short: "Canonicalize the layout of resource Go files",
+		Long: `Fmt rewrites Go files that declare Kubernetes resources into a canonical
+layout, so generated and hand-written code read the same way:
+
+  - fields of resource literals are ordered TypeMeta, ObjectMeta, Spec,
+    followed by the other fields in their original order
+  - Labels, MatchLabels and Selector maps are sorted by key
+  - the file is formatted with gofmt
+
+Only the top-level variables that declare resources are reordered. Fmt never
+changes what the resources declare; use 'wetwire-k8s lint --fix' for that.
+
+If PATH is not specified, the current directory is used. The names of the
+files that were rewritten are printed.
+
+Examples:
+  wetwire-k8s fmt                # Format all files in the current directory
+  wetwire-k8s fmt ./k8s          # Format a directory
+  wetwire-k8s fmt -l ./k8s       # List files that are not formatted
+  wetwire-k8s fmt -d main.go     # Show the changes without writing them`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := format.GoFiles(pathArg(args))
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			for _, file := range files {
+				if list || diff {
+					fileDiff, err := format.Diff(file)
+					if err != nil {
+						return err
+					}
+					if fileDiff == nil {
+						continue
+					}
+					if list {
+						fmt.Fprintln(out, file)
+					}
+					if diff {
+						fmt.Fprint(out, fileDiff.Diff)
+					}
+					continue
+				}
+
+				changed, err := format.File(file)
+				if err != nil {
+					return err
+				}
+				if changed {
+					fmt.Fprintln(out, file)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&list, "list", "l", false, "List files whose formatting differs, without rewriting them")
+	cmd.Flags().BoolVarP(&diff, "diff", "d", false, "Print diffs instead of rewriting files")
+
+	return cmd
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const unformattedResource = `package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var AppConfig = corev1.ConfigMap{
+	Data:       map[string]string{"key": "value"},
+	ObjectMeta: metav1.ObjectMeta{Name: "app-config", Labels: map[string]string{"tier": "backend", "app": "api"}},
+}
+`
+
+const formattedResource = `package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var AppConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-config", Labels: map[string]string{"app": "api", "tier": "backend"}},
+	Data:       map[string]string{"key": "value"},
+}
+`
+
+func TestFmtCommand(t *testing.T) {
+	dir := t.TempDir()
+	unformatted := filepath.Join(dir, "config.go")
+	formatted := filepath.Join(dir, "formatted.go")
+	require.NoError(t, os.WriteFile(unformatted, []byte(unformattedResource), 0644))
+	require.NoError(t, os.WriteFile(formatted, []byte(formattedResource), 0644))
+
+	t.Run("list", func(t *testing.T) {
+		stdout, _, err := runTestCommand([]string{"fmt", "-l", dir})
+		require.NoError(t, err)
+		assert.Equal(t, unformatted+"\n", stdout.String())
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		stdout, _, err := runTestCommand([]string{"fmt", "-d", dir})
+		require.NoError(t, err)
+		assert.Contains(t, stdout.String(), "+++ "+unformatted+"\n")
+		assert.Contains(t, stdout.String(), `+	ObjectMeta: metav1.ObjectMeta{Name: "app-config", Labels: map[string]string{"app": "api", "tier": "backend"}},`)
+
+		content, err := os.ReadFile(unformatted)
+		require.NoError(t, err)
+		assert.Equal(t, unformattedResource, string(content), "diff should not rewrite files")
+	})
+
+	t.Run("write", func(t *testing.T) {
+		stdout, _, err := runTestCommand([]string{"fmt", dir})
+		require.NoError(t, err)
+		assert.Equal(t, unformatted+"\n", stdout.String())
+
+		content, err := os.ReadFile(unformatted)
+		require.NoError(t, err)
+		assert.Equal(t, formattedResource, string(content))
+
+		stdout, _, err = runTestCommand([]string{"fmt", dir})
+		require.NoError(t, err)
+		assert.Empty(t, stdout.String())
+	})
+}
+
+func TestFmtCommand_NonExistentPath(t *testing.T) {
+	_, _, err := runTestCommand([]string{"fmt", "/nonexistent/path"})
+	assert.ErrorContains(t, err, "failed to access path")
+}
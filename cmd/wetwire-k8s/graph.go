@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	coredomain "github.com/lex00/wetwire-core-go/domain"
+	"github.com/lex00/wetwire-k8s-go/domain"
+	"github.com/spf13/cobra"
+)
+
+// configureGraphCmd extends the auto-generated graph command with
+// k8s-specific graph options.
+func configureGraphCmd(rootCmd *cobra.Command, d *domain.K8sDomain) {
+	graphCmd := findSubcommand(rootCmd, "graph")
+	if graphCmd == nil {
+		return
+	}
+
+	var includeExternal, transitive, collapse bool
+	graphCmd.Flags().BoolVar(&includeExternal, "include-external", false,
+		"Show references to objects not defined in the project as dashed edges")
+	graphCmd.Flags().BoolVar(&transitive, "transitive", false,
+		"Also show indirect dependencies, styled differently from direct ones")
+	graphCmd.Flags().BoolVar(&collapse, "collapse", false,
+		"Show only top-level objects, hiding helper values such as Containers")
+
+	graphCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		path := "."
+		if len(args) > 0 {
+			path = args[0]
+		}
+
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		format, _ := cmd.Flags().GetString("format")
+		if format == "text" {
+			// The global --format defaults to "text"; use the graph default
+			format = ""
+		}
+
+		ctx := coredomain.NewContextWithVerbose(context.Background(), path, verbose)
+		result, err := d.GraphWithOptions(ctx, path, domain.K8sGraphOpts{
+			GraphOpts:       coredomain.GraphOpts{Format: format},
+			IncludeExternal: includeExternal,
+			Transitive:      transitive,
+			Collapse:        collapse,
+		})
+		if err != nil {
+			return fmt.Errorf("graph failed: %w", err)
+		}
+
+		// Graph output is already rendered in the requested format
+		fmt.Fprintln(cmd.OutOrStdout(), result.Data)
+		return nil
+	}
+}
@@ -10,61 +10,80 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// newClusterClient returns the client used by import --from-cluster.
+// It is a variable so tests can replace the cluster.
+var newClusterClient = func(kubeconfig string) importer.ClusterClient {
+	return importer.KubectlClient{Kubeconfig: kubeconfig}
+}
+
 // newImportCmd creates the import subcommand
 func newImportCmd() *cobra.Command {
 	var output string
 	var pkgName string
 	var varPrefix string
+	var fromCluster string
+	var namespace string
+	var kubeconfig string
+	var enumConstants bool
 
 	cmd := &cobra.Command{
-		Use:   "import <file>",
+		Use:   "import [file]",
 		Short: "Convert Kubernetes YAML manifests to Go code",
 		Long: `Import reads YAML manifests and generates Go source code
 using the wetwire pattern.
 
 Use '-' as the file path to read from stdin.
 
+Use --from-cluster <type>/<name> instead of a file to import an object that
+is running in the cluster. It is fetched with kubectl, and status and
+server-managed metadata (uid, resourceVersion, managedFields, ...) are removed
+before the Go code is generated.
+
+Fields of Kubernetes enum types, such as a Service type, a port protocol or a
+restart policy, are written as typed constants like corev1.ProtocolTCP. Use
+--enum-constants=false to write them as string literals instead.
+
 Examples:
   wetwire-k8s import deployment.yaml           # Convert YAML to Go
   wetwire-k8s import -o k8s.go deployment.yaml # Save to file
   wetwire-k8s import -p myapp deployment.yaml  # Use custom package name
-  cat manifests.yaml | wetwire-k8s import -    # Read from stdin`,
-		Args: cobra.ExactArgs(1),
+  cat manifests.yaml | wetwire-k8s import -    # Read from stdin
+  wetwire-k8s import --from-cluster deployment/web-app -n default`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if fromCluster != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			inputFile := args[0]
+			// Configure importer
+			opts := importer.Options{
+				PackageName:   pkgName,
+				VarPrefix:     varPrefix,
+				EnumConstants: enumConstants,
+			}
 
-			// Read input
-			var inputData []byte
-			var err error
+			log := newLogger(cmd, cmd.ErrOrStderr())
 
-			if inputFile == "-" {
-				inputData, err = io.ReadAll(os.Stdin)
+			var result *importer.Result
+			var err error
+			if fromCluster != "" {
+				log.Debugf("Importing %s from the cluster", fromCluster)
+				result, err = importer.ImportFromCluster(newClusterClient(kubeconfig), fromCluster, namespace, opts)
 				if err != nil {
-					return fmt.Errorf("failed to read stdin: %w", err)
+					err = fmt.Errorf("import failed: %w", err)
 				}
 			} else {
-				inputData, err = os.ReadFile(inputFile)
-				if err != nil {
-					return fmt.Errorf("failed to read file: %w", err)
-				}
-			}
-
-			// Configure importer
-			opts := importer.Options{
-				PackageName: pkgName,
-				VarPrefix:   varPrefix,
+				log.Debugf("Importing %s", args[0])
+				result, err = importFromFile(args[0], opts)
 			}
-
-			// Run import
-			result, err := importer.ImportBytes(inputData, opts)
 			if err != nil {
-				return fmt.Errorf("import failed: %w", err)
+				return err
 			}
 
 			// Output warnings to stderr
-			errWriter := cmd.ErrOrStderr()
 			for _, warn := range result.Warnings {
-				fmt.Fprintf(errWriter, "warning: %s\n", warn)
+				log.Infof("warning: %s", warn)
 			}
 
 			// Determine output destination
@@ -85,7 +104,7 @@ Examples:
 					return fmt.Errorf("failed to write output: %w", err)
 				}
 
-				fmt.Fprintf(errWriter, "Imported %d resources to %s\n", result.ResourceCount, output)
+				log.Infof("Imported %d resources to %s", result.ResourceCount, output)
 			}
 
 			return nil
@@ -95,6 +114,34 @@ Examples:
 	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file path (default: stdout)")
 	cmd.Flags().StringVarP(&pkgName, "package", "p", "main", "Go package name")
 	cmd.Flags().StringVar(&varPrefix, "var-prefix", "", "Prefix for generated variable names")
+	cmd.Flags().StringVar(&fromCluster, "from-cluster", "", "Import a live object from the cluster, as <type>/<name>")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace of the --from-cluster object (default: current context)")
+	cmd.Flags().StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig used with --from-cluster")
+	cmd.Flags().BoolVar(&enumConstants, "enum-constants", true, "Write enum fields as typed constants instead of string literals")
 
 	return cmd
 }
+
+// importFromFile imports the manifests in inputFile, or stdin when it is "-".
+func importFromFile(inputFile string, opts importer.Options) (*importer.Result, error) {
+	var inputData []byte
+	var err error
+
+	if inputFile == "-" {
+		inputData, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+	} else {
+		inputData, err = os.ReadFile(inputFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+	}
+
+	result, err := importer.ImportBytes(inputData, opts)
+	if err != nil {
+		return nil, fmt.Errorf("import failed: %w", err)
+	}
+	return result, nil
+}
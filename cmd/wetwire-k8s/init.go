@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	coredomain "github.com/lex00/wetwire-core-go/domain"
+	"github.com/lex00/wetwire-k8s-go/domain"
+	"github.com/spf13/cobra"
+)
+
+// configureInitCmd extends the auto-generated init command with project
+// templates.
+func configureInitCmd(rootCmd *cobra.Command, d *domain.K8sDomain) {
+	initCmd := findSubcommand(rootCmd, "init")
+	if initCmd == nil {
+		return
+	}
+
+	initCmd.Long += `
+
+Use --template to scaffold a working starter instead of a single Namespace,
+matching the example of the same name: ` + strings.Join(domain.InitTemplates(), ", ") + `.
+Use --list-templates to print the available templates.`
+
+	var template string
+	var listTemplates bool
+	initCmd.Flags().StringVarP(&template, "template", "t", "",
+		"Scaffold the resources of a template ("+strings.Join(domain.InitTemplates(), ", ")+")")
+	initCmd.Flags().BoolVar(&listTemplates, "list-templates", false, "List the available templates and exit")
+
+	initCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if listTemplates {
+			for _, name := range domain.InitTemplates() {
+				fmt.Fprintln(cmd.OutOrStdout(), name)
+			}
+			return nil
+		}
+
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		format, _ := cmd.Flags().GetString("format")
+		name, _ := cmd.Flags().GetString("name")
+		path, _ := cmd.Flags().GetString("path")
+		scenario, _ := cmd.Flags().GetBool("scenario")
+		description, _ := cmd.Flags().GetString("description")
+
+		ctx := coredomain.NewContextWithVerbose(context.Background(), ".", verbose)
+		result, err := d.InitWithOptions(ctx, ".", domain.K8sInitOpts{
+			InitOpts: coredomain.InitOpts{
+				Name:        name,
+				Path:        path,
+				Scenario:    scenario,
+				Description: description,
+			},
+			Template: template,
+		})
+		if err != nil {
+			return fmt.Errorf("init failed: %w", err)
+		}
+
+		text, err := coredomain.FormatResult(result, format)
+		if err != nil {
+			return fmt.Errorf("failed to format result: %w", err)
+		}
+		fmt.Fprint(cmd.OutOrStdout(), text)
+		return nil
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	coredomain "github.com/lex00/wetwire-core-go/domain"
+	"github.com/lex00/wetwire-k8s-go/domain"
+	"github.com/spf13/cobra"
+)
+
+// configureLintCmd extends the auto-generated lint command with report
+// formats that the core result formatter does not support, a dry-run mode
+// for --fix and a warning threshold.
+func configureLintCmd(rootCmd *cobra.Command, d *domain.K8sDomain) {
+	lintCmd := findSubcommand(rootCmd, "lint")
+	if lintCmd == nil {
+		return
+	}
+
+	lintCmd.Long += `
+
+Use --format github for GitHub Actions annotations, --format sarif for a
+SARIF 2.1.0 log that can be uploaded to GitHub code scanning, or --format junit
+for JUnit XML test reports.
+
+Use --fix --dry-run to print a unified diff of the fixes without applying them.
+
+By default any issue fails the command. With --max-warnings N it fails only on
+errors or on more than N warnings. Linting a directory ends the status line
+with the number of errors, warnings and info issues and the files checked.
+
+Use --config <file> to read the lint settings from a .wetwire.yaml other than
+the one found next to the path, such as a stricter CI profile. Without a path
+argument, its source directory is linted.
+
+Use --watch to lint again whenever a .go file under the path changes, until
+interrupted with Ctrl+C.`
+
+	var dryRun bool
+	var maxWarnings int
+	var watch bool
+	lintCmd.Flags().BoolVar(&dryRun, "dry-run", false,
+		"With --fix, print a diff of the fixes instead of applying them")
+	lintCmd.Flags().IntVar(&maxWarnings, "max-warnings", -1,
+		"Fail only on errors or more than this many warnings (-1 fails on any issue)")
+	lintCmd.Flags().BoolVar(&watch, "watch", false, "Lint again when .go files change")
+	lintCmd.Flags().String("config", "", "Read lint settings from this .wetwire.yaml")
+
+	lintCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		fix, _ := cmd.Flags().GetBool("fix")
+		if dryRun && !fix {
+			return fmt.Errorf("--dry-run requires --fix")
+		}
+
+		format, _ := cmd.Flags().GetString("format")
+		opts := domain.K8sLintOpts{
+			LintOpts: coredomain.LintOpts{Format: format, Fix: fix},
+			DryRun:   dryRun,
+		}
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if cfg != nil {
+			opts.ConfigFile = cfg.Path
+			if len(args) == 0 && cfg.Source != "" {
+				args = []string{cfg.Source}
+			}
+		}
+		if maxWarnings >= 0 {
+			opts.MaxWarnings = &maxWarnings
+		}
+		if watch {
+			return runWatched(cmd, pathArg(args), func() error {
+				return runLint(cmd, args, d, opts)
+			})
+		}
+		return runLint(cmd, args, d, opts)
+	}
+}
+
+// runLint runs the linter and prints the result. Report formats and the fix
+// diff in dry-run mode are printed as-is; other formats go through the core
+// result formatter. The command fails when the result does.
+func runLint(cmd *cobra.Command, args []string, d *domain.K8sDomain, opts domain.K8sLintOpts) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	opts.Disable, _ = cmd.Flags().GetStringSlice("disable")
+
+	log := newLogger(cmd, cmd.OutOrStdout())
+	log.Debugf("Linting %s", path)
+
+	ctx := coredomain.NewContextWithVerbose(context.Background(), path, verbose)
+	result, err := d.LintWithOptions(ctx, path, opts)
+	if err != nil {
+		return fmt.Errorf("lint failed: %w", err)
+	}
+
+	if opts.DryRun {
+		if diff, _ := result.Data.(string); diff != "" {
+			fmt.Fprint(cmd.OutOrStdout(), diff)
+		} else {
+			log.Infof("%s", result.Message)
+		}
+		return nil
+	}
+
+	if domain.IsLintReportFormat(opts.Format) {
+		if report, ok := result.Data.(string); ok {
+			fmt.Fprint(cmd.OutOrStdout(), report)
+		}
+	} else {
+		output, err := coredomain.FormatResult(result, opts.Format)
+		if err != nil {
+			return fmt.Errorf("failed to format result: %w", err)
+		}
+		if result.Success && opts.Format == "text" {
+			// A passing text result is only a success message
+			log.Infof("%s", output)
+		} else {
+			fmt.Fprint(cmd.OutOrStdout(), output)
+		}
+	}
+
+	if !result.Success {
+		// Keep the report parseable: lint failures are not usage errors
+		cmd.SilenceUsage = true
+		return errOperationFailed
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runLintCommand runs the domain lint command with the k8s extensions
+// applied, as main() configures it.
+func runLintCommand(args []string) (*bytes.Buffer, error) {
+	stdout := &bytes.Buffer{}
+
+	d := &domain.K8sDomain{}
+	rootCmd := domain.CreateRootCommand(d)
+	configureLintCmd(rootCmd, d)
+	rootCmd.SetOut(stdout)
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs(append([]string{"lint"}, args...))
+
+	err := rootCmd.Execute()
+	return stdout, err
+}
+
+const lintBadFile = "../../internal/lint/testdata/wk8006_bad.go"
+
+func TestLintCommand_SARIF(t *testing.T) {
+	stdout, err := runLintCommand([]string{lintBadFile, "--format", "sarif"})
+	assert.Error(t, err, "lint should fail when issues are found")
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	var rules []string
+	for _, result := range log.Runs[0].Results {
+		rules = append(rules, result.RuleID)
+	}
+	assert.Contains(t, rules, "WK8006")
+}
+
+func TestLintCommand_GitHub(t *testing.T) {
+	stdout, err := runLintCommand([]string{lintBadFile, "--format", "github"})
+	assert.Error(t, err)
+	assert.Contains(t, stdout.String(), "::error file=")
+}
+
+func TestLintCommand_JUnit(t *testing.T) {
+	stdout, err := runLintCommand([]string{lintBadFile, "--format", "junit"})
+	assert.Error(t, err)
+
+	var suites struct {
+		Failures int `xml:"failures,attr"`
+	}
+	require.NoError(t, xml.Unmarshal(stdout.Bytes(), &suites))
+	assert.Greater(t, suites.Failures, 0)
+}
+
+func TestLintCommand_FixDryRun(t *testing.T) {
+	content := `package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+var MyContainer = corev1.Container{
+	Name:  "app",
+	Image: "nginx:1.21",
+}
+`
+	testFile := filepath.Join(t.TempDir(), "app.go")
+	require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+	t.Run("should print the diff without writing", func(t *testing.T) {
+		stdout, err := runLintCommand([]string{testFile, "--fix", "--dry-run"})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(stdout.String(), "--- "+testFile+".orig\n"))
+		assert.Contains(t, stdout.String(), "ImagePullPolicy")
+
+		unchanged, err := os.ReadFile(testFile)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(unchanged))
+	})
+
+	t.Run("should require --fix", func(t *testing.T) {
+		_, err := runLintCommand([]string{testFile, "--dry-run"})
+		assert.ErrorContains(t, err, "--dry-run requires --fix")
+	})
+}
+
+func TestLintCommand_MaxWarnings(t *testing.T) {
+	// One warning (WK8102) and one info issue (WK8013), no errors
+	dir := t.TempDir()
+	content := `package app
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var AppConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+	Data:       map[string]string{"LOG_LEVEL": "info"},
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.go"), []byte(content), 0644))
+
+	t.Run("should fail on any issue by default", func(t *testing.T) {
+		stdout, err := runLintCommand([]string{dir})
+		assert.Error(t, err)
+		assert.Contains(t, stdout.String(), "✗ Failed: lint issues found: 0 errors, 1 warning, 1 info across 1 file\n")
+	})
+
+	t.Run("should pass with warnings within the limit", func(t *testing.T) {
+		stdout, err := runLintCommand([]string{dir, "--max-warnings", "1"})
+		require.NoError(t, err)
+		assert.Contains(t, stdout.String(), "✓ Success: lint issues found (warnings within the limit of 1): 0 errors, 1 warning, 1 info across 1 file\n")
+		assert.Contains(t, stdout.String(), "WK8102", "issues are still listed")
+	})
+
+	t.Run("should fail with warnings over the limit", func(t *testing.T) {
+		stdout, err := runLintCommand([]string{dir, "--max-warnings", "0"})
+		assert.Error(t, err)
+		assert.Contains(t, stdout.String(), "✗ Failed: lint issues found (warnings over the limit of 0): 0 errors, 1 warning, 1 info across 1 file\n")
+	})
+
+	t.Run("should fail on errors regardless of the limit", func(t *testing.T) {
+		_, err := runLintCommand([]string{lintBadFile, "--max-warnings", "100"})
+		assert.Error(t, err)
+	})
+
+	t.Run("should not summarize single files", func(t *testing.T) {
+		stdout, err := runLintCommand([]string{filepath.Join(dir, "app.go")})
+		assert.Error(t, err)
+		assert.Contains(t, stdout.String(), "✗ Failed: lint issues found\n")
+	})
+}
+
+func TestLintCommand_Config(t *testing.T) {
+	// One warning (WK8102) and one info issue (WK8013)
+	dir := t.TempDir()
+	source := filepath.Join(dir, "k8s")
+	require.NoError(t, os.Mkdir(source, 0755))
+	content := `package app
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var AppConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+	Data:       map[string]string{"LOG_LEVEL": "info"},
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(source, "app.go"), []byte(content), 0644))
+	relaxed := filepath.Join(dir, "relaxed.yaml")
+	require.NoError(t, os.WriteFile(relaxed, []byte("source: k8s\nlint:\n  min_severity: error\n"), 0644))
+
+	_, err := runLintCommand([]string{source})
+	assert.Error(t, err, "without a config the warning fails lint")
+
+	stdout, err := runLintCommand([]string{"--config", relaxed})
+	require.NoError(t, err, "the config lints its source with its settings")
+	assert.NotContains(t, stdout.String(), "WK8102")
+}
+
+func TestLintToolHandler(t *testing.T) {
+	handler := lintToolHandler((&domain.K8sDomain{}).Linter())
+
+	t.Run("should return the SARIF report", func(t *testing.T) {
+		out, err := handler(context.Background(), map[string]any{
+			"package": lintBadFile,
+			"format":  "sarif",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, out, `"ruleId": "WK8006"`)
+	})
+
+	t.Run("should return a structured result for other formats", func(t *testing.T) {
+		out, err := handler(context.Background(), map[string]any{
+			"package": lintBadFile,
+			"disable": []any{"WK8006"},
+		})
+		require.NoError(t, err)
+
+		var result toolResult
+		require.NoError(t, json.Unmarshal([]byte(out), &result))
+		assert.False(t, result.OK)
+		assert.Equal(t, "lint issues found", result.Summary)
+		require.NotEmpty(t, result.Errors)
+		for _, e := range result.Errors {
+			assert.Contains(t, e.File, "wk8006_bad.go")
+			assert.Positive(t, e.Line)
+			assert.NotEmpty(t, e.Code)
+			assert.NotEmpty(t, e.Message)
+		}
+		assert.NotContains(t, out, "WK8006")
+	})
+
+	t.Run("should report a missing path as a failed result", func(t *testing.T) {
+		out, err := handler(context.Background(), map[string]any{"package": "does-not-exist"})
+		require.NoError(t, err)
+
+		var result toolResult
+		require.NoError(t, json.Unmarshal([]byte(out), &result))
+		assert.False(t, result.OK)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, result.Summary, result.Errors[0].Message)
+	})
+}
+
+func TestLintToolSchema(t *testing.T) {
+	schema := lintToolSchema()
+	format := schema["properties"].(map[string]any)["format"].(map[string]any)
+	assert.Contains(t, format["enum"], "sarif")
+	assert.Contains(t, schema["properties"], "package")
+}
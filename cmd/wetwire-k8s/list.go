@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	coredomain "github.com/lex00/wetwire-core-go/domain"
+	"github.com/lex00/wetwire-k8s-go/domain"
+	"github.com/spf13/cobra"
+)
+
+// configureListCmd extends the auto-generated list command with kind and
+// namespace filters and a choice of output shape.
+func configureListCmd(rootCmd *cobra.Command, d *domain.K8sDomain) {
+	listCmd := findSubcommand(rootCmd, "list")
+	if listCmd == nil {
+		return
+	}
+
+	var kind, namespace, output string
+	var pruneHelpers bool
+	listCmd.Flags().StringVarP(&kind, "kind", "k", "", "List only resources of this kind (e.g. Deployment)")
+	listCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "List only resources in this namespace")
+	listCmd.Flags().StringVarP(&output, "output", "o", "",
+		"Print the resources as "+strings.Join(domain.ListOutputFormats, ", ")+" instead of a result")
+	listCmd.Flags().BoolVar(&pruneHelpers, "prune-helpers", false, "Leave helper values (Containers, PodSpecs, ...) declared as variables out of the list")
+
+	listCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		path := "."
+		if len(args) > 0 {
+			path = args[0]
+		}
+
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		format, _ := cmd.Flags().GetString("format")
+		listType, _ := cmd.Flags().GetString("type")
+
+		ctx := coredomain.NewContextWithVerbose(context.Background(), path, verbose)
+		result, err := d.ListWithOptions(ctx, path, domain.K8sListOpts{
+			ListOpts:     coredomain.ListOpts{Format: format, Type: listType},
+			Kind:         kind,
+			Namespace:    namespace,
+			PruneHelpers: pruneHelpers,
+		})
+		if err != nil {
+			return fmt.Errorf("list failed: %w", err)
+		}
+
+		rendered, err := renderListResult(result, format, output)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), rendered)
+		return nil
+	}
+}
+
+// renderListResult renders the list data in the --output shape, or the
+// whole result in the global --format when no output shape is given.
+func renderListResult(result *coredomain.Result, format, output string) (string, error) {
+	if output == "" {
+		return coredomain.FormatResult(result, format)
+	}
+
+	list, _ := result.Data.([]map[string]any)
+	return domain.RenderList(list, output)
+}
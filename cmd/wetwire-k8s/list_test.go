@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runListCommand executes the list command and returns stdout.
+func runListCommand(args []string) (*bytes.Buffer, error) {
+	stdout := &bytes.Buffer{}
+
+	d := &domain.K8sDomain{}
+	rootCmd := domain.CreateRootCommand(d)
+	configureListCmd(rootCmd, d)
+	rootCmd.SetOut(stdout)
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs(append([]string{"list"}, args...))
+
+	err := rootCmd.Execute()
+	return stdout, err
+}
+
+const listTestdata = "../../internal/discover/testdata"
+
+func TestListCommand_Output(t *testing.T) {
+	t.Run("should print a filtered table", func(t *testing.T) {
+		stdout, err := runListCommand([]string{listTestdata, "--kind", "Service", "-n", "frontend", "-o", "table"})
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.Regexp(t, `^NAME\s+KIND\s+NAMESPACE\s+FILE\s+DESCRIPTION$`, lines[0])
+		assert.Regexp(t, `^FrontendService\s+Service\s+frontend\s+\S+service\.go:9\s+Service selecting the pods of FrontendDeployment in deployment\.go$`, lines[1])
+	})
+
+	t.Run("should print the list as JSON", func(t *testing.T) {
+		stdout, err := runListCommand([]string{listTestdata, "-n", "frontend", "-o", "json"})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(stdout.String(), "[\n"))
+		assert.Contains(t, stdout.String(), `"name": "FrontendDeployment"`)
+	})
+
+	t.Run("should reject unknown output formats", func(t *testing.T) {
+		_, err := runListCommand([]string{listTestdata, "-o", "csv"})
+		assert.ErrorContains(t, err, "unknown output format")
+	})
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// logLevel is how much a command prints besides its output, such as the
+// manifests of build or the report of lint.
+type logLevel int
+
+const (
+	logError logLevel = iota // Errors only (--quiet)
+	logInfo                  // Errors, warnings, progress and success messages
+	logDebug                 // Everything, including debug details (--verbose)
+)
+
+// logLevelEnv selects the log level when neither --quiet nor --verbose is
+// given: "error", "info" or "debug".
+const logLevelEnv = "WETWIRE_LOG_LEVEL"
+
+// addQuietFlag adds the global --quiet flag to rootCmd, the counterpart of
+// the --verbose flag it already has.
+func addQuietFlag(rootCmd *cobra.Command) {
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Print errors only, not progress or success messages")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
+}
+
+// logger prints the status messages of a command at or below its level.
+// Info messages go to out. Errors and debug messages go to stderr, so that
+// they do not mix with output printed to stdout.
+type logger struct {
+	level logLevel
+	out   io.Writer
+	err   io.Writer
+}
+
+// newLogger returns a logger for cmd at the level set by its flags, writing
+// info messages to out.
+func newLogger(cmd *cobra.Command, out io.Writer) *logger {
+	return &logger{level: commandLogLevel(cmd), out: out, err: cmd.ErrOrStderr()}
+}
+
+// commandLogLevel returns the level set by --quiet or --verbose, or else by
+// the WETWIRE_LOG_LEVEL environment variable.
+func commandLogLevel(cmd *cobra.Command) logLevel {
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		return logError
+	}
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+		return logDebug
+	}
+	return envLogLevel()
+}
+
+// envLogLevel returns the level set by WETWIRE_LOG_LEVEL, defaulting to
+// logInfo.
+func envLogLevel() logLevel {
+	switch strings.ToLower(os.Getenv(logLevelEnv)) {
+	case "error", "quiet":
+		return logError
+	case "debug", "verbose":
+		return logDebug
+	}
+	return logInfo
+}
+
+// Errorf prints an error. Errors are printed at every level.
+func (l *logger) Errorf(format string, args ...any) {
+	printLine(l.err, format, args...)
+}
+
+// Infof prints a warning, progress or success message unless --quiet is set.
+func (l *logger) Infof(format string, args ...any) {
+	if l.level >= logInfo {
+		printLine(l.out, format, args...)
+	}
+}
+
+// Debugf prints a message only when --verbose is set.
+func (l *logger) Debugf(format string, args ...any) {
+	if l.level >= logDebug {
+		printLine(l.err, format, args...)
+	}
+}
+
+// printLine prints a formatted message ending with exactly one newline.
+func printLine(w io.Writer, format string, args ...any) {
+	fmt.Fprintln(w, strings.TrimRight(fmt.Sprintf(format, args...), "\n"))
+}
@@ -17,16 +17,32 @@ func main() {
 	// Create the domain and root command
 	d := &domain.K8sDomain{}
 	rootCmd := domain.CreateRootCommand(d)
+	addQuietFlag(rootCmd)
+	configureBuildCmd(rootCmd, d)
+	configureGraphCmd(rootCmd, d)
+	configureInitCmd(rootCmd, d)
+	configureLintCmd(rootCmd, d)
+	configureListCmd(rootCmd, d)
+
+	// The custom diff command below compares built output against manifests
+	// or a live cluster and replaces the generic two-file diff.
+	if diffCmd := findSubcommand(rootCmd, "diff"); diffCmd != nil {
+		rootCmd.RemoveCommand(diffCmd)
+	}
 
 	// Add custom commands that are not part of the standard domain interface
 	rootCmd.AddCommand(
 		newImportCmd(),
+		newRoundtripCmd(),
 		newDiffCmd(),
 		newWatchCmd(),
+		newFmtCmd(),
 		newTestCmd(),
 		newDesignCmd(),
 		newMCPCmd(),
 		newCodegenCmd(),
+		newRulesCmd(),
+		newExplainCmd(),
 	)
 
 	if err := rootCmd.Execute(); err != nil {
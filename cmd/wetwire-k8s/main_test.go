@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/lex00/wetwire-k8s-go/internal/importer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -122,6 +123,47 @@ func TestImportCommand_Help(t *testing.T) {
 	assert.Contains(t, stdout.String(), "Import reads YAML manifests")
 }
 
+// clusterClientFunc adapts a function to importer.ClusterClient.
+type clusterClientFunc func(resource, name, namespace string) ([]byte, error)
+
+func (f clusterClientFunc) Get(resource, name, namespace string) ([]byte, error) {
+	return f(resource, name, namespace)
+}
+
+func TestImportCommand_FromCluster(t *testing.T) {
+	var gotKubeconfig, gotRef string
+	prev := newClusterClient
+	newClusterClient = func(kubeconfig string) importer.ClusterClient {
+		gotKubeconfig = kubeconfig
+		return clusterClientFunc(func(resource, name, namespace string) ([]byte, error) {
+			gotRef = namespace + "/" + resource + "/" + name
+			return []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: prod
+  uid: 0b3c1f7e-6a2d-4f0e-8c9b-1d2e3f4a5b6c
+  resourceVersion: "1042"
+data:
+  key: value
+`), nil
+		})
+	}
+	defer func() { newClusterClient = prev }()
+
+	stdout, _, err := runTestCommand([]string{"import", "--from-cluster", "configmap/app-config", "-n", "prod", "--kubeconfig", "/tmp/kubeconfig"})
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/kubeconfig", gotKubeconfig)
+	assert.Equal(t, "prod/configmap/app-config", gotRef)
+	assert.Contains(t, stdout.String(), "var AppConfigConfigMap = corev1.ConfigMap{")
+	assert.NotContains(t, stdout.String(), "ResourceVersion")
+
+	// A file cannot be combined with --from-cluster
+	_, _, err = runTestCommand([]string{"import", "--from-cluster", "configmap/app-config", "manifest.yaml"})
+	assert.Error(t, err)
+}
+
 func TestHelpCommand(t *testing.T) {
 	stdout, _, err := runTestCommand([]string{"--help"})
 	assert.NoError(t, err)
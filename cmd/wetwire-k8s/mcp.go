@@ -1,17 +1,27 @@
 // MCP server implementation for embedded design mode.
 //
 // When mcp subcommand is called, this runs the MCP protocol over stdio,
-// providing wetwire_build, wetwire_lint, wetwire_validate, wetwire_list, wetwire_graph, and wetwire_init tools.
+// providing wetwire_build, wetwire_lint, wetwire_fix, wetwire_validate, wetwire_list, wetwire_graph, and wetwire_init tools.
 package main
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
 
 	"github.com/lex00/wetwire-core-go/domain"
+	"github.com/lex00/wetwire-core-go/mcp"
 	k8sdomain "github.com/lex00/wetwire-k8s-go/domain"
 	"github.com/spf13/cobra"
 )
 
+// lintFormats are the formats accepted by the wetwire_lint tool.
+var lintFormats = []string{"text", "json", "github", "sarif", "junit"}
+
+// graphFormats are the formats accepted by the wetwire_graph tool.
+var graphFormats = []string{"dot", "mermaid", "json"}
+
 // newMCPCmd creates the mcp subcommand.
 func newMCPCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -22,9 +32,10 @@ func newMCPCmd() *cobra.Command {
 This command runs an MCP server over stdio, providing tools for:
   - wetwire_build: Generate Kubernetes manifests from Go code
   - wetwire_lint: Lint Go code for wetwire-k8s patterns
-  - wetwire_validate: Validate manifests using kubeconform
-  - wetwire_list: List discovered Kubernetes resources
-  - wetwire_graph: Generate dependency graphs
+  - wetwire_fix: Apply lint auto-fixes, or preview them as a diff
+  - wetwire_validate: Validate resources against the built-in API schemas (offline)
+  - wetwire_list: List discovered Kubernetes resources as JSON
+  - wetwire_graph: Generate dependency graphs (DOT, Mermaid or JSON)
   - wetwire_init: Initialize new wetwire-k8s projects
 
 This is typically called by Claude Code or other MCP clients, not directly by users.`,
@@ -42,6 +53,308 @@ func runMCPServer(cmd *cobra.Command, args []string) error {
 	// Build MCP server using auto-generation from domain
 	server := domain.BuildMCPServer(k8sDomain)
 
+	// Replace the generic build tool so failures are reported as structured
+	// results
+	server.RegisterToolWithSchema("wetwire_build", "Build Kubernetes manifests from Go resource declarations",
+		buildToolHandler(k8sDomain.Builder()), mcp.BuildSchema)
+
+	// Replace the generic lint tool so it also offers the report formats
+	server.RegisterToolWithSchema("wetwire_lint", "Lint domain resources",
+		lintToolHandler(k8sDomain.Linter()), lintToolSchema())
+
+	server.RegisterToolWithSchema("wetwire_fix",
+		"Apply lint auto-fixes and return the fixes applied and the issues that remain, or with dry_run the diff of the fixes",
+		fixToolHandler(k8sDomain), fixToolSchema())
+
+	// Replace the generic list tool with one returning the resources as JSON
+	server.RegisterToolWithSchema("wetwire_list",
+		"List the Kubernetes resources defined in a package: variable name, kind, namespace, file, line and dependencies",
+		listToolHandler(k8sDomain), listToolSchema())
+
+	// Replace the generic graph tool to offer JSON graphs and external references
+	server.RegisterToolWithSchema("wetwire_graph", "Visualize resource dependencies (DOT/Mermaid/JSON)",
+		graphToolHandler(k8sDomain), graphToolSchema())
+
 	// Start stdio server
 	return server.Start(context.Background())
 }
+
+// lintToolSchema returns the core wetwire_lint schema with the format enum
+// extended to the k8s lint formats.
+func lintToolSchema() map[string]any {
+	properties := make(map[string]any)
+	for name, prop := range mcp.LintSchema["properties"].(map[string]any) {
+		properties[name] = prop
+	}
+	properties["format"] = map[string]any{
+		"type":        "string",
+		"enum":        lintFormats,
+		"description": "Output format (default: text). github, sarif and junit return the rendered report",
+	}
+
+	schema := make(map[string]any)
+	for key, value := range mcp.LintSchema {
+		schema[key] = value
+	}
+	schema["properties"] = properties
+	return schema
+}
+
+// lintToolHandler handles wetwire_lint calls. Report formats return the
+// rendered report; other formats return the result as JSON.
+func lintToolHandler(linter domain.Linter) mcp.ToolHandler {
+	return func(ctx context.Context, args map[string]any) (string, error) {
+		path, _ := args["package"].(string)
+
+		opts := domain.LintOpts{}
+		opts.Format, _ = args["format"].(string)
+		opts.Fix, _ = args["fix"].(bool)
+		if disable, ok := args["disable"].([]any); ok {
+			for _, d := range disable {
+				if s, ok := d.(string); ok {
+					opts.Disable = append(opts.Disable, s)
+				}
+			}
+		}
+
+		result, err := linter.Lint(domain.NewContext(ctx, path), path, opts)
+		if err != nil {
+			return toolFailure("wetwire_lint", fmt.Errorf("lint operation failed: %w", err))
+		}
+
+		if report, ok := result.Data.(string); ok && k8sdomain.IsLintReportFormat(opts.Format) {
+			return report, nil
+		}
+		return newToolResult(result).encode()
+	}
+}
+
+// buildToolHandler handles wetwire_build calls. On success the data holds
+// the manifests, or nothing when they were written to the output file.
+func buildToolHandler(builder domain.Builder) mcp.ToolHandler {
+	return func(ctx context.Context, args map[string]any) (string, error) {
+		path, _ := args["package"].(string)
+
+		opts := domain.BuildOpts{}
+		opts.Format, _ = args["format"].(string)
+		opts.Output, _ = args["output"].(string)
+		opts.DryRun, _ = args["dry_run"].(bool)
+
+		result, err := builder.Build(domain.NewContext(ctx, path), path, opts)
+		if err != nil {
+			return toolFailure("wetwire_build", fmt.Errorf("build operation failed: %w", err))
+		}
+		return newToolResult(result).encode()
+	}
+}
+
+// toolResult is the JSON result of the build and lint tools. Failures are
+// results rather than tool errors, so that clients can act on each error.
+type toolResult struct {
+	OK      bool        `json:"ok"`
+	Summary string      `json:"summary"` // Human-readable outcome
+	Errors  []toolError `json:"errors,omitempty"`
+	Data    any         `json:"data,omitempty"`
+}
+
+// toolError is a single error in a toolResult. Errors that do not come from
+// a source location only have a message.
+type toolError struct {
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Message  string `json:"message"`
+}
+
+// newToolResult converts a domain result to a toolResult.
+func newToolResult(result *domain.Result) toolResult {
+	tr := toolResult{
+		OK:      result.Success,
+		Summary: result.Message,
+		Data:    result.Data,
+	}
+	for _, e := range result.Errors {
+		tr.Errors = append(tr.Errors, toolError{
+			File:     e.Path,
+			Line:     e.Line,
+			Column:   e.Column,
+			Severity: e.Severity,
+			Code:     e.Code,
+			Message:  e.Message,
+		})
+	}
+	if s, ok := tr.Data.(string); ok && s == "" {
+		tr.Data = nil
+	}
+	return tr
+}
+
+// toolFailure returns a failed toolResult for an error that prevented the
+// tool from running, and logs it when WETWIRE_MCP_DEBUG is set or
+// WETWIRE_LOG_LEVEL is debug.
+func toolFailure(tool string, err error) (string, error) {
+	if os.Getenv("WETWIRE_MCP_DEBUG") != "" || envLogLevel() == logDebug {
+		fmt.Fprintf(os.Stderr, "[MCP:wetwire-k8s] %s failed: %v\n", tool, err)
+	}
+	return toolResult{
+		Summary: err.Error(),
+		Errors:  []toolError{{Message: err.Error()}},
+	}.encode()
+}
+
+// encode serializes the result as JSON.
+func (tr toolResult) encode() (string, error) {
+	data, err := json.MarshalIndent(tr, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize result: %w", err)
+	}
+	return string(data), nil
+}
+
+// graphToolSchema returns the wetwire_graph schema with the k8s graph
+// formats and options.
+func graphToolSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"package": map[string]any{
+				"type":        "string",
+				"description": "Package path to analyze",
+			},
+			"format": map[string]any{
+				"type":        "string",
+				"enum":        graphFormats,
+				"description": "Output format (default: dot)",
+			},
+			"include_external": map[string]any{
+				"type":        "boolean",
+				"description": "Show references to objects not defined in the project, such as Secrets created out-of-band",
+			},
+			"transitive": map[string]any{
+				"type":        "boolean",
+				"description": "Also show indirect dependencies, styled differently from direct ones",
+			},
+			"collapse": map[string]any{
+				"type":        "boolean",
+				"description": "Show only top-level objects, hiding helper values such as Containers",
+			},
+		},
+	}
+}
+
+// graphToolHandler handles wetwire_graph calls and returns the rendered
+// graph as-is.
+func graphToolHandler(d *k8sdomain.K8sDomain) mcp.ToolHandler {
+	return func(ctx context.Context, args map[string]any) (string, error) {
+		path, _ := args["package"].(string)
+
+		opts := k8sdomain.K8sGraphOpts{}
+		opts.Format, _ = args["format"].(string)
+		opts.IncludeExternal, _ = args["include_external"].(bool)
+		opts.Transitive, _ = args["transitive"].(bool)
+		opts.Collapse, _ = args["collapse"].(bool)
+
+		result, err := d.GraphWithOptions(domain.NewContext(ctx, path), path, opts)
+		if err != nil {
+			return "", fmt.Errorf("graph operation failed: %w", err)
+		}
+
+		graph, _ := result.Data.(string)
+		return graph, nil
+	}
+}
+
+// listToolSchema returns the wetwire_list schema with the k8s filters.
+func listToolSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"package": map[string]any{
+				"type":        "string",
+				"description": "Package path to discover from",
+			},
+			"kind": map[string]any{
+				"type":        "string",
+				"description": "List only resources of this kind, e.g. Deployment",
+			},
+			"namespace": map[string]any{
+				"type":        "string",
+				"description": "List only resources in this namespace",
+			},
+		},
+	}
+}
+
+// listToolHandler handles wetwire_list calls and returns the discovered
+// resources as a JSON array.
+func listToolHandler(d *k8sdomain.K8sDomain) mcp.ToolHandler {
+	return func(ctx context.Context, args map[string]any) (string, error) {
+		path, _ := args["package"].(string)
+
+		opts := k8sdomain.K8sListOpts{}
+		opts.Kind, _ = args["kind"].(string)
+		opts.Namespace, _ = args["namespace"].(string)
+
+		result, err := d.ListWithOptions(domain.NewContext(ctx, path), path, opts)
+		if err != nil {
+			return "", fmt.Errorf("list operation failed: %w", err)
+		}
+
+		list, _ := result.Data.([]map[string]any)
+		return k8sdomain.RenderList(list, "json")
+	}
+}
+
+// fixToolSchema returns the wetwire_fix schema.
+func fixToolSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"package": map[string]any{
+				"type":        "string",
+				"description": "File or package path to fix",
+			},
+			"dry_run": map[string]any{
+				"type":        "boolean",
+				"description": "Return a unified diff of the fixes instead of writing them",
+			},
+			"disable": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+				"description": "Rule IDs to skip, e.g. WK8105",
+			},
+		},
+	}
+}
+
+// fixToolHandler handles wetwire_fix calls and returns the fix report as
+// JSON.
+func fixToolHandler(d *k8sdomain.K8sDomain) mcp.ToolHandler {
+	return func(ctx context.Context, args map[string]any) (string, error) {
+		path, _ := args["package"].(string)
+
+		opts := k8sdomain.K8sLintOpts{}
+		opts.Fix = true
+		opts.DryRun, _ = args["dry_run"].(bool)
+		if disable, ok := args["disable"].([]any); ok {
+			for _, d := range disable {
+				if s, ok := d.(string); ok {
+					opts.Disable = append(opts.Disable, s)
+				}
+			}
+		}
+
+		report, err := d.Fix(domain.NewContext(ctx, path), path, opts)
+		if err != nil {
+			return "", fmt.Errorf("fix operation failed: %w", err)
+		}
+
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to serialize result: %w", err)
+		}
+		return string(data), nil
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mcpTestdata is a package whose Service depends on a Deployment declared
+// in another file.
+const mcpTestdata = "../../internal/discover/testdata/multifile"
+
+func TestBuildToolHandler(t *testing.T) {
+	handler := buildToolHandler((&domain.K8sDomain{}).Builder())
+
+	decode := func(out string) map[string]any {
+		t.Helper()
+		var result map[string]any
+		require.NoError(t, json.Unmarshal([]byte(out), &result))
+		return result
+	}
+
+	t.Run("should return the manifests on success", func(t *testing.T) {
+		out, err := handler(context.Background(), map[string]any{"package": mcpTestdata})
+		require.NoError(t, err)
+
+		result := decode(out)
+		assert.Equal(t, true, result["ok"])
+		assert.Equal(t, "Build completed", result["summary"])
+		assert.NotContains(t, result, "errors")
+		assert.Contains(t, result["data"], "kind: Service")
+	})
+
+	t.Run("should return structured errors for a failing build", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "empty.go"), []byte("package empty\n"), 0644))
+
+		out, err := handler(context.Background(), map[string]any{"package": dir})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"ok":      false,
+			"summary": "no resources found",
+			"errors": []any{
+				map[string]any{"file": dir, "message": "no Kubernetes resources found"},
+			},
+		}, decode(out))
+	})
+
+	t.Run("should return a failed result when the build cannot run", func(t *testing.T) {
+		out, err := handler(context.Background(), map[string]any{"package": filepath.Join(t.TempDir(), "missing")})
+		require.NoError(t, err)
+
+		result := decode(out)
+		assert.Equal(t, false, result["ok"])
+		assert.Contains(t, result["summary"], "build operation failed")
+		require.Len(t, result["errors"], 1)
+		assert.Equal(t, result["summary"], result["errors"].([]any)[0].(map[string]any)["message"])
+	})
+}
+
+func TestGraphToolHandler(t *testing.T) {
+	handler := graphToolHandler(&domain.K8sDomain{})
+
+	t.Run("should return the graph in the requested format", func(t *testing.T) {
+		out, err := handler(context.Background(), map[string]any{
+			"package": mcpTestdata,
+			"format":  "mermaid",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, out, "graph TD")
+		assert.Contains(t, out, "FrontendService --> FrontendDeployment")
+	})
+
+	t.Run("should return a JSON graph", func(t *testing.T) {
+		out, err := handler(context.Background(), map[string]any{
+			"package": mcpTestdata,
+			"format":  "json",
+		})
+		require.NoError(t, err)
+
+		var graph struct {
+			Nodes []map[string]any `json:"nodes"`
+			Edges []map[string]any `json:"edges"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &graph))
+		assert.Len(t, graph.Nodes, 2)
+		require.Len(t, graph.Edges, 1)
+		assert.Equal(t, "FrontendService", graph.Edges[0]["source"])
+		assert.Equal(t, "FrontendDeployment", graph.Edges[0]["target"])
+	})
+
+	t.Run("should reject unknown formats", func(t *testing.T) {
+		_, err := handler(context.Background(), map[string]any{
+			"package": mcpTestdata,
+			"format":  "svg",
+		})
+		assert.ErrorContains(t, err, "unknown format")
+	})
+}
+
+func TestGraphToolSchema(t *testing.T) {
+	schema := graphToolSchema()
+	properties := schema["properties"].(map[string]any)
+	assert.Equal(t, graphFormats, properties["format"].(map[string]any)["enum"])
+	assert.Contains(t, properties, "package")
+	assert.Contains(t, properties, "include_external")
+	assert.Contains(t, properties, "transitive")
+	assert.Contains(t, properties, "collapse")
+}
+
+func TestListToolHandler(t *testing.T) {
+	handler := listToolHandler(&domain.K8sDomain{})
+
+	list := func(args map[string]any) []map[string]any {
+		t.Helper()
+		out, err := handler(context.Background(), args)
+		require.NoError(t, err)
+		var items []map[string]any
+		require.NoError(t, json.Unmarshal([]byte(out), &items))
+		return items
+	}
+
+	t.Run("should return the discovered resources", func(t *testing.T) {
+		items := list(map[string]any{"package": mcpTestdata})
+		require.Len(t, items, 2)
+
+		service := items[1]
+		assert.Equal(t, "FrontendService", service["name"])
+		assert.Equal(t, "Service", service["kind"])
+		assert.Equal(t, "frontend", service["namespace"])
+		assert.Contains(t, service["file"], "service.go")
+		assert.Equal(t, float64(9), service["line"])
+		assert.Equal(t, []any{"FrontendDeployment"}, service["dependencies"])
+	})
+
+	t.Run("should filter by kind", func(t *testing.T) {
+		items := list(map[string]any{"package": mcpTestdata, "kind": "Deployment"})
+		require.Len(t, items, 1)
+		assert.Equal(t, "FrontendDeployment", items[0]["name"])
+	})
+
+	t.Run("should return an empty array when nothing matches", func(t *testing.T) {
+		assert.Empty(t, list(map[string]any{"package": mcpTestdata, "kind": "Secret"}))
+	})
+}
+
+func TestFixToolHandler(t *testing.T) {
+	content := `package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+var MyContainer = corev1.Container{
+	Name:  "app",
+	Image: "nginx:1.21",
+}
+`
+	handler := fixToolHandler(&domain.K8sDomain{})
+
+	fix := func(args map[string]any) domain.FixReport {
+		t.Helper()
+		out, err := handler(context.Background(), args)
+		require.NoError(t, err)
+		var report domain.FixReport
+		require.NoError(t, json.Unmarshal([]byte(out), &report))
+		return report
+	}
+
+	rules := func(report domain.FixReport) []string {
+		var rules []string
+		for _, f := range report.Fixes {
+			rules = append(rules, f.Rule)
+		}
+		return rules
+	}
+
+	t.Run("should return the diff without writing in dry-run mode", func(t *testing.T) {
+		testFile := filepath.Join(t.TempDir(), "app.go")
+		require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+		report := fix(map[string]any{"package": testFile, "dry_run": true})
+		assert.Equal(t, []string{"WK8105", "WK8201"}, rules(report))
+		assert.Contains(t, report.Diff, "ImagePullPolicy")
+
+		unchanged, err := os.ReadFile(testFile)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(unchanged))
+	})
+
+	t.Run("should apply the fixes and report remaining issues", func(t *testing.T) {
+		testFile := filepath.Join(t.TempDir(), "app.go")
+		require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+		report := fix(map[string]any{"package": testFile})
+		assert.Equal(t, []string{"WK8105", "WK8201"}, rules(report))
+		assert.Empty(t, report.Diff)
+		for _, issue := range report.Issues {
+			assert.NotContains(t, []string{"WK8105", "WK8201"}, issue.Code)
+		}
+
+		fixed, err := os.ReadFile(testFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(fixed), "ImagePullPolicy")
+	})
+
+	t.Run("should skip disabled rules", func(t *testing.T) {
+		testFile := filepath.Join(t.TempDir(), "app.go")
+		require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+		report := fix(map[string]any{"package": testFile, "disable": []any{"WK8105"}})
+		assert.Equal(t, []string{"WK8201"}, rules(report))
+	})
+}
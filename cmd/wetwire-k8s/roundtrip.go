@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lex00/wetwire-k8s-go/internal/roundtrip"
+	"github.com/spf13/cobra"
+)
+
+// newRoundtripCmd creates the roundtrip subcommand
+func newRoundtripCmd() *cobra.Command {
+	var showGo bool
+
+	cmd := &cobra.Command{
+		Use:   "roundtrip <file>",
+		Short: "Check that manifests survive import and build unchanged",
+		Long: `Roundtrip imports YAML manifests to Go, compiles and runs the generated
+code, builds it back to YAML and compares the result with the original.
+Fields lost or changed in translation are reported, and the command fails
+if there are any.
+
+The go command is required to run the generated code.
+
+Use '-' as the file path to read from stdin.
+
+Examples:
+  wetwire-k8s roundtrip deployment.yaml           # Report lost fields
+  wetwire-k8s roundtrip --show-go deployment.yaml # Also print the generated Go`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var data []byte
+			var err error
+			if args[0] == "-" {
+				data, err = io.ReadAll(os.Stdin)
+				if err != nil {
+					return fmt.Errorf("failed to read stdin: %w", err)
+				}
+			} else {
+				data, err = os.ReadFile(args[0])
+				if err != nil {
+					return fmt.Errorf("failed to read file: %w", err)
+				}
+			}
+
+			result, err := roundtrip.RoundTrip(data, roundtrip.DefaultOptions())
+			if err != nil {
+				return fmt.Errorf("round trip failed: %w", err)
+			}
+
+			for _, warn := range result.Warnings {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s\n", warn)
+			}
+
+			out := cmd.OutOrStdout()
+			if showGo {
+				fmt.Fprintln(out, result.GoCode)
+			}
+			if result.Equivalent {
+				fmt.Fprintln(out, "Round trip preserved all fields")
+				return nil
+			}
+			fmt.Fprint(out, roundtrip.DiffSummary(result.Differences))
+			return fmt.Errorf("round trip changed %d field(s)", len(result.Differences))
+		},
+	}
+
+	cmd.Flags().BoolVar(&showGo, "show-go", false, "Print the generated Go code")
+
+	return cmd
+}
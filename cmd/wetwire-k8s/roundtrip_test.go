@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundtripCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping round-trip test in short mode")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+
+	t.Run("preserved", func(t *testing.T) {
+		stdout, _, err := runTestCommand([]string{"roundtrip", filepath.Join("..", "..", "testdata", "roundtrip", "examples", "service.yaml")})
+		require.NoError(t, err)
+		assert.Contains(t, stdout.String(), "Round trip preserved all fields")
+	})
+
+	t.Run("lost fields", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "policy.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: runner
+automountServiceAccountToken: false
+`), 0644))
+
+		stdout, _, err := runTestCommand([]string{"roundtrip", path})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "round trip changed 1 field(s)")
+		assert.Contains(t, stdout.String(), "MISSING: doc[0].automountServiceAccountToken")
+	})
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lex00/wetwire-k8s-go/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+// newRulesCmd creates the rules subcommand
+func newRulesCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "List all lint rules",
+		Long: `Rules prints the catalog of registered lint rules with their ID, name,
+description, severity, category and whether an auto-fix is available.
+
+Examples:
+  wetwire-k8s rules                   # Markdown table
+  wetwire-k8s rules --format json     # Machine-readable JSON`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			infos := lint.Catalog(lint.AllRules())
+
+			switch format {
+			case "json":
+				return lint.WriteCatalogJSON(cmd.OutOrStdout(), infos)
+			case "markdown", "md":
+				return lint.WriteCatalogMarkdown(cmd.OutOrStdout(), infos)
+			default:
+				return fmt.Errorf("unsupported format: %s (supported: markdown, json)", format)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "markdown", "Output format (markdown, json)")
+
+	return cmd
+}
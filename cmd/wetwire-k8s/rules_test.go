@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/internal/lint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesCommand_JSON(t *testing.T) {
+	stdout, _, err := runTestCommand([]string{"rules", "--format", "json"})
+	require.NoError(t, err)
+
+	var infos []lint.RuleInfo
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &infos))
+
+	byID := make(map[string]lint.RuleInfo)
+	for _, info := range infos {
+		byID[info.ID] = info
+	}
+	for _, rule := range lint.AllRules() {
+		assert.Contains(t, byID, rule.ID)
+	}
+	assert.True(t, byID["WK8105"].Fixable, "WK8105 should have a fixer")
+	assert.False(t, byID["WK8006"].Fixable, "WK8006 should not have a fixer")
+}
+
+func TestRulesCommand_Markdown(t *testing.T) {
+	stdout, _, err := runTestCommand([]string{"rules"})
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), "| Rule | Name | Description | Severity | Category | Auto-fix |")
+	assert.Contains(t, stdout.String(), "| WK8001 |")
+}
+
+func TestRulesCommand_UnknownFormat(t *testing.T) {
+	_, _, err := runTestCommand([]string{"rules", "--format", "xml"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported format")
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/lex00/wetwire-core-go/agent/orchestrator"
 	"github.com/lex00/wetwire-core-go/agent/personas"
 	"github.com/lex00/wetwire-core-go/agent/results"
-	"github.com/lex00/wetwire-k8s-go/internal/kiro"
 	"github.com/spf13/cobra"
 )
 
@@ -42,22 +41,27 @@ Available personas:
 Example:
   wetwire-k8s test --persona beginner --prompt "Create a deployment with 3 replicas"
   wetwire-k8s test --all-personas --prompt "Create an nginx deployment"
-  wetwire-k8s test --provider kiro --prompt "Create nginx deployment"`,
+  wetwire-k8s test --provider kiro --prompt "Create nginx deployment"
+
+Providers:
+  core       Claude CLI if installed, otherwise the Anthropic API (default)
+  anthropic  Anthropic API, requires ANTHROPIC_API_KEY
+  kiro       Kiro CLI (kiro-cli), driven by the same agent loop`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if prompt == "" {
 				return fmt.Errorf("--prompt flag is required")
 			}
 
-			// Handle kiro provider
-			if provider == "kiro" {
-				return runTestKiro(prompt)
+			// Reject unknown providers before running any persona
+			if _, err := newDesignProvider(provider, outputDir); err != nil {
+				return err
 			}
 
 			if allPersonas {
-				return runTestAllPersonas(prompt, outputDir, scenario, maxLintCycles, stream)
+				return runTestAllPersonas(prompt, outputDir, scenario, provider, maxLintCycles, stream)
 			}
 
-			return runTestSinglePersona(prompt, outputDir, persona, scenario, maxLintCycles, stream)
+			return runTestSinglePersona(prompt, outputDir, persona, scenario, provider, maxLintCycles, stream)
 		},
 	}
 
@@ -68,14 +72,14 @@ Example:
 	cmd.Flags().StringVar(&scenario, "scenario", "default", "Scenario name for tracking")
 	cmd.Flags().IntVar(&maxLintCycles, "max-lint-cycles", 3, "Maximum lint/fix cycles")
 	cmd.Flags().BoolVar(&stream, "stream", false, "Stream AI responses")
-	cmd.Flags().StringVar(&provider, "provider", "core", "AI provider to use (core, kiro)")
+	cmd.Flags().StringVar(&provider, "provider", "core", "AI provider to use (core, anthropic, kiro)")
 	_ = cmd.MarkFlagRequired("prompt")
 
 	return cmd
 }
 
 // runTestAllPersonas runs the test with all available personas.
-func runTestAllPersonas(prompt, outputDir, scenario string, maxLintCycles int, stream bool) error {
+func runTestAllPersonas(prompt, outputDir, scenario, provider string, maxLintCycles int, stream bool) error {
 	personaNames := personas.Names()
 	var failed []string
 
@@ -87,7 +91,7 @@ func runTestAllPersonas(prompt, outputDir, scenario string, maxLintCycles int, s
 
 		fmt.Printf("=== Running persona: %s ===\n", personaName)
 
-		err := runTestSinglePersona(prompt, personaOutputDir, personaName, scenario, maxLintCycles, stream)
+		err := runTestSinglePersona(prompt, personaOutputDir, personaName, scenario, provider, maxLintCycles, stream)
 		if err != nil {
 			fmt.Printf("Persona %s: FAILED - %v\n\n", personaName, err)
 			failed = append(failed, personaName)
@@ -110,7 +114,7 @@ func runTestAllPersonas(prompt, outputDir, scenario string, maxLintCycles int, s
 }
 
 // runTestSinglePersona runs a test with a single persona.
-func runTestSinglePersona(prompt, outputDir, personaName, scenario string, maxLintCycles int, stream bool) error {
+func runTestSinglePersona(prompt, outputDir, personaName, scenario, provider string, maxLintCycles int, stream bool) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -129,6 +133,11 @@ func runTestSinglePersona(prompt, outputDir, personaName, scenario string, maxLi
 		return fmt.Errorf("invalid persona: %w", err)
 	}
 
+	aiProvider, err := newDesignProvider(provider, outputDir)
+	if err != nil {
+		return err
+	}
+
 	// Create session for tracking
 	session := results.NewSession(personaName, scenario)
 
@@ -147,6 +156,7 @@ func runTestSinglePersona(prompt, outputDir, personaName, scenario string, maxLi
 	// Create runner agent with K8s domain config
 	runner, err := agents.NewRunnerAgent(agents.RunnerConfig{
 		Domain:        K8sDomain(),
+		Provider:      aiProvider,
 		WorkDir:       outputDir,
 		MaxLintCycles: maxLintCycles,
 		Session:       session,
@@ -190,14 +200,3 @@ func runTestSinglePersona(prompt, outputDir, personaName, scenario string, maxLi
 
 	return nil
 }
-
-// runTestKiro runs the test command using the Kiro provider.
-func runTestKiro(prompt string) error {
-	ctx := context.Background()
-	output, err := kiro.RunTest(ctx, prompt)
-	if err != nil {
-		return fmt.Errorf("kiro test failed: %w", err)
-	}
-	fmt.Println(output)
-	return nil
-}
@@ -19,3 +19,8 @@ func TestTestCommand_MissingPrompt(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "prompt")
 }
+
+func TestTestCommand_UnknownProvider(t *testing.T) {
+	_, _, err := runTestCommand([]string{"test", "--prompt", "Create nginx", "--provider", "openai"})
+	assert.ErrorContains(t, err, `unknown provider "openai"`)
+}
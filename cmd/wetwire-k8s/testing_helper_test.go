@@ -17,10 +17,14 @@ func newTestRootCmd() *cobra.Command {
 
 	rootCmd.AddCommand(
 		newImportCmd(),
+		newRoundtripCmd(),
 		newDiffCmd(),
 		newWatchCmd(),
+		newFmtCmd(),
 		newTestCmd(),
 		newDesignCmd(),
+		newRulesCmd(),
+		newExplainCmd(),
 	)
 
 	return rootCmd
@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -71,67 +73,21 @@ Examples:
 
 // runWatchLoop runs the main watch loop
 func runWatchLoop(sourcePath, outputPath string, interval time.Duration, writer io.Writer) error {
-	// Create file watcher
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		return fmt.Errorf("failed to create file watcher: %w", err)
-	}
-	defer watcher.Close()
-
-	// Add source path to watcher
-	if err := addWatchPath(watcher, sourcePath); err != nil {
-		return fmt.Errorf("failed to add watch path: %w", err)
-	}
-
-	// Perform initial build
 	fmt.Fprintln(writer, "Starting watch mode...")
-	if err := performBuild(sourcePath, outputPath, writer); err != nil {
-		fmt.Fprintf(writer, "Initial build failed: %v\n", err)
-	}
-
-	// Debounce timer
-	var debounceTimer *time.Timer
-	var debounceMutex sync.Mutex
-
-	for {
-		select {
-		case event, ok := <-watcher.Events:
-			if !ok {
-				return nil
-			}
-
-			// Only react to Go file changes
-			if !isGoFile(event.Name) {
-				continue
-			}
-
-			// Check for write or create events
-			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
-				continue
-			}
-
-			// Debounce: reset timer on each event
-			debounceMutex.Lock()
-			if debounceTimer != nil {
-				debounceTimer.Stop()
-			}
-			debounceTimer = time.AfterFunc(interval, func() {
-				fmt.Fprintf(writer, "\nFile changed: %s\n", filepath.Base(event.Name))
-				if err := performBuild(sourcePath, outputPath, writer); err != nil {
-					fmt.Fprintf(writer, "Build failed: %v\n", err)
-				} else {
-					fmt.Fprintln(writer, "Build complete")
-				}
-			})
-			debounceMutex.Unlock()
-
-		case err, ok := <-watcher.Errors:
-			if !ok {
-				return nil
+	return watchGoFiles(context.Background(), sourcePath, interval, writer, func(changed string) {
+		if changed == "" {
+			if err := performBuild(sourcePath, outputPath, writer); err != nil {
+				fmt.Fprintf(writer, "Initial build failed: %v\n", err)
 			}
-			fmt.Fprintf(writer, "Watcher error: %v\n", err)
+			return
 		}
-	}
+		fmt.Fprintf(writer, "\nFile changed: %s\n", filepath.Base(changed))
+		if err := performBuild(sourcePath, outputPath, writer); err != nil {
+			fmt.Fprintf(writer, "Build failed: %v\n", err)
+		} else {
+			fmt.Fprintln(writer, "Build complete")
+		}
+	})
 }
 
 // runWatchWithContext runs watch with a context for cancellation (used in tests)
@@ -147,6 +103,26 @@ func runWatchWithContextAndCallback(ctx context.Context, sourcePath, outputPath
 		return fmt.Errorf("failed to resolve path: %w", err)
 	}
 
+	return watchGoFiles(ctx, absPath, interval, writer, func(changed string) {
+		if err := performBuild(absPath, outputPath, writer); err != nil {
+			if changed == "" {
+				fmt.Fprintf(writer, "Initial build failed: %v\n", err)
+			} else {
+				fmt.Fprintf(writer, "Build failed: %v\n", err)
+			}
+		}
+		if onBuild != nil {
+			onBuild()
+		}
+	})
+}
+
+// watchGoFiles calls run once with an empty name and then again, with the
+// name of the changed file, whenever a .go file under path is written or
+// created. Runs are debounced by interval so that a burst of writes triggers a
+// single run, and never overlap. If path is a file, only that file is watched.
+// watchGoFiles blocks until ctx is done and then returns ctx.Err().
+func watchGoFiles(ctx context.Context, path string, interval time.Duration, writer io.Writer, run func(changed string)) error {
 	// Create file watcher
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -154,19 +130,29 @@ func runWatchWithContextAndCallback(ctx context.Context, sourcePath, outputPath
 	}
 	defer watcher.Close()
 
-	// Add source path to watcher
-	if err := addWatchPath(watcher, absPath); err != nil {
+	// Watch the directory of a single file, since editors often replace the
+	// file rather than writing to it
+	onlyFile := ""
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		onlyFile = filepath.Clean(path)
+		err = watcher.Add(filepath.Dir(path))
+		if err != nil {
+			return fmt.Errorf("failed to add watch path: %w", err)
+		}
+	} else if err := addWatchPath(watcher, path); err != nil {
 		return fmt.Errorf("failed to add watch path: %w", err)
 	}
 
-	// Perform initial build
-	if err := performBuild(absPath, outputPath, writer); err != nil {
-		fmt.Fprintf(writer, "Initial build failed: %v\n", err)
-	}
-	if onBuild != nil {
-		onBuild()
+	// Serialize runs so a slow run is not overlapped by the next one
+	var runMutex sync.Mutex
+	runOnce := func(changed string) {
+		runMutex.Lock()
+		defer runMutex.Unlock()
+		run(changed)
 	}
 
+	runOnce("")
+
 	// Debounce timer
 	var debounceTimer *time.Timer
 	var debounceMutex sync.Mutex
@@ -190,6 +176,9 @@ func runWatchWithContextAndCallback(ctx context.Context, sourcePath, outputPath
 			if !isGoFile(event.Name) {
 				continue
 			}
+			if onlyFile != "" && filepath.Clean(event.Name) != onlyFile {
+				continue
+			}
 
 			// Check for write or create events
 			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
@@ -201,13 +190,9 @@ func runWatchWithContextAndCallback(ctx context.Context, sourcePath, outputPath
 			if debounceTimer != nil {
 				debounceTimer.Stop()
 			}
+			changed := event.Name
 			debounceTimer = time.AfterFunc(interval, func() {
-				if err := performBuild(absPath, outputPath, writer); err != nil {
-					fmt.Fprintf(writer, "Build failed: %v\n", err)
-				}
-				if onBuild != nil {
-					onBuild()
-				}
+				runOnce(changed)
 			})
 			debounceMutex.Unlock()
 
@@ -220,6 +205,47 @@ func runWatchWithContextAndCallback(ctx context.Context, sourcePath, outputPath
 	}
 }
 
+// watchDebounce is how long --watch waits after the last change before it
+// runs the command again.
+var watchDebounce = 300 * time.Millisecond
+
+// errOperationFailed is returned by commands that have already printed a
+// failed result, so there is nothing more to report.
+var errOperationFailed = errors.New("operation failed")
+
+// runWatched implements --watch for the build and lint commands: it calls run
+// once and again after every change to a .go file under path, until the
+// command's context is done or the process is interrupted. A failing run is
+// reported and the watch continues.
+func runWatched(cmd *cobra.Command, path string, run func() error) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); err != nil {
+		return fmt.Errorf("source path does not exist: %s", absPath)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	writer := cmd.OutOrStdout()
+	log := newLogger(cmd, writer)
+	log.Infof("Watching %s for changes (Ctrl+C to stop)", path)
+	err = watchGoFiles(ctx, absPath, watchDebounce, writer, func(changed string) {
+		if changed != "" {
+			log.Infof("\nFile changed: %s", filepath.Base(changed))
+		}
+		if err := run(); err != nil && !errors.Is(err, errOperationFailed) {
+			log.Errorf("Error: %v", err)
+		}
+	})
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return nil
+	}
+	return err
+}
+
 // addWatchPath adds a path (and subdirectories) to the watcher
 func addWatchPath(watcher *fsnotify.Watcher, path string) error {
 	return filepath.Walk(path, func(walkPath string, info os.FileInfo, err error) error {
@@ -1,13 +1,17 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/lex00/wetwire-k8s-go/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -60,3 +64,114 @@ type discardWriter struct{}
 func (d *discardWriter) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
+
+// syncBuffer is a bytes.Buffer that can be written by the watch goroutine
+// while the test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+const watchConfigMapSource = `package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var %s = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: %q},
+	Data:       map[string]string{"key": "value"},
+}
+`
+
+// startWatchCommand runs the domain command with --watch in the background
+// and returns its output and a function that stops it and returns its error.
+func startWatchCommand(t *testing.T, args []string) (*syncBuffer, func() error) {
+	t.Helper()
+
+	prev := watchDebounce
+	watchDebounce = 50 * time.Millisecond
+	t.Cleanup(func() { watchDebounce = prev })
+
+	out := &syncBuffer{}
+	d := &domain.K8sDomain{}
+	rootCmd := domain.CreateRootCommand(d)
+	configureBuildCmd(rootCmd, d)
+	configureLintCmd(rootCmd, d)
+	rootCmd.SetOut(out)
+	rootCmd.SetErr(out)
+	rootCmd.SetArgs(append(args, "--watch"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- rootCmd.ExecuteContext(ctx) }()
+
+	return out, func() error {
+		cancel()
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("watch did not stop after cancel")
+			return nil
+		}
+	}
+}
+
+func TestBuildCommand_WatchRebuildsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.go")
+	require.NoError(t, os.WriteFile(file, []byte(fmt.Sprintf(watchConfigMapSource, "AppConfig", "app-config")), 0644))
+
+	out, stop := startWatchCommand(t, []string{"build", dir})
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "name: app-config")
+	}, 5*time.Second, 20*time.Millisecond, "initial build should run")
+
+	require.NoError(t, os.WriteFile(file, []byte(fmt.Sprintf(watchConfigMapSource, "CacheConfig", "cache-config")), 0644))
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "name: cache-config")
+	}, 5*time.Second, 20*time.Millisecond, "a change should trigger a rebuild")
+
+	assert.NoError(t, stop())
+	assert.Contains(t, out.String(), "File changed: config.go")
+}
+
+func TestLintCommand_WatchRelintsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.go")
+	require.NoError(t, os.WriteFile(file, []byte(fmt.Sprintf(watchConfigMapSource, "AppConfig", "settings")), 0644))
+
+	out, stop := startWatchCommand(t, []string{"lint", dir})
+
+	// The metadata name does not match the variable name (WK8007)
+	require.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "0 errors, 2 warnings, 1 info across 1 file")
+	}, 5*time.Second, 20*time.Millisecond, "initial lint should run")
+
+	require.NoError(t, os.WriteFile(file, []byte(fmt.Sprintf(watchConfigMapSource, "AppConfig", "app-config")), 0644))
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "0 errors, 1 warning, 1 info across 1 file")
+	}, 5*time.Second, 20*time.Millisecond, "a change should trigger another lint run")
+
+	// Lint failures are reported by the result, not as command errors
+	assert.NoError(t, stop())
+	assert.Contains(t, out.String(), "File changed: config.go")
+	assert.NotContains(t, out.String(), "Error: operation failed")
+}
@@ -217,7 +217,10 @@ func compareResources(resources1, resources2 []resource, opts domain.DiffOpts) *
 	}
 }
 
-// compareSpecs compares two spec maps and returns a list of changes.
+// compareSpecs returns the changes from v1 to v2, one per field: a value
+// present on one side only is reported field by field, with its value, rather
+// than as a whole subtree. Map keys are compared in sorted order so that the
+// changes are reported in a stable order.
 func compareSpecs(v1, v2 interface{}, path string, opts domain.DiffOpts) []string {
 	var changes []string
 
@@ -226,16 +229,10 @@ func compareSpecs(v1, v2 interface{}, path string, opts domain.DiffOpts) []strin
 		return nil
 	}
 	if v1 == nil {
-		if path != "" {
-			return []string{fmt.Sprintf("%s: added", path)}
-		}
-		return nil
+		return fieldChanges(v2, path, "added")
 	}
 	if v2 == nil {
-		if path != "" {
-			return []string{fmt.Sprintf("%s: removed", path)}
-		}
-		return nil
+		return fieldChanges(v1, path, "removed")
 	}
 
 	// Type mismatch
@@ -249,21 +246,8 @@ func compareSpecs(v1, v2 interface{}, path string, opts domain.DiffOpts) []strin
 	switch val1 := v1.(type) {
 	case map[string]interface{}:
 		val2 := v2.(map[string]interface{})
-		// Check for keys in v1 not in v2
-		for k := range val1 {
-			if _, exists := val2[k]; !exists {
-				subPath := joinPath(path, k)
-				changes = append(changes, fmt.Sprintf("%s: removed", subPath))
-			}
-		}
-		// Check for keys in v2 not in v1, or changed
-		for k, v2Val := range val2 {
-			subPath := joinPath(path, k)
-			if v1Val, exists := val1[k]; !exists {
-				changes = append(changes, fmt.Sprintf("%s: added", subPath))
-			} else {
-				changes = append(changes, compareSpecs(v1Val, v2Val, subPath, opts)...)
-			}
+		for _, k := range unionKeys(val1, val2) {
+			changes = append(changes, compareSpecs(val1[k], val2[k], joinPath(path, k), opts)...)
 		}
 
 	case []interface{}:
@@ -274,12 +258,16 @@ func compareSpecs(v1, v2 interface{}, path string, opts domain.DiffOpts) []strin
 				changes = append(changes, fmt.Sprintf("%s: changed", path))
 			}
 		} else {
-			// Compare element by element
-			if len(val1) != len(val2) {
-				changes = append(changes, fmt.Sprintf("%s: length changed (%d -> %d)", path, len(val1), len(val2)))
-			} else {
-				for i := range val1 {
-					subPath := fmt.Sprintf("%s[%d]", path, i)
+			// Compare element by element, then report the extra elements
+			// of the longer list
+			for i := 0; i < len(val1) || i < len(val2); i++ {
+				subPath := fmt.Sprintf("%s[%d]", path, i)
+				switch {
+				case i >= len(val1):
+					changes = append(changes, fieldChanges(val2[i], subPath, "added")...)
+				case i >= len(val2):
+					changes = append(changes, fieldChanges(val1[i], subPath, "removed")...)
+				default:
 					changes = append(changes, compareSpecs(val1[i], val2[i], subPath, opts)...)
 				}
 			}
@@ -297,6 +285,54 @@ func compareSpecs(v1, v2 interface{}, path string, opts domain.DiffOpts) []strin
 	return changes
 }
 
+// fieldChanges reports each field of a value present on one side only as
+// "path: added (value)" or "path: removed (value)". Empty maps and lists are
+// reported as a single field.
+func fieldChanges(v interface{}, path, action string) []string {
+	value := v
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 0 {
+			value = "{}"
+			break
+		}
+		var changes []string
+		for _, k := range unionKeys(val, nil) {
+			changes = append(changes, fieldChanges(val[k], joinPath(path, k), action)...)
+		}
+		return changes
+	case []interface{}:
+		if len(val) == 0 {
+			value = "[]"
+			break
+		}
+		var changes []string
+		for i, elem := range val {
+			changes = append(changes, fieldChanges(elem, fmt.Sprintf("%s[%d]", path, i), action)...)
+		}
+		return changes
+	}
+	if path == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s: %s (%v)", path, action, value)}
+}
+
+// unionKeys returns the keys of both maps, sorted.
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // joinPath joins path components.
 func joinPath(base, key string) string {
 	if base == "" {
@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lex00/wetwire-k8s-go/differ"
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/lex00/wetwire-k8s-go/internal/roundtrip"
+	"github.com/lex00/wetwire-k8s-go/internal/serialize"
+	"gopkg.in/yaml.v3"
+)
+
+// K8sDiffOpts configures comparing built manifests against a target.
+type K8sDiffOpts struct {
+	DiffOpts
+
+	// Against is a YAML manifest file or a directory of manifests.
+	Against string
+
+	// Live compares against the objects in the cluster instead of Against.
+	Live bool
+
+	// Kubeconfig overrides the kubeconfig used for Live comparisons.
+	Kubeconfig string
+}
+
+// kubectlGet fetches the live objects matching the given manifests.
+// It is a variable so tests can replace the cluster lookup.
+var kubectlGet = func(manifests []byte, kubeconfig string) ([]byte, error) {
+	args := []string{"get", "-f", "-", "-o", "yaml", "--ignore-not-found"}
+	if kubeconfig != "" {
+		args = append(args, "--kubeconfig", kubeconfig)
+	}
+
+	cmd := exec.Command("kubectl", args...)
+	cmd.Stdin = bytes.NewReader(manifests)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("kubectl get: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// DiffAgainst builds the manifests at path and compares them with a target:
+// a YAML file or directory, or the live cluster when opts.Live is set. Both
+// sides are normalized so that server-populated fields and zero values do
+// not show up as changes. Added entries exist only in the built output,
+// removed entries exist only in the target.
+func (d *K8sDomain) DiffAgainst(ctx *Context, path string, opts K8sDiffOpts) (*DiffResult, error) {
+	if opts.Live == (opts.Against != "") {
+		return nil, errors.New("exactly one of a target manifest or a live cluster comparison is required")
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolve path: %w", err)
+	}
+
+	result, err := build.Build(absPath, build.Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	manifests, err := resourceManifests(result.OrderedResources, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("evaluation failed: %w", err)
+	}
+	generated, err := serializeToYAML(serializedManifests(manifests), serialize.YAMLOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("serialization failed: %w", err)
+	}
+
+	var target []byte
+	if opts.Live {
+		kubeconfig, err := resolveKubeconfig(opts.Kubeconfig)
+		if err != nil {
+			return nil, err
+		}
+		target, err = readLiveObjects(generated, kubeconfig)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		target, err = readManifests(opts.Against)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	normalizedTarget, err := normalizeManifests(target)
+	if err != nil {
+		return nil, fmt.Errorf("parse target manifests: %w", err)
+	}
+	normalizedGenerated, err := normalizeManifests(generated)
+	if err != nil {
+		return nil, fmt.Errorf("parse generated manifests: %w", err)
+	}
+
+	return differ.Compare(normalizedTarget, normalizedGenerated, opts.DiffOpts)
+}
+
+// readManifests reads a manifest file, or all .yaml/.yml files in a
+// directory in lexical order, as one multi-document stream.
+func readManifests(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read target %q: %w", path, err)
+	}
+	if !info.IsDir() {
+		return os.ReadFile(path)
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := filepath.Ext(p)
+		if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read target %q: %w", path, err)
+	}
+	sort.Strings(files)
+
+	var docs [][]byte
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read target %q: %w", f, err)
+		}
+		docs = append(docs, bytes.TrimSpace(data))
+	}
+	return bytes.Join(docs, []byte("\n---\n")), nil
+}
+
+// resolveKubeconfig returns the kubeconfig to use for live comparisons, or
+// an error when none is available.
+func resolveKubeconfig(explicit string) (string, error) {
+	if explicit != "" {
+		if _, err := os.Stat(explicit); err != nil {
+			return "", fmt.Errorf("kubeconfig %q not found", explicit)
+		}
+		return explicit, nil
+	}
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		return "", nil
+	}
+	home, err := os.UserHomeDir()
+	if err == nil {
+		if _, err := os.Stat(filepath.Join(home, ".kube", "config")); err == nil {
+			return "", nil
+		}
+	}
+	return "", errors.New("no kubeconfig found: set KUBECONFIG or pass --kubeconfig")
+}
+
+// readLiveObjects fetches the live counterparts of the generated manifests
+// as a multi-document stream. Objects missing from the cluster are omitted.
+func readLiveObjects(generated []byte, kubeconfig string) ([]byte, error) {
+	out, err := kubectlGet(generated, kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := roundtrip.ParseMultiDocYAML(out)
+	if err != nil {
+		return nil, fmt.Errorf("parse live objects: %w", err)
+	}
+
+	// kubectl returns a List when more than one object matches
+	var objects []interface{}
+	for _, doc := range docs {
+		if items, ok := doc["items"].([]interface{}); ok && doc["kind"] == "List" {
+			objects = append(objects, items...)
+			continue
+		}
+		objects = append(objects, doc)
+	}
+
+	var buf bytes.Buffer
+	for i, obj := range objects {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("marshal live object: %w", err)
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// normalizeManifests normalizes each document with serialize.Normalize and
+// returns them as a multi-document stream.
+func normalizeManifests(data []byte) ([]byte, error) {
+	docs, err := roundtrip.ParseMultiDocYAML(data)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := make([]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		manifests = append(manifests, serialize.Normalize(doc))
+	}
+	return serialize.ToMultiYAML(manifests)
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const diffTestSource = `package testdata
+
+import corev1 "k8s.io/api/core/v1"
+
+var AppConfig = corev1.ConfigMap{}
+`
+
+func writeDiffSource(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.go"), []byte(diffTestSource), 0644))
+	return dir
+}
+
+func TestK8sDomain_DiffAgainst_File(t *testing.T) {
+	src := writeDiffSource(t)
+	target := filepath.Join(t.TempDir(), "manifest.yaml")
+	require.NoError(t, os.WriteFile(target, []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  resourceVersion: "42"
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: "{}"
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: stale
+`), 0644))
+
+	d := &K8sDomain{}
+	result, err := d.DiffAgainst(&Context{}, src, K8sDiffOpts{Against: target})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Summary.Removed)
+	assert.Equal(t, 0, result.Summary.Added)
+	assert.Equal(t, 0, result.Summary.Modified)
+	require.Len(t, result.Entries, 1)
+	assert.Equal(t, "Secret/stale", result.Entries[0].Resource)
+}
+
+func TestK8sDomain_DiffAgainst_Directory(t *testing.T) {
+	src := writeDiffSource(t)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not yaml"), 0644))
+
+	d := &K8sDomain{}
+	result, err := d.DiffAgainst(&Context{}, src, K8sDiffOpts{Against: dir})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Summary.Added)
+	assert.Equal(t, "ConfigMap/app-config", result.Entries[0].Resource)
+}
+
+func TestK8sDomain_DiffAgainst_Live(t *testing.T) {
+	src := writeDiffSource(t)
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte("apiVersion: v1\nkind: Config\n"), 0644))
+
+	orig := kubectlGet
+	defer func() { kubectlGet = orig }()
+
+	var gotKubeconfig string
+	kubectlGet = func(manifests []byte, cfg string) ([]byte, error) {
+		gotKubeconfig = cfg
+		assert.Contains(t, string(manifests), "kind: ConfigMap")
+		return []byte(`apiVersion: v1
+kind: List
+items:
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: app-config
+      uid: abc
+    data:
+      key: value
+`), nil
+	}
+
+	d := &K8sDomain{}
+	result, err := d.DiffAgainst(&Context{}, src, K8sDiffOpts{Live: true, Kubeconfig: kubeconfig})
+	require.NoError(t, err)
+
+	assert.Equal(t, kubeconfig, gotKubeconfig)
+	assert.Equal(t, 1, result.Summary.Modified)
+	require.Len(t, result.Entries, 1)
+	assert.Equal(t, "ConfigMap/app-config", result.Entries[0].Resource)
+}
+
+func TestK8sDomain_DiffAgainst_RequiresOneTarget(t *testing.T) {
+	src := writeDiffSource(t)
+	d := &K8sDomain{}
+
+	_, err := d.DiffAgainst(&Context{}, src, K8sDiffOpts{})
+	assert.Error(t, err)
+
+	_, err = d.DiffAgainst(&Context{}, src, K8sDiffOpts{Against: "x.yaml", Live: true})
+	assert.Error(t, err)
+}
+
+func TestK8sDomain_DiffAgainst_MissingKubeconfig(t *testing.T) {
+	src := writeDiffSource(t)
+	d := &K8sDomain{}
+
+	_, err := d.DiffAgainst(&Context{}, src, K8sDiffOpts{Live: true, Kubeconfig: "/nonexistent/config"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "kubeconfig")
+}
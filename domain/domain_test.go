@@ -1,13 +1,18 @@
 package domain
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	coredomain "github.com/lex00/wetwire-core-go/domain"
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 // Compile-time interface checks
@@ -90,24 +95,75 @@ var ContainerMissingPolicy = corev1.Container{
 	})
 }
 
+func TestK8sDomain_LintWithOptions_DryRun(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "test_fix.go")
+	content := `package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+var MyContainer = corev1.Container{
+	Name:  "app",
+	Image: "nginx:1.21",
+}
+`
+	require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+	d := &K8sDomain{}
+	result, err := d.LintWithOptions(&Context{}, tempDir, K8sLintOpts{
+		LintOpts: LintOpts{Fix: true},
+		DryRun:   true,
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, "Fixes would change 1 file(s)", result.Message)
+
+	diff := result.Data.(string)
+	assert.Contains(t, diff, "--- "+testFile+".orig\n+++ "+testFile+"\n")
+	assert.Contains(t, diff, `+	Image: "nginx:1.21", ImagePullPolicy: "IfNotPresent",`)
+
+	// The file is left untouched
+	unchanged, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(unchanged))
+
+	t.Run("should report when there is nothing to fix", func(t *testing.T) {
+		result, err := d.LintWithOptions(&Context{}, tempDir, K8sLintOpts{
+			LintOpts: LintOpts{Fix: true, Disable: []string{"WK8105", "WK8201"}},
+			DryRun:   true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "No fixes to apply", result.Message)
+		assert.Empty(t, result.Data)
+	})
+}
+
 func TestK8sLinter_Lint_FixWithNoFixableIssues(t *testing.T) {
 	// Create a temporary directory with a file that has only unfixable issues
-	// (WK8105/WK8002 are fixable, but this file doesn't trigger those)
+	// (WK8105/WK8002/WK8201 are fixable, but this file doesn't trigger those)
 	tempDir := t.TempDir()
 	testFile := filepath.Join(tempDir, "test_good.go")
 
-	// This file has ImagePullPolicy set (so WK8105 won't fire)
-	// but may have other unfixable security warnings
+	// This file has ImagePullPolicy and limits set (so WK8105 and WK8201
+	// won't fire) but may have other unfixable security warnings
 	content := `package testdata
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 var ContainerWithPolicy = corev1.Container{
 	Name:            "app",
 	Image:           "nginx:1.21",
 	ImagePullPolicy: "IfNotPresent",
+	Resources: corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("500m"),
+		},
+	},
 }
 `
 	err := os.WriteFile(testFile, []byte(content), 0644)
@@ -166,3 +222,1095 @@ var ContainerLatest = corev1.Container{
 	// Should still report the unfixable issue
 	assert.Contains(t, result.Message, "lint issues found")
 }
+
+func TestK8sLinter_Lint_ConfigFile(t *testing.T) {
+	tempDir := t.TempDir()
+	content := `package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+var ContainerMissingPolicy = corev1.Container{
+	Name:  "app",
+	Image: "nginx:1.21",
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "app.go"), []byte(content), 0644))
+
+	config := `lint:
+  disabled_rules: [WK8201]
+  severity:
+    WK8105: error
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".wetwire.yaml"), []byte(config), 0644))
+
+	domain := &K8sDomain{}
+	result, err := domain.Linter().Lint(&Context{}, tempDir, LintOpts{})
+	require.NoError(t, err)
+
+	found := false
+	for _, e := range result.Errors {
+		assert.NotEqual(t, "WK8201", e.Code, "WK8201 is disabled in .wetwire.yaml")
+		if e.Code == "WK8105" {
+			found = true
+			assert.Equal(t, "error", e.Severity)
+		}
+	}
+	assert.True(t, found, "Expected WK8105 issue")
+}
+
+func TestK8sValidator_Validate_Schema(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "app.go")
+	content := `package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var WebDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web"},
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "web", Image: "nginx:1.25"}},
+			},
+		},
+	},
+}
+`
+	require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+	validator := (&K8sDomain{}).Validator()
+	result, err := validator.Validate(&Context{}, tempDir, ValidateOpts{})
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, testFile, result.Errors[0].Path)
+	assert.Equal(t, 11, result.Errors[0].Line)
+	assert.Equal(t, "error", result.Errors[0].Severity)
+	assert.Equal(t, "WebDeployment: missing required field spec.selector in DeploymentSpec", result.Errors[0].Message)
+
+	// Adding the selector fixes it
+	fixed := strings.Replace(content, "Spec: appsv1.DeploymentSpec{\n",
+		"Spec: appsv1.DeploymentSpec{\n\t\tSelector: &metav1.LabelSelector{},\n", 1)
+	require.NoError(t, os.WriteFile(testFile, []byte(fixed), 0644))
+
+	result, err = validator.Validate(&Context{}, testFile, ValidateOpts{})
+	require.NoError(t, err)
+	assert.True(t, result.Success, "%v", result.Errors)
+}
+
+func TestK8sValidator_Validate_Targets(t *testing.T) {
+	tempDir := t.TempDir()
+	content, err := os.ReadFile(filepath.Join("..", "examples", "hpa", "main.go"))
+	require.NoError(t, err)
+	src := strings.Replace(string(content), `Name:       "web-app",`, `Name:       "missing",`, 1)
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "main.go"), []byte(src), 0644))
+
+	result, err := (&K8sDomain{}).Validator().Validate(&Context{}, tempDir, ValidateOpts{})
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, `WebAppHPA: ScaleTargetRef refers to Deployment "missing", which is not defined`, result.Errors[0].Message)
+	assert.Equal(t, 101, result.Errors[0].Line)
+}
+
+func TestK8sGrapher_Graph_GroupsByNamespace(t *testing.T) {
+	tempDir := t.TempDir()
+	content := `package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var WebDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-alpha"},
+}
+
+var WebConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-config"},
+}
+`
+	err := os.WriteFile(filepath.Join(tempDir, "app.go"), []byte(content), 0644)
+	require.NoError(t, err)
+
+	domain := &K8sDomain{}
+	grapher := domain.Grapher()
+	ctx := &Context{}
+
+	t.Run("dot", func(t *testing.T) {
+		result, err := grapher.Graph(ctx, tempDir, GraphOpts{Format: "dot"})
+		require.NoError(t, err)
+		graph := result.Data.(string)
+		assert.Contains(t, graph, "subgraph cluster_team_alpha {")
+		assert.Contains(t, graph, `label="team-alpha";`)
+		assert.Contains(t, graph, "subgraph cluster_default {")
+	})
+
+	t.Run("mermaid", func(t *testing.T) {
+		result, err := grapher.Graph(ctx, tempDir, GraphOpts{Format: "mermaid"})
+		require.NoError(t, err)
+		graph := result.Data.(string)
+		assert.Contains(t, graph, "subgraph ns_team_alpha [team-alpha]")
+		assert.Contains(t, graph, "subgraph ns_default [default]")
+		assert.Contains(t, graph, "end")
+	})
+}
+
+func TestK8sGrapher_Graph_JSON(t *testing.T) {
+	tempDir := t.TempDir()
+	content := `package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var WebConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-config", Namespace: "shop"},
+}
+
+var WebDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: WebConfig.Name, Namespace: "shop"},
+}
+`
+	err := os.WriteFile(filepath.Join(tempDir, "app.go"), []byte(content), 0644)
+	require.NoError(t, err)
+
+	domain := &K8sDomain{}
+	result, err := domain.Grapher().Graph(&Context{}, tempDir, GraphOpts{Format: "json"})
+	require.NoError(t, err)
+
+	var graph struct {
+		Nodes []map[string]interface{} `json:"nodes"`
+		Edges []map[string]interface{} `json:"edges"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Data.(string)), &graph))
+
+	require.Len(t, graph.Nodes, 2)
+	assert.Equal(t, "WebConfig", graph.Nodes[0]["name"])
+	assert.Equal(t, "corev1.ConfigMap", graph.Nodes[0]["type"])
+	assert.Equal(t, "shop", graph.Nodes[0]["namespace"])
+	assert.Contains(t, graph.Nodes[0]["file"], "app.go")
+	assert.NotZero(t, graph.Nodes[0]["line"])
+
+	require.Len(t, graph.Edges, 1)
+	assert.Equal(t, map[string]interface{}{
+		"source": "WebDeployment",
+		"target": "WebConfig",
+		"kind":   "reference",
+	}, graph.Edges[0])
+}
+
+func TestK8sGrapher_Graph_UnknownFormat(t *testing.T) {
+	domain := &K8sDomain{}
+	_, err := domain.Grapher().Graph(&Context{}, t.TempDir(), GraphOpts{Format: "svg"})
+	assert.ErrorContains(t, err, "unknown format")
+}
+
+func TestK8sDomain_GraphWithOptions_IncludeExternal(t *testing.T) {
+	tempDir := t.TempDir()
+	content := `package testdata
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var AppConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+}
+
+var MigrateJob = batchv1.Job{
+	ObjectMeta: metav1.ObjectMeta{Name: "migrate"},
+	Spec: batchv1.JobSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{
+					{Name: "config", VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"},
+						},
+					}},
+					{Name: "creds", VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{SecretName: "database-credentials"},
+					}},
+				},
+			},
+		},
+	},
+}
+`
+	err := os.WriteFile(filepath.Join(tempDir, "app.go"), []byte(content), 0644)
+	require.NoError(t, err)
+
+	domain := &K8sDomain{}
+	ctx := &Context{}
+
+	t.Run("dot", func(t *testing.T) {
+		result, err := domain.GraphWithOptions(ctx, tempDir, K8sGraphOpts{
+			GraphOpts:       GraphOpts{Format: "dot"},
+			IncludeExternal: true,
+		})
+		require.NoError(t, err)
+		graph := result.Data.(string)
+		assert.Contains(t, graph, `"MigrateJob" -> "external:Secret/database-credentials" [style=dashed, color=red];`)
+		// app-config is defined in the project, so it is not external
+		assert.NotContains(t, graph, "external:ConfigMap/app-config")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		result, err := domain.GraphWithOptions(ctx, tempDir, K8sGraphOpts{
+			GraphOpts:       GraphOpts{Format: "json"},
+			IncludeExternal: true,
+		})
+		require.NoError(t, err)
+		graph := result.Data.(string)
+		assert.Contains(t, graph, `"kind": "external"`)
+		assert.Contains(t, graph, `"type": "Secret, external"`)
+	})
+
+	t.Run("excluded by default", func(t *testing.T) {
+		result, err := domain.Grapher().Graph(ctx, tempDir, GraphOpts{Format: "dot"})
+		require.NoError(t, err)
+		assert.NotContains(t, result.Data.(string), "external")
+	})
+}
+
+const transitiveGraphSource = `package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var WebConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-config"},
+}
+
+var WebContainer = corev1.Container{
+	Name:  "web",
+	Image: "nginx:1.27",
+	EnvFrom: []corev1.EnvFromSource{{
+		ConfigMapRef: &corev1.ConfigMapEnvSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: WebConfig.Name},
+		},
+	}},
+}
+
+var Web = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web"},
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{WebContainer}},
+		},
+	},
+}
+
+var WebService = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "web"},
+	Spec: corev1.ServiceSpec{
+		Selector: Web.Spec.Template.Labels,
+	},
+}
+`
+
+func TestK8sDomain_GraphWithOptions_Transitive(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "app.go"), []byte(transitiveGraphSource), 0644))
+
+	d := &K8sDomain{}
+	result, err := d.GraphWithOptions(&Context{}, tempDir, K8sGraphOpts{
+		GraphOpts:  GraphOpts{Format: "dot"},
+		Transitive: true,
+	})
+	require.NoError(t, err)
+	graph := result.Data.(string)
+
+	// Direct dependencies keep the plain style
+	assert.Contains(t, graph, "  \"WebService\" -> \"Web\";\n")
+	assert.Contains(t, graph, "  \"Web\" -> \"WebContainer\";\n")
+	assert.Contains(t, graph, "  \"WebContainer\" -> \"WebConfig\";\n")
+
+	// Indirect dependencies are dotted, and never duplicate a direct edge
+	assert.Contains(t, graph, "  \"Web\" -> \"WebConfig\" [style=dotted, color=gray];\n")
+	assert.Contains(t, graph, "  \"WebService\" -> \"WebContainer\" [style=dotted, color=gray];\n")
+	assert.Contains(t, graph, "  \"WebService\" -> \"WebConfig\" [style=dotted, color=gray];\n")
+	assert.NotContains(t, graph, "\"Web\" -> \"WebContainer\" [style=dotted")
+	assert.Equal(t, 3, strings.Count(graph, "style=dotted"))
+
+	t.Run("json", func(t *testing.T) {
+		result, err := d.GraphWithOptions(&Context{}, tempDir, K8sGraphOpts{
+			GraphOpts:  GraphOpts{Format: "json"},
+			Transitive: true,
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result.Data, `"source": "Web",
+      "target": "WebConfig",
+      "kind": "transitive"`)
+	})
+
+	t.Run("mermaid", func(t *testing.T) {
+		result, err := d.GraphWithOptions(&Context{}, tempDir, K8sGraphOpts{
+			GraphOpts:  GraphOpts{Format: "mermaid"},
+			Transitive: true,
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result.Data, "  Web -. indirect .-> WebConfig\n")
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		result, err := d.GraphWithOptions(&Context{}, tempDir, K8sGraphOpts{GraphOpts: GraphOpts{Format: "dot"}})
+		require.NoError(t, err)
+		assert.NotContains(t, result.Data, "style=dotted")
+	})
+}
+
+func TestK8sDomain_GraphWithOptions_Collapse(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "app.go"), []byte(transitiveGraphSource), 0644))
+
+	d := &K8sDomain{}
+	result, err := d.GraphWithOptions(&Context{}, tempDir, K8sGraphOpts{
+		GraphOpts:  GraphOpts{Format: "dot"},
+		Collapse:   true,
+		Transitive: true,
+	})
+	require.NoError(t, err)
+	graph := result.Data.(string)
+
+	// The Container helper is hidden and the Deployment is connected to the
+	// ConfigMap it used through it
+	assert.NotContains(t, graph, "WebContainer")
+	assert.Contains(t, graph, "  \"Web\" -> \"WebConfig\";\n")
+	assert.Contains(t, graph, "  \"WebService\" -> \"Web\";\n")
+	assert.Contains(t, graph, "  \"WebService\" -> \"WebConfig\" [style=dotted, color=gray];\n")
+}
+
+func TestCollapseHelpers_ExternalRefs(t *testing.T) {
+	resources := []discover.Resource{
+		{Name: "Web", Type: "appsv1.Deployment", Dependencies: []string{"WebContainer"}},
+		{Name: "Worker", Type: "appsv1.Deployment", Dependencies: []string{"WebContainer"}},
+		{Name: "WebContainer", Type: "corev1.Container"},
+		{Name: "Orphan", Type: "corev1.Container"},
+	}
+	external := []externalRef{
+		{source: "WebContainer", id: "external:Secret/token", name: "token", kind: "Secret"},
+		{source: "Orphan", id: "external:Secret/unused", name: "unused", kind: "Secret"},
+		{source: "Web", id: "external:Secret/token", name: "token", kind: "Secret"},
+	}
+
+	collapsed, refs := collapseHelpers(resources, external)
+
+	var names []string
+	for _, r := range collapsed {
+		names = append(names, r.Name)
+		assert.Empty(t, r.Dependencies)
+	}
+	assert.Equal(t, []string{"Web", "Worker"}, names)
+
+	// References made by a helper move to the resources that use it; those
+	// of unused helpers are dropped and duplicates are merged
+	var sources []string
+	for _, ref := range refs {
+		sources = append(sources, ref.source+" -> "+ref.id)
+	}
+	assert.Equal(t, []string{"Web -> external:Secret/token", "Worker -> external:Secret/token"}, sources)
+}
+
+func TestK8sDomain_PruneHelpers(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "app.go"), []byte(transitiveGraphSource), 0644))
+	d := &K8sDomain{}
+
+	t.Run("build", func(t *testing.T) {
+		result, err := d.BuildWithOptions(&Context{}, tempDir, K8sBuildOpts{
+			BuildOpts: BuildOpts{Format: "yaml"},
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result.Data, "kind: Container")
+
+		result, err = d.BuildWithOptions(&Context{}, tempDir, K8sBuildOpts{
+			BuildOpts:    BuildOpts{Format: "yaml"},
+			PruneHelpers: true,
+		})
+		require.NoError(t, err)
+		output := result.Data.(string)
+		assert.NotContains(t, output, "kind: Container")
+		assert.Equal(t, 3, strings.Count(output, "kind: "))
+		assert.Less(t, strings.Index(output, "kind: ConfigMap"), strings.Index(output, "kind: Deployment"))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		result, err := d.ListWithOptions(&Context{}, tempDir, K8sListOpts{PruneHelpers: true})
+		require.NoError(t, err)
+		list := result.Data.([]map[string]any)
+		require.Len(t, list, 3)
+		for _, item := range list {
+			assert.NotEqual(t, "WebContainer", item["name"])
+			if item["name"] == "Web" {
+				assert.Equal(t, []string{"WebConfig"}, item["dependencies"])
+			}
+		}
+	})
+}
+
+func TestK8sDomain_BuildNamespaceOverride(t *testing.T) {
+	d := &K8sDomain{}
+	rbacExample := filepath.Join("..", "examples", "rbac")
+
+	result, err := d.BuildWithOptions(&Context{}, rbacExample, K8sBuildOpts{
+		BuildOpts: BuildOpts{Format: "yaml"},
+		Namespace: "staging",
+	})
+	require.NoError(t, err)
+	require.True(t, result.Success)
+
+	namespaces := make(map[string]string)
+	for _, doc := range strings.Split(result.Data.(string), "---\n") {
+		var manifest struct {
+			Kind     string
+			Metadata struct {
+				Namespace *string
+			}
+		}
+		require.NoError(t, yaml.Unmarshal([]byte(doc), &manifest))
+		namespace := "<none>"
+		if manifest.Metadata.Namespace != nil {
+			namespace = *manifest.Metadata.Namespace
+		}
+		namespaces[manifest.Kind] = namespace
+	}
+
+	// The example puts its namespaced resources in "default"
+	assert.Equal(t, map[string]string{
+		"ServiceAccount":     "staging",
+		"Role":               "staging",
+		"RoleBinding":        "staging",
+		"ClusterRole":        "<none>",
+		"ClusterRoleBinding": "<none>",
+	}, namespaces)
+
+	t.Run("without override the code's namespace is kept", func(t *testing.T) {
+		result, err := d.BuildWithOptions(&Context{}, rbacExample, K8sBuildOpts{
+			BuildOpts: BuildOpts{Format: "yaml"},
+		})
+		require.NoError(t, err)
+		output := result.Data.(string)
+		// The three namespaced objects and the subjects of both bindings
+		assert.Equal(t, 5, strings.Count(output, "namespace: default"), output)
+		assert.NotContains(t, output, "staging")
+	})
+
+	t.Run("helm charts are rejected", func(t *testing.T) {
+		_, err := d.BuildWithOptions(&Context{}, rbacExample, K8sBuildOpts{
+			BuildOpts: BuildOpts{Format: "helm", Output: t.TempDir(), DryRun: true},
+			Namespace: "staging",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "namespace override is not supported for helm charts")
+	})
+}
+
+func TestK8sDomain_BuildOverlay(t *testing.T) {
+	writePackage := func(t *testing.T, content string) string {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(content), 0644))
+		return dir
+	}
+
+	base := writePackage(t, `package main
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var Web = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+}
+
+var WebService = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+}
+`)
+	overlay := writePackage(t, `package main
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var ProdWeb = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"env": "prod"}},
+}
+
+var WebConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-config", Namespace: "default"},
+}
+`)
+
+	d := &K8sDomain{}
+	result, err := d.BuildWithOptions(&Context{}, base, K8sBuildOpts{
+		BuildOpts: BuildOpts{Format: "yaml"},
+		Overlay:   overlay,
+	})
+	require.NoError(t, err)
+	require.True(t, result.Success, result.Errors)
+
+	// The Deployment is patched rather than duplicated, and the new
+	// ConfigMap is ordered with the base resources
+	var kinds []string
+	for _, doc := range strings.Split(result.Data.(string), "---\n") {
+		var manifest struct{ Kind string }
+		require.NoError(t, yaml.Unmarshal([]byte(doc), &manifest))
+		kinds = append(kinds, manifest.Kind)
+	}
+	assert.Equal(t, []string{"ConfigMap", "Deployment", "Service"}, kinds)
+	assert.Equal(t, 3, strings.Count(result.Data.(string), "namespace: default"))
+
+	t.Run("fields set in the code are patched", func(t *testing.T) {
+		project := t.TempDir()
+		base := filepath.Join(project, "base")
+		prod := filepath.Join(project, "prod")
+		require.NoError(t, os.Mkdir(base, 0755))
+		require.NoError(t, os.Mkdir(prod, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(base, "main.go"), []byte(`package main
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+var Web = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"app": "web"}},
+	Spec: appsv1.DeploymentSpec{
+		Replicas: ptr(int32(1)),
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "web", Image: "nginx:1.27"}},
+			},
+		},
+	},
+}
+`), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(prod, "main.go"), []byte(`package main
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+var Web = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"env": "prod"}},
+	Spec:       appsv1.DeploymentSpec{Replicas: ptr(int32(5))},
+}
+`), 0644))
+
+		result, err := d.BuildWithOptions(&Context{}, base, K8sBuildOpts{
+			BuildOpts: BuildOpts{Format: "yaml"},
+			Overlay:   prod,
+		})
+		require.NoError(t, err)
+		require.True(t, result.Success, result.Errors)
+
+		var manifest struct {
+			Kind     string
+			Metadata struct {
+				Name   string
+				Labels map[string]string
+			}
+			Spec struct {
+				Replicas int
+				Template struct {
+					Spec struct {
+						Containers []struct{ Name, Image string }
+					}
+				}
+			}
+		}
+		require.NoError(t, yaml.Unmarshal([]byte(result.Data.(string)), &manifest))
+		assert.Equal(t, "Deployment", manifest.Kind)
+		assert.Equal(t, "web", manifest.Metadata.Name)
+		assert.Equal(t, map[string]string{"app": "web", "env": "prod"}, manifest.Metadata.Labels)
+		assert.Equal(t, 5, manifest.Spec.Replicas)
+		// Fields the overlay leaves unset keep the base values
+		require.Len(t, manifest.Spec.Template.Spec.Containers, 1)
+		assert.Equal(t, "nginx:1.27", manifest.Spec.Template.Spec.Containers[0].Image)
+	})
+
+	t.Run("conflicting overlay resources are located", func(t *testing.T) {
+		conflict := writePackage(t, `package main
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var Web = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-canary"},
+}
+`)
+		result, err := d.BuildWithOptions(&Context{}, base, K8sBuildOpts{
+			BuildOpts: BuildOpts{Format: "yaml"},
+			Overlay:   conflict,
+		})
+		require.NoError(t, err)
+		require.False(t, result.Success)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, 8, result.Errors[0].Line)
+		assert.Equal(t, "overlay-conflict", result.Errors[0].Code)
+	})
+
+	t.Run("helm charts are rejected", func(t *testing.T) {
+		_, err := d.BuildWithOptions(&Context{}, base, K8sBuildOpts{
+			BuildOpts: BuildOpts{Format: "helm", Output: t.TempDir(), DryRun: true},
+			Overlay:   overlay,
+		})
+		assert.ErrorContains(t, err, "overlays are not supported for helm charts")
+	})
+}
+
+func TestK8sDomain_BuildOverrides(t *testing.T) {
+	d := &K8sDomain{}
+	webService := filepath.Join("..", "examples", "web-service")
+
+	result, err := d.BuildWithOptions(&Context{}, webService, K8sBuildOpts{
+		BuildOpts: BuildOpts{Format: "yaml"},
+		Overrides: build.Overrides{Replicas: []build.ReplicasOverride{{Name: "webapp", Replicas: 5}}},
+	})
+	require.NoError(t, err)
+	require.True(t, result.Success, result.Errors)
+
+	replicas := make(map[string]int)
+	for _, doc := range strings.Split(result.Data.(string), "---\n") {
+		var manifest struct {
+			Kind string
+			Spec struct{ Replicas int }
+		}
+		require.NoError(t, yaml.Unmarshal([]byte(doc), &manifest))
+		replicas[manifest.Kind] = manifest.Spec.Replicas
+	}
+	assert.Equal(t, 5, replicas["Deployment"])
+	assert.Zero(t, replicas["Service"])
+
+	t.Run("unmatched overrides fail the build", func(t *testing.T) {
+		result, err := d.BuildWithOptions(&Context{}, webService, K8sBuildOpts{
+			BuildOpts: BuildOpts{Format: "yaml"},
+			Overrides: build.Overrides{
+				Images:   []build.ImageOverride{{Container: "sidecar", Image: "envoy:1.30"}},
+				Replicas: []build.ReplicasOverride{{Name: "web-app-service", Replicas: 2}},
+			},
+		})
+		require.NoError(t, err)
+		require.False(t, result.Success)
+		require.Len(t, result.Errors, 2)
+		for _, e := range result.Errors {
+			assert.Equal(t, build.CodeUnmatchedOverride, e.Code)
+		}
+		assert.Contains(t, result.Errors[1].Message, `no Deployment, StatefulSet or ReplicaSet named "web-app-service"`)
+	})
+
+	t.Run("helm charts are rejected", func(t *testing.T) {
+		_, err := d.BuildWithOptions(&Context{}, webService, K8sBuildOpts{
+			BuildOpts: BuildOpts{Format: "helm", Output: t.TempDir(), DryRun: true},
+			Overrides: build.Overrides{Replicas: []build.ReplicasOverride{{Name: "webapp", Replicas: 5}}},
+		})
+		assert.ErrorContains(t, err, "overrides are not supported for helm charts")
+	})
+}
+
+func TestK8sDomain_BuildKubeVersion(t *testing.T) {
+	d := &K8sDomain{}
+	hpa := filepath.Join("..", "examples", "hpa")
+
+	result, err := d.BuildWithOptions(&Context{}, hpa, K8sBuildOpts{
+		BuildOpts:   BuildOpts{Format: "yaml"},
+		KubeVersion: "1.22",
+	})
+	require.NoError(t, err)
+	require.True(t, result.Success, result.Errors)
+
+	out := result.Data.(string)
+	assert.NotContains(t, out, "apiVersion: autoscaling/v2\n")
+	assert.Equal(t, 2, strings.Count(out, "apiVersion: autoscaling/v2beta2"))
+	require.Len(t, result.Errors, 2)
+	for _, e := range result.Errors {
+		assert.Equal(t, "warning", e.Severity)
+		assert.Equal(t, "kube-version-downgrade", e.Code)
+	}
+
+	t.Run("current versions build unchanged", func(t *testing.T) {
+		current, err := d.BuildWithOptions(&Context{}, hpa, K8sBuildOpts{
+			BuildOpts:   BuildOpts{Format: "yaml"},
+			KubeVersion: "1.30",
+		})
+		require.NoError(t, err)
+		assert.Empty(t, current.Errors)
+		assert.Contains(t, current.Data.(string), "apiVersion: autoscaling/v2\n")
+	})
+
+	t.Run("invalid versions are rejected", func(t *testing.T) {
+		_, err := d.BuildWithOptions(&Context{}, hpa, K8sBuildOpts{
+			BuildOpts:   BuildOpts{Format: "yaml"},
+			KubeVersion: "latest",
+		})
+		assert.ErrorContains(t, err, "invalid Kubernetes version")
+	})
+}
+
+func TestK8sDomain_BuildOwnerReferences(t *testing.T) {
+	d := &K8sDomain{}
+	webService := filepath.Join("..", "examples", "web-service")
+
+	result, err := d.BuildWithOptions(&Context{}, webService, K8sBuildOpts{
+		BuildOpts:       BuildOpts{Format: "yaml"},
+		OwnerReferences: true,
+		OwnerUIDs:       []build.OwnerUID{{Kind: "Deployment", Name: "webapp", UID: "6f1f5c1e-3c4b-4f7e-9a51-2d0e8b7c9a10"}},
+	})
+	require.NoError(t, err)
+	require.True(t, result.Success)
+
+	owners := make(map[string][]string)
+	for _, doc := range strings.Split(result.Data.(string), "---\n") {
+		var manifest struct {
+			Kind     string
+			Metadata struct {
+				OwnerReferences []struct {
+					APIVersion string `yaml:"apiVersion"`
+					Kind       string
+					Name       string
+					UID        string
+					Controller bool
+				} `yaml:"ownerReferences"`
+			}
+		}
+		require.NoError(t, yaml.Unmarshal([]byte(doc), &manifest))
+		for _, ref := range manifest.Metadata.OwnerReferences {
+			assert.Equal(t, "6f1f5c1e-3c4b-4f7e-9a51-2d0e8b7c9a10", ref.UID, "UID of the live owner")
+			assert.True(t, ref.Controller)
+			owners[manifest.Kind] = append(owners[manifest.Kind], ref.APIVersion+"/"+ref.Kind+"/"+ref.Name)
+		}
+	}
+
+	// The Service selects the Deployment's pods; the Ingress refers to the
+	// Service, which is not a workload
+	assert.Equal(t, map[string][]string{
+		"Service": {"apps/v1/Deployment/webapp"},
+	}, owners)
+
+	t.Run("owners without a UID fail the build", func(t *testing.T) {
+		result, err := d.BuildWithOptions(&Context{}, webService, K8sBuildOpts{
+			BuildOpts:       BuildOpts{Format: "yaml"},
+			OwnerReferences: true,
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Success)
+		require.NotEmpty(t, result.Errors)
+		assert.Equal(t, build.CodeUnknownOwnerUID, result.Errors[0].Code)
+		assert.Contains(t, result.Errors[0].Message, "owned by Deployment webapp, whose UID is unknown")
+	})
+
+	t.Run("helm charts are rejected", func(t *testing.T) {
+		_, err := d.BuildWithOptions(&Context{}, webService, K8sBuildOpts{
+			BuildOpts:       BuildOpts{Format: "helm", Output: t.TempDir(), DryRun: true},
+			OwnerReferences: true,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "owner references are not supported for helm charts")
+	})
+}
+
+func TestK8sDomain_ClusterScopedNamespace(t *testing.T) {
+	tempDir := t.TempDir()
+	content := `package k8s
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var NodeReader = rbacv1.ClusterRole{
+	ObjectMeta: metav1.ObjectMeta{Name: "node-reader", Namespace: "default"},
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "rbac.go"), []byte(content), 0644))
+	d := &K8sDomain{}
+
+	// The namespace is stripped from the built manifest
+	result, err := d.BuildWithOptions(&Context{}, tempDir, K8sBuildOpts{
+		BuildOpts: BuildOpts{Format: "yaml"},
+	})
+	require.NoError(t, err)
+	output := result.Data.(string)
+	assert.Contains(t, output, "kind: ClusterRole\n")
+	assert.NotContains(t, output, "namespace:")
+
+	// and lint warns about it
+	result, err = d.LintWithOptions(&Context{}, tempDir, K8sLintOpts{})
+	require.NoError(t, err)
+	var found bool
+	for _, e := range result.Errors {
+		if e.Code == "WK8018" {
+			found = true
+			assert.Equal(t, "warning", e.Severity)
+			assert.Contains(t, e.Message, "ClusterRole NodeReader sets a namespace")
+		}
+	}
+	assert.True(t, found, "expected a WK8018 warning, got %+v", result.Errors)
+}
+
+func TestK8sDomain_BuildWebServiceExample(t *testing.T) {
+	// Regression test: helper values in examples/web-service are never
+	// emitted as manifests, with or without --prune-helpers
+	d := &K8sDomain{}
+	for _, prune := range []bool{false, true} {
+		result, err := d.BuildWithOptions(&Context{}, filepath.Join("..", "examples", "web-service"), K8sBuildOpts{
+			BuildOpts:    BuildOpts{Format: "yaml"},
+			PruneHelpers: prune,
+		})
+		require.NoError(t, err)
+		output := result.Data.(string)
+		assert.Equal(t, 3, strings.Count(output, "\nkind: "), output)
+		for _, kind := range []string{"Deployment", "Service", "Ingress"} {
+			assert.Contains(t, output, "kind: "+kind+"\n")
+		}
+	}
+}
+
+func TestK8sDomain_InitTemplate(t *testing.T) {
+	d := &K8sDomain{}
+	dir := t.TempDir()
+
+	result, err := d.InitWithOptions(&Context{}, dir, K8sInitOpts{
+		InitOpts: InitOpts{Path: dir},
+		Template: "web-service",
+	})
+	require.NoError(t, err)
+	require.True(t, result.Success, result.Message)
+	assert.FileExists(t, filepath.Join(dir, "k8s", "main.go"))
+
+	built, err := d.BuildWithOptions(&Context{}, filepath.Join(dir, "k8s"), K8sBuildOpts{
+		BuildOpts: BuildOpts{Format: "yaml"},
+	})
+	require.NoError(t, err)
+	require.True(t, built.Success, built.Message)
+	output := built.Data.(string)
+	for _, kind := range []string{"Deployment", "Service", "Ingress"} {
+		assert.Contains(t, output, "kind: "+kind+"\n")
+	}
+}
+
+func TestK8sDomain_InitTemplatesBuild(t *testing.T) {
+	d := &K8sDomain{}
+	for _, name := range InitTemplates() {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			result, err := d.InitWithOptions(&Context{}, dir, K8sInitOpts{
+				InitOpts: InitOpts{Path: dir},
+				Template: name,
+			})
+			require.NoError(t, err)
+			require.True(t, result.Success, result.Message)
+
+			built, err := d.BuildWithOptions(&Context{}, filepath.Join(dir, "k8s"), K8sBuildOpts{
+				BuildOpts: BuildOpts{Format: "yaml"},
+			})
+			require.NoError(t, err)
+			assert.True(t, built.Success, built.Message)
+		})
+	}
+}
+
+func TestK8sDomain_InitUnknownTemplate(t *testing.T) {
+	d := &K8sDomain{}
+	dir := filepath.Join(t.TempDir(), "project")
+
+	_, err := d.InitWithOptions(&Context{}, dir, K8sInitOpts{
+		InitOpts: InitOpts{Path: dir},
+		Template: "nope",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown template "nope"`)
+	assert.NoDirExists(t, dir)
+}
+
+func TestK8sDomain_BuildErrorPositions(t *testing.T) {
+	d := &K8sDomain{}
+
+	tests := []struct {
+		name    string
+		source  string
+		message string
+		line    int
+		code    string
+		errMsg  string
+		file    bool // Build the file rather than its directory
+	}{
+		{
+			name: "invalid name",
+			source: `package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var AppConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+}
+
+var BadConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "Bad_Config"},
+}
+`,
+			message: "invalid resource names",
+			line:    12,
+			code:    "invalid-name",
+			errMsg:  `ConfigMap BadConfig has metadata name "Bad_Config", which is not a valid Kubernetes name`,
+		},
+		{
+			name: "cycle",
+			source: `package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var First = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "first"},
+	Data:       map[string]string{"other": Second.Name},
+}
+
+var Second = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "second"},
+	Data:       map[string]string{"other": First.Name},
+}
+`,
+			message: "cycle detected",
+			line:    8,
+			code:    "cycle",
+			errMsg:  "First -> Second -> First",
+		},
+		{
+			name: "syntax error",
+			source: `package k8s
+
+import corev1 "k8s.io/api/core/v1"
+
+var Broken = corev1.ConfigMap{
+	Data: map[string]string{"key" "value"},
+}
+`,
+			message: "discovery failed",
+			line:    6,
+			code:    "syntax-error",
+			errMsg:  "missing ','",
+			// Directory discovery skips files that do not parse
+			file: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "main.go")
+			require.NoError(t, os.WriteFile(file, []byte(tt.source), 0644))
+			path := dir
+			if tt.file {
+				path = file
+			}
+
+			built, err := d.BuildWithOptions(&Context{}, path, K8sBuildOpts{BuildOpts: BuildOpts{Format: "yaml"}})
+			require.NoError(t, err)
+			validate, err := d.Validator().Validate(&Context{}, path, ValidateOpts{})
+			require.NoError(t, err)
+
+			for _, result := range []*Result{built, validate} {
+				assert.False(t, result.Success)
+				assert.Equal(t, tt.message, result.Message)
+				require.Len(t, result.Errors, 1)
+				e := result.Errors[0]
+				assert.Equal(t, file, e.Path)
+				assert.Equal(t, tt.line, e.Line)
+				assert.Equal(t, tt.code, e.Code)
+				assert.Contains(t, e.Message, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestK8sDomain_BuildSkipValidation(t *testing.T) {
+	d := &K8sDomain{}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(`package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var First = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "first"},
+	Data:       map[string]string{"other": Second.Name},
+}
+
+var Second = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "second"},
+	Data:       map[string]string{"other": First.Name},
+}
+`), 0644))
+
+	// The cycle is not detected up front, but still fails the ordering
+	result, err := d.BuildWithOptions(&Context{}, dir, K8sBuildOpts{
+		BuildOpts:      BuildOpts{Format: "yaml"},
+		SkipValidation: true,
+	})
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, "ordering failed", result.Message)
+}
+
+func TestK8sDomain_BuildSource(t *testing.T) {
+	d := &K8sDomain{}
+	source := []byte(`package piped
+
+import corev1 "k8s.io/api/core/v1"
+
+// AppConfig configures the app.
+var AppConfig = corev1.ConfigMap{}
+`)
+
+	result, err := d.BuildWithOptions(&Context{}, "stdin.go", K8sBuildOpts{
+		BuildOpts:  BuildOpts{Format: "yaml"},
+		Source:     source,
+		Provenance: true,
+	})
+	require.NoError(t, err)
+	require.True(t, result.Success, result.Errors)
+	assert.Contains(t, result.Data, "# source: stdin.go:6\n# AppConfig configures the app.\n")
+	assert.Contains(t, result.Data, "name: app-config")
+
+	_, err = d.BuildWithOptions(&Context{}, "stdin.go", K8sBuildOpts{
+		BuildOpts: BuildOpts{Format: "helm", Output: t.TempDir()},
+		Source:    source,
+	})
+	assert.ErrorContains(t, err, "not supported for helm charts")
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/lex00/wetwire-k8s-go/internal/lint"
+)
+
+// FixReport is the outcome of applying lint auto-fixes.
+type FixReport struct {
+	// Fixes are the fixes applied, or that would be applied in dry-run mode.
+	Fixes []FixEntry `json:"fixes"`
+
+	// Issues are the lint issues that remain after fixing. They are not
+	// computed in dry-run mode.
+	Issues []Error `json:"issues,omitempty"`
+
+	// Diff is the unified diff of the fixes in dry-run mode.
+	Diff string `json:"diff,omitempty"`
+}
+
+// FixEntry is a single fix applied to a file.
+type FixEntry struct {
+	File        string `json:"file"`
+	Rule        string `json:"rule,omitempty"`
+	Description string `json:"description,omitempty"`
+	Error       string `json:"error,omitempty"` // Set if the file could not be fixed
+}
+
+// Fix applies the lint auto-fixes to the code at path and lints it again to
+// report the issues that remain. With opts.DryRun, nothing is written and
+// the report holds the diff of the fixes instead. opts.Disable skips rules.
+func (d *K8sDomain) Fix(ctx *Context, path string, opts K8sLintOpts) (*FixReport, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolve path: %w", err)
+	}
+
+	config, err := loadLintConfig(absPath, opts.ConfigFile, opts.Disable)
+	if err != nil {
+		return nil, err
+	}
+
+	results, diffs, err := runFixer(absPath, config, opts.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &FixReport{Fixes: []FixEntry{}}
+	for _, r := range results {
+		entry := FixEntry{File: r.File, Rule: r.Rule, Description: r.Description}
+		switch {
+		case r.Error != nil:
+			entry.Error = r.Error.Error()
+		case !r.Fixed:
+			continue
+		}
+		report.Fixes = append(report.Fixes, entry)
+	}
+
+	if opts.DryRun {
+		report.Diff = joinDiffs(diffs)
+		return report, nil
+	}
+
+	lintResult, err := lint.NewLinter(config).LintWithResult(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("lint failed: %w", err)
+	}
+	report.Issues = lintErrors(lintResult.Issues)
+	return report, nil
+}
@@ -2,9 +2,14 @@
 package domain
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"go/scanner"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	coredomain "github.com/lex00/wetwire-core-go/domain"
@@ -14,6 +19,7 @@ import (
 	"github.com/lex00/wetwire-k8s-go/internal/lint"
 	"github.com/lex00/wetwire-k8s-go/internal/registry"
 	"github.com/lex00/wetwire-k8s-go/internal/serialize"
+	"github.com/lex00/wetwire-k8s-go/internal/validate"
 	"github.com/spf13/cobra"
 )
 
@@ -105,18 +111,136 @@ func CreateRootCommand(d coredomain.Domain) *cobra.Command {
 	return coredomain.Run(d)
 }
 
+// K8sBuildOpts extends BuildOpts with k8s-specific build options.
+type K8sBuildOpts struct {
+	BuildOpts
+
+	// Source, when set, is the Go source of a single file to build, such as
+	// a file piped to stdin. The path passed to BuildWithOptions is then its
+	// file name in errors and provenance comments rather than a file on
+	// disk. It cannot be used with the helm format, which reads the source
+	// files again.
+	Source []byte
+
+	// ApplyOrder also orders resources after the ConfigMaps, Secrets,
+	// PersistentVolumeClaims and ServiceAccounts they refer to by name, so
+	// that the output can be applied with kubectl apply -f in order.
+	ApplyOrder bool
+
+	// Indent is the number of spaces per nesting level in YAML output, from 2
+	// to 9. Zero uses serialize.DefaultYAMLIndent.
+	Indent int
+
+	// LeadingSeparator starts YAML output with a "---" line, even when there
+	// is a single document.
+	LeadingSeparator bool
+
+	// Provenance starts YAML output with a "# Generated by wetwire-k8s"
+	// header and each document with a "# source: file.go:line" comment
+	// naming the Go declaration it was built from.
+	Provenance bool
+
+	// PruneHelpers leaves helper values, such as a Container or PodSpec
+	// declared as its own variable, out of the output so that only API
+	// objects are emitted. See discover.IsHelper.
+	PruneHelpers bool
+
+	// Namespace, when set, replaces metadata.namespace on every namespaced
+	// resource so the same code can target different environments.
+	// Cluster-scoped kinds are left unchanged. It cannot be used with the
+	// helm format.
+	Namespace string
+
+	// OwnerReferences sets ownerReferences on resources that refer to a
+	// workload in the package, such as a Service selecting a Deployment's
+	// pods, so that they are garbage collected with it. The UID of each
+	// owner is taken from OwnerUIDs, and an owner without one fails the
+	// build; see build.SetOwnerReferences. It cannot be used with the helm
+	// format.
+	OwnerReferences bool
+
+	// OwnerUIDs are the UIDs of the live workloads that OwnerReferences
+	// makes owners.
+	OwnerUIDs []build.OwnerUID
+
+	// Overlay is the directory of a package whose resources patch the
+	// resources being built, such as the settings of one environment. An
+	// overlay resource with the same kind and name as a base resource is
+	// merged into it with build.PatchManifest, and the other overlay
+	// resources are added to the output. See build.MergeOverlay. It cannot
+	// be used with the helm format.
+	Overlay string
+
+	// Overrides sets container images and replica counts on the built
+	// manifests, replacing the values in the code, for example to pin the
+	// images a CI pipeline pushed. An override that matches nothing fails
+	// the build. See build.ApplyOverrides. It cannot be used with the helm
+	// format.
+	Overrides build.Overrides
+
+	// JSONList writes JSON output as a v1 List object holding the resources,
+	// which kubectl apply accepts, rather than a bare array. It requires the
+	// json format.
+	JSONList bool
+
+	// KubeVersion, when set, is the Kubernetes version ("1.27") the output
+	// must be served by: resources are moved to older apiVersions and fields
+	// the version does not support are dropped, each with a warning in the
+	// result. See build.DowngradeManifests. It cannot be used with the helm
+	// format.
+	KubeVersion string
+
+	// ConfigHash annotates the pod templates of workloads with a hash of
+	// the ConfigMaps and Secrets they refer to, so that changing their
+	// content rolls the workloads out. See build.ApplyConfigHashes. It
+	// cannot be used with the helm format.
+	ConfigHash bool
+
+	// SkipValidation skips the checks that the references between resources
+	// point at declared resources and form no cycle, as build.skip_validation
+	// in .wetwire.yaml does. A cycle still fails when the resources are
+	// ordered.
+	SkipValidation bool
+}
+
+// BuildWithOptions builds the code at path using k8s-specific options.
+func (d *K8sDomain) BuildWithOptions(ctx *Context, path string, opts K8sBuildOpts) (*Result, error) {
+	return (&k8sBuilder{}).build(ctx, path, opts)
+}
+
 // k8sBuilder implements domain.Builder
 type k8sBuilder struct{}
 
 func (b *k8sBuilder) Build(ctx *Context, path string, opts BuildOpts) (*Result, error) {
+	return b.build(ctx, path, K8sBuildOpts{BuildOpts: opts})
+}
+
+func (b *k8sBuilder) build(ctx *Context, path string, opts K8sBuildOpts) (*Result, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, fmt.Errorf("resolve path: %w", err)
 	}
+	// Rejected before the owners' UIDs are looked up
+	if opts.Format == "helm" && opts.OwnerReferences {
+		return nil, fmt.Errorf("owner references are not supported for helm charts")
+	}
 
 	// Discover all resources
-	resources, err := discoverResources(absPath)
+	var resources []discover.Resource
+	if opts.Source != nil {
+		if opts.Format == "helm" {
+			return nil, fmt.Errorf("building from stdin is not supported for helm charts")
+		}
+		absPath = path
+		resources, err = discover.DiscoverSource(opts.Source, path)
+	} else {
+		resources, err = discoverResources(absPath)
+	}
 	if err != nil {
+		var syntaxErrs scanner.ErrorList
+		if errors.As(err, &syntaxErrs) {
+			return buildErrorResult("discovery failed", absPath, err), nil
+		}
 		return nil, fmt.Errorf("discovery failed: %w", err)
 	}
 
@@ -127,74 +251,243 @@ func (b *k8sBuilder) Build(ctx *Context, path string, opts BuildOpts) (*Result,
 		}), nil
 	}
 
-	// Validate references
-	if err := build.ValidateReferences(resources); err != nil {
-		return nil, fmt.Errorf("validation failed: %w", err)
+	if !opts.SkipValidation {
+		// Validate references
+		if err := build.ValidateReferences(resources); err != nil {
+			return buildErrorResult("validation failed", absPath, err), nil
+		}
+
+		// Detect cycles
+		if err := build.DetectCycles(resources); err != nil {
+			return buildErrorResult("cycle detected", absPath, err), nil
+		}
 	}
 
-	// Detect cycles
-	if err := build.DetectCycles(resources); err != nil {
-		return nil, fmt.Errorf("cycle detection failed: %w", err)
+	// Check that generated names are legal Kubernetes names
+	if err := build.ValidateNames(resources); err != nil {
+		return buildErrorResult("invalid resource names", absPath, err), nil
+	}
+
+	if opts.JSONList && opts.Format != "json" {
+		return nil, fmt.Errorf("JSON list output requires the json format")
+	}
+	var kubeVersion build.KubeVersion
+	if opts.KubeVersion != "" {
+		if kubeVersion, err = build.ParseKubeVersion(opts.KubeVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	// Patch the base with the overlay package and add its new resources
+	var patches map[string]discover.Resource
+	if opts.Overlay != "" {
+		if opts.Format == "helm" {
+			return nil, fmt.Errorf("overlays are not supported for helm charts")
+		}
+		overlayPath, err := filepath.Abs(opts.Overlay)
+		if err != nil {
+			return nil, fmt.Errorf("resolve overlay path: %w", err)
+		}
+		overlay, result, err := discoverOverlay(overlayPath)
+		if result != nil || err != nil {
+			return result, err
+		}
+		resources, patches, err = build.MergeOverlay(resources, overlay)
+		if err != nil {
+			return buildErrorResult("invalid overlay", overlayPath, err), nil
+		}
+		for name, patch := range patches {
+			patches[name] = build.OverrideNamespace([]discover.Resource{patch}, opts.Namespace)[0]
+		}
+	}
+
+	resources = build.OverrideNamespace(resources, opts.Namespace)
+
+	// Collapse identical duplicates and reject conflicting ones
+	resources, err = build.Deduplicate(resources)
+	if err != nil {
+		return buildErrorResult("conflicting duplicate resources", absPath, err), nil
+	}
+	if opts.OwnerReferences {
+		resources, err = build.SetOwnerReferences(resources, opts.OwnerUIDs)
+		if err != nil {
+			return buildErrorResult("unknown owner UIDs", absPath, err), nil
+		}
 	}
 
 	// Topological sort
-	orderedResources, err := build.TopologicalSort(resources)
+	toOrder := resources
+	if opts.ApplyOrder {
+		toOrder = build.AddApplyOrderDependencies(resources)
+	}
+	orderedResources, err := build.TopologicalSort(toOrder)
 	if err != nil {
-		return nil, fmt.Errorf("ordering failed: %w", err)
+		return buildErrorResult("ordering failed", absPath, err), nil
+	}
+	if opts.PruneHelpers {
+		orderedResources, _ = collapseHelpers(orderedResources, nil)
+		if len(orderedResources) == 0 {
+			return NewErrorResult("no resources found", Error{
+				Path:    absPath,
+				Message: "only helper values found, no Kubernetes objects",
+			}), nil
+		}
+	}
+
+	// Helm charts are written as a directory rather than a single document
+	if opts.Format == "helm" {
+		if opts.Namespace != "" {
+			return nil, fmt.Errorf("namespace override is not supported for helm charts; set the namespace when installing the chart")
+		}
+		if !opts.Overrides.Empty() {
+			return nil, fmt.Errorf("image and replica overrides are not supported for helm charts; set them in the chart values")
+		}
+		if opts.KubeVersion != "" {
+			return nil, fmt.Errorf("kube version targeting is not supported for helm charts")
+		}
+		if opts.ConfigHash {
+			return nil, fmt.Errorf("config hash annotations are not supported for helm charts")
+		}
+		return buildHelmChart(orderedResources, opts.BuildOpts)
 	}
 
 	// Serialize resources
 	var outputData []byte
-	if opts.Format == "json" {
-		outputData, err = serializeToJSON(orderedResources)
-	} else {
-		outputData, err = serializeToYAML(orderedResources)
+	manifests, err := resourceManifests(orderedResources, patches, opts.Source)
+	if err != nil {
+		return buildErrorResult("evaluation failed", absPath, err), nil
+	}
+	if err := build.ApplyOverrides(manifests, opts.Overrides); err != nil {
+		return buildErrorResult("unmatched overrides", absPath, err), nil
+	}
+	if opts.ConfigHash {
+		if err := build.ApplyConfigHashes(manifests); err != nil {
+			return nil, err
+		}
+	}
+	var warnings []Error
+	if opts.KubeVersion != "" {
+		downgrades, err := build.DowngradeManifests(manifests, kubeVersion)
+		if err != nil {
+			return buildErrorResult("unsupported Kubernetes version", absPath, err), nil
+		}
+		for _, warning := range downgrades {
+			warnings = append(warnings, Error{Path: absPath, Severity: "warning", Message: warning, Code: "kube-version-downgrade"})
+		}
+	}
+	manifests = serializedManifests(manifests)
+	yamlOpts := serialize.YAMLOptions{
+		Indent:           opts.Indent,
+		LeadingSeparator: opts.LeadingSeparator,
+	}
+	switch {
+	case opts.Format == "json":
+		outputData, err = serializeToJSON(manifests, serialize.JSONOptions{List: opts.JSONList})
+	case opts.Provenance:
+		outputData, err = serializeWithProvenance(absPath, orderedResources, manifests, yamlOpts)
+	default:
+		outputData, err = serializeToYAML(manifests, yamlOpts)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("serialization failed: %w", err)
 	}
 
 	// Handle output file
+	var result *Result
 	if !opts.DryRun && opts.Output != "" {
 		if err := os.WriteFile(opts.Output, outputData, 0644); err != nil {
 			return nil, fmt.Errorf("write output: %w", err)
 		}
-		return NewResult(fmt.Sprintf("Wrote %s", opts.Output)), nil
+		result = NewResult(fmt.Sprintf("Wrote %s", opts.Output))
+	} else {
+		result = NewResultWithData("Build completed", string(outputData))
+	}
+	result.Errors = warnings
+	return result, nil
+}
+
+// buildHelmChart exports resources as a Helm chart in the opts.Output directory.
+// The chart name is taken from the output directory name.
+func buildHelmChart(resources []discover.Resource, opts BuildOpts) (*Result, error) {
+	if opts.Output == "" {
+		return nil, fmt.Errorf("helm format requires an output directory (--output)")
 	}
 
-	return NewResultWithData("Build completed", string(outputData)), nil
+	absOutput, err := filepath.Abs(opts.Output)
+	if err != nil {
+		return nil, fmt.Errorf("resolve output path: %w", err)
+	}
+
+	chart, err := build.ToHelmChart(resources, build.ChartMeta{
+		Name:       filepath.Base(absOutput),
+		AppVersion: Version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("helm export failed: %w", err)
+	}
+
+	if opts.DryRun {
+		return NewResultWithData("Helm chart preview", chart.Paths()), nil
+	}
+
+	written, err := chart.Write(absOutput)
+	if err != nil {
+		return nil, fmt.Errorf("write helm chart: %w", err)
+	}
+	return NewResultWithData(fmt.Sprintf("Wrote Helm chart to %s", opts.Output), written), nil
+}
+
+// K8sLintOpts extends LintOpts with k8s-specific lint options.
+type K8sLintOpts struct {
+	LintOpts
+
+	// DryRun, together with Fix, computes the fixes without writing them and
+	// returns a unified diff of the proposed changes as the result data.
+	DryRun bool
+
+	// MaxWarnings, when set, lets lint succeed with at most this many
+	// warnings and any number of info issues; errors always fail. When nil,
+	// any issue fails.
+	MaxWarnings *int
+
+	// ConfigFile, when set, is the .wetwire.yaml to read the lint settings
+	// from, instead of the one found in the directory of path or its
+	// parents.
+	ConfigFile string
+}
+
+// LintWithOptions lints the code at path using k8s-specific options.
+func (d *K8sDomain) LintWithOptions(ctx *Context, path string, opts K8sLintOpts) (*Result, error) {
+	return (&k8sLinter{}).lint(ctx, path, opts)
 }
 
 // k8sLinter implements domain.Linter
 type k8sLinter struct{}
 
 func (l *k8sLinter) Lint(ctx *Context, path string, opts LintOpts) (*Result, error) {
+	return l.lint(ctx, path, K8sLintOpts{LintOpts: opts})
+}
+
+func (l *k8sLinter) lint(ctx *Context, path string, opts K8sLintOpts) (*Result, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, fmt.Errorf("resolve path: %w", err)
 	}
 
-	// Create linter config
-	config := &lint.Config{
-		MinSeverity:   lint.SeverityInfo,
-		DisabledRules: opts.Disable,
+	config, err := loadLintConfig(absPath, opts.ConfigFile, opts.Disable)
+	if err != nil {
+		return nil, err
+	}
+
+	// In dry-run mode, preview the fixes instead of applying them
+	if opts.Fix && opts.DryRun {
+		return previewFixes(absPath, config)
 	}
 
 	// If Fix mode is enabled, run the fixer first
 	if opts.Fix {
-		fixer := lint.NewFixer(config)
-		info, err := os.Stat(absPath)
-		if err != nil {
-			return nil, fmt.Errorf("stat path: %w", err)
-		}
-
-		if info.IsDir() {
-			_, err = fixer.FixDirectory(absPath)
-		} else {
-			_, err = fixer.FixFile(absPath)
-		}
-		if err != nil {
-			return nil, fmt.Errorf("fix failed: %w", err)
+		if _, _, err := runFixer(absPath, config, false); err != nil {
+			return nil, err
 		}
 	}
 
@@ -202,16 +495,134 @@ func (l *k8sLinter) Lint(ctx *Context, path string, opts LintOpts) (*Result, err
 	linter := lint.NewLinter(config)
 
 	// Run lint
-	issues, err := linter.Lint(absPath)
+	lintResult, err := linter.LintWithResult(absPath)
 	if err != nil {
 		return nil, fmt.Errorf("lint failed: %w", err)
 	}
+	issues := lintResult.Issues
+
+	// Report formats are rendered in full and returned as the result data
+	report, err := renderLintReport(lintResult, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	// Directory runs end the message with the issue counts
+	summarize := func(message string) string {
+		if info, err := os.Stat(absPath); err == nil && info.IsDir() {
+			return message + ": " + lintResult.Summary()
+		}
+		return message
+	}
 
 	if len(issues) == 0 {
-		return NewResult("No lint issues found"), nil
+		result := NewResult(summarize("No lint issues found"))
+		result.Data = report
+		return result, nil
 	}
 
-	// Convert to domain errors
+	errs := lintErrors(issues)
+
+	if opts.MaxWarnings != nil && lintResult.ErrorCount == 0 && lintResult.WarningCount <= *opts.MaxWarnings {
+		result := NewResult(summarize(fmt.Sprintf("lint issues found (warnings within the limit of %d)", *opts.MaxWarnings)))
+		result.Errors = errs
+		result.Data = report
+		return result, nil
+	}
+
+	// If Fix mode was enabled but issues remain, note that in the message
+	message := "lint issues found"
+	switch {
+	case opts.MaxWarnings != nil && lintResult.ErrorCount == 0:
+		message = fmt.Sprintf("lint issues found (warnings over the limit of %d)", *opts.MaxWarnings)
+	case opts.Fix:
+		message = "lint issues found (some issues could not be auto-fixed)"
+	}
+
+	result := NewErrorResultMultiple(summarize(message), errs)
+	result.Data = report
+	return result, nil
+}
+
+// previewFixes computes the fixes for path without writing them and returns
+// a unified diff of every file that would change as the result data.
+func previewFixes(absPath string, config *lint.Config) (*Result, error) {
+	_, diffs, err := runFixer(absPath, config, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(diffs) == 0 {
+		return NewResultWithData("No fixes to apply", ""), nil
+	}
+	return NewResultWithData(fmt.Sprintf("Fixes would change %d file(s)", len(diffs)), joinDiffs(diffs)), nil
+}
+
+// loadLintConfig returns the lint configuration for path, starting from
+// configFile, or else the .wetwire.yaml found for path if there is one, with
+// the given rules disabled.
+func loadLintConfig(absPath, configFile string, disable []string) (*lint.Config, error) {
+	config := &lint.Config{
+		MinSeverity: lint.SeverityInfo,
+	}
+	configPath := configFile
+	if configPath == "" {
+		configPath = lint.FindConfigFile(absPath)
+	}
+	if configPath != "" {
+		var err error
+		config, err = lint.LoadConfig(configPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	config.DisabledRules = append(config.DisabledRules, disable...)
+	return config, nil
+}
+
+// runFixer applies the auto-fixes to a file or directory. In dry-run mode
+// nothing is written and the diffs of the files that would change are
+// returned instead.
+func runFixer(absPath string, config *lint.Config, dryRun bool) ([]lint.FixResult, []lint.FileDiff, error) {
+	fixer := lint.NewFixer(config)
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("stat path: %w", err)
+	}
+
+	var results []lint.FixResult
+	var diffs []lint.FileDiff
+	switch {
+	case info.IsDir() && dryRun:
+		results, diffs, err = fixer.DiffDirectory(absPath)
+	case info.IsDir():
+		results, err = fixer.FixDirectory(absPath)
+	case dryRun:
+		var diff *lint.FileDiff
+		results, diff, err = fixer.DiffFile(absPath)
+		if diff != nil {
+			diffs = append(diffs, *diff)
+		}
+	default:
+		results, err = fixer.FixFile(absPath)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("fix failed: %w", err)
+	}
+	return results, diffs, nil
+}
+
+// joinDiffs concatenates the diffs of several files.
+func joinDiffs(diffs []lint.FileDiff) string {
+	var buf strings.Builder
+	for _, diff := range diffs {
+		buf.WriteString(diff.Diff)
+	}
+	return buf.String()
+}
+
+// lintErrors converts lint issues to domain errors.
+func lintErrors(issues []lint.Issue) []Error {
 	errs := make([]Error, 0, len(issues))
 	for _, issue := range issues {
 		errs = append(errs, Error{
@@ -222,25 +633,79 @@ func (l *k8sLinter) Lint(ctx *Context, path string, opts LintOpts) (*Result, err
 			Code:     issue.Rule,
 		})
 	}
+	return errs
+}
 
-	// If Fix mode was enabled but issues remain, note that in the message
-	if opts.Fix {
-		return NewErrorResultMultiple("lint issues found (some issues could not be auto-fixed)", errs), nil
+// IsLintReportFormat reports whether a lint format is rendered as a complete
+// report document (returned as the result data) rather than as a result.
+func IsLintReportFormat(format string) bool {
+	switch lint.OutputFormat(format) {
+	case lint.FormatGitHub, lint.FormatSARIF, lint.FormatJUnit:
+		return true
+	}
+	return false
+}
+
+// renderLintReport renders lint results in a report format, or returns nil
+// for formats handled by the result formatter.
+func renderLintReport(result *lint.LintResult, format string) (any, error) {
+	if !IsLintReportFormat(format) {
+		return nil, nil
+	}
+
+	// Report file names relative to the working directory
+	baseDir, _ := os.Getwd()
+
+	formatter := lint.NewFormatter(lint.OutputFormat(format))
+	switch f := formatter.(type) {
+	case *lint.SARIFFormatter:
+		f.ToolVersion = Version
+		f.BaseDir = baseDir
+	case *lint.JUnitFormatter:
+		f.BaseDir = baseDir
 	}
 
-	return NewErrorResultMultiple("lint issues found", errs), nil
+	var buf bytes.Buffer
+	if err := formatter.Format(result, &buf); err != nil {
+		return nil, fmt.Errorf("format lint report: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// K8sInitOpts extends InitOpts with k8s-specific project options.
+type K8sInitOpts struct {
+	InitOpts
+
+	// Template, when set, scaffolds the resources of the named template
+	// instead of a single Namespace. See InitTemplates for the names.
+	Template string
+}
+
+// InitWithOptions initializes a project at path using k8s-specific options.
+func (d *K8sDomain) InitWithOptions(ctx *Context, path string, opts K8sInitOpts) (*Result, error) {
+	return (&k8sInitializer{}).init(ctx, path, opts)
 }
 
 // k8sInitializer implements domain.Initializer
 type k8sInitializer struct{}
 
 func (i *k8sInitializer) Init(ctx *Context, path string, opts InitOpts) (*Result, error) {
+	return i.init(ctx, path, K8sInitOpts{InitOpts: opts})
+}
+
+func (i *k8sInitializer) init(ctx *Context, path string, opts K8sInitOpts) (*Result, error) {
 	// Use opts.Path if provided, otherwise fall back to path argument
 	targetPath := opts.Path
 	if targetPath == "" || targetPath == "." {
 		targetPath = path
 	}
 
+	if opts.Template != "" {
+		if err := checkTemplate(opts.Template); err != nil {
+			return nil, err
+		}
+	}
+
 	// Handle scenario initialization
 	if opts.Scenario {
 		return i.initScenario(ctx, targetPath, opts)
@@ -251,7 +716,7 @@ func (i *k8sInitializer) Init(ctx *Context, path string, opts InitOpts) (*Result
 }
 
 // initScenario creates a full scenario structure with prompts and expected outputs
-func (i *k8sInitializer) initScenario(ctx *Context, path string, opts InitOpts) (*Result, error) {
+func (i *k8sInitializer) initScenario(ctx *Context, path string, opts K8sInitOpts) (*Result, error) {
 	name := opts.Name
 	if name == "" {
 		name = filepath.Base(path)
@@ -275,6 +740,21 @@ func (i *k8sInitializer) initScenario(ctx *Context, path string, opts InitOpts)
 		return nil, fmt.Errorf("create expected/k8s directory: %w", err)
 	}
 
+	// Scaffold the template's resources as the expected output
+	if opts.Template != "" {
+		written, err := writeTemplate(opts.Template, expectedK8sDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range written {
+			created = append(created, "expected/k8s/"+file)
+		}
+		return NewResultWithData(
+			fmt.Sprintf("Created scenario %s with %d files", name, len(created)),
+			created,
+		), nil
+	}
+
 	// Create example namespace in expected/k8s/
 	exampleNamespace := `package k8s
 
@@ -306,7 +786,7 @@ var AppNamespace = &corev1.Namespace{
 }
 
 // initProject creates a basic project with example Kubernetes resources
-func (i *k8sInitializer) initProject(ctx *Context, path string, opts InitOpts) (*Result, error) {
+func (i *k8sInitializer) initProject(ctx *Context, path string, opts K8sInitOpts) (*Result, error) {
 	// Create directory
 	if err := os.MkdirAll(path, 0755); err != nil {
 		return nil, fmt.Errorf("create directory: %w", err)
@@ -318,27 +798,13 @@ func (i *k8sInitializer) initProject(ctx *Context, path string, opts InitOpts) (
 		return nil, fmt.Errorf("create k8s directory: %w", err)
 	}
 
-	// Create namespace.go
-	namespaceContent := `package k8s
-
-import (
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-)
-
-// AppNamespace defines the namespace for the application
-var AppNamespace = &corev1.Namespace{
-	ObjectMeta: metav1.ObjectMeta{
-		Name: "my-app",
-		Labels: map[string]string{
-			"app.kubernetes.io/name": "my-app",
-		},
-	},
-}
-`
-	nsPath := filepath.Join(k8sDir, "namespace.go")
-	if err := os.WriteFile(nsPath, []byte(namespaceContent), 0644); err != nil {
-		return nil, fmt.Errorf("write namespace.go: %w", err)
+	// Create the template's resources, or namespace.go
+	if opts.Template != "" {
+		if _, err := writeTemplate(opts.Template, k8sDir); err != nil {
+			return nil, err
+		}
+	} else if err := writeExampleNamespace(k8sDir); err != nil {
+		return nil, err
 	}
 
 	// Create .wetwire.yaml
@@ -368,6 +834,33 @@ build:
 	return NewResult(fmt.Sprintf("Initialized k8s project in %s", path)), nil
 }
 
+// writeExampleNamespace writes namespace.go, declaring a Namespace for the
+// application, into dir.
+func writeExampleNamespace(dir string) error {
+	namespaceContent := `package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AppNamespace defines the namespace for the application
+var AppNamespace = &corev1.Namespace{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "my-app",
+		Labels: map[string]string{
+			"app.kubernetes.io/name": "my-app",
+		},
+	},
+}
+`
+	nsPath := filepath.Join(dir, "namespace.go")
+	if err := os.WriteFile(nsPath, []byte(namespaceContent), 0644); err != nil {
+		return fmt.Errorf("write namespace.go: %w", err)
+	}
+	return nil
+}
+
 // k8sValidator implements domain.Validator
 type k8sValidator struct{}
 
@@ -380,65 +873,118 @@ func (v *k8sValidator) Validate(ctx *Context, path string, opts ValidateOpts) (*
 	// Discover all resources
 	resources, err := discoverResources(absPath)
 	if err != nil {
+		var syntaxErrs scanner.ErrorList
+		if errors.As(err, &syntaxErrs) {
+			return buildErrorResult("discovery failed", absPath, err), nil
+		}
 		return nil, fmt.Errorf("discovery failed: %w", err)
 	}
 
 	// Validate references
 	if err := build.ValidateReferences(resources); err != nil {
-		return NewErrorResult("validation failed", Error{
-			Path:    absPath,
-			Message: err.Error(),
-		}), nil
+		return buildErrorResult("validation failed", absPath, err), nil
 	}
 
 	// Detect cycles
 	if err := build.DetectCycles(resources); err != nil {
-		return NewErrorResult("cycle detected", Error{
-			Path:    absPath,
-			Message: err.Error(),
-		}), nil
+		return buildErrorResult("cycle detected", absPath, err), nil
 	}
 
-	return NewResult("Validation passed"), nil
-}
+	// Check that generated names are legal Kubernetes names
+	if err := build.ValidateNames(resources); err != nil {
+		return buildErrorResult("invalid resource names", absPath, err), nil
+	}
 
-// k8sLister implements domain.Lister
-type k8sLister struct{}
+	// Check that no object is declared twice with different definitions
+	if _, err := build.Deduplicate(resources); err != nil {
+		return buildErrorResult("conflicting duplicate resources", absPath, err), nil
+	}
 
-func (l *k8sLister) List(ctx *Context, path string, opts ListOpts) (*Result, error) {
-	absPath, err := filepath.Abs(path)
+	var errs []Error
+
+	// Check selectors and targets that refer to other resources by name
+	targetIssues, err := build.ValidateTargets(resources)
 	if err != nil {
-		return nil, fmt.Errorf("resolve path: %w", err)
+		return nil, fmt.Errorf("target validation failed: %w", err)
+	}
+	for _, issue := range targetIssues {
+		errs = append(errs, Error{
+			Path:     issue.File,
+			Line:     issue.Line,
+			Severity: "error",
+			Message:  fmt.Sprintf("%s: %s", issue.Resource, issue.Message),
+		})
 	}
 
-	// Discover all resources
-	resources, err := discoverResources(absPath)
+	// Check resources against the built-in API types
+	schemaIssues, err := validateSchema(absPath)
 	if err != nil {
-		return nil, fmt.Errorf("discovery failed: %w", err)
+		return nil, fmt.Errorf("schema validation failed: %w", err)
+	}
+	for _, issue := range schemaIssues {
+		errs = append(errs, Error{
+			Path:     issue.File,
+			Line:     issue.Line,
+			Column:   issue.Column,
+			Severity: "error",
+			Message:  fmt.Sprintf("%s: %s", issue.Resource, issue.Message),
+		})
 	}
 
-	// Build list
-	list := make([]map[string]any, 0)
-	for _, r := range resources {
-		item := map[string]any{
-			"name": r.Name,
-			"type": r.Type,
-			"file": r.File,
-			"line": r.Line,
-		}
-		if len(r.Dependencies) > 0 {
-			item["dependencies"] = r.Dependencies
-		}
-		list = append(list, item)
+	if len(errs) > 0 {
+		return NewErrorResultMultiple("validation failed", errs), nil
+	}
+
+	return NewResult("Validation passed"), nil
+}
+
+// validateSchema validates the resources in a file or directory against the
+// built-in API types.
+func validateSchema(path string) ([]validate.Issue, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access path %q: %w", path, err)
+	}
+
+	if info.IsDir() {
+		return validate.ValidateDirectory(path)
 	}
+	return validate.ValidateFile(path)
+}
+
+// K8sGraphOpts extends GraphOpts with k8s-specific graph options.
+type K8sGraphOpts struct {
+	GraphOpts
 
-	return NewResultWithData(fmt.Sprintf("Discovered %d resources", len(list)), list), nil
+	// IncludeExternal draws references to objects that are not defined in
+	// the project (e.g. a Secret created out-of-band) as dashed edges to
+	// synthetic external nodes instead of dropping them.
+	IncludeExternal bool
+
+	// Transitive also draws the indirect dependencies of each resource, the
+	// resources it reaches only through other resources, styled differently
+	// from direct dependencies.
+	Transitive bool
+
+	// Collapse hides helper values such as Containers and Volumes declared
+	// as their own variables, leaving only top-level objects. A resource that
+	// uses a helper is connected to the resources the helper refers to.
+	Collapse bool
+}
+
+// GraphWithOptions generates a dependency graph using k8s-specific options.
+func (d *K8sDomain) GraphWithOptions(ctx *Context, path string, opts K8sGraphOpts) (*Result, error) {
+	return (&k8sGrapher{}).graph(ctx, path, opts)
 }
 
 // k8sGrapher implements domain.Grapher
 type k8sGrapher struct{}
 
 func (g *k8sGrapher) Graph(ctx *Context, path string, opts GraphOpts) (*Result, error) {
+	return g.graph(ctx, path, K8sGraphOpts{GraphOpts: opts})
+}
+
+func (g *k8sGrapher) graph(ctx *Context, path string, opts K8sGraphOpts) (*Result, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, fmt.Errorf("resolve path: %w", err)
@@ -450,13 +996,30 @@ func (g *k8sGrapher) Graph(ctx *Context, path string, opts GraphOpts) (*Result,
 		return nil, fmt.Errorf("discovery failed: %w", err)
 	}
 
+	var external []externalRef
+	if opts.IncludeExternal {
+		external = findExternalRefs(resources)
+	}
+	if opts.Collapse {
+		resources, external = collapseHelpers(resources, external)
+	}
+	var indirect map[string][]string
+	if opts.Transitive {
+		indirect = transitiveDependencies(resources)
+	}
+
 	// Generate graph
 	var graph string
 	switch opts.Format {
 	case "dot", "":
-		graph = generateDOTGraph(resources)
+		graph = generateDOTGraph(resources, external, indirect)
 	case "mermaid":
-		graph = generateMermaidGraph(resources)
+		graph = generateMermaidGraph(resources, external, indirect)
+	case "json":
+		graph, err = generateJSONGraph(resources, external, indirect)
+		if err != nil {
+			return nil, fmt.Errorf("generate json graph: %w", err)
+		}
 	default:
 		return nil, fmt.Errorf("unknown format: %s", opts.Format)
 	}
@@ -479,66 +1042,135 @@ func discoverResources(path string) ([]discover.Resource, error) {
 	return discover.DiscoverFile(path)
 }
 
-// serializeToYAML serializes resources to YAML format
-func serializeToYAML(resources []discover.Resource) ([]byte, error) {
-	// Convert resources to manifests
-	var manifests []interface{}
-	for _, r := range resources {
-		manifest := createManifestFromResource(r)
-		manifests = append(manifests, manifest)
+// buildErrorResult returns a failed Result for an error from discovery or
+// validation, with an Error for each problem at the file and line it
+// concerns: the declaration of each resource in a build.ValidationError, or
+// the position of each syntax error in source that does not parse. Other
+// errors are reported at path.
+func buildErrorResult(message, path string, err error) *Result {
+	var validationErr *build.ValidationError
+	if errors.As(err, &validationErr) {
+		errs := make([]Error, len(validationErr.Errors))
+		for i, e := range validationErr.Errors {
+			errs[i] = Error{
+				Path:     e.File,
+				Line:     e.Line,
+				Severity: "error",
+				Message:  e.Message,
+				Code:     e.Code,
+			}
+			if e.File == "" {
+				errs[i].Path = path
+			}
+		}
+		return NewErrorResultMultiple(message, errs)
 	}
-	return serialize.ToMultiYAML(manifests)
-}
 
-// serializeToJSON serializes resources to JSON format
-func serializeToJSON(resources []discover.Resource) ([]byte, error) {
-	// Convert resources to manifests
-	var manifests []interface{}
-	for _, r := range resources {
-		manifest := createManifestFromResource(r)
-		manifests = append(manifests, manifest)
+	var syntaxErrs scanner.ErrorList
+	if errors.As(err, &syntaxErrs) {
+		errs := make([]Error, len(syntaxErrs))
+		for i, e := range syntaxErrs {
+			errs[i] = Error{
+				Path:     e.Pos.Filename,
+				Line:     e.Pos.Line,
+				Column:   e.Pos.Column,
+				Severity: "error",
+				Message:  e.Msg,
+				Code:     "syntax-error",
+			}
+		}
+		return NewErrorResultMultiple(message, errs)
 	}
 
-	if len(manifests) == 0 {
-		return []byte("[]"), nil
+	return NewErrorResult(message, Error{
+		Path:     path,
+		Severity: "error",
+		Message:  err.Error(),
+	})
+}
+
+// discoverOverlay discovers and validates the resources of an overlay
+// package. Problems in the overlay are returned as an error result.
+func discoverOverlay(path string) ([]discover.Resource, *Result, error) {
+	resources, err := discoverResources(path)
+	if err != nil {
+		var syntaxErrs scanner.ErrorList
+		if errors.As(err, &syntaxErrs) {
+			return nil, buildErrorResult("overlay discovery failed", path, err), nil
+		}
+		return nil, nil, fmt.Errorf("overlay discovery failed: %w", err)
+	}
+	if err := build.ValidateReferences(resources); err != nil {
+		return nil, buildErrorResult("overlay validation failed", path, err), nil
+	}
+	if err := build.DetectCycles(resources); err != nil {
+		return nil, buildErrorResult("cycle detected in overlay", path, err), nil
+	}
+	if err := build.ValidateNames(resources); err != nil {
+		return nil, buildErrorResult("invalid resource names in overlay", path, err), nil
 	}
+	return resources, nil, nil
+}
 
-	if len(manifests) == 1 {
-		return serialize.ToJSON(manifests[0])
+// resourceManifests evaluates resources, reading them from source when it is
+// not nil, and returns the manifest of each one, patched by the overlay
+// resource in patches that declares the same object, if any. See
+// build.Manifests.
+func resourceManifests(resources []discover.Resource, patches map[string]discover.Resource, source []byte) ([]interface{}, error) {
+	evaluated, err := build.Manifests(resources, source)
+	if err != nil {
+		return nil, err
 	}
 
-	// For multiple resources, create a JSON array
-	var result []byte
-	result = append(result, '[')
-	for i, m := range manifests {
-		if i > 0 {
-			result = append(result, ',', '\n')
+	var patched []int
+	var overlay []discover.Resource
+	for i, r := range resources {
+		if patch, ok := patches[r.Name]; ok {
+			patched = append(patched, i)
+			overlay = append(overlay, patch)
 		}
-		jsonBytes, err := serialize.ToJSON(m)
-		if err != nil {
-			return nil, err
+	}
+	patchManifests, err := build.Manifests(overlay, nil)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range patched {
+		evaluated[i] = build.PatchManifest(evaluated[i], patchManifests[j])
+	}
+
+	manifests := make([]interface{}, len(evaluated))
+	for i, manifest := range evaluated {
+		manifests[i] = manifest
+	}
+	return manifests, nil
+}
+
+// serializedManifests returns manifests as serialize.Manifest values, so that
+// serializing them keeps the zero values their typed resources keep.
+func serializedManifests(manifests []interface{}) []interface{} {
+	serialized := make([]interface{}, len(manifests))
+	for i, manifest := range manifests {
+		if m, ok := manifest.(map[string]interface{}); ok {
+			serialized[i] = serialize.Manifest(m)
+		} else {
+			serialized[i] = manifest
 		}
-		result = append(result, jsonBytes...)
 	}
-	result = append(result, ']')
-	return result, nil
+	return serialized
 }
 
-// createManifestFromResource creates a basic manifest map from discovered resource
-func createManifestFromResource(r discover.Resource) map[string]interface{} {
-	// Parse the resource type to determine apiVersion and kind
-	apiVersion, kind := parseResourceType(r.Type)
+// serializeToYAML serializes manifests to YAML format
+func serializeToYAML(manifests []interface{}, opts serialize.YAMLOptions) ([]byte, error) {
+	return serialize.ToMultiYAMLWithOptions(manifests, opts)
+}
 
-	// Create a manifest with the discovered information
-	manifest := map[string]interface{}{
-		"apiVersion": apiVersion,
-		"kind":       kind,
-		"metadata": map[string]interface{}{
-			"name": toKubernetesName(r.Name),
-		},
+// serializeToJSON serializes manifests to JSON format: a single manifest as
+// an object and several as an array, or a List object with opts.List.
+func serializeToJSON(manifests []interface{}, opts serialize.JSONOptions) ([]byte, error) {
+	if len(manifests) == 1 && !opts.List {
+		return serialize.ToJSON(manifests[0])
 	}
-
-	return manifest
+	return serialize.ToMultiJSON(manifests, opts)
 }
 
 // parseResourceType extracts apiVersion and kind from a Go type string
@@ -584,21 +1216,111 @@ func mapPackageToAPIVersion(pkg string) string {
 	return "v1"
 }
 
-// toKubernetesName converts a Go variable name to a Kubernetes resource name
-// e.g., "MyDeployment" -> "my-deployment"
-func toKubernetesName(name string) string {
-	var result strings.Builder
-	for i, r := range name {
-		if i > 0 && r >= 'A' && r <= 'Z' {
-			result.WriteRune('-')
+// transitiveDependencies returns, for each resource, the resources it
+// depends on only indirectly: those reachable through its dependencies that
+// are not direct dependencies themselves. Each list is sorted.
+func transitiveDependencies(resources []discover.Resource) map[string][]string {
+	deps := make(map[string][]string, len(resources))
+	for _, r := range resources {
+		deps[r.Name] = r.Dependencies
+	}
+
+	indirect := make(map[string][]string)
+	for _, r := range resources {
+		direct := make(map[string]bool)
+		for _, dep := range r.Dependencies {
+			direct[dep] = true
+		}
+
+		// Walk the graph from the direct dependencies; visited guards
+		// against cycles
+		visited := map[string]bool{r.Name: true}
+		stack := append([]string(nil), r.Dependencies...)
+		for len(stack) > 0 {
+			name := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+			if _, ok := deps[name]; !ok {
+				continue
+			}
+			if !direct[name] {
+				indirect[r.Name] = append(indirect[r.Name], name)
+			}
+			stack = append(stack, deps[name]...)
 		}
-		result.WriteRune(r)
+		sort.Strings(indirect[r.Name])
 	}
-	return strings.ToLower(result.String())
+	return indirect
 }
 
-// generateDOTGraph generates a DOT format dependency graph
-func generateDOTGraph(resources []discover.Resource) string {
+// collapseHelpers removes helper values from the graph, keeping only
+// top-level resources. A resource that depends on a helper depends instead on
+// the resources the helper refers to, directly or through other helpers, and
+// external references made by a helper are attributed to the resources that
+// use it.
+func collapseHelpers(resources []discover.Resource, external []externalRef) ([]discover.Resource, []externalRef) {
+	byName := make(map[string]discover.Resource, len(resources))
+	for _, r := range resources {
+		byName[r.Name] = r
+	}
+	isHelper := func(name string) bool {
+		r, ok := byName[name]
+		return ok && discover.IsHelper(r)
+	}
+
+	var collapsed []discover.Resource
+	users := make(map[string][]string) // helper -> resources that use it
+	for _, r := range resources {
+		if discover.IsHelper(r) {
+			continue
+		}
+
+		var deps []string
+		seen := map[string]bool{r.Name: true}
+		stack := append([]string(nil), r.Dependencies...)
+		for len(stack) > 0 {
+			name := stack[0]
+			stack = stack[1:]
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if isHelper(name) {
+				users[name] = append(users[name], r.Name)
+				stack = append(stack, byName[name].Dependencies...)
+				continue
+			}
+			deps = append(deps, name)
+		}
+
+		r.Dependencies = deps
+		collapsed = append(collapsed, r)
+	}
+
+	var refs []externalRef
+	seen := make(map[externalRef]bool)
+	for _, ref := range external {
+		sources := []string{ref.source}
+		if isHelper(ref.source) {
+			sources = users[ref.source]
+		}
+		for _, source := range sources {
+			ref.source = source
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return collapsed, refs
+}
+
+// generateDOTGraph generates a DOT format dependency graph. Indirect
+// dependencies, when given, are drawn as dotted gray edges.
+func generateDOTGraph(resources []discover.Resource, external []externalRef, indirect map[string][]string) string {
 	var b strings.Builder
 	b.WriteString("digraph dependencies {\n")
 	b.WriteString("  rankdir=TB;\n")
@@ -610,10 +1332,21 @@ func generateDOTGraph(resources []discover.Resource) string {
 		resourceSet[r.Name] = true
 	}
 
-	// Output nodes
-	for _, r := range resources {
-		label := fmt.Sprintf("%s\\n(%s)", r.Name, r.Type)
-		fmt.Fprintf(&b, "  \"%s\" [label=\"%s\"];\n", r.Name, label)
+	// Output nodes, clustered by namespace
+	namespaces, groups := groupByNamespace(resources)
+	for _, ns := range namespaces {
+		fmt.Fprintf(&b, "  subgraph cluster_%s {\n", graphID(ns))
+		fmt.Fprintf(&b, "    label=\"%s\";\n", ns)
+		for _, r := range groups[ns] {
+			label := fmt.Sprintf("%s\\n(%s)", r.Name, r.Type)
+			fmt.Fprintf(&b, "    \"%s\" [label=\"%s\"];\n", r.Name, label)
+		}
+		b.WriteString("  }\n")
+	}
+
+	// Output external nodes outside of any namespace cluster
+	for _, node := range externalNodes(external) {
+		fmt.Fprintf(&b, "  \"%s\" [label=\"%s\\n(%s)\", style=dashed, color=red];\n", node.id, node.name, node.label())
 	}
 
 	b.WriteString("\n")
@@ -626,13 +1359,22 @@ func generateDOTGraph(resources []discover.Resource) string {
 			}
 		}
 	}
+	for _, r := range resources {
+		for _, dep := range indirect[r.Name] {
+			fmt.Fprintf(&b, "  \"%s\" -> \"%s\" [style=dotted, color=gray];\n", r.Name, dep)
+		}
+	}
+	for _, ref := range external {
+		fmt.Fprintf(&b, "  \"%s\" -> \"%s\" [style=dashed, color=red];\n", ref.source, ref.id)
+	}
 
 	b.WriteString("}")
 	return b.String()
 }
 
-// generateMermaidGraph generates a Mermaid format dependency graph
-func generateMermaidGraph(resources []discover.Resource) string {
+// generateMermaidGraph generates a Mermaid format dependency graph. Indirect
+// dependencies, when given, are drawn as dotted edges labeled "indirect".
+func generateMermaidGraph(resources []discover.Resource, external []externalRef, indirect map[string][]string) string {
 	var b strings.Builder
 	b.WriteString("graph TD\n")
 
@@ -642,9 +1384,20 @@ func generateMermaidGraph(resources []discover.Resource) string {
 		resourceSet[r.Name] = true
 	}
 
-	// Output nodes
-	for _, r := range resources {
-		fmt.Fprintf(&b, "  %s[%s: %s]\n", r.Name, r.Name, r.Type)
+	// Output nodes, grouped by namespace
+	namespaces, groups := groupByNamespace(resources)
+	for _, ns := range namespaces {
+		fmt.Fprintf(&b, "  subgraph ns_%s [%s]\n", graphID(ns), ns)
+		for _, r := range groups[ns] {
+			fmt.Fprintf(&b, "    %s[%s: %s]\n", r.Name, r.Name, r.Type)
+		}
+		b.WriteString("  end\n")
+	}
+
+	// Output external nodes outside of any namespace subgraph
+	nodes := externalNodes(external)
+	for _, node := range nodes {
+		fmt.Fprintf(&b, "  %s[%s: %s]:::external\n", graphID(node.id), node.name, node.label())
 	}
 
 	// Output edges
@@ -655,6 +1408,208 @@ func generateMermaidGraph(resources []discover.Resource) string {
 			}
 		}
 	}
+	for _, r := range resources {
+		for _, dep := range indirect[r.Name] {
+			fmt.Fprintf(&b, "  %s -. indirect .-> %s\n", r.Name, dep)
+		}
+	}
+	for _, ref := range external {
+		fmt.Fprintf(&b, "  %s -.-> %s\n", ref.source, graphID(ref.id))
+	}
+
+	if len(nodes) > 0 {
+		b.WriteString("  classDef external stroke:#d33,stroke-dasharray:5 5\n")
+	}
+
+	return b.String()
+}
+
+// graphNode is a resource in the JSON dependency graph.
+type graphNode struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Namespace string `json:"namespace,omitempty"`
+	File      string `json:"file,omitempty"`
+	Line      int    `json:"line,omitempty"`
+}
+
+// graphEdge is a dependency between two resources in the JSON graph.
+type graphEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Kind   string `json:"kind"`
+}
+
+// generateJSONGraph generates a JSON dependency graph with nodes and edges.
+// Indirect dependencies, when given, are edges of kind "transitive".
+func generateJSONGraph(resources []discover.Resource, external []externalRef, indirect map[string][]string) (string, error) {
+	graph := struct {
+		Nodes []graphNode `json:"nodes"`
+		Edges []graphEdge `json:"edges"`
+	}{
+		Nodes: []graphNode{},
+		Edges: []graphEdge{},
+	}
+
+	// Build resource set
+	resourceSet := make(map[string]bool)
+	for _, r := range resources {
+		resourceSet[r.Name] = true
+	}
+
+	for _, r := range resources {
+		graph.Nodes = append(graph.Nodes, graphNode{
+			Name:      r.Name,
+			Type:      r.Type,
+			Namespace: r.Namespace,
+			File:      r.File,
+			Line:      r.Line,
+		})
+
+		// Sort dependencies so edge order is stable across runs
+		deps := append([]string(nil), r.Dependencies...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if resourceSet[dep] {
+				graph.Edges = append(graph.Edges, graphEdge{
+					Source: r.Name,
+					Target: dep,
+					Kind:   "reference",
+				})
+			}
+		}
+		for _, dep := range indirect[r.Name] {
+			graph.Edges = append(graph.Edges, graphEdge{
+				Source: r.Name,
+				Target: dep,
+				Kind:   "transitive",
+			})
+		}
+	}
+
+	for _, node := range externalNodes(external) {
+		graph.Nodes = append(graph.Nodes, graphNode{
+			Name: node.id,
+			Type: node.label(),
+		})
+	}
+	for _, ref := range external {
+		graph.Edges = append(graph.Edges, graphEdge{
+			Source: ref.source,
+			Target: ref.id,
+			Kind:   "external",
+		})
+	}
 
+	data, err := json.MarshalIndent(graph, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// externalRef is a reference from a discovered resource to an object that is
+// not defined in the project.
+type externalRef struct {
+	source string // Referencing resource variable name
+	id     string // Synthetic node ID, e.g. "external:Secret/db-credentials"
+	name   string // Referenced name
+	kind   string // Referenced kind, empty for Go variable references
+}
+
+func (r externalRef) label() string {
+	if r.kind == "" {
+		return "external"
+	}
+	return r.kind + ", external"
+}
+
+// findExternalRefs returns the references that do not resolve to a discovered
+// resource: Go variables that are not resources, and objects referenced by
+// metadata name (Secrets, ConfigMaps, PVCs) that are not defined.
+func findExternalRefs(resources []discover.Resource) []externalRef {
+	resourceSet := make(map[string]bool)
+	objectSet := make(map[discover.NameRef]bool)
+	for _, r := range resources {
+		resourceSet[r.Name] = true
+		if r.MetadataName != "" {
+			_, kind := parseResourceType(r.Type)
+			objectSet[discover.NameRef{Kind: kind, Name: r.MetadataName}] = true
+		}
+	}
+
+	var refs []externalRef
+	for _, r := range resources {
+		deps := append([]string(nil), r.Dependencies...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if !resourceSet[dep] {
+				refs = append(refs, externalRef{
+					source: r.Name,
+					id:     "external:" + dep,
+					name:   dep,
+				})
+			}
+		}
+		for _, ref := range r.NameRefs {
+			if !objectSet[ref] {
+				refs = append(refs, externalRef{
+					source: r.Name,
+					id:     fmt.Sprintf("external:%s/%s", ref.Kind, ref.Name),
+					name:   ref.Name,
+					kind:   ref.Kind,
+				})
+			}
+		}
+	}
+	return refs
+}
+
+// externalNodes returns one reference per distinct external node, in order
+// of first appearance.
+func externalNodes(refs []externalRef) []externalRef {
+	seen := make(map[string]bool)
+	var nodes []externalRef
+	for _, ref := range refs {
+		if !seen[ref.id] {
+			seen[ref.id] = true
+			nodes = append(nodes, ref)
+		}
+	}
+	return nodes
+}
+
+// groupByNamespace groups resources by namespace, returning the sorted
+// namespace names alongside the groups. Resources without a namespace are
+// placed in "default".
+func groupByNamespace(resources []discover.Resource) ([]string, map[string][]discover.Resource) {
+	groups := make(map[string][]discover.Resource)
+	for _, r := range resources {
+		ns := r.Namespace
+		if ns == "" {
+			ns = "default"
+		}
+		groups[ns] = append(groups[ns], r)
+	}
+
+	namespaces := make([]string, 0, len(groups))
+	for ns := range groups {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces, groups
+}
+
+// graphID converts a namespace into an identifier usable in DOT and Mermaid
+// e.g., "team-alpha" -> "team_alpha"
+func graphID(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
 	return b.String()
 }
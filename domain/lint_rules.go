@@ -0,0 +1,35 @@
+package domain
+
+import "github.com/lex00/wetwire-k8s-go/internal/lint"
+
+// LintRule is a lint rule; see RegisterLintRule.
+type LintRule = lint.Rule
+
+// LintRuleExample shows code that violates a lint rule and code that
+// satisfies it.
+type LintRuleExample = lint.RuleExample
+
+// LintVisitor checks a lint rule node by node; see LintRule.Visit.
+type LintVisitor = lint.Visitor
+
+// LintIssue is a problem reported by a lint rule.
+type LintIssue = lint.Issue
+
+// LintSeverity is the severity of a lint rule or issue.
+type LintSeverity = lint.Severity
+
+// Lint severities.
+const (
+	LintSeverityError   = lint.SeverityError
+	LintSeverityWarning = lint.SeverityWarning
+	LintSeverityInfo    = lint.SeverityInfo
+)
+
+// RegisterLintRule adds a custom rule to the rules run by lint, so that
+// programs embedding wetwire-k8s can add organization-specific checks without
+// forking it. Rules are configured like the built-in ones: .wetwire.yaml and
+// --disable refer to them by ID. It panics if the rule has no ID, neither a
+// Check nor a Visit function, or an ID that is already registered.
+func RegisterLintRule(rule LintRule) {
+	lint.Register(rule)
+}
@@ -0,0 +1,212 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+	"gopkg.in/yaml.v3"
+)
+
+// K8sListOpts extends ListOpts with k8s-specific filters.
+type K8sListOpts struct {
+	ListOpts
+
+	// Kind lists only resources of this kind, e.g. "Deployment". The match
+	// is case-insensitive. ListOpts.Type is used when Kind is empty.
+	Kind string
+
+	// Namespace lists only resources whose metadata.namespace is set to
+	// this namespace.
+	Namespace string
+
+	// PruneHelpers leaves helper values, such as a Container or PodSpec
+	// declared as its own variable, out of the list. Resources that use a
+	// helper are listed as depending on what the helper refers to.
+	PruneHelpers bool
+}
+
+// ListWithOptions lists the resources at path using k8s-specific options.
+func (d *K8sDomain) ListWithOptions(ctx *Context, path string, opts K8sListOpts) (*Result, error) {
+	return (&k8sLister{}).list(ctx, path, opts)
+}
+
+// k8sLister implements domain.Lister
+type k8sLister struct{}
+
+func (l *k8sLister) List(ctx *Context, path string, opts ListOpts) (*Result, error) {
+	return l.list(ctx, path, K8sListOpts{ListOpts: opts})
+}
+
+func (l *k8sLister) list(ctx *Context, path string, opts K8sListOpts) (*Result, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolve path: %w", err)
+	}
+
+	// Discover all resources
+	resources, err := discoverResources(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("discovery failed: %w", err)
+	}
+	if opts.PruneHelpers {
+		resources, _ = collapseHelpers(resources, nil)
+	}
+
+	kind := opts.Kind
+	if kind == "" {
+		kind = opts.Type
+	}
+
+	// Build list, flagging resources that declare the same object as an
+	// earlier one: same type, metadata name and namespace
+	list := make([]map[string]any, 0)
+	declared := make(map[objectKey]string)
+	duplicates := 0
+	for _, r := range resources {
+		if !matchesListFilter(r, kind, opts.Namespace) {
+			continue
+		}
+
+		_, resourceKind := parseResourceType(r.Type)
+		item := map[string]any{
+			"name": r.Name,
+			"kind": resourceKind,
+			"type": r.Type,
+			"file": r.File,
+			"line": r.Line,
+		}
+		if r.Namespace != "" {
+			item["namespace"] = r.Namespace
+		}
+		if len(r.Dependencies) > 0 {
+			item["dependencies"] = r.Dependencies
+		}
+		if r.Doc != "" {
+			item["description"] = r.Doc
+		}
+		if r.MetadataName != "" {
+			key := objectKey{r.Type, r.Namespace, r.MetadataName}
+			if first, ok := declared[key]; ok {
+				item["duplicateOf"] = first
+				duplicates++
+			} else {
+				declared[key] = r.Name
+			}
+		}
+		list = append(list, item)
+	}
+
+	message := fmt.Sprintf("Discovered %d resources", len(list))
+	if duplicates > 0 {
+		message += fmt.Sprintf(" (%d duplicates)", duplicates)
+	}
+	return NewResultWithData(message, list), nil
+}
+
+// objectKey identifies the Kubernetes object a resource declares. Objects
+// with the same name in different namespaces are distinct.
+type objectKey struct {
+	typ, namespace, name string
+}
+
+// matchesListFilter reports whether a resource has the given kind and
+// namespace. Empty filters match every resource.
+func matchesListFilter(r discover.Resource, kind, namespace string) bool {
+	if kind != "" {
+		if _, resourceKind := parseResourceType(r.Type); !strings.EqualFold(resourceKind, kind) {
+			return false
+		}
+	}
+	return namespace == "" || r.Namespace == namespace
+}
+
+// ListOutputFormats are the output formats supported by RenderList.
+var ListOutputFormats = []string{"json", "yaml", "table"}
+
+// RenderList renders the resources returned by List: as a JSON or YAML
+// array, or as a table with NAME, KIND, NAMESPACE and FILE columns, and a
+// DESCRIPTION column when a resource has a doc comment.
+func RenderList(list []map[string]any, output string) (string, error) {
+	switch output {
+	case "json":
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshal list: %w", err)
+		}
+		return string(data) + "\n", nil
+	case "yaml":
+		data, err := yaml.Marshal(list)
+		if err != nil {
+			return "", fmt.Errorf("marshal list: %w", err)
+		}
+		return string(data), nil
+	case "table":
+		// Show file names relative to the working directory
+		baseDir, _ := os.Getwd()
+		return renderListTable(list, baseDir), nil
+	default:
+		return "", fmt.Errorf("unknown output format %q (supported: %s)", output, strings.Join(ListOutputFormats, ", "))
+	}
+}
+
+// renderListTable renders resources as a table with aligned columns. File
+// names under baseDir are shown relative to it. The DESCRIPTION column, the
+// first line of each doc comment, is left out when no resource has one.
+func renderListTable(list []map[string]any, baseDir string) string {
+	described := false
+	for _, item := range list {
+		if description, _ := item["description"].(string); description != "" {
+			described = true
+		}
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	if described {
+		fmt.Fprintln(w, "NAME\tKIND\tNAMESPACE\tFILE\tDESCRIPTION")
+	} else {
+		fmt.Fprintln(w, "NAME\tKIND\tNAMESPACE\tFILE")
+	}
+
+	for _, item := range list {
+		name, _ := item["name"].(string)
+		kind, _ := item["kind"].(string)
+		namespace, _ := item["namespace"].(string)
+		file, _ := item["file"].(string)
+		line, _ := item["line"].(int)
+
+		if baseDir != "" {
+			if rel, err := filepath.Rel(baseDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+				file = rel
+			}
+		}
+		if line > 0 {
+			file = fmt.Sprintf("%s:%d", file, line)
+		}
+
+		if described {
+			description, _ := item["description"].(string)
+			description, _, _ = strings.Cut(description, "\n")
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, kind, namespace, file, description)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, kind, namespace, file)
+		}
+	}
+
+	w.Flush()
+	if !described {
+		return buf.String()
+	}
+	// Undocumented resources leave the padding of the FILE column behind
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeListFixture writes a package with resources in two namespaces.
+func writeListFixture(t *testing.T) string {
+	t.Helper()
+	tempDir := t.TempDir()
+	content := `package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var WebDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-alpha"},
+}
+
+var WebService = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-alpha"},
+}
+
+var WorkerDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "team-beta"},
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "app.go"), []byte(content), 0644))
+	return tempDir
+}
+
+func TestK8sLister_List_Namespaces(t *testing.T) {
+	tempDir := t.TempDir()
+	content := `package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var AlphaApp = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-alpha"},
+}
+
+var BetaApp = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-beta"},
+}
+
+var BetaAppCopy = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-beta"},
+}
+`
+	err := os.WriteFile(filepath.Join(tempDir, "app.go"), []byte(content), 0644)
+	require.NoError(t, err)
+
+	result, err := (&K8sDomain{}).Lister().List(&Context{}, tempDir, ListOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, "Discovered 3 resources (1 duplicates)", result.Message)
+
+	list, ok := result.Data.([]map[string]any)
+	require.True(t, ok)
+	require.Len(t, list, 3)
+
+	assert.Equal(t, "AlphaApp", list[0]["name"])
+	assert.Equal(t, "team-alpha", list[0]["namespace"])
+	assert.NotContains(t, list[0], "duplicateOf")
+
+	assert.Equal(t, "BetaApp", list[1]["name"])
+	assert.Equal(t, "team-beta", list[1]["namespace"])
+	assert.NotContains(t, list[1], "duplicateOf", "same name in another namespace is not a duplicate")
+
+	assert.Equal(t, "BetaAppCopy", list[2]["name"])
+	assert.Equal(t, "BetaApp", list[2]["duplicateOf"])
+}
+
+func TestK8sDomain_ListWithOptions_Filters(t *testing.T) {
+	dir := writeListFixture(t)
+	d := &K8sDomain{}
+
+	names := func(opts K8sListOpts) []string {
+		t.Helper()
+		result, err := d.ListWithOptions(&Context{}, dir, opts)
+		require.NoError(t, err)
+		var names []string
+		for _, item := range result.Data.([]map[string]any) {
+			names = append(names, item["name"].(string))
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"WebDeployment", "WebService", "WorkerDeployment"}, names(K8sListOpts{}))
+	assert.Equal(t, []string{"WebDeployment", "WorkerDeployment"}, names(K8sListOpts{Kind: "deployment"}))
+	assert.Equal(t, []string{"WebDeployment", "WebService"}, names(K8sListOpts{Namespace: "team-alpha"}))
+	assert.Equal(t, []string{"WorkerDeployment"}, names(K8sListOpts{Kind: "Deployment", Namespace: "team-beta"}))
+	assert.Empty(t, names(K8sListOpts{Kind: "ConfigMap"}))
+
+	// The core --type filter selects kinds when Kind is not set
+	assert.Equal(t, []string{"WebService"}, names(K8sListOpts{ListOpts: ListOpts{Type: "Service"}}))
+}
+
+func TestRenderListTable(t *testing.T) {
+	list := []map[string]any{
+		{"name": "WebDeployment", "kind": "Deployment", "namespace": "team-alpha", "file": "/src/k8s/app.go", "line": 9},
+		{"name": "Cfg", "kind": "ConfigMap", "file": "/src/k8s/config.go", "line": 3},
+		{"name": "Outside", "kind": "Secret", "file": "/other/secret.go", "line": 12},
+	}
+
+	table := renderListTable(list, "/src")
+	assert.Equal(t, ""+
+		"NAME            KIND         NAMESPACE    FILE\n"+
+		"WebDeployment   Deployment   team-alpha   k8s/app.go:9\n"+
+		"Cfg             ConfigMap                 k8s/config.go:3\n"+
+		"Outside         Secret                    /other/secret.go:12\n", table)
+
+	list[1]["description"] = "Cfg configures the app.\n\nIt is mounted by every pod."
+	table = renderListTable(list, "/src")
+	assert.Equal(t, ""+
+		"NAME            KIND         NAMESPACE    FILE                  DESCRIPTION\n"+
+		"WebDeployment   Deployment   team-alpha   k8s/app.go:9\n"+
+		"Cfg             ConfigMap                 k8s/config.go:3       Cfg configures the app.\n"+
+		"Outside         Secret                    /other/secret.go:12\n", table)
+
+}
+
+func TestRenderList_Formats(t *testing.T) {
+	list := []map[string]any{{"name": "Web", "type": "appsv1.Deployment"}}
+
+	out, err := RenderList(list, "json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"name": "Web", "type": "appsv1.Deployment"}]`, out)
+
+	out, err = RenderList(list, "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "- name: Web\n  type: appsv1.Deployment\n", out)
+
+	_, err = RenderList(list, "csv")
+	assert.ErrorContains(t, err, `unknown output format "csv"`)
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+	"github.com/lex00/wetwire-k8s-go/internal/serialize"
+)
+
+// serializeWithProvenance serializes resources to multi-document YAML like
+// serializeToYAML, but starts the output with a comment naming the generator
+// and the source package, and each document with a "# source: file:line"
+// comment for the resource it was built from, followed by the doc comment of
+// its variable. Source files are shown relative to root, the path that was
+// built.
+func serializeWithProvenance(root string, resources []discover.Resource, manifests []interface{}, opts serialize.YAMLOptions) ([]byte, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by wetwire-k8s from %s at %s; do not edit\n", sourcePackage(resources), Version)
+
+	docOpts := serialize.YAMLOptions{Indent: opts.Indent}
+	for i, r := range resources {
+		doc, err := serialize.ToYAMLWithOptions(manifests[i], docOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize resource %d: %w", i, err)
+		}
+
+		if i > 0 || opts.LeadingSeparator {
+			b.WriteString("---\n")
+		}
+		fmt.Fprintf(&b, "# source: %s:%d\n", sourcePath(root, r.File), r.Line)
+		for _, line := range docLines(r.Doc) {
+			b.WriteString(strings.TrimSpace("# "+line) + "\n")
+		}
+		b.WriteString(strings.TrimSpace(string(doc)))
+		if i < len(resources)-1 {
+			b.WriteString("\n")
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+// docLines returns the lines of a resource's doc comment, or nil if it has
+// none.
+func docLines(doc string) []string {
+	if doc == "" {
+		return nil
+	}
+	return strings.Split(doc, "\n")
+}
+
+// sourcePackage returns the Go package name declared by the file of the
+// first resource, or "unknown package" if it cannot be read.
+func sourcePackage(resources []discover.Resource) string {
+	if len(resources) == 0 {
+		return "unknown package"
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), resources[0].File, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return "unknown package"
+	}
+	return "package " + f.Name.Name
+}
+
+// sourcePath returns file relative to root, or relative to the directory of
+// root when root is a file. The path is returned unchanged if it is not
+// under root.
+func sourcePath(root, file string) string {
+	if file == root {
+		return filepath.Base(file)
+	}
+	if info, err := os.Stat(root); err == nil && !info.IsDir() {
+		root = filepath.Dir(root)
+	}
+	rel, err := filepath.Rel(root, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
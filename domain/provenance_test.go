@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestK8sDomain_BuildWithOptions_Provenance(t *testing.T) {
+	tempDir := t.TempDir()
+	content := `package web
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var AppConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+}
+
+// AppService exposes the app.
+//
+// It is internal only.
+var AppService = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-service"},
+}
+`
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "k8s"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "k8s", "app.go"), []byte(content), 0644))
+
+	prev := Version
+	Version = "v1.2.3"
+	defer func() { Version = prev }()
+
+	d := &K8sDomain{}
+	result, err := d.BuildWithOptions(&Context{}, tempDir, K8sBuildOpts{
+		BuildOpts:  BuildOpts{Format: "yaml"},
+		Provenance: true,
+	})
+	require.NoError(t, err)
+
+	expected := `# Generated by wetwire-k8s from package web at v1.2.3; do not edit
+# source: k8s/app.go:8
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+---
+# source: k8s/app.go:15
+# AppService exposes the app.
+#
+# It is internal only.
+apiVersion: v1
+kind: Service
+metadata:
+  name: app-service`
+	assert.Equal(t, expected, result.Data)
+
+	t.Run("leading separator", func(t *testing.T) {
+		result, err := d.BuildWithOptions(&Context{}, tempDir, K8sBuildOpts{
+			BuildOpts:        BuildOpts{Format: "yaml"},
+			Provenance:       true,
+			LeadingSeparator: true,
+		})
+		require.NoError(t, err)
+		output := result.Data.(string)
+		assert.True(t, strings.HasPrefix(output, "# Generated by wetwire-k8s from package web at v1.2.3; do not edit\n---\n# source: k8s/app.go:8\n"))
+		assert.Equal(t, 2, strings.Count(output, "---\n"))
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		result, err := d.BuildWithOptions(&Context{}, filepath.Join(tempDir, "k8s", "app.go"), K8sBuildOpts{
+			BuildOpts:  BuildOpts{Format: "yaml"},
+			Provenance: true,
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result.Data, "# source: app.go:15\n# AppService exposes the app.\n")
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		result, err := d.BuildWithOptions(&Context{}, tempDir, K8sBuildOpts{
+			BuildOpts: BuildOpts{Format: "yaml"},
+		})
+		require.NoError(t, err)
+		assert.NotContains(t, result.Data, "#")
+	})
+}
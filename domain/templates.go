@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// templates holds the project templates for init --template, one directory
+// per template. Files are stored with a .tmpl suffix so that they are not
+// compiled as part of this package.
+//
+//go:embed templates
+var templates embed.FS
+
+// InitTemplates returns the names of the templates init --template accepts,
+// in sorted order.
+func InitTemplates() []string {
+	entries, _ := templates.ReadDir("templates")
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkTemplate returns an error naming the available templates if name is
+// not one of them.
+func checkTemplate(name string) error {
+	for _, t := range InitTemplates() {
+		if t == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown template %q (available: %s)", name, strings.Join(InitTemplates(), ", "))
+}
+
+// writeTemplate writes the files of the named template into dir, without
+// their .tmpl suffix, and returns their names.
+func writeTemplate(name, dir string) ([]string, error) {
+	root := path.Join("templates", name)
+	var written []string
+	err := fs.WalkDir(templates, root, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		content, err := templates.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel := strings.TrimSuffix(strings.TrimPrefix(p, root+"/"), ".tmpl")
+		target := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			return err
+		}
+		written = append(written, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("write template %s: %w", name, err)
+	}
+	return written, nil
+}
@@ -105,9 +105,10 @@ var BatchProcessorJob = batchv1.Job{
 		},
 	},
 	Spec: batchv1.JobSpec{
-		Completions:  ptr(int32(10)),
-		Parallelism:  ptr(int32(3)),
-		BackoffLimit: ptr(int32(5)),
+		Completions:             ptr(int32(10)),
+		Parallelism:             ptr(int32(3)),
+		BackoffLimit:            ptr(int32(5)),
+		TTLSecondsAfterFinished: ptr(int32(3600)),
 		Template: corev1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
 				Labels: map[string]string{
@@ -163,10 +164,11 @@ var ReportGeneratorJob = batchv1.Job{
 		},
 	},
 	Spec: batchv1.JobSpec{
-		CompletionMode: ptr(batchv1.IndexedCompletion),
-		Completions:    ptr(int32(5)),
-		Parallelism:    ptr(int32(5)),
-		BackoffLimit:   ptr(int32(2)),
+		CompletionMode:          ptr(batchv1.IndexedCompletion),
+		Completions:             ptr(int32(5)),
+		Parallelism:             ptr(int32(5)),
+		BackoffLimit:            ptr(int32(2)),
+		TTLSecondsAfterFinished: ptr(int32(3600)),
 		Template: corev1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
 				Labels: map[string]string{
@@ -0,0 +1,50 @@
+package build
+
+import "github.com/lex00/wetwire-k8s-go/internal/discover"
+
+// AddApplyOrderDependencies returns a copy of resources in which every
+// resource also depends on the resources it refers to by metadata name rather
+// than by Go variable: ConfigMaps and Secrets used through configMapKeyRef,
+// secretKeyRef, envFrom or volumes, PersistentVolumeClaims mounted as volumes
+// and the pod's serviceAccountName. Sorting the result applies those objects
+// before the workloads that use them.
+//
+// A reference matches a resource of the referenced kind with the same
+// metadata name in the same namespace; an unset namespace matches any
+// namespace. References to objects that are not defined are ignored.
+func AddApplyOrderDependencies(resources []discover.Resource) []discover.Resource {
+	result := make([]discover.Resource, len(resources))
+	for i, r := range resources {
+		r.Dependencies = append([]string(nil), r.Dependencies...)
+		for _, ref := range r.NameRefs {
+			for _, target := range resources {
+				if target.Name != r.Name && refersTo(r, ref, target) && !contains(r.Dependencies, target.Name) {
+					r.Dependencies = append(r.Dependencies, target.Name)
+				}
+			}
+		}
+		result[i] = r
+	}
+	return result
+}
+
+// refersTo reports whether ref, made from resource from, names target.
+func refersTo(from discover.Resource, ref discover.NameRef, target discover.Resource) bool {
+	if target.MetadataName != ref.Name {
+		return false
+	}
+	if _, kind := resourceAPIVersionKind(target.Type); kind != ref.Kind {
+		return false
+	}
+	return from.Namespace == "" || target.Namespace == "" || from.Namespace == target.Namespace
+}
+
+// contains reports whether names contains name.
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
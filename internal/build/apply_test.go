@@ -0,0 +1,150 @@
+package build_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeNameRefExample writes a copy of examples/configmap-secret in which the
+// Deployment refers to its ConfigMaps and Secrets by literal name instead of
+// through their Go variables.
+func writeNameRefExample(t *testing.T) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join("..", "..", "examples", "configmap-secret", "main.go"))
+	require.NoError(t, err)
+
+	src := strings.NewReplacer(
+		"AppConfig.Name", `"app-config"`,
+		"NginxConfig.Name", `"nginx-config"`,
+		"AppSecrets.Name", `"app-secrets"`,
+		"TLSSecret.Name", `"tls-secret"`,
+	).Replace(string(content))
+
+	path := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(path, []byte(src), 0644))
+	return path
+}
+
+func TestAddApplyOrderDependencies_ConfigMapSecretExample(t *testing.T) {
+	resources, err := discover.DiscoverFile(writeNameRefExample(t))
+	require.NoError(t, err)
+
+	var deployment discover.Resource
+	for _, r := range resources {
+		if r.Name == "AppDeployment" {
+			deployment = r
+		}
+	}
+	require.Empty(t, deployment.Dependencies, "the example copy should have no Go references")
+
+	augmented := build.AddApplyOrderDependencies(resources)
+	for _, r := range augmented {
+		if r.Name == "AppDeployment" {
+			assert.ElementsMatch(t, []string{"AppConfig", "NginxConfig", "AppSecrets", "TLSSecret"}, r.Dependencies)
+		} else {
+			assert.Empty(t, r.Dependencies, "%s should not gain dependencies", r.Name)
+		}
+	}
+
+	// The input is left unchanged
+	for _, r := range resources {
+		assert.Empty(t, r.Dependencies)
+	}
+}
+
+func TestAddApplyOrderDependencies_Matching(t *testing.T) {
+	resources := []discover.Resource{
+		{
+			Name:      "Worker",
+			Type:      "batchv1.Job",
+			Namespace: "jobs",
+			NameRefs: []discover.NameRef{
+				{Kind: "ServiceAccount", Name: "runner"},
+				{Kind: "Secret", Name: "token"},
+				{Kind: "ConfigMap", Name: "settings"},
+				{Kind: "PersistentVolumeClaim", Name: "external-claim"},
+			},
+		},
+		{Name: "Runner", Type: "corev1.ServiceAccount", MetadataName: "runner", Namespace: "jobs"},
+		{Name: "OtherToken", Type: "corev1.Secret", MetadataName: "token", Namespace: "other"},
+		{Name: "Settings", Type: "corev1.ConfigMap", MetadataName: "settings"},
+		{Name: "SettingsSecret", Type: "corev1.Secret", MetadataName: "settings"},
+	}
+
+	augmented := build.AddApplyOrderDependencies(resources)
+
+	// Matches by kind and name; a different namespace or kind does not match,
+	// and undefined objects are ignored
+	assert.Equal(t, []string{"Runner", "Settings"}, augmented[0].Dependencies)
+}
+
+func TestBuild_ApplyOrder(t *testing.T) {
+	// The ConfigMap has to wait for the Service it references, so without
+	// apply order the Deployment, which has no Go references, is emitted
+	// before the ConfigMap it reads by name
+	src := `package example
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var APIService = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "api"},
+}
+
+var WebConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-config"},
+	Data:       map[string]string{"API_HOST": APIService.Name},
+}
+
+var Web = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web"},
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:  "web",
+					Image: "web:1.0",
+					EnvFrom: []corev1.EnvFromSource{{
+						ConfigMapRef: &corev1.ConfigMapEnvSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "web-config"},
+						},
+					}},
+				}},
+			},
+		},
+	},
+}
+`
+	path := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(path, []byte(src), 0644))
+
+	names := func(resources []discover.Resource) []string {
+		var result []string
+		for _, r := range resources {
+			result = append(result, r.Name)
+		}
+		return result
+	}
+
+	result, err := build.Build(path, build.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Web", "APIService", "WebConfig"}, names(result.OrderedResources))
+
+	result, err = build.Build(path, build.Options{ApplyOrder: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"APIService", "WebConfig", "Web"}, names(result.OrderedResources))
+
+	// Result.Resources keeps the discovered dependencies
+	assert.Empty(t, result.Resources[2].Dependencies)
+}
@@ -13,7 +13,8 @@ import (
 //
 // Pipeline stages:
 // 1. DISCOVER - Parse source files and find resource declarations
-// 2. VALIDATE - Check references exist, detect cycles
+// 2. VALIDATE - Check references exist, detect cycles, check names and
+// collapse or reject duplicate objects
 // 3. EXTRACT - Execute source to get runtime values (placeholder for now)
 // 4. ORDER - Topological sort by dependencies
 // 5. SERIALIZE - Convert to YAML/JSON (depends on #3, stub for now)
@@ -34,12 +35,32 @@ func Build(path string, opts Options) (*Result, error) {
 		return nil, fmt.Errorf("cycle detection failed: %w", err)
 	}
 
+	if err := ValidateNames(resources); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
 	// Stage 3: EXTRACT (placeholder for now)
 	// This will be implemented in issue #3 to execute the source code
 	// and get the actual runtime values of the resources.
 
+	resources = OverrideNamespace(resources, opts.Namespace)
+	resources, err = Deduplicate(resources)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if opts.OwnerReferences {
+		resources, err = SetOwnerReferences(resources, opts.OwnerUIDs)
+		if err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+	}
+
 	// Stage 4: ORDER
-	orderedResources, err := TopologicalSort(resources)
+	toOrder := resources
+	if opts.ApplyOrder {
+		toOrder = AddApplyOrderDependencies(resources)
+	}
+	orderedResources, err := TopologicalSort(toOrder)
 	if err != nil {
 		return nil, fmt.Errorf("ordering failed: %w", err)
 	}
@@ -182,6 +182,33 @@ func TestDetectCycles_ComplexCycle(t *testing.T) {
 	assert.Contains(t, err.Error(), "cycle", "error should mention cycle")
 }
 
+func TestDetectCycles_ReportsPath(t *testing.T) {
+	t.Run("three-resource cycle", func(t *testing.T) {
+		resources := []discover.Resource{
+			{Name: "A", Type: "corev1.ConfigMap", Dependencies: []string{"B"}},
+			{Name: "B", Type: "appsv1.Deployment", Dependencies: []string{"C"}},
+			{Name: "C", Type: "corev1.Service", Dependencies: []string{"A"}},
+		}
+
+		err := build.DetectCycles(resources)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "A -> B -> C -> A")
+	})
+
+	t.Run("resources leading into the cycle are not listed", func(t *testing.T) {
+		resources := []discover.Resource{
+			{Name: "Entry", Type: "corev1.Service", Dependencies: []string{"A"}},
+			{Name: "A", Type: "corev1.ConfigMap", Dependencies: []string{"B"}},
+			{Name: "B", Type: "appsv1.Deployment", Dependencies: []string{"C"}},
+			{Name: "C", Type: "corev1.Secret", Dependencies: []string{"A"}},
+		}
+
+		err := build.DetectCycles(resources)
+		require.Error(t, err)
+		assert.Equal(t, "cycle detected: A -> B -> C -> A", err.Error())
+	})
+}
+
 func TestDetectCycles_MultipleCycles(t *testing.T) {
 	// Test detection of multiple independent cycles
 	resources := []discover.Resource{
@@ -317,6 +344,41 @@ var Invalid = &corev1.ConfigMap{
 	assert.Error(t, err, "build should fail with invalid references")
 }
 
+func TestBuild_HelperVariablesAreNotDependencies(t *testing.T) {
+	// Test that non-resource helper values (names, shared labels) are inlined
+	// rather than treated as references to missing resources
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "helpers.go")
+	content := `package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// configName is a helper value, not a K8s resource
+var configName = "app-config"
+
+var appLabels = map[string]string{"app": "web"}
+
+var Config = &corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:   configName,
+		Labels: appLabels,
+	},
+}
+`
+	err := os.WriteFile(testFile, []byte(content), 0644)
+	require.NoError(t, err)
+
+	result, err := build.Build(tempDir, build.Options{
+		OutputMode: build.SingleFile,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Resources, 1)
+	assert.Empty(t, result.Resources[0].Dependencies)
+}
+
 func TestBuild_CircularDependencies(t *testing.T) {
 	// Test that build fails with circular dependencies
 	tempDir := t.TempDir()
@@ -408,3 +470,94 @@ func max(a, b int) int {
 	}
 	return b
 }
+
+func TestTopologicalSort_KindPriority(t *testing.T) {
+	// Independent resources are ordered by kind priority, then by name,
+	// whatever order they were discovered in
+	resources := []discover.Resource{
+		{Name: "WebService", Type: "corev1.Service"},
+		{Name: "WebDeployment", Type: "appsv1.Deployment"},
+		{Name: "WebConfig", Type: "corev1.ConfigMap"},
+		{Name: "AppConfig", Type: "corev1.ConfigMap"},
+		{Name: "Widget", Type: "examplev1.Widget"},
+		{Name: "AppNamespace", Type: "corev1.Namespace"},
+	}
+
+	sorted, err := build.TopologicalSort(resources)
+	require.NoError(t, err)
+
+	var names []string
+	for _, r := range sorted {
+		names = append(names, r.Name)
+	}
+	assert.Equal(t, []string{"AppNamespace", "AppConfig", "WebConfig", "WebDeployment", "WebService", "Widget"}, names)
+}
+
+func TestTopologicalSort_DependenciesBeforePriority(t *testing.T) {
+	// A dependency is placed first even if its kind has a lower priority
+	resources := []discover.Resource{
+		{Name: "Quota", Type: "corev1.ResourceQuota", Dependencies: []string{"Web"}},
+		{Name: "Web", Type: "appsv1.Deployment"},
+	}
+
+	sorted, err := build.TopologicalSort(resources)
+	require.NoError(t, err)
+	require.Len(t, sorted, 2)
+	assert.Equal(t, "Web", sorted[0].Name)
+	assert.Equal(t, "Quota", sorted[1].Name)
+}
+
+func TestTopologicalSort_NamespaceSetupExample(t *testing.T) {
+	resources, err := discover.DiscoverFile(filepath.Join("..", "..", "examples", "namespace-setup", "main.go"))
+	require.NoError(t, err)
+
+	// Sorting must not depend on discovery order
+	reversed := make([]discover.Resource, len(resources))
+	for i, r := range resources {
+		reversed[len(resources)-1-i] = r
+	}
+
+	want := []string{
+		"TeamNamespace",
+		"AllowDNS",
+		"AllowSameNamespace",
+		"ComputeQuota",
+		"DefaultDenyAll",
+		"DefaultLimits",
+		"TeamServiceAccount",
+		"DeveloperRole",
+		"DeveloperBinding",
+	}
+	for _, input := range [][]discover.Resource{resources, reversed} {
+		sorted, err := build.TopologicalSort(input)
+		require.NoError(t, err)
+
+		var names []string
+		for _, r := range sorted {
+			names = append(names, r.Name)
+		}
+		assert.Equal(t, want, names)
+	}
+}
+
+func TestOverrideNamespace(t *testing.T) {
+	resources := []discover.Resource{
+		{Name: "App", Type: "appsv1.Deployment", Namespace: "default"},
+		{Name: "Config", Type: "corev1.ConfigMap"},
+		{Name: "AppNamespace", Type: "corev1.Namespace"},
+		{Name: "Reader", Type: "rbacv1.ClusterRole"},
+		{Name: "Data", Type: "corev1.PersistentVolume"},
+		{Name: "AppContainer", Type: "corev1.Container"},
+	}
+
+	overridden := build.OverrideNamespace(resources, "prod")
+	var namespaces []string
+	for _, r := range overridden {
+		namespaces = append(namespaces, r.Namespace)
+	}
+	assert.Equal(t, []string{"prod", "prod", "", "", "", ""}, namespaces)
+
+	// The input is not modified
+	assert.Equal(t, "default", resources[0].Namespace)
+	assert.Equal(t, resources, build.OverrideNamespace(resources, ""))
+}
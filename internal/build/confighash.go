@@ -0,0 +1,190 @@
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ConfigHashAnnotation is the pod template annotation holding the hash of
+// the ConfigMaps and Secrets a workload refers to.
+const ConfigHashAnnotation = "wetwire-k8s/config-hash"
+
+// configDataKeys are the fields of ConfigMaps and Secrets that hold their
+// content.
+var configDataKeys = []string{"data", "binaryData", "stringData"}
+
+// ApplyConfigHashes annotates the pod templates of workloads, and Pods, with
+// ConfigHashAnnotation: a hash of the content of the ConfigMaps and Secrets
+// in manifests that the pod spec mounts as volumes or reads through envFrom
+// or env valueFrom. Changing the content of one changes the annotation, so
+// applying the output rolls the workload out with the new configuration.
+// Manifests are modified in place.
+//
+// ConfigMaps and Secrets that are not in manifests, such as Secrets created
+// by an operator, are not part of the hash.
+func ApplyConfigHashes(manifests []interface{}) error {
+	hashes := make(map[string]string)
+	for _, m := range manifests {
+		manifest, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := manifest["kind"].(string)
+		if kind != "ConfigMap" && kind != "Secret" {
+			continue
+		}
+		content := make(map[string]interface{})
+		for _, key := range configDataKeys {
+			if value, ok := manifest[key]; ok {
+				content[key] = value
+			}
+		}
+		// encoding/json sorts map keys, so equal content hashes equally
+		data, err := json.Marshal(content)
+		if err != nil {
+			return fmt.Errorf("hashing %s %s: %w", kind, manifestName(manifest), err)
+		}
+		sum := sha256.Sum256(data)
+		hashes[configKey(kind, manifestNamespace(manifest), manifestName(manifest))] = hex.EncodeToString(sum[:])
+	}
+
+	for _, m := range manifests {
+		manifest, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		podSpec := manifestPodSpec(manifest)
+		if podSpec == nil {
+			continue
+		}
+		namespace := manifestNamespace(manifest)
+		var referenced []string
+		for _, ref := range podConfigRefs(podSpec) {
+			key := configKey(ref.kind, namespace, ref.name)
+			if hash, ok := hashes[key]; ok && !contains(referenced, key+"="+hash) {
+				referenced = append(referenced, key+"="+hash)
+			}
+		}
+		if len(referenced) == 0 {
+			continue
+		}
+		sort.Strings(referenced)
+		sum := sha256.Sum256([]byte(strings.Join(referenced, "\n")))
+		annotations := childMap(podMetadata(manifest), "annotations")
+		annotations[ConfigHashAnnotation] = hex.EncodeToString(sum[:])
+	}
+	return nil
+}
+
+// configRef is a ConfigMap or Secret a pod spec refers to by name.
+type configRef struct {
+	kind string
+	name string
+}
+
+// podConfigRefs returns the ConfigMaps and Secrets podSpec refers to in its
+// volumes and in the envFrom and env of its containers and init containers.
+func podConfigRefs(podSpec map[string]interface{}) []configRef {
+	var refs []configRef
+	add := func(kind string, m map[string]interface{}, key string) {
+		if name, ok := stringAt(m, key, "name"); ok {
+			refs = append(refs, configRef{kind, name})
+		}
+	}
+
+	volumes, _ := podSpec["volumes"].([]interface{})
+	for _, v := range volumes {
+		volume, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		add("ConfigMap", volume, "configMap")
+		if name, ok := stringAt(volume, "secret", "secretName"); ok {
+			refs = append(refs, configRef{"Secret", name})
+		}
+		sources, _ := mapAt(volume, "projected")["sources"].([]interface{})
+		for _, s := range sources {
+			if source, ok := s.(map[string]interface{}); ok {
+				add("ConfigMap", source, "configMap")
+				add("Secret", source, "secret")
+			}
+		}
+	}
+
+	for _, key := range []string{"initContainers", "containers"} {
+		containers, _ := podSpec[key].([]interface{})
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			envFrom, _ := container["envFrom"].([]interface{})
+			for _, e := range envFrom {
+				if source, ok := e.(map[string]interface{}); ok {
+					add("ConfigMap", source, "configMapRef")
+					add("Secret", source, "secretRef")
+				}
+			}
+			env, _ := container["env"].([]interface{})
+			for _, e := range env {
+				if envVar, ok := e.(map[string]interface{}); ok {
+					valueFrom := mapAt(envVar, "valueFrom")
+					add("ConfigMap", valueFrom, "configMapKeyRef")
+					add("Secret", valueFrom, "secretKeyRef")
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// podMetadata returns the metadata of a Pod manifest or of the pod template
+// of a workload manifest, creating it if it is not set. The manifest must
+// have a pod spec; see manifestPodSpec.
+func podMetadata(manifest map[string]interface{}) map[string]interface{} {
+	path := []string{"spec", "template"}
+	switch manifest["kind"] {
+	case "Pod":
+		path = nil
+	case "CronJob":
+		path = []string{"spec", "jobTemplate", "spec", "template"}
+	}
+	current := manifest
+	for _, key := range path {
+		current = childMap(current, key)
+	}
+	return childMap(current, "metadata")
+}
+
+// configKey identifies a ConfigMap or Secret within the manifests.
+func configKey(kind, namespace, name string) string {
+	return kind + "/" + objectPath(namespace, name)
+}
+
+// manifestName returns metadata.name of manifest.
+func manifestName(manifest map[string]interface{}) string {
+	name, _ := stringAt(manifest, "metadata", "name")
+	return name
+}
+
+// manifestNamespace returns metadata.namespace of manifest.
+func manifestNamespace(manifest map[string]interface{}) string {
+	namespace, _ := stringAt(manifest, "metadata", "namespace")
+	return namespace
+}
+
+// mapAt returns the map under key in m, or nil if it is not set.
+func mapAt(m map[string]interface{}, key string) map[string]interface{} {
+	child, _ := m[key].(map[string]interface{})
+	return child
+}
+
+// stringAt returns the string at m[key][field].
+func stringAt(m map[string]interface{}, key, field string) (string, bool) {
+	s, ok := mapAt(m, key)[field].(string)
+	return s, ok && s != ""
+}
@@ -0,0 +1,79 @@
+package build_test
+
+import (
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func configMap(name, level string) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		"data":       map[string]interface{}{"LOG_LEVEL": level},
+	}
+}
+
+// configHash builds a Deployment that reads the ConfigMap app-config through
+// envFrom and returns its config hash annotation.
+func configHash(t *testing.T, config map[string]interface{}) string {
+	t.Helper()
+	web := baseDeployment()
+	podSpec := web["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	container := podSpec["containers"].([]interface{})[0].(map[string]interface{})
+	container["envFrom"] = []interface{}{
+		map[string]interface{}{"configMapRef": map[string]interface{}{"name": "app-config"}},
+	}
+
+	require.NoError(t, build.ApplyConfigHashes([]interface{}{config, web}))
+	template := web["spec"].(map[string]interface{})["template"].(map[string]interface{})
+	metadata, _ := template["metadata"].(map[string]interface{})
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	hash, _ := annotations[build.ConfigHashAnnotation].(string)
+	return hash
+}
+
+func TestApplyConfigHashes(t *testing.T) {
+	hash := configHash(t, configMap("app-config", "info"))
+	require.Len(t, hash, 64)
+	assert.Equal(t, hash, configHash(t, configMap("app-config", "info")), "the hash must be stable")
+	assert.NotEqual(t, hash, configHash(t, configMap("app-config", "debug")), "changing a ConfigMap value must change the hash")
+	assert.Empty(t, configHash(t, configMap("other-config", "info")), "unreferenced ConfigMaps must not annotate the pod")
+
+	t.Run("volumes, env and CronJobs", func(t *testing.T) {
+		secret := map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]interface{}{"name": "db"},
+			"stringData": map[string]interface{}{"password": "hunter2"},
+		}
+		job := cronJob()
+		podSpec := job["spec"].(map[string]interface{})["jobTemplate"].(map[string]interface{})["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+		podSpec["volumes"] = []interface{}{
+			map[string]interface{}{"name": "db", "secret": map[string]interface{}{"secretName": "db"}},
+		}
+		pod := map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]interface{}{"name": "debug"},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{
+					"name": "debug",
+					"env": []interface{}{map[string]interface{}{
+						"name":      "PASSWORD",
+						"valueFrom": map[string]interface{}{"secretKeyRef": map[string]interface{}{"name": "db", "key": "password"}},
+					}},
+				}},
+			},
+		}
+
+		require.NoError(t, build.ApplyConfigHashes([]interface{}{secret, job, pod}))
+		jobAnnotations := job["spec"].(map[string]interface{})["jobTemplate"].(map[string]interface{})["spec"].(map[string]interface{})["template"].(map[string]interface{})["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})
+		podAnnotations := pod["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})
+		assert.NotEmpty(t, jobAnnotations[build.ConfigHashAnnotation])
+		assert.Equal(t, jobAnnotations[build.ConfigHashAnnotation], podAnnotations[build.ConfigHashAnnotation])
+	})
+}
@@ -0,0 +1,185 @@
+package build
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/fs"
+	"strings"
+
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+)
+
+// Deduplicate checks for resources that declare the same object, meaning the
+// same apiVersion, kind, namespace and name, which happens when files
+// discovered together declare it more than once. Duplicates whose Go
+// definitions are identical are collapsed into the first one, and references
+// to the others are redirected to it. Duplicates that differ are a
+// *ValidationError listing where each is declared, since only one of them
+// can be applied.
+// Helper values are not checked.
+func Deduplicate(resources []discover.Resource) ([]discover.Resource, error) {
+	groups := make(map[string][]int)
+	var keys []string
+	for i, r := range resources {
+		if discover.IsHelper(r) {
+			continue
+		}
+		apiVersion, kind := resourceAPIVersionKind(r.Type)
+		key := fmt.Sprintf("%s %s %s", apiVersion, kind, objectPath(r.Namespace, ObjectName(r)))
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	var definitions map[int]string
+	replaced := make(map[string]string)
+	var errors []ResourceError
+	for _, key := range keys {
+		group := groups[key]
+		if len(group) < 2 {
+			continue
+		}
+		if definitions == nil {
+			var err error
+			if definitions, err = resourceDefinitions(resources); err != nil {
+				return nil, err
+			}
+		}
+
+		first := resources[group[0]]
+		for _, i := range group[1:] {
+			r := resources[i]
+			if definitions[i] != "" && definitions[i] == definitions[group[0]] {
+				replaced[r.Name] = first.Name
+				continue
+			}
+			_, kind := resourceAPIVersionKind(r.Type)
+			errors = append(errors, resourceError(r, CodeConflictingDuplicate,
+				fmt.Sprintf("%s %s is declared differently by %s (%s:%d) and %s",
+					kind, objectPath(r.Namespace, ObjectName(r)), first.Name, first.File, first.Line, r.Name)))
+		}
+	}
+
+	if len(errors) > 0 {
+		return nil, &ValidationError{Summary: "conflicting duplicate resources", Errors: errors}
+	}
+	if len(replaced) == 0 {
+		return resources, nil
+	}
+
+	result := make([]discover.Resource, 0, len(resources)-len(replaced))
+	for _, r := range resources {
+		if _, ok := replaced[r.Name]; ok {
+			continue
+		}
+		if len(r.Dependencies) > 0 {
+			deps := make([]string, 0, len(r.Dependencies))
+			for _, dep := range r.Dependencies {
+				if kept, ok := replaced[dep]; ok {
+					dep = kept
+				}
+				if dep != r.Name && !contains(deps, dep) {
+					deps = append(deps, dep)
+				}
+			}
+			r.Dependencies = deps
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+// objectPath returns "namespace/name", or just the name when the namespace
+// is not set.
+func objectPath(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// resourceDefinitions returns the source of the initializer of each
+// resource, keyed by its index and printed in canonical form, so that
+// definitions differing only in layout or comments compare equal. Resources
+// whose initializer is not found, or whose file is not on disk because it
+// was discovered with discover.DiscoverSource, have no entry.
+func resourceDefinitions(resources []discover.Resource) (map[int]string, error) {
+	fset := token.NewFileSet()
+	files := make(map[string]*ast.File)
+	definitions := make(map[int]string)
+
+	for i, r := range resources {
+		file, ok := files[r.File]
+		if !ok {
+			var err error
+			file, err = parser.ParseFile(fset, r.File, nil, 0)
+			if errors.Is(err, fs.ErrNotExist) {
+				file = nil
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", r.File, err)
+			}
+			files[r.File] = file
+		}
+		if file == nil {
+			continue
+		}
+
+		value := findDefinition(fset, file, r)
+		if value == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := printer.Fprint(&buf, fset, value); err != nil {
+			return nil, fmt.Errorf("failed to print %s: %w", r.Name, err)
+		}
+		definitions[i] = buf.String()
+	}
+	return definitions, nil
+}
+
+// findDefinition returns the initializer of r in file: the value of the
+// variable named r.Name, or for a resource declared as an element of a slice
+// or map, the element on line r.Line of the variable its name starts with.
+func findDefinition(fset *token.FileSet, file *ast.File, r discover.Resource) ast.Expr {
+	var found ast.Expr
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, name := range valueSpec.Names {
+				if i >= len(valueSpec.Values) {
+					continue
+				}
+				value := valueSpec.Values[i]
+				if name.Name == r.Name {
+					return value
+				}
+				lit, ok := value.(*ast.CompositeLit)
+				if !ok || !strings.HasPrefix(r.Name, name.Name) {
+					continue
+				}
+				for _, elt := range lit.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						elt = kv.Value
+					}
+					if fset.Position(elt.Pos()).Line == r.Line {
+						found = elt
+					}
+				}
+			}
+		}
+	}
+	return found
+}
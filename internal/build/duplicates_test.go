@@ -0,0 +1,106 @@
+package build_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sharedConfig = `package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var %s = &corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "shared-config", Namespace: "prod"},
+	Data:       map[string]string{"LOG_LEVEL": %q},
+}
+`
+
+// writeDuplicates writes two files declaring the shared-config ConfigMap,
+// with the given log levels, and a Deployment that uses the second one.
+func writeDuplicates(t *testing.T, first, second string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.go": fmt.Sprintf(sharedConfig, "SharedConfig", first),
+		"b.go": fmt.Sprintf(sharedConfig, "CopiedConfig", second),
+		"c.go": `package k8s
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var App = &appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "prod", Annotations: map[string]string{"config": CopiedConfig.Name}},
+}
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestBuild_IdenticalDuplicates(t *testing.T) {
+	dir := writeDuplicates(t, "info", "info")
+
+	result, err := build.Build(dir, build.Options{OutputMode: build.SingleFile})
+	require.NoError(t, err)
+
+	var names []string
+	for _, r := range result.OrderedResources {
+		names = append(names, r.Name)
+	}
+	assert.Equal(t, []string{"SharedConfig", "App"}, names)
+
+	app := result.OrderedResources[1]
+	assert.Equal(t, []string{"SharedConfig"}, app.Dependencies, "reference moved to the kept resource")
+}
+
+func TestBuild_ConflictingDuplicates(t *testing.T) {
+	dir := writeDuplicates(t, "info", "debug")
+
+	_, err := build.Build(dir, build.Options{OutputMode: build.SingleFile})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "conflicting duplicate resources")
+	assert.Contains(t, err.Error(), "ConfigMap prod/shared-config is declared differently by SharedConfig")
+	assert.Contains(t, err.Error(), filepath.Join(dir, "a.go")+":8")
+	assert.Contains(t, err.Error(), "and CopiedConfig ("+filepath.Join(dir, "b.go")+":8)")
+}
+
+func TestDeduplicate_DifferentNamespaces(t *testing.T) {
+	// The same name in different namespaces is not a duplicate
+	dir := t.TempDir()
+	for i, namespace := range []string{"dev", "prod"} {
+		content := fmt.Sprintf(`package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var Config%d = &corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: %q},
+}
+`, i, namespace)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, namespace+".go"), []byte(content), 0644))
+	}
+
+	result, err := build.Build(dir, build.Options{OutputMode: build.SingleFile})
+	require.NoError(t, err)
+	assert.Len(t, result.Resources, 2)
+
+	// Until the namespace override puts both in the same namespace
+	_, err = build.Build(dir, build.Options{OutputMode: build.SingleFile, Namespace: "staging"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ConfigMap staging/settings is declared differently by Config0")
+}
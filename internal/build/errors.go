@@ -0,0 +1,60 @@
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+)
+
+// Codes of the problems reported in a ValidationError.
+const (
+	CodeInvalidReference     = "invalid-reference"
+	CodeCycle                = "cycle"
+	CodeInvalidName          = "invalid-name"
+	CodeConflictingDuplicate = "conflicting-duplicate"
+)
+
+// ResourceError is a problem with one resource, located at its declaration.
+type ResourceError struct {
+	Resource string // Variable of the resource
+	File     string // Source file of the declaration
+	Line     int    // Line of the declaration
+	Code     string // Class of problem, e.g. CodeCycle
+	Message  string // Description of the problem, without the position
+}
+
+// Error returns the message followed by the position of the resource, if
+// known.
+func (e ResourceError) Error() string {
+	if e.File == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s (%s:%d)", e.Message, e.File, e.Line)
+}
+
+// ValidationError is returned when the resources of a package cannot be
+// built, with a ResourceError for each problem found, so that callers can
+// report every problem at its location.
+type ValidationError struct {
+	Summary string // What failed, e.g. "invalid resource names"
+	Errors  []ResourceError
+}
+
+// Error returns the summary followed by the problems, one per line when
+// there are several.
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 1 {
+		return fmt.Sprintf("%s: %s", e.Summary, e.Errors[0].Error())
+	}
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%s:\n  - %s", e.Summary, strings.Join(messages, "\n  - "))
+}
+
+// resourceError returns a ResourceError located at the declaration of r.
+func resourceError(r discover.Resource, code, message string) ResourceError {
+	return ResourceError{Resource: r.Name, File: r.File, Line: r.Line, Code: code, Message: message}
+}
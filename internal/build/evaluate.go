@@ -0,0 +1,398 @@
+package build
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	gobuild "go/build"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+	"github.com/lex00/wetwire-k8s-go/internal/serialize"
+)
+
+// evaluatedModules are the modules resource code imports. A temporary module
+// requires them at the versions this binary was built with.
+var evaluatedModules = []string{"k8s.io/api", "k8s.io/apimachinery"}
+
+// evaluateFile is the name of the file added to a package to print the
+// values of its resources.
+const evaluateFile = "zz_wetwire_evaluate.go"
+
+// Manifests evaluates resources, see Evaluate, and returns the manifest of
+// each one. The apiVersion and kind of its Go type, the name from ObjectName
+// and the namespace and owner references of the resource are set, and the
+// manifest is cleaned like serialize.Serialize cleans a typed value.
+func Manifests(resources []discover.Resource, source []byte) ([]map[string]interface{}, error) {
+	objects, err := Evaluate(resources, source)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := make([]map[string]interface{}, len(objects))
+	for i, obj := range objects {
+		r := resources[i]
+		metadata, _ := obj["metadata"].(map[string]interface{})
+		if metadata == nil {
+			metadata = make(map[string]interface{})
+			obj["metadata"] = metadata
+		}
+		if name, _ := metadata["name"].(string); name == "" {
+			metadata["name"] = ObjectName(r)
+		}
+		if r.Namespace != "" {
+			metadata["namespace"] = r.Namespace
+		}
+		if len(r.OwnerReferences) > 0 {
+			metadata["ownerReferences"] = r.OwnerReferences
+		}
+
+		manifest, err := serialize.Serialize(serialize.Typed(obj))
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize %s: %w", r.Name, err)
+		}
+		manifests[i] = manifest
+	}
+	return manifests, nil
+}
+
+// Evaluate compiles and runs the Go code that declares resources and returns
+// the value of each resource encoded as JSON, in the order of resources.
+// Objects without TypeMeta get the apiVersion and kind of their Go type.
+//
+// The resources of each package are evaluated with the go command, so a Go
+// toolchain is required. A package inside a module is run in place, as a
+// main package, with its own dependencies. Source read from stdin, passed as
+// source, and packages outside any module are run in a temporary module that
+// requires the Kubernetes modules this binary was built with; the go.sum of
+// the enclosing project, if any, is used to resolve them without network
+// access.
+func Evaluate(resources []discover.Resource, source []byte) ([]map[string]interface{}, error) {
+	type unit struct {
+		dir       string
+		pkg       string
+		resources []int
+	}
+	var units []*unit
+	byPackage := make(map[string]*unit)
+	for i, r := range resources {
+		dir, pkg := filepath.Dir(r.File), ""
+		if source == nil {
+			var err error
+			if pkg, err = packageName(r.File); err != nil {
+				return nil, err
+			}
+		}
+		key := dir + "\x00" + pkg
+		u, ok := byPackage[key]
+		if !ok {
+			u = &unit{dir: dir, pkg: pkg}
+			byPackage[key] = u
+			units = append(units, u)
+		}
+		u.resources = append(u.resources, i)
+	}
+
+	objects := make([]map[string]interface{}, len(resources))
+	for _, u := range units {
+		var exprs []string
+		for _, i := range u.resources {
+			exprs = append(exprs, resourceExpr(resources[i]))
+		}
+
+		var out []map[string]interface{}
+		var err error
+		if source != nil {
+			out, err = evaluateSource(source, exprs)
+		} else {
+			out, err = evaluatePackage(u.dir, u.pkg, exprs)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(out) != len(exprs) {
+			return nil, fmt.Errorf("evaluated %d of %d resources in %s", len(out), len(exprs), u.dir)
+		}
+
+		for j, i := range u.resources {
+			obj := out[j]
+			if obj == nil {
+				return nil, fmt.Errorf("%s is nil", resources[i].Name)
+			}
+			apiVersion, kind := APIVersionKind(resources[i])
+			if _, ok := obj["apiVersion"]; !ok {
+				obj["apiVersion"] = apiVersion
+			}
+			if _, ok := obj["kind"]; !ok {
+				obj["kind"] = kind
+			}
+			objects[i] = obj
+		}
+	}
+	return objects, nil
+}
+
+// resourceExpr returns the Go expression of the value of r.
+func resourceExpr(r discover.Resource) string {
+	if r.Expr != "" {
+		return r.Expr
+	}
+	return r.Name
+}
+
+// evaluatePackage evaluates exprs in the package pkg in dir. Every file of
+// the package that matches the build constraints is compiled, with its
+// package clause rewritten to main.
+func evaluatePackage(dir, pkg string, exprs []string) ([]map[string]interface{}, error) {
+	files, err := packageFiles(dir, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string][]byte, len(files))
+	hasMain := false
+	for _, name := range files {
+		src, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if sources[name], err = asMainPackage(src); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		hasMain = hasMain || declaresMain(src)
+	}
+	sources[evaluateFile] = []byte(evaluateProgram(exprs, !hasMain))
+
+	tmp, err := os.MkdirTemp("", "wetwire-evaluate-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if findModuleRoot(dir) == "" {
+		for name, src := range sources {
+			if err := os.WriteFile(filepath.Join(tmp, name), src, 0644); err != nil {
+				return nil, fmt.Errorf("failed to write %s: %w", name, err)
+			}
+		}
+		if err := writeModule(tmp); err != nil {
+			return nil, err
+		}
+		return runEvaluation(tmp, []string{"."}, "GOFLAGS=-mod=mod")
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	replace := make(map[string]string, len(sources))
+	args := []string{"-overlay=" + filepath.Join(tmp, "overlay.json")}
+	for _, name := range append(files, evaluateFile) {
+		path := filepath.Join(tmp, name)
+		if err := os.WriteFile(path, sources[name], 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		replace[filepath.Join(absDir, name)] = path
+		args = append(args, name)
+	}
+	overlay, err := json.Marshal(map[string]interface{}{"Replace": replace})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "overlay.json"), overlay, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write overlay: %w", err)
+	}
+	return runEvaluation(absDir, args)
+}
+
+// evaluateSource evaluates exprs in a single file of Go source, in a
+// temporary module.
+func evaluateSource(source []byte, exprs []string) ([]map[string]interface{}, error) {
+	src, err := asMainPackage(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source: %w", err)
+	}
+
+	tmp, err := os.MkdirTemp("", "wetwire-evaluate-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := os.WriteFile(filepath.Join(tmp, "resources.go"), src, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write Go file: %w", err)
+	}
+	program := evaluateProgram(exprs, !declaresMain(source))
+	if err := os.WriteFile(filepath.Join(tmp, evaluateFile), []byte(program), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write Go file: %w", err)
+	}
+	if err := writeModule(tmp); err != nil {
+		return nil, err
+	}
+	return runEvaluation(tmp, []string{"."}, "GOFLAGS=-mod=mod")
+}
+
+// runEvaluation runs go run with args in dir and decodes the JSON array the
+// evaluation program prints.
+func runEvaluation(dir string, args []string, env ...string) ([]map[string]interface{}, error) {
+	cmd := exec.Command("go", append([]string{"run"}, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to evaluate resources: %s\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &objects); err != nil {
+		return nil, fmt.Errorf("failed to decode resources: %w", err)
+	}
+	return objects, nil
+}
+
+// evaluateProgram returns a file that prints exprs as a JSON array and exits
+// before the package's own main function runs. With addMain, the file also
+// declares an empty main function for packages without one. The imports are
+// renamed so that they do not clash with the package's own declarations.
+func evaluateProgram(exprs []string, addMain bool) string {
+	var b strings.Builder
+	b.WriteString("package main\n\n")
+	b.WriteString("import (\n\twetwirejson \"encoding/json\"\n\twetwireos \"os\"\n)\n\n")
+	b.WriteString("func init() {\n")
+	b.WriteString("\tif err := wetwirejson.NewEncoder(wetwireos.Stdout).Encode([]interface{}{\n")
+	for _, expr := range exprs {
+		fmt.Fprintf(&b, "\t\t%s,\n", expr)
+	}
+	b.WriteString("\t}); err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\twetwireos.Exit(0)\n}\n")
+	if addMain {
+		b.WriteString("\nfunc main() {}\n")
+	}
+	return b.String()
+}
+
+// packageName returns the package name of a Go file.
+func packageName(path string) (string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return "", err
+	}
+	return file.Name.Name, nil
+}
+
+// packageFiles returns the names of the non-test Go files of package pkg in
+// dir that match the build constraints, sorted.
+func packageFiles(dir, pkg string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == evaluateFile {
+			continue
+		}
+		if ok, err := gobuild.Default.MatchFile(dir, name); err != nil || !ok {
+			continue
+		}
+		if got, err := packageName(filepath.Join(dir, name)); err != nil || got != pkg {
+			continue
+		}
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// asMainPackage returns src with its package clause renamed to main.
+func asMainPackage(src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.PackageClauseOnly)
+	if err != nil {
+		return nil, err
+	}
+	start := fset.Position(file.Name.Pos()).Offset
+	end := fset.Position(file.Name.End()).Offset
+	out := make([]byte, 0, len(src))
+	out = append(out, src[:start]...)
+	out = append(out, "main"...)
+	return append(out, src[end:]...), nil
+}
+
+// declaresMain reports whether Go source declares a main function.
+func declaresMain(src []byte) bool {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return false
+	}
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+			return true
+		}
+	}
+	return false
+}
+
+// findModuleRoot returns the directory of the go.mod that encloses dir, or ""
+// if there is none.
+func findModuleRoot(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// writeModule writes the go.mod of a temporary module in dir, and copies the
+// go.sum of the project enclosing the working directory if there is one.
+func writeModule(dir string) error {
+	versions := make(map[string]string)
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			versions[dep.Path] = dep.Version
+		}
+	}
+
+	var mod strings.Builder
+	mod.WriteString("module wetwireevaluate\n\ngo 1.25.0\n\nrequire (\n")
+	for _, path := range evaluatedModules {
+		version := versions[path]
+		if version == "" {
+			return fmt.Errorf("cannot determine the version of %s", path)
+		}
+		fmt.Fprintf(&mod, "\t%s %s\n", path, version)
+	}
+	mod.WriteString(")\n")
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(mod.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write go.mod: %w", err)
+	}
+
+	if root := findModuleRoot("."); root != "" {
+		if sum, err := os.ReadFile(filepath.Join(root, "go.sum")); err == nil {
+			if err := os.WriteFile(filepath.Join(dir, "go.sum"), sum, 0644); err != nil {
+				return fmt.Errorf("failed to write go.sum: %w", err)
+			}
+		}
+	}
+	return nil
+}
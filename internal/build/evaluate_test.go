@@ -0,0 +1,75 @@
+package build_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifests(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.go"), []byte(`package app
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var Services = []corev1.Service{
+	{ObjectMeta: metav1.ObjectMeta{Name: "web"}},
+	{ObjectMeta: metav1.ObjectMeta{Name: "api"}},
+}
+
+var PausedWorker = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{Replicas: ptr(int32(0))},
+}
+`), 0644))
+	// A helper in another file of the package
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ptr.go"), []byte(`package app
+
+func ptr[T any](v T) *T { return &v }
+`), 0644))
+
+	resources, err := discover.DiscoverDirectory(dir)
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+
+	manifests, err := build.Manifests(resources, nil)
+	require.NoError(t, err)
+
+	byName := make(map[string]map[string]interface{})
+	for _, m := range manifests {
+		byName[m["metadata"].(map[string]interface{})["name"].(string)] = m
+	}
+	require.Contains(t, byName, "web")
+	require.Contains(t, byName, "api")
+	assert.Equal(t, "v1", byName["api"]["apiVersion"])
+	assert.Equal(t, "Service", byName["api"]["kind"])
+
+	// The name comes from the variable and replicas: 0 is kept
+	worker := byName["paused-worker"]
+	require.NotNil(t, worker)
+	assert.Equal(t, "apps/v1", worker["apiVersion"])
+	assert.Equal(t, map[string]interface{}{"replicas": float64(0)}, worker["spec"])
+}
+
+func TestManifests_EvaluationError(t *testing.T) {
+	source := []byte(`package main
+
+import corev1 "k8s.io/api/core/v1"
+
+var Web = corev1.Service{Spec: corev1.ServiceSpec{Type: undefinedType}}
+`)
+	resources, err := discover.DiscoverSource(source, "stdin.go")
+	require.NoError(t, err)
+
+	_, err = build.Manifests(resources, source)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "undefined: undefinedType")
+}
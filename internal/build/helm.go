@@ -0,0 +1,558 @@
+package build
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+	"github.com/lex00/wetwire-k8s-go/internal/registry"
+	"github.com/lex00/wetwire-k8s-go/internal/serialize"
+	"gopkg.in/yaml.v3"
+)
+
+// ChartMeta describes the Chart.yaml metadata of an exported Helm chart.
+type ChartMeta struct {
+	Name        string
+	Description string
+	Version     string
+	AppVersion  string
+}
+
+// HelmChart is an in-memory Helm chart.
+// Files maps chart-relative paths (e.g., "templates/web.yaml") to file contents.
+type HelmChart struct {
+	Files map[string][]byte
+}
+
+// Paths returns the chart-relative file paths in sorted order.
+func (c *HelmChart) Paths() []string {
+	paths := make([]string, 0, len(c.Files))
+	for p := range c.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Write writes all chart files beneath dir and returns the written paths.
+func (c *HelmChart) Write(dir string) ([]string, error) {
+	var written []string
+	for _, p := range c.Paths() {
+		target := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create chart directory: %w", err)
+		}
+		if err := os.WriteFile(target, c.Files[p], 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", p, err)
+		}
+		written = append(written, target)
+	}
+	return written, nil
+}
+
+// helmLiterals holds the literal values lifted out of a resource declaration.
+type helmLiterals struct {
+	name       string
+	namespace  string
+	replicas   int64
+	containers []helmContainer
+}
+
+// helmContainer is a container name/image pair found in a pod template.
+type helmContainer struct {
+	name  string
+	image string
+}
+
+// ToHelmChart renders resources as a minimal Helm chart.
+//
+// Each resource becomes a template under templates/ holding its full
+// manifest, evaluated like the build does; see Manifests. Image references,
+// replica counts and namespaces found as literals in the Go source are lifted
+// into values.yaml and replaced in the template by references to their values.
+// Literals repeated across resources (the same image, or the same replica
+// count) share a single value so they can be changed in one place.
+func ToHelmChart(resources []discover.Resource, meta ChartMeta) (*HelmChart, error) {
+	if meta.Name == "" {
+		meta.Name = "wetwire-chart"
+	}
+	if meta.Version == "" {
+		meta.Version = "0.1.0"
+	}
+	if meta.AppVersion == "" {
+		meta.AppVersion = "1.0.0"
+	}
+	if meta.Description == "" {
+		meta.Description = "A Helm chart generated by wetwire-k8s"
+	}
+
+	literals, err := extractHelmLiterals(resources)
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := Manifests(resources, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+
+	// Namespace: the most common literal namespace becomes the chart default.
+	namespace := mostCommon(collectStrings(resources, literals, func(l helmLiterals) string { return l.namespace }))
+	if namespace == "" {
+		namespace = "default"
+	}
+	values["namespace"] = namespace
+
+	// Images: one value per distinct image, keyed by repository name.
+	imageKeys := assignImageKeys(resources, literals)
+	if len(imageKeys) > 0 {
+		images := map[string]interface{}{}
+		for image, key := range imageKeys {
+			images[key] = image
+		}
+		values["images"] = images
+	}
+
+	// Replicas: a count shared by several resources becomes replicaCount,
+	// anything else stays scoped to its resource.
+	replicaCounts := map[int64]int{}
+	for _, r := range resources {
+		if l := literals[r.Name]; l.replicas > 0 {
+			replicaCounts[l.replicas]++
+		}
+	}
+	var sharedReplicas int64
+	for count, n := range replicaCounts {
+		if n > 1 && (n > replicaCounts[sharedReplicas] || (n == replicaCounts[sharedReplicas] && count < sharedReplicas)) {
+			sharedReplicas = count
+		}
+	}
+	if sharedReplicas > 0 {
+		values["replicaCount"] = sharedReplicas
+	}
+
+	chart := &HelmChart{Files: map[string][]byte{}}
+	for i, r := range resources {
+		l := literals[r.Name]
+		key := helmValueKey(r.Name)
+
+		replicasRef := ""
+		if l.replicas > 0 {
+			if l.replicas == sharedReplicas {
+				replicasRef = "{{ .Values.replicaCount }}"
+			} else {
+				values[key] = map[string]interface{}{"replicas": l.replicas}
+				replicasRef = fmt.Sprintf("{{ .Values.%s.replicas }}", key)
+			}
+		}
+
+		// A namespace other than the chart default stays a literal
+		namespaceRef := ""
+		if l.namespace != "" && l.namespace == namespace {
+			namespaceRef = "{{ .Values.namespace }}"
+		}
+
+		// Names are only unique per kind, so the kind is part of the file name.
+		_, kind := resourceAPIVersionKind(r.Type)
+		tmplPath := fmt.Sprintf("templates/%s-%s.yaml", helmTemplateName(r, l), strings.ToLower(kind))
+		tmpl, err := renderHelmTemplate(manifests[i], namespaceRef, replicasRef, imageKeys)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", r.Name, err)
+		}
+		chart.Files[tmplPath] = tmpl
+	}
+
+	chartYAML, err := yaml.Marshal(struct {
+		APIVersion  string `yaml:"apiVersion"`
+		Name        string `yaml:"name"`
+		Description string `yaml:"description"`
+		Type        string `yaml:"type"`
+		Version     string `yaml:"version"`
+		AppVersion  string `yaml:"appVersion"`
+	}{"v2", meta.Name, meta.Description, "application", meta.Version, meta.AppVersion})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Chart.yaml: %w", err)
+	}
+	chart.Files["Chart.yaml"] = chartYAML
+
+	valuesYAML, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal values.yaml: %w", err)
+	}
+	chart.Files["values.yaml"] = valuesYAML
+
+	return chart, nil
+}
+
+// renderHelmTemplate renders the manifest of a resource as a Helm template,
+// with its namespace, replicas and the images in imageKeys replaced by the
+// given value references. Empty references leave the manifest's own value.
+func renderHelmTemplate(manifest map[string]interface{}, namespaceRef, replicasRef string, imageKeys map[string]string) ([]byte, error) {
+	// Lifted values are set to placeholders that are replaced by their
+	// references once serialized, since references are not valid YAML values
+	// of their fields.
+	var refs []string
+	placeholder := func(ref string) string {
+		refs = append(refs, ref)
+		return fmt.Sprintf("WETWIRE_HELM_VALUE_%d_", len(refs)-1)
+	}
+
+	if metadata, ok := manifest["metadata"].(map[string]interface{}); ok && namespaceRef != "" {
+		metadata["namespace"] = placeholder(namespaceRef)
+	}
+	spec, _ := manifest["spec"].(map[string]interface{})
+	if spec != nil && replicasRef != "" {
+		spec["replicas"] = placeholder(replicasRef)
+	}
+
+	kind, _ := manifest["kind"].(string)
+	podSpecPath := podSpecPathForKind(kind)
+	podSpec := spec
+	for _, segment := range podSpecPath {
+		podSpec, _ = podSpec[segment].(map[string]interface{})
+	}
+	if podSpecPath != nil && podSpec != nil {
+		for _, field := range []string{"initContainers", "containers"} {
+			containers, _ := podSpec[field].([]interface{})
+			for _, c := range containers {
+				container, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				image, _ := container["image"].(string)
+				if key, ok := imageKeys[image]; ok {
+					container["image"] = placeholder(fmt.Sprintf(`"{{ .Values.images.%s }}"`, key))
+				}
+			}
+		}
+	}
+
+	data, err := serialize.ToYAMLWithOptions(serialize.Manifest(manifest), serialize.YAMLOptions{Indent: 2})
+	if err != nil {
+		return nil, err
+	}
+	for i := len(refs) - 1; i >= 0; i-- {
+		data = bytes.ReplaceAll(data, []byte(fmt.Sprintf("WETWIRE_HELM_VALUE_%d_", i)), []byte(refs[i]))
+	}
+	return data, nil
+}
+
+// podSpecPathForKind returns the spec path (below spec:) leading to the pod spec
+// of workload kinds, or nil if the kind has no pod template.
+func podSpecPathForKind(kind string) []string {
+	switch kind {
+	case "Pod":
+		return []string{}
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+		return []string{"template", "spec"}
+	case "CronJob":
+		return []string{"jobTemplate", "spec", "template", "spec"}
+	}
+	return nil
+}
+
+// APIVersionKind returns the apiVersion and kind of a discovered resource.
+func APIVersionKind(r discover.Resource) (string, string) {
+	return resourceAPIVersionKind(r.Type)
+}
+
+// resourceAPIVersionKind resolves apiVersion and kind for a discovered Go type
+// such as "appsv1.Deployment".
+func resourceAPIVersionKind(typeStr string) (string, string) {
+	parts := strings.Split(typeStr, ".")
+	if len(parts) != 2 {
+		return "v1", typeStr
+	}
+	apiVersion := registry.DefaultRegistry.APIVersionForPackage(parts[0])
+	if apiVersion == "" {
+		apiVersion = "v1"
+	}
+	return apiVersion, parts[1]
+}
+
+// helmTemplateName returns the Kubernetes name used for a resource template,
+// preferring the literal metadata name over the Go variable name.
+func helmTemplateName(r discover.Resource, l helmLiterals) string {
+	if l.name != "" {
+		return l.name
+	}
+	var b strings.Builder
+	for i, c := range r.Name {
+		if i > 0 && unicode.IsUpper(c) {
+			b.WriteRune('-')
+		}
+		b.WriteRune(unicode.ToLower(c))
+	}
+	return b.String()
+}
+
+// helmValueKey converts a Go variable name to a lowerCamel values.yaml key.
+// Keys that would shadow the chart-wide values get a "Resource" suffix.
+func helmValueKey(name string) string {
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	key := string(runes)
+	switch key {
+	case "namespace", "images", "replicaCount":
+		key += "Resource"
+	}
+	return key
+}
+
+// assignImageKeys maps each distinct image to a values.yaml key derived from
+// its repository name, disambiguating collisions with a numeric suffix.
+func assignImageKeys(resources []discover.Resource, literals map[string]helmLiterals) map[string]string {
+	keys := map[string]string{}
+	used := map[string]bool{}
+	for _, r := range resources {
+		for _, c := range literals[r.Name].containers {
+			if _, ok := keys[c.image]; ok {
+				continue
+			}
+			base := imageValueKey(c.image)
+			key := base
+			for i := 2; used[key]; i++ {
+				key = base + strconv.Itoa(i)
+			}
+			used[key] = true
+			keys[c.image] = key
+		}
+	}
+	return keys
+}
+
+// imageValueKey derives a lowerCamel key from an image reference,
+// e.g., "docker.io/library/gb-frontend:v5" -> "gbFrontend".
+func imageValueKey(image string) string {
+	repo := image
+	if i := strings.Index(repo, "@"); i >= 0 {
+		repo = repo[:i]
+	}
+	if i := strings.LastIndex(repo, "/"); i >= 0 {
+		repo = repo[i+1:]
+	}
+	if i := strings.Index(repo, ":"); i >= 0 {
+		repo = repo[:i]
+	}
+
+	var b strings.Builder
+	upper := false
+	for _, c := range repo {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			upper = b.Len() > 0
+			continue
+		}
+		if upper {
+			c = unicode.ToUpper(c)
+			upper = false
+		}
+		b.WriteRune(c)
+	}
+	if b.Len() == 0 {
+		return "image"
+	}
+	return b.String()
+}
+
+// collectStrings returns the non-empty values selected from each resource's literals.
+func collectStrings(resources []discover.Resource, literals map[string]helmLiterals, get func(helmLiterals) string) []string {
+	var result []string
+	for _, r := range resources {
+		if v := get(literals[r.Name]); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// mostCommon returns the most frequent value, breaking ties alphabetically.
+func mostCommon(values []string) string {
+	counts := map[string]int{}
+	for _, v := range values {
+		counts[v]++
+	}
+	best := ""
+	for v, n := range counts {
+		if n > counts[best] || (n == counts[best] && v < best) {
+			best = v
+		}
+	}
+	return best
+}
+
+// extractHelmLiterals parses the source files of the resources and collects
+// the literal values that can be parameterized in the chart.
+func extractHelmLiterals(resources []discover.Resource) (map[string]helmLiterals, error) {
+	files := map[string]*ast.File{}
+	literals := map[string]helmLiterals{}
+
+	for _, r := range resources {
+		file, ok := files[r.File]
+		if !ok {
+			parsed, err := parser.ParseFile(token.NewFileSet(), r.File, nil, 0)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", r.File, err)
+			}
+			files[r.File] = parsed
+			file = parsed
+		}
+
+		value := findVarValue(file, r.Name)
+		if value == nil {
+			continue
+		}
+		literals[r.Name] = collectHelmLiterals(value)
+	}
+
+	return literals, nil
+}
+
+// findVarValue returns the initializer of the named top-level variable.
+func findVarValue(file *ast.File, name string) ast.Expr {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, ident := range valueSpec.Names {
+				if ident.Name == name && i < len(valueSpec.Values) {
+					return valueSpec.Values[i]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// collectHelmLiterals walks a resource initializer collecting metadata,
+// replica and container image literals.
+func collectHelmLiterals(expr ast.Expr) helmLiterals {
+	var l helmLiterals
+	seenMeta := false
+
+	ast.Inspect(expr, func(n ast.Node) bool {
+		compLit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+
+		switch compositeTypeName(compLit) {
+		case "ObjectMeta":
+			// Only the resource's own metadata; pod template metadata comes later.
+			if !seenMeta {
+				seenMeta = true
+				l.name = stringField(compLit, "Name")
+				l.namespace = stringField(compLit, "Namespace")
+			}
+		case "Container":
+			l.addContainer(compLit)
+		}
+
+		// Elements of []corev1.Container{{...}} have their type elided.
+		if arr, ok := compLit.Type.(*ast.ArrayType); ok && typeExprName(arr.Elt) == "Container" {
+			for _, elt := range compLit.Elts {
+				if elem, ok := elt.(*ast.CompositeLit); ok && elem.Type == nil {
+					l.addContainer(elem)
+				}
+			}
+		}
+
+		if replicas := fieldValue(compLit, "Replicas"); replicas != nil && l.replicas == 0 {
+			l.replicas = intLiteral(replicas)
+		}
+
+		return true
+	})
+
+	return l
+}
+
+// addContainer records the name and image of a container literal.
+func (l *helmLiterals) addContainer(compLit *ast.CompositeLit) {
+	c := helmContainer{name: stringField(compLit, "Name"), image: stringField(compLit, "Image")}
+	if c.name != "" && c.image != "" {
+		l.containers = append(l.containers, c)
+	}
+}
+
+// compositeTypeName returns the unqualified type name of a composite literal.
+func compositeTypeName(compLit *ast.CompositeLit) string {
+	return typeExprName(compLit.Type)
+}
+
+// typeExprName returns the unqualified name of a type expression.
+func typeExprName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// fieldValue returns the value of a keyed field in a composite literal.
+func fieldValue(compLit *ast.CompositeLit, field string) ast.Expr {
+	for _, elt := range compLit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok && key.Name == field {
+			return kv.Value
+		}
+	}
+	return nil
+}
+
+// stringField returns the string literal value of a keyed field, or "".
+func stringField(compLit *ast.CompositeLit, field string) string {
+	lit, ok := fieldValue(compLit, field).(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// intLiteral extracts an integer from a literal, a conversion such as
+// int32(3), or a pointer helper such as ptr(int32(3)). Returns 0 otherwise.
+func intLiteral(expr ast.Expr) int64 {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind == token.INT {
+			v, err := strconv.ParseInt(e.Value, 0, 64)
+			if err == nil {
+				return v
+			}
+		}
+	case *ast.CallExpr:
+		if len(e.Args) == 1 {
+			return intLiteral(e.Args[0])
+		}
+	case *ast.UnaryExpr:
+		return intLiteral(e.X)
+	}
+	return 0
+}
@@ -0,0 +1,228 @@
+package build_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const helmSource = `package app
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+var WebDeployment = &appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+	Spec: appsv1.DeploymentSpec{
+		Replicas: ptr(int32(3)),
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "web", Image: "nginx:1.25"}},
+			},
+		},
+	},
+}
+
+var ApiDeployment = &appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+	Spec: appsv1.DeploymentSpec{
+		Replicas: ptr(int32(3)),
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "api", Image: "ghcr.io/acme/api:2.0"},
+					{Name: "proxy", Image: "nginx:1.25"},
+				},
+			},
+		},
+	},
+}
+
+var WorkerDeployment = &appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "shop"},
+	Spec: appsv1.DeploymentSpec{
+		Replicas: ptr(int32(1)),
+	},
+}
+
+var WebService = &corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+}
+`
+
+func discoverHelmSource(t *testing.T) []discover.Resource {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.go")
+	require.NoError(t, os.WriteFile(path, []byte(helmSource), 0644))
+
+	resources, err := discover.DiscoverFile(path)
+	require.NoError(t, err)
+	require.Len(t, resources, 4)
+	return resources
+}
+
+func TestToHelmChart_Layout(t *testing.T) {
+	chart, err := build.ToHelmChart(discoverHelmSource(t), build.ChartMeta{Name: "shop"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"Chart.yaml",
+		"templates/api-deployment.yaml",
+		"templates/web-deployment.yaml",
+		"templates/web-service.yaml",
+		"templates/worker-deployment.yaml",
+		"values.yaml",
+	}, chart.Paths())
+
+	var meta map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(chart.Files["Chart.yaml"], &meta))
+	assert.Equal(t, "v2", meta["apiVersion"])
+	assert.Equal(t, "shop", meta["name"])
+	assert.Equal(t, "0.1.0", meta["version"])
+}
+
+func TestToHelmChart_LiftsValues(t *testing.T) {
+	chart, err := build.ToHelmChart(discoverHelmSource(t), build.ChartMeta{})
+	require.NoError(t, err)
+
+	var values map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(chart.Files["values.yaml"], &values))
+
+	assert.Equal(t, "shop", values["namespace"])
+	// 3 replicas is shared by two deployments, 1 is specific to the worker
+	assert.Equal(t, 3, values["replicaCount"])
+	assert.Equal(t, map[string]interface{}{"replicas": 1}, values["workerDeployment"])
+	// nginx:1.25 is used twice but lifted once
+	assert.Equal(t, map[string]interface{}{
+		"nginx": "nginx:1.25",
+		"api":   "ghcr.io/acme/api:2.0",
+	}, values["images"])
+
+	api := string(chart.Files["templates/api-deployment.yaml"])
+	assert.Contains(t, api, "namespace: {{ .Values.namespace }}")
+	assert.Contains(t, api, "replicas: {{ .Values.replicaCount }}")
+	assert.Contains(t, api, `image: "{{ .Values.images.api }}"`)
+	assert.Contains(t, api, `image: "{{ .Values.images.nginx }}"`)
+
+	worker := string(chart.Files["templates/worker-deployment.yaml"])
+	assert.Contains(t, worker, "replicas: {{ .Values.workerDeployment.replicas }}")
+
+	svc := string(chart.Files["templates/web-service.yaml"])
+	assert.Contains(t, svc, "kind: Service")
+	assert.NotContains(t, svc, "replicas")
+}
+
+// renderHelmTemplate executes a chart template with the chart's values, as
+// helm install does, and returns the resulting manifest.
+func renderHelmTemplate(t *testing.T, chart *build.HelmChart, path string) map[string]interface{} {
+	t.Helper()
+	var values map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(chart.Files["values.yaml"], &values))
+
+	tmpl, err := template.New(path).Option("missingkey=error").Parse(string(chart.Files[path]))
+	require.NoError(t, err)
+	var out bytes.Buffer
+	require.NoError(t, tmpl.Execute(&out, map[string]interface{}{"Values": values}))
+
+	var manifest map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(out.Bytes(), &manifest), out.String())
+	return manifest
+}
+
+func TestToHelmChart_KeepsSpec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.go")
+	require.NoError(t, os.WriteFile(path, []byte(`package app
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+var webLabels = map[string]string{"app": "web"}
+
+var WebDeployment = &appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+	Spec: appsv1.DeploymentSpec{
+		Replicas: ptr(int32(2)),
+		Selector: &metav1.LabelSelector{MatchLabels: webLabels},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: webLabels},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:  "web",
+					Image: "nginx:1.25",
+					Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+				}},
+			},
+		},
+	},
+}
+
+var WebService = &corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+	Spec: corev1.ServiceSpec{
+		Selector: webLabels,
+		Ports:    []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromString("http")}},
+	},
+}
+`), 0644))
+	resources, err := discover.DiscoverFile(path)
+	require.NoError(t, err)
+
+	chart, err := build.ToHelmChart(resources, build.ChartMeta{})
+	require.NoError(t, err)
+
+	deployment := renderHelmTemplate(t, chart, "templates/web-deployment.yaml")
+	assert.Equal(t, map[string]interface{}{"name": "web", "namespace": "shop"}, deployment["metadata"])
+	assert.Equal(t, map[string]interface{}{
+		"replicas": 2,
+		"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+		"template": map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "web"}},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{
+					"name":  "web",
+					"image": "nginx:1.25",
+					"ports": []interface{}{map[string]interface{}{"name": "http", "containerPort": 8080}},
+				}},
+			},
+		},
+	}, deployment["spec"])
+
+	service := renderHelmTemplate(t, chart, "templates/web-service.yaml")
+	assert.Equal(t, map[string]interface{}{
+		"selector": map[string]interface{}{"app": "web"},
+		"ports":    []interface{}{map[string]interface{}{"port": 80, "targetPort": "http"}},
+	}, service["spec"])
+}
+
+func TestHelmChart_Write(t *testing.T) {
+	chart, err := build.ToHelmChart(discoverHelmSource(t), build.ChartMeta{Name: "shop"})
+	require.NoError(t, err)
+
+	outDir := filepath.Join(t.TempDir(), "chart")
+	written, err := chart.Write(outDir)
+	require.NoError(t, err)
+	assert.Len(t, written, len(chart.Files))
+
+	assert.FileExists(t, filepath.Join(outDir, "Chart.yaml"))
+	assert.FileExists(t, filepath.Join(outDir, "values.yaml"))
+	assert.FileExists(t, filepath.Join(outDir, "templates", "web-service.yaml"))
+}
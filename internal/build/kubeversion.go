@@ -0,0 +1,157 @@
+package build
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CodeUnsupportedKubeVersion is the code of resources that cannot be served
+// by the target Kubernetes version in any apiVersion.
+const CodeUnsupportedKubeVersion = "unsupported-kube-version"
+
+// KubeVersion is a Kubernetes minor release, such as 1.27, that manifests
+// are built for.
+type KubeVersion struct {
+	Major int
+	Minor int
+}
+
+// String returns the version as "1.27".
+func (v KubeVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// ParseKubeVersion parses a Kubernetes version written as 1.27, v1.27 or
+// 1.27.3. The patch release is ignored.
+func ParseKubeVersion(s string) (KubeVersion, error) {
+	parts := strings.Split(strings.TrimPrefix(s, "v"), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return KubeVersion{}, fmt.Errorf("invalid Kubernetes version %q: expected major.minor, e.g. 1.27", s)
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return KubeVersion{}, fmt.Errorf("invalid Kubernetes version %q: expected major.minor, e.g. 1.27", s)
+		}
+		numbers[i] = n
+	}
+	if numbers[0] != 1 {
+		return KubeVersion{}, fmt.Errorf("unsupported Kubernetes version %q: only 1.x is supported", s)
+	}
+	return KubeVersion{Major: numbers[0], Minor: numbers[1]}, nil
+}
+
+// apiVersionFallback is an older apiVersion of a kind with the same schema,
+// used for clusters that predate the newer one.
+type apiVersionFallback struct {
+	kind          string
+	apiVersion    string // apiVersion in the code
+	since         int    // Minor release that first served apiVersion
+	fallback      string // Older apiVersion with the same schema
+	fallbackSince int    // Minor release that first served fallback
+}
+
+// apiVersionFallbacks are the apiVersions that graduated while their beta
+// version was still served, so that a manifest can be moved back to the
+// beta version for older clusters without converting its fields. Ingress is
+// not listed: networking.k8s.io/v1beta1 has a different backend schema.
+var apiVersionFallbacks = []apiVersionFallback{
+	{"HorizontalPodAutoscaler", "autoscaling/v2", 23, "autoscaling/v2beta2", 12},
+	{"PodDisruptionBudget", "policy/v1", 21, "policy/v1beta1", 5},
+	{"CronJob", "batch/v1", 21, "batch/v1beta1", 8},
+}
+
+// fieldIntroduction is a field that clusters before a minor release do not
+// support. Older API servers reject it or, worse, silently drop it.
+type fieldIntroduction struct {
+	kind  string
+	path  []string
+	since int // Minor release that enabled the field by default
+}
+
+// fieldIntroductions are the fields dropped from manifests built for older
+// clusters.
+var fieldIntroductions = []fieldIntroduction{
+	{"HorizontalPodAutoscaler", []string{"spec", "behavior"}, 18},
+	{"Service", []string{"spec", "internalTrafficPolicy"}, 22},
+	{"StatefulSet", []string{"spec", "minReadySeconds"}, 23},
+	{"StatefulSet", []string{"spec", "persistentVolumeClaimRetentionPolicy"}, 27},
+	{"StatefulSet", []string{"spec", "ordinals"}, 27},
+	{"CronJob", []string{"spec", "timeZone"}, 25},
+	{"Job", []string{"spec", "podFailurePolicy"}, 26},
+	{"Job", []string{"spec", "backoffLimitPerIndex"}, 29},
+	{"Job", []string{"spec", "maxFailedIndexes"}, 29},
+	{"PodDisruptionBudget", []string{"spec", "unhealthyPodEvictionPolicy"}, 27},
+}
+
+// DowngradeManifests adapts manifests, which are modified in place, to the
+// target Kubernetes version: resources whose apiVersion the target does not
+// serve are moved to an older apiVersion with the same schema, and fields
+// the target does not support are dropped. It returns a warning for each
+// change, naming the resource.
+//
+// Resources that the target cannot serve in any apiVersion are a
+// *ValidationError with CodeUnsupportedKubeVersion.
+func DowngradeManifests(manifests []interface{}, target KubeVersion) ([]string, error) {
+	var warnings []string
+	var errs []ResourceError
+	for _, m := range manifests {
+		manifest, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := manifest["kind"].(string)
+		apiVersion, _ := manifest["apiVersion"].(string)
+		object := kind
+		if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+			if name, ok := metadata["name"].(string); ok {
+				object += " " + name
+			}
+		}
+
+		for _, f := range apiVersionFallbacks {
+			if f.kind != kind || f.apiVersion != apiVersion || target.Minor >= f.since {
+				continue
+			}
+			if target.Minor < f.fallbackSince {
+				errs = append(errs, ResourceError{Code: CodeUnsupportedKubeVersion,
+					Message: fmt.Sprintf("%s: Kubernetes %s serves neither %s nor %s", object, target, f.apiVersion, f.fallback)})
+				continue
+			}
+			manifest["apiVersion"] = f.fallback
+			warnings = append(warnings, fmt.Sprintf("%s: Kubernetes %s does not serve %s; using %s", object, target, f.apiVersion, f.fallback))
+		}
+
+		for _, f := range fieldIntroductions {
+			if f.kind != kind || target.Minor >= f.since {
+				continue
+			}
+			if deletePath(manifest, f.path) {
+				warnings = append(warnings, fmt.Sprintf("%s: dropped %s, which Kubernetes %s does not support", object, strings.Join(f.path, "."), target))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return warnings, &ValidationError{Summary: "unsupported Kubernetes version", Errors: errs}
+	}
+	return warnings, nil
+}
+
+// deletePath deletes the value at path in m and reports whether it was set.
+func deletePath(m map[string]interface{}, path []string) bool {
+	for _, key := range path[:len(path)-1] {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			return false
+		}
+		m = next
+	}
+	last := path[len(path)-1]
+	if _, ok := m[last]; !ok {
+		return false
+	}
+	delete(m, last)
+	return true
+}
@@ -0,0 +1,104 @@
+package build_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKubeVersion(t *testing.T) {
+	for _, s := range []string{"1.22", "v1.22", "1.22.7"} {
+		v, err := build.ParseKubeVersion(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, build.KubeVersion{Major: 1, Minor: 22}, v, s)
+	}
+	assert.Equal(t, "1.22", build.KubeVersion{Major: 1, Minor: 22}.String())
+
+	for _, s := range []string{"", "1", "1.x", "1.22.3.4", "2.0", "latest"} {
+		_, err := build.ParseKubeVersion(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func hpaV2() map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "autoscaling/v2",
+		"kind":       "HorizontalPodAutoscaler",
+		"metadata":   map[string]interface{}{"name": "web"},
+		"spec": map[string]interface{}{
+			"scaleTargetRef": map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "web"},
+			"minReplicas":    2,
+			"maxReplicas":    10,
+			"behavior": map[string]interface{}{
+				"scaleDown": map[string]interface{}{"stabilizationWindowSeconds": 300},
+			},
+		},
+	}
+}
+
+func TestDowngradeManifests_HPA(t *testing.T) {
+	t.Run("before autoscaling/v2", func(t *testing.T) {
+		hpa := hpaV2()
+		warnings, err := build.DowngradeManifests([]interface{}{hpa}, build.KubeVersion{Major: 1, Minor: 22})
+		require.NoError(t, err)
+
+		assert.Equal(t, "autoscaling/v2beta2", hpa["apiVersion"])
+		assert.Contains(t, hpa["spec"], "behavior", "v2beta2 supports behavior since 1.18")
+		assert.Equal(t, []string{"HorizontalPodAutoscaler web: Kubernetes 1.22 does not serve autoscaling/v2; using autoscaling/v2beta2"}, warnings)
+	})
+
+	t.Run("before behavior", func(t *testing.T) {
+		hpa := hpaV2()
+		warnings, err := build.DowngradeManifests([]interface{}{hpa}, build.KubeVersion{Major: 1, Minor: 17})
+		require.NoError(t, err)
+
+		assert.Equal(t, "autoscaling/v2beta2", hpa["apiVersion"])
+		assert.NotContains(t, hpa["spec"], "behavior")
+		assert.Equal(t, 10, hpa["spec"].(map[string]interface{})["maxReplicas"], "other fields are kept")
+		require.Len(t, warnings, 2)
+		assert.Equal(t, "HorizontalPodAutoscaler web: dropped spec.behavior, which Kubernetes 1.17 does not support", warnings[1])
+	})
+
+	t.Run("current cluster", func(t *testing.T) {
+		hpa := hpaV2()
+		warnings, err := build.DowngradeManifests([]interface{}{hpa}, build.KubeVersion{Major: 1, Minor: 30})
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+		assert.Equal(t, hpaV2(), hpa)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := build.DowngradeManifests([]interface{}{hpaV2()}, build.KubeVersion{Major: 1, Minor: 11})
+		var validationErr *build.ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		require.Len(t, validationErr.Errors, 1)
+		assert.Equal(t, build.CodeUnsupportedKubeVersion, validationErr.Errors[0].Code)
+		assert.Equal(t, "HorizontalPodAutoscaler web: Kubernetes 1.11 serves neither autoscaling/v2 nor autoscaling/v2beta2", validationErr.Errors[0].Message)
+	})
+}
+
+func TestDowngradeManifests_Fields(t *testing.T) {
+	cron := cronJob()
+	cron["spec"].(map[string]interface{})["timeZone"] = "Europe/Paris"
+	pdb := map[string]interface{}{
+		"apiVersion": "policy/v1",
+		"kind":       "PodDisruptionBudget",
+		"metadata":   map[string]interface{}{"name": "web"},
+		"spec":       map[string]interface{}{"minAvailable": 1, "unhealthyPodEvictionPolicy": "AlwaysAllow"},
+	}
+
+	warnings, err := build.DowngradeManifests([]interface{}{cron, pdb}, build.KubeVersion{Major: 1, Minor: 24})
+	require.NoError(t, err)
+
+	assert.Equal(t, "batch/v1", cron["apiVersion"], "batch/v1 is served since 1.21")
+	assert.NotContains(t, cron["spec"], "timeZone")
+	assert.Equal(t, "policy/v1", pdb["apiVersion"])
+	assert.Equal(t, map[string]interface{}{"minAvailable": 1}, pdb["spec"])
+	assert.Equal(t, []string{
+		"CronJob report: dropped spec.timeZone, which Kubernetes 1.24 does not support",
+		"PodDisruptionBudget web: dropped spec.unhealthyPodEvictionPolicy, which Kubernetes 1.24 does not support",
+	}, warnings)
+}
@@ -0,0 +1,96 @@
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+)
+
+// maxNameLength is the maximum length of a DNS-1123 or DNS-1035 label.
+const maxNameLength = 63
+
+// dns1035Kinds are the kinds whose names must be DNS-1035 labels, which
+// start with a letter, rather than DNS-1123 labels.
+var dns1035Kinds = map[string]bool{
+	"Service": true,
+}
+
+// ResourceName converts a Go variable name to the Kubernetes name it is
+// built as, e.g. "MyDeployment" -> "my-deployment". The result is not
+// guaranteed to be a valid name; see ValidateNames.
+func ResourceName(varName string) string {
+	var result strings.Builder
+	for i, r := range varName {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			result.WriteRune('-')
+		}
+		result.WriteRune(r)
+	}
+	return strings.ToLower(result.String())
+}
+
+// ObjectName returns the Kubernetes name of a resource: its metadata name
+// when the code sets one, and otherwise the name generated from its variable.
+func ObjectName(r discover.Resource) string {
+	if r.MetadataName != "" {
+		return r.MetadataName
+	}
+	return ResourceName(r.Name)
+}
+
+// ValidateNames checks that resources have legal Kubernetes names: DNS-1123
+// labels of at most 63 lower case alphanumeric characters or '-', starting
+// and ending with an alphanumeric character. Services must also start with a
+// letter (DNS-1035). The name checked is the metadata name when the resource
+// sets one, and otherwise the name generated from its variable. Helper values
+// are not checked, since they are not objects in their own right.
+func ValidateNames(resources []discover.Resource) error {
+	var errors []ResourceError
+	for _, r := range resources {
+		if discover.IsHelper(r) {
+			continue
+		}
+		_, kind := resourceAPIVersionKind(r.Type)
+		name, source := ObjectName(r), "metadata name"
+		if r.MetadataName == "" {
+			source = "generated name"
+		}
+		if problem := nameProblem(name, dns1035Kinds[kind]); problem != "" {
+			errors = append(errors, resourceError(r, CodeInvalidName,
+				fmt.Sprintf("%s %s has %s %q, which is not a valid Kubernetes name: %s", kind, r.Name, source, name, problem)))
+		}
+	}
+
+	if len(errors) > 0 {
+		return &ValidationError{Summary: "invalid resource names", Errors: errors}
+	}
+
+	return nil
+}
+
+// nameProblem describes why name is not a DNS-1123 label, or a DNS-1035
+// label if dns1035 is set, or returns "" if it is valid.
+func nameProblem(name string, dns1035 bool) string {
+	if len(name) > maxNameLength {
+		return fmt.Sprintf("must be no more than %d characters", maxNameLength)
+	}
+
+	isAlpha := func(c byte) bool { return c >= 'a' && c <= 'z' }
+	isAlnum := func(c byte) bool { return isAlpha(c) || (c >= '0' && c <= '9') }
+
+	valid := name != "" && isAlnum(name[0]) && isAlnum(name[len(name)-1])
+	for i := 0; valid && i < len(name); i++ {
+		valid = isAlnum(name[i]) || name[i] == '-'
+	}
+	if dns1035 {
+		if !valid || !isAlpha(name[0]) {
+			return "must consist of lower case alphanumeric characters or '-', start with an alphabetic character, and end with an alphanumeric character"
+		}
+		return ""
+	}
+	if !valid {
+		return "must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character"
+	}
+	return ""
+}
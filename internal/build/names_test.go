@@ -0,0 +1,108 @@
+package build_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceName(t *testing.T) {
+	assert.Equal(t, "my-deployment", build.ResourceName("MyDeployment"))
+	assert.Equal(t, "web-app", build.ResourceName("webApp"))
+	assert.Equal(t, "a-p-i", build.ResourceName("API"))
+	assert.Equal(t, "app_-config", build.ResourceName("App_Config"))
+}
+
+func TestValidateNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource discover.Resource
+		problem  string
+	}{
+		{
+			name:     "generated name",
+			resource: discover.Resource{Name: "WebApp", Type: "appsv1.Deployment"},
+		},
+		{
+			name:     "metadata name",
+			resource: discover.Resource{Name: "WebApp", Type: "appsv1.Deployment", MetadataName: "web-app"},
+		},
+		{
+			name:     "underscore in variable name",
+			resource: discover.Resource{Name: "App_Config", Type: "corev1.ConfigMap"},
+			problem:  `ConfigMap App_Config has generated name "app_-config"`,
+		},
+		{
+			name:     "generated name too long",
+			resource: discover.Resource{Name: "The" + strings.Repeat("Very", 16) + "LongConfig", Type: "corev1.ConfigMap"},
+			problem:  "must be no more than 63 characters",
+		},
+		{
+			name:     "metadata name ends with a hyphen",
+			resource: discover.Resource{Name: "Config", Type: "corev1.ConfigMap", MetadataName: "config-"},
+			problem:  "must start and end with an alphanumeric character",
+		},
+		{
+			name:     "DNS-1123 name may start with a digit",
+			resource: discover.Resource{Name: "Config", Type: "corev1.ConfigMap", MetadataName: "1-config"},
+		},
+		{
+			name:     "Service name must start with a letter",
+			resource: discover.Resource{Name: "Api", Type: "corev1.Service", MetadataName: "1-api"},
+			problem:  "start with an alphabetic character",
+		},
+		{
+			name:     "helpers are not checked",
+			resource: discover.Resource{Name: "App_Container", Type: "corev1.Container"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := build.ValidateNames([]discover.Resource{tt.resource})
+			if tt.problem == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.problem)
+		})
+	}
+}
+
+func TestBuild_InvalidResourceName(t *testing.T) {
+	tempDir := t.TempDir()
+	content := `package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+var App_Settings = &corev1.ConfigMap{
+	Data: map[string]string{"key": "value"},
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "names.go"), []byte(content), 0644))
+
+	_, err := build.Build(tempDir, build.Options{OutputMode: build.SingleFile})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `ConfigMap App_Settings has generated name "app_-settings"`)
+	assert.Contains(t, err.Error(), "names.go:7")
+
+	var validationErr *build.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Len(t, validationErr.Errors, 1)
+	assert.Equal(t, build.ResourceError{
+		Resource: "App_Settings",
+		File:     filepath.Join(tempDir, "names.go"),
+		Line:     7,
+		Code:     build.CodeInvalidName,
+		Message:  `ConfigMap App_Settings has generated name "app_-settings", which is not a valid Kubernetes name: must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character`,
+	}, validationErr.Errors[0])
+}
@@ -0,0 +1,26 @@
+package build
+
+import (
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+	"github.com/lex00/wetwire-k8s-go/internal/serialize"
+)
+
+// OverrideNamespace returns a copy of resources in which every namespaced
+// resource is in namespace, whatever namespace its code sets, so the same
+// package can be built for different environments. Cluster-scoped kinds such
+// as Namespace, ClusterRole and PersistentVolume, and helper values, are left
+// unchanged. An empty namespace returns resources as they are.
+func OverrideNamespace(resources []discover.Resource, namespace string) []discover.Resource {
+	if namespace == "" {
+		return resources
+	}
+
+	result := make([]discover.Resource, len(resources))
+	for i, r := range resources {
+		if _, kind := resourceAPIVersionKind(r.Type); !serialize.IsClusterScoped(kind) && !discover.IsHelper(r) {
+			r.Namespace = namespace
+		}
+		result[i] = r
+	}
+	return result
+}
@@ -2,12 +2,61 @@ package build
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/lex00/wetwire-k8s-go/internal/discover"
 )
 
+// kindOrder groups kinds in the order they can safely be applied: namespaces
+// and their policies first, then configuration and storage, identities and
+// RBAC, workloads, and finally the Services and Ingresses that expose them.
+// Kinds that are not listed come last.
+var kindOrder = [][]string{
+	{"Namespace"},
+	{"ResourceQuota", "LimitRange", "NetworkPolicy", "PriorityClass"},
+	{"ConfigMap", "Secret"},
+	{"StorageClass", "PersistentVolume", "PersistentVolumeClaim"},
+	{"ServiceAccount"},
+	{"ClusterRole", "Role"},
+	{"ClusterRoleBinding", "RoleBinding"},
+	{"Pod", "ReplicaSet", "Deployment", "StatefulSet", "DaemonSet", "Job", "CronJob"},
+	{"HorizontalPodAutoscaler", "PodDisruptionBudget"},
+	{"Service"},
+	{"IngressClass", "Ingress"},
+}
+
+// kindPriority maps each kind in kindOrder to the index of its group.
+var kindPriority = func() map[string]int {
+	priority := make(map[string]int)
+	for i, kinds := range kindOrder {
+		for _, kind := range kinds {
+			priority[kind] = i
+		}
+	}
+	return priority
+}()
+
+// resourcePriority returns the position of a resource's kind in kindOrder.
+func resourcePriority(r discover.Resource) int {
+	_, kind := resourceAPIVersionKind(r.Type)
+	return KindPriority(kind)
+}
+
+// KindPriority returns the position of kind in the apply order used by
+// TopologicalSort. Kinds that should be applied first have lower values, and
+// unknown kinds come after all known ones.
+func KindPriority(kind string) int {
+	if priority, ok := kindPriority[kind]; ok {
+		return priority
+	}
+	return len(kindOrder)
+}
+
 // TopologicalSort sorts resources in topological order using Kahn's algorithm.
 // Resources with no dependencies come first, followed by resources that depend on them.
+// Among resources that are ready at the same time, those whose kind comes
+// first in kindOrder are placed first, then resources are ordered by name, so
+// the order does not depend on the order of discovery.
 // Returns an error if a cycle is detected.
 func TopologicalSort(resources []discover.Resource) ([]discover.Resource, error) {
 	// Handle empty input
@@ -48,7 +97,14 @@ func TopologicalSort(resources []discover.Resource) ([]discover.Resource, error)
 	// Process resources in topological order
 	var sorted []discover.Resource
 	for len(queue) > 0 {
-		// Dequeue a resource with no dependencies
+		// Dequeue the first ready resource by kind priority, then name
+		sort.Slice(queue, func(i, j int) bool {
+			pi, pj := resourcePriority(resourceMap[queue[i]]), resourcePriority(resourceMap[queue[j]])
+			if pi != pj {
+				return pi < pj
+			}
+			return queue[i] < queue[j]
+		})
 		current := queue[0]
 		queue = queue[1:]
 
@@ -0,0 +1,187 @@
+package build
+
+import (
+	"fmt"
+
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+)
+
+// CodeOverlayConflict is the code of overlay resources that cannot be matched
+// against the base unambiguously.
+const CodeOverlayConflict = "overlay-conflict"
+
+// MergeOverlay combines the resources of a base package with those of an
+// overlay package built on top of it, for example to build one package for
+// several environments without Kustomize.
+//
+// An overlay resource declaring the same object as a base resource, meaning
+// the same apiVersion, kind and name, patches it: it is returned in patches,
+// keyed by the variable name of the base resource, and the base resource
+// keeps its place. An overlay resource that leaves its namespace unset
+// matches the object in any namespace. The other overlay resources are
+// added to the base, with their references to overlay patches redirected to
+// the base resources they patch.
+//
+// Overlay resources matching objects in several namespaces, and added
+// resources whose variable name is taken in the base, are a *ValidationError.
+func MergeOverlay(base, overlay []discover.Resource) ([]discover.Resource, map[string]discover.Resource, error) {
+	names := make(map[string]bool, len(base))
+	for _, r := range base {
+		names[r.Name] = true
+	}
+
+	patches := make(map[string]discover.Resource)
+	replaced := make(map[string]string)
+	var added []discover.Resource
+	var errors []ResourceError
+	for _, o := range overlay {
+		var matches []discover.Resource
+		for _, r := range base {
+			if sameObject(r, o) {
+				matches = append(matches, r)
+			}
+		}
+
+		switch {
+		case len(matches) > 1:
+			errors = append(errors, resourceError(o, CodeOverlayConflict,
+				fmt.Sprintf("%s patches %s in %d namespaces; set its namespace", o.Name, ObjectName(o), len(matches))))
+		case len(matches) == 1:
+			patches[matches[0].Name] = o
+			replaced[o.Name] = matches[0].Name
+		case names[o.Name]:
+			errors = append(errors, resourceError(o, CodeOverlayConflict,
+				fmt.Sprintf("%s declares a new object but a base resource has the same variable name", o.Name)))
+		default:
+			added = append(added, o)
+		}
+	}
+	if len(errors) > 0 {
+		return nil, nil, &ValidationError{Summary: "invalid overlay", Errors: errors}
+	}
+
+	result := append([]discover.Resource(nil), base...)
+	for _, r := range added {
+		if len(r.Dependencies) > 0 {
+			deps := make([]string, 0, len(r.Dependencies))
+			for _, dep := range r.Dependencies {
+				if target, ok := replaced[dep]; ok {
+					dep = target
+				}
+				if !contains(deps, dep) {
+					deps = append(deps, dep)
+				}
+			}
+			r.Dependencies = deps
+		}
+		result = append(result, r)
+	}
+	return result, patches, nil
+}
+
+// sameObject reports whether overlay resource o declares the object of base
+// resource r. An unset overlay namespace matches any namespace.
+func sameObject(r, o discover.Resource) bool {
+	apiVersion, kind := resourceAPIVersionKind(r.Type)
+	overlayAPIVersion, overlayKind := resourceAPIVersionKind(o.Type)
+	if apiVersion != overlayAPIVersion || kind != overlayKind || ObjectName(r) != ObjectName(o) {
+		return false
+	}
+	return o.Namespace == "" || o.Namespace == r.Namespace
+}
+
+// PatchManifest returns manifest with patch merged into it, following the
+// rules of a Kubernetes strategic merge patch for the common cases:
+//
+//   - maps, such as labels and annotations, are merged key by key
+//   - a null value removes the key
+//   - lists whose elements all have a "name", such as containers, ports, env
+//     and volumes, are merged element by element, matched by name, with new
+//     elements appended
+//   - other values, including scalars such as replicas and images and other
+//     lists, are replaced
+//
+// The apiVersion, kind and metadata.name of manifest are kept, since they
+// identify the object being patched. Neither argument is modified.
+func PatchManifest(manifest, patch map[string]interface{}) map[string]interface{} {
+	result := mergeMaps(manifest, patch)
+	for _, key := range []string{"apiVersion", "kind"} {
+		if value, ok := manifest[key]; ok {
+			result[key] = value
+		}
+	}
+	if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+		if name, ok := metadata["name"]; ok {
+			if merged, ok := result["metadata"].(map[string]interface{}); ok {
+				merged["name"] = name
+			}
+		}
+	}
+	return result
+}
+
+// mergeMaps returns a new map holding base with patch merged into it.
+func mergeMaps(base, patch map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(base)+len(patch))
+	for key, value := range base {
+		result[key] = value
+	}
+	for key, value := range patch {
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+		result[key] = mergeValues(result[key], value)
+	}
+	return result
+}
+
+// mergeValues merges patch into base according to PatchManifest.
+func mergeValues(base, patch interface{}) interface{} {
+	switch p := patch.(type) {
+	case map[string]interface{}:
+		if b, ok := base.(map[string]interface{}); ok {
+			return mergeMaps(b, p)
+		}
+	case []interface{}:
+		if b, ok := base.([]interface{}); ok && namedElements(b) && namedElements(p) {
+			return mergeNamedLists(b, p)
+		}
+	}
+	return patch
+}
+
+// namedElements reports whether every element of list is a map with a
+// string "name".
+func namedElements(list []interface{}) bool {
+	for _, elt := range list {
+		m, ok := elt.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if name, ok := m["name"].(string); !ok || name == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeNamedLists merges the elements of patch into the elements of base
+// with the same name and appends the others.
+func mergeNamedLists(base, patch []interface{}) []interface{} {
+	result := append([]interface{}(nil), base...)
+	index := make(map[string]int, len(base))
+	for i, elt := range base {
+		index[elt.(map[string]interface{})["name"].(string)] = i
+	}
+	for _, elt := range patch {
+		m := elt.(map[string]interface{})
+		if i, ok := index[m["name"].(string)]; ok {
+			result[i] = mergeMaps(result[i].(map[string]interface{}), m)
+			continue
+		}
+		index[m["name"].(string)] = len(result)
+		result = append(result, m)
+	}
+	return result
+}
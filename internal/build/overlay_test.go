@@ -0,0 +1,142 @@
+package build_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func baseDeployment() map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "web",
+			"namespace": "default",
+			"labels":    map[string]interface{}{"app": "web"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": 1,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "web", "image": "nginx:1.27", "ports": []interface{}{map[string]interface{}{"containerPort": 80}}},
+						map[string]interface{}{"name": "proxy", "image": "envoy:1.30"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestPatchManifest(t *testing.T) {
+	base := baseDeployment()
+
+	// The production overlay bumps replicas and adds a label
+	patched := build.PatchManifest(base, map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":   "web-prod",
+			"labels": map[string]interface{}{"environment": "production"},
+		},
+		"spec": map[string]interface{}{"replicas": 3},
+	})
+
+	metadata := patched["metadata"].(map[string]interface{})
+	assert.Equal(t, "web", metadata["name"], "the name of the base object is kept")
+	assert.Equal(t, "default", metadata["namespace"])
+	assert.Equal(t, map[string]interface{}{"app": "web", "environment": "production"}, metadata["labels"])
+
+	spec := patched["spec"].(map[string]interface{})
+	assert.Equal(t, 3, spec["replicas"])
+	assert.NotNil(t, spec["template"], "fields not in the patch are kept")
+
+	// The base is not modified
+	assert.Equal(t, baseDeployment(), base)
+}
+
+func TestPatchManifest_Lists(t *testing.T) {
+	patched := build.PatchManifest(baseDeployment(), map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "web", "image": "nginx:1.28"},
+						map[string]interface{}{"name": "metrics", "image": "exporter:0.15"},
+					},
+				},
+			},
+		},
+	})
+
+	podSpec := patched["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "web", "image": "nginx:1.28", "ports": []interface{}{map[string]interface{}{"containerPort": 80}}},
+		map[string]interface{}{"name": "proxy", "image": "envoy:1.30"},
+		map[string]interface{}{"name": "metrics", "image": "exporter:0.15"},
+	}, podSpec["containers"], "containers are merged by name")
+
+	// Lists without names are replaced, and null removes a field
+	patched = build.PatchManifest(map[string]interface{}{
+		"spec": map[string]interface{}{"args": []interface{}{"--a", "--b"}, "paused": true},
+	}, map[string]interface{}{
+		"spec": map[string]interface{}{"args": []interface{}{"--c"}, "paused": nil},
+	})
+	assert.Equal(t, map[string]interface{}{"args": []interface{}{"--c"}}, patched["spec"])
+}
+
+func TestMergeOverlay(t *testing.T) {
+	base := []discover.Resource{
+		{Name: "WebDeployment", Type: "appsv1.Deployment", Namespace: "default", MetadataName: "web"},
+		{Name: "WebService", Type: "corev1.Service", Namespace: "default", MetadataName: "web", Dependencies: []string{"WebDeployment"}},
+	}
+	overlay := []discover.Resource{
+		{Name: "ProdWeb", Type: "appsv1.Deployment", MetadataName: "web"},
+		{Name: "WebBudget", Type: "policyv1.PodDisruptionBudget", Namespace: "default", Dependencies: []string{"ProdWeb"}},
+	}
+
+	resources, patches, err := build.MergeOverlay(base, overlay)
+	require.NoError(t, err)
+
+	require.Len(t, resources, 3)
+	assert.Equal(t, "WebDeployment", resources[0].Name)
+	assert.Equal(t, "WebService", resources[1].Name)
+	assert.Equal(t, "WebBudget", resources[2].Name)
+	assert.Equal(t, []string{"WebDeployment"}, resources[2].Dependencies, "references to a patch go to the patched resource")
+
+	require.Len(t, patches, 1)
+	assert.Equal(t, "ProdWeb", patches["WebDeployment"].Name)
+}
+
+func TestMergeOverlay_Conflicts(t *testing.T) {
+	base := []discover.Resource{
+		{Name: "Config", Type: "corev1.ConfigMap", Namespace: "staging", MetadataName: "config"},
+		{Name: "ProdConfig", Type: "corev1.ConfigMap", Namespace: "prod", MetadataName: "config"},
+		{Name: "Web", Type: "appsv1.Deployment", MetadataName: "web"},
+	}
+	overlay := []discover.Resource{
+		{Name: "Settings", Type: "corev1.ConfigMap", MetadataName: "config", File: "overlay.go", Line: 5},
+		{Name: "Web", Type: "appsv1.Deployment", MetadataName: "web-canary", File: "overlay.go", Line: 9},
+	}
+
+	_, _, err := build.MergeOverlay(base, overlay)
+	var validationErr *build.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "invalid overlay", validationErr.Summary)
+	require.Len(t, validationErr.Errors, 2)
+	assert.Equal(t, build.CodeOverlayConflict, validationErr.Errors[0].Code)
+	assert.Contains(t, validationErr.Errors[0].Message, "in 2 namespaces")
+	assert.Equal(t, 9, validationErr.Errors[1].Line)
+
+	// With its namespace set, the ConfigMap patches one object
+	overlay[0].Namespace = "prod"
+	overlay = overlay[:1]
+	_, patches, err := build.MergeOverlay(base, overlay)
+	require.NoError(t, err)
+	assert.Equal(t, "Settings", patches["ProdConfig"].Name)
+}
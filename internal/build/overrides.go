@@ -0,0 +1,182 @@
+package build
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CodeUnmatchedOverride is the code of image and replica overrides that match
+// no container or workload.
+const CodeUnmatchedOverride = "unmatched-override"
+
+// ImageOverride sets the image of containers, for example to pin the image
+// a CI pipeline pushed to a registry.
+type ImageOverride struct {
+	Workload  string // metadata.name of the workload, or "" for every workload
+	Container string // Name of the containers to update
+	Image     string // New image, e.g. "nginx:1.27"
+}
+
+// ReplicasOverride sets the replica count of a Deployment, StatefulSet or
+// ReplicaSet.
+type ReplicasOverride struct {
+	Name     string // metadata.name of the workload
+	Replicas int64
+}
+
+// Overrides are fields set on the built manifests, replacing the values in
+// the code.
+type Overrides struct {
+	Images   []ImageOverride
+	Replicas []ReplicasOverride
+}
+
+// Empty reports whether o overrides nothing.
+func (o Overrides) Empty() bool {
+	return len(o.Images) == 0 && len(o.Replicas) == 0
+}
+
+// ParseImageOverride parses an image override written as container=image,
+// or workload/container=image to update the container of one workload only.
+func ParseImageOverride(s string) (ImageOverride, error) {
+	selector, image, ok := strings.Cut(s, "=")
+	if !ok || selector == "" || image == "" {
+		return ImageOverride{}, fmt.Errorf("invalid image override %q: expected [workload/]container=image", s)
+	}
+	workload, container, qualified := strings.Cut(selector, "/")
+	if !qualified {
+		workload, container = "", selector
+	}
+	if (qualified && workload == "") || container == "" || strings.Contains(container, "/") {
+		return ImageOverride{}, fmt.Errorf("invalid image override %q: expected [workload/]container=image", s)
+	}
+	return ImageOverride{Workload: workload, Container: container, Image: image}, nil
+}
+
+// ParseReplicasOverride parses a replica override written as name=count.
+func ParseReplicasOverride(s string) (ReplicasOverride, error) {
+	name, count, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return ReplicasOverride{}, fmt.Errorf("invalid replicas override %q: expected name=count", s)
+	}
+	replicas, err := strconv.ParseInt(count, 10, 32)
+	if err != nil || replicas < 0 {
+		return ReplicasOverride{}, fmt.Errorf("invalid replicas override %q: count must be a non-negative integer", s)
+	}
+	return ReplicasOverride{Name: name, Replicas: replicas}, nil
+}
+
+// scalableKinds are the kinds whose spec.replicas an override can set.
+var scalableKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"ReplicaSet":  true,
+}
+
+// ApplyOverrides sets the images and replica counts of overrides on the
+// manifests, which are modified in place. Image overrides update the
+// containers and init containers of Pods and of the pod templates of
+// workloads, including CronJobs.
+//
+// Overrides that match nothing are a *ValidationError with
+// CodeUnmatchedOverride, so that a misspelled name does not silently leave
+// the value in the code in place.
+func ApplyOverrides(manifests []interface{}, overrides Overrides) error {
+	imageMatched := make([]bool, len(overrides.Images))
+	replicasMatched := make([]bool, len(overrides.Replicas))
+
+	for _, m := range manifests {
+		manifest, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := manifest["kind"].(string)
+		var name string
+		if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+			name, _ = metadata["name"].(string)
+		}
+
+		for i, o := range overrides.Replicas {
+			if scalableKinds[kind] && name == o.Name {
+				spec := childMap(manifest, "spec")
+				spec["replicas"] = o.Replicas
+				replicasMatched[i] = true
+			}
+		}
+
+		podSpec := manifestPodSpec(manifest)
+		if podSpec == nil {
+			continue
+		}
+		for i, o := range overrides.Images {
+			if o.Workload != "" && o.Workload != name {
+				continue
+			}
+			for _, key := range []string{"initContainers", "containers"} {
+				containers, _ := podSpec[key].([]interface{})
+				for _, c := range containers {
+					if container, ok := c.(map[string]interface{}); ok && container["name"] == o.Container {
+						container["image"] = o.Image
+						imageMatched[i] = true
+					}
+				}
+			}
+		}
+	}
+
+	var errs []ResourceError
+	for i, o := range overrides.Images {
+		switch {
+		case imageMatched[i]:
+		case o.Workload != "":
+			errs = append(errs, ResourceError{Code: CodeUnmatchedOverride,
+				Message: fmt.Sprintf("image override %s/%s: no workload %q with a container named %q", o.Workload, o.Container, o.Workload, o.Container)})
+		default:
+			errs = append(errs, ResourceError{Code: CodeUnmatchedOverride,
+				Message: fmt.Sprintf("image override %s: no container named %q", o.Container, o.Container)})
+		}
+	}
+	for i, o := range overrides.Replicas {
+		if !replicasMatched[i] {
+			errs = append(errs, ResourceError{Code: CodeUnmatchedOverride,
+				Message: fmt.Sprintf("replicas override %s: no Deployment, StatefulSet or ReplicaSet named %q", o.Name, o.Name)})
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Summary: "unmatched overrides", Errors: errs}
+	}
+	return nil
+}
+
+// manifestPodSpec returns the pod spec of a Pod manifest or of the pod
+// template of a workload manifest, or nil if it has none.
+func manifestPodSpec(manifest map[string]interface{}) map[string]interface{} {
+	path := []string{"spec", "template", "spec"}
+	switch manifest["kind"] {
+	case "Pod":
+		path = []string{"spec"}
+	case "CronJob":
+		path = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	}
+
+	current := manifest
+	for _, key := range path {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = next
+	}
+	return current
+}
+
+// childMap returns the map under key in m, creating it if it is not set.
+func childMap(m map[string]interface{}, key string) map[string]interface{} {
+	child, ok := m[key].(map[string]interface{})
+	if !ok {
+		child = make(map[string]interface{})
+		m[key] = child
+	}
+	return child
+}
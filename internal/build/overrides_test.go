@@ -0,0 +1,121 @@
+package build_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImageOverride(t *testing.T) {
+	o, err := build.ParseImageOverride("app=registry.example.com/app:1.27")
+	require.NoError(t, err)
+	assert.Equal(t, build.ImageOverride{Container: "app", Image: "registry.example.com/app:1.27"}, o)
+
+	o, err = build.ParseImageOverride("web/app=nginx:1.27")
+	require.NoError(t, err)
+	assert.Equal(t, build.ImageOverride{Workload: "web", Container: "app", Image: "nginx:1.27"}, o)
+
+	for _, s := range []string{"app", "=nginx", "app=", "/app=nginx", "web/=nginx", "a/b/c=nginx"} {
+		_, err := build.ParseImageOverride(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestParseReplicasOverride(t *testing.T) {
+	o, err := build.ParseReplicasOverride("web-app=5")
+	require.NoError(t, err)
+	assert.Equal(t, build.ReplicasOverride{Name: "web-app", Replicas: 5}, o)
+
+	for _, s := range []string{"web-app", "=5", "web-app=", "web-app=-1", "web-app=five", "web-app=99999999999"} {
+		_, err := build.ParseReplicasOverride(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func cronJob() map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "CronJob",
+		"metadata":   map[string]interface{}{"name": "report"},
+		"spec": map[string]interface{}{
+			"jobTemplate": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"initContainers": []interface{}{map[string]interface{}{"name": "migrate", "image": "app:1.0"}},
+							"containers":     []interface{}{map[string]interface{}{"name": "app", "image": "app:1.0"}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestApplyOverrides_Images(t *testing.T) {
+	web, job := baseDeployment(), cronJob()
+	manifests := []interface{}{web, job}
+
+	err := build.ApplyOverrides(manifests, build.Overrides{Images: []build.ImageOverride{
+		{Container: "web", Image: "nginx:1.28"},
+		{Workload: "report", Container: "migrate", Image: "app:2.0"},
+	}})
+	require.NoError(t, err)
+
+	containers := web["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})
+	assert.Equal(t, "nginx:1.28", containers[0].(map[string]interface{})["image"])
+	assert.Equal(t, "envoy:1.30", containers[1].(map[string]interface{})["image"], "other containers are kept")
+
+	podSpec := job["spec"].(map[string]interface{})["jobTemplate"].(map[string]interface{})["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	assert.Equal(t, "app:2.0", podSpec["initContainers"].([]interface{})[0].(map[string]interface{})["image"])
+	assert.Equal(t, "app:1.0", podSpec["containers"].([]interface{})[0].(map[string]interface{})["image"])
+}
+
+func TestApplyOverrides_Replicas(t *testing.T) {
+	web := baseDeployment()
+	service := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]interface{}{"name": "web"},
+	}
+	stub := map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "StatefulSet",
+		"metadata":   map[string]interface{}{"name": "db"},
+	}
+
+	err := build.ApplyOverrides([]interface{}{web, service, stub}, build.Overrides{Replicas: []build.ReplicasOverride{
+		{Name: "web", Replicas: 5},
+		{Name: "db", Replicas: 3},
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(5), web["spec"].(map[string]interface{})["replicas"])
+	assert.NotContains(t, service, "spec", "only workloads are scaled")
+	assert.Equal(t, map[string]interface{}{"replicas": int64(3)}, stub["spec"], "a missing spec is created")
+}
+
+func TestApplyOverrides_Unmatched(t *testing.T) {
+	err := build.ApplyOverrides([]interface{}{baseDeployment(), cronJob()}, build.Overrides{
+		Images: []build.ImageOverride{
+			{Container: "web", Image: "nginx:1.28"},
+			{Container: "sidecar", Image: "envoy:1.31"},
+			{Workload: "report", Container: "web", Image: "nginx:1.28"},
+		},
+		Replicas: []build.ReplicasOverride{{Name: "report", Replicas: 2}},
+	})
+
+	var validationErr *build.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "unmatched overrides", validationErr.Summary)
+	require.Len(t, validationErr.Errors, 3)
+	for _, e := range validationErr.Errors {
+		assert.Equal(t, build.CodeUnmatchedOverride, e.Code)
+	}
+	assert.Equal(t, `image override sidecar: no container named "sidecar"`, validationErr.Errors[0].Message)
+	assert.Equal(t, `image override report/web: no workload "report" with a container named "web"`, validationErr.Errors[1].Message)
+	assert.Equal(t, `replicas override report: no Deployment, StatefulSet or ReplicaSet named "report"`, validationErr.Errors[2].Message)
+}
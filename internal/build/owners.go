@@ -0,0 +1,152 @@
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+	"github.com/lex00/wetwire-k8s-go/internal/serialize"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ownerKinds are the kinds that own the resources referring to them.
+var ownerKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+	"ReplicaSet":  true,
+	"Job":         true,
+	"CronJob":     true,
+}
+
+// CodeUnknownOwnerUID is the code of owner references whose owner has no
+// UID in the OwnerUID list.
+const CodeUnknownOwnerUID = "unknown-owner-uid"
+
+// OwnerUID is the UID of a live workload, as assigned by the API server when
+// it was created, for use in owner references to it.
+type OwnerUID struct {
+	Namespace string // metadata.namespace of the workload, or "" for any namespace
+	Kind      string // Kind of the workload, e.g. "Deployment"
+	Name      string // metadata.name of the workload
+	UID       types.UID
+}
+
+// ParseOwnerUID parses an owner UID written as kind/name=uid, or
+// namespace/kind/name=uid for the workload of one namespace only, as in
+// deployment/web=6f1f5c1e-3c4b-4f7e-9a51-2d0e8b7c9a10. The UID is printed by
+// kubectl get deployment web -o jsonpath='{.metadata.uid}'.
+func ParseOwnerUID(s string) (OwnerUID, error) {
+	selector, uid, ok := strings.Cut(s, "=")
+	parts := strings.Split(selector, "/")
+	if !ok || uid == "" || len(parts) < 2 || len(parts) > 3 {
+		return OwnerUID{}, fmt.Errorf("invalid owner UID %q: expected [namespace/]kind/name=uid", s)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return OwnerUID{}, fmt.Errorf("invalid owner UID %q: expected [namespace/]kind/name=uid", s)
+		}
+	}
+	o := OwnerUID{Kind: parts[len(parts)-2], Name: parts[len(parts)-1], UID: types.UID(uid)}
+	if len(parts) == 3 {
+		o.Namespace = parts[0]
+	}
+	return o, nil
+}
+
+// SetOwnerReferences returns a copy of resources in which each resource that
+// refers to exactly one workload (a Deployment, StatefulSet, DaemonSet,
+// ReplicaSet, Job or CronJob) in its own namespace has that workload as its
+// controller owner, so that deleting the workload garbage collects it, such
+// as a Service selecting a Deployment's pods. Workloads, cluster-scoped
+// kinds and helper values are never owned, and resources referring to
+// several workloads are left unchanged since their owner is ambiguous.
+//
+// The API server assigns UIDs when objects are created, and the garbage
+// collector deletes resources whose owner UID does not exist, so the UID of
+// each owner is taken from uids, which hold the UIDs of the live workloads.
+// Owners without one are a *ValidationError with CodeUnknownOwnerUID rather
+// than references with a made-up UID.
+func SetOwnerReferences(resources []discover.Resource, uids []OwnerUID) ([]discover.Resource, error) {
+	byName := make(map[string]discover.Resource, len(resources))
+	for _, r := range resources {
+		byName[r.Name] = r
+	}
+
+	result := make([]discover.Resource, len(resources))
+	var errs []ResourceError
+	for i, r := range resources {
+		result[i] = r
+		_, kind := resourceAPIVersionKind(r.Type)
+		if ownerKinds[kind] || serialize.IsClusterScoped(kind) || discover.IsHelper(r) {
+			continue
+		}
+
+		var owners []discover.Resource
+		for _, dep := range r.Dependencies {
+			owner, ok := byName[dep]
+			if !ok || owner.Namespace != r.Namespace {
+				continue
+			}
+			if _, ownerKind := resourceAPIVersionKind(owner.Type); ownerKinds[ownerKind] {
+				owners = append(owners, owner)
+			}
+		}
+		if len(owners) != 1 {
+			continue
+		}
+
+		ref, ok := ownerReference(owners[0], uids)
+		if !ok {
+			errs = append(errs, resourceError(r, CodeUnknownOwnerUID,
+				fmt.Sprintf("%s %s is owned by %s %s, whose UID is unknown; give the UID of the live %s as %s=<uid>", kind, r.Name, ref.Kind, ref.Name, strings.ToLower(ref.Kind), ownerSelector(owners[0], ref))))
+			continue
+		}
+		result[i].OwnerReferences = []metav1.OwnerReference{ref}
+	}
+	if len(errs) > 0 {
+		return nil, &ValidationError{Summary: "unknown owner UIDs", Errors: errs}
+	}
+	return result, nil
+}
+
+// ownerReference returns a controller reference to owner, and whether uids
+// hold its UID.
+func ownerReference(owner discover.Resource, uids []OwnerUID) (metav1.OwnerReference, bool) {
+	apiVersion, kind := resourceAPIVersionKind(owner.Type)
+	controller := true
+	ref := metav1.OwnerReference{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Name:       ObjectName(owner),
+		Controller: &controller,
+	}
+
+	// A UID for the owner's namespace takes precedence over one for any
+	// namespace
+	var found bool
+	for _, o := range uids {
+		if !strings.EqualFold(o.Kind, kind) || o.Name != ref.Name {
+			continue
+		}
+		if o.Namespace == owner.Namespace && owner.Namespace != "" {
+			ref.UID = o.UID
+			return ref, true
+		}
+		if o.Namespace == "" && !found {
+			ref.UID, found = o.UID, true
+		}
+	}
+	return ref, found
+}
+
+// ownerSelector returns the [namespace/]kind/name an OwnerUID for the owner
+// of ref is written as.
+func ownerSelector(owner discover.Resource, ref metav1.OwnerReference) string {
+	selector := strings.ToLower(ref.Kind) + "/" + ref.Name
+	if owner.Namespace != "" {
+		selector = owner.Namespace + "/" + selector
+	}
+	return selector
+}
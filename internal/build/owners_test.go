@@ -0,0 +1,123 @@
+package build_test
+
+import (
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSetOwnerReferences(t *testing.T) {
+	resources := []discover.Resource{
+		{Name: "WebDeployment", Type: "appsv1.Deployment", Namespace: "prod", MetadataName: "web"},
+		{Name: "WebService", Type: "corev1.Service", Namespace: "prod", Dependencies: []string{"WebDeployment"}},
+		{Name: "WebConfig", Type: "corev1.ConfigMap", Namespace: "prod"},
+		{Name: "OtherService", Type: "corev1.Service", Namespace: "staging", Dependencies: []string{"WebDeployment"}},
+		{Name: "Worker", Type: "appsv1.Deployment", Namespace: "prod", Dependencies: []string{"WebDeployment"}},
+		{Name: "Both", Type: "corev1.Service", Namespace: "prod", Dependencies: []string{"WebDeployment", "Worker"}},
+		{Name: "Reader", Type: "rbacv1.ClusterRole", Dependencies: []string{"WebDeployment"}},
+	}
+
+	uids := []build.OwnerUID{
+		{Namespace: "prod", Kind: "Deployment", Name: "web", UID: "6f1f5c1e-3c4b-4f7e-9a51-2d0e8b7c9a10"},
+		{Namespace: "staging", Kind: "Deployment", Name: "web", UID: "0b3d6c8e-5a1f-4c2e-8d7b-9e4f1a2c3b5d"},
+	}
+	owned, err := build.SetOwnerReferences(resources, uids)
+	require.NoError(t, err)
+	require.Len(t, owned, len(resources))
+
+	// Only WebService refers to a single workload in its own namespace
+	for i, r := range owned {
+		if r.Name != "WebService" {
+			assert.Empty(t, r.OwnerReferences, r.Name)
+		} else {
+			require.Len(t, owned[i].OwnerReferences, 1)
+		}
+	}
+
+	ref := owned[1].OwnerReferences[0]
+	assert.Equal(t, "apps/v1", ref.APIVersion)
+	assert.Equal(t, "Deployment", ref.Kind)
+	assert.Equal(t, "web", ref.Name, "metadata name of the owner")
+	require.NotNil(t, ref.Controller)
+	assert.True(t, *ref.Controller)
+
+	// The UID is the one given for the live owner in its namespace
+	assert.Equal(t, types.UID("6f1f5c1e-3c4b-4f7e-9a51-2d0e8b7c9a10"), ref.UID)
+
+	// The input is not modified
+	assert.Empty(t, resources[1].OwnerReferences)
+}
+
+func TestSetOwnerReferences_UID(t *testing.T) {
+	resources := []discover.Resource{
+		{Name: "WebDeployment", Type: "appsv1.Deployment", Namespace: "prod", MetadataName: "web"},
+		{Name: "WebService", Type: "corev1.Service", Namespace: "prod", Dependencies: []string{"WebDeployment"}},
+	}
+
+	t.Run("a UID without a namespace matches any namespace", func(t *testing.T) {
+		owned, err := build.SetOwnerReferences(resources, []build.OwnerUID{
+			{Kind: "deployment", Name: "web", UID: "any-namespace"},
+		})
+		require.NoError(t, err)
+		require.Len(t, owned[1].OwnerReferences, 1)
+		assert.Equal(t, types.UID("any-namespace"), owned[1].OwnerReferences[0].UID)
+	})
+
+	t.Run("a UID for the owner's namespace takes precedence", func(t *testing.T) {
+		owned, err := build.SetOwnerReferences(resources, []build.OwnerUID{
+			{Kind: "Deployment", Name: "web", UID: "any-namespace"},
+			{Namespace: "prod", Kind: "Deployment", Name: "web", UID: "prod"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, types.UID("prod"), owned[1].OwnerReferences[0].UID)
+	})
+
+	t.Run("an owner without a UID is an error", func(t *testing.T) {
+		for _, uids := range [][]build.OwnerUID{
+			nil,
+			{{Namespace: "staging", Kind: "Deployment", Name: "web", UID: "staging"}},
+			{{Kind: "StatefulSet", Name: "web", UID: "statefulset"}},
+		} {
+			_, err := build.SetOwnerReferences(resources, uids)
+			var validationErr *build.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			require.Len(t, validationErr.Errors, 1)
+			assert.Equal(t, build.CodeUnknownOwnerUID, validationErr.Errors[0].Code)
+			assert.Equal(t, "WebService", validationErr.Errors[0].Resource)
+			assert.Equal(t, "Service WebService is owned by Deployment web, whose UID is unknown; give the UID of the live deployment as prod/deployment/web=<uid>", validationErr.Errors[0].Message)
+		}
+	})
+}
+
+func TestParseOwnerUID(t *testing.T) {
+	o, err := build.ParseOwnerUID("deployment/web=6f1f5c1e-3c4b-4f7e-9a51-2d0e8b7c9a10")
+	require.NoError(t, err)
+	assert.Equal(t, build.OwnerUID{Kind: "deployment", Name: "web", UID: "6f1f5c1e-3c4b-4f7e-9a51-2d0e8b7c9a10"}, o)
+
+	o, err = build.ParseOwnerUID("prod/Deployment/web=abc")
+	require.NoError(t, err)
+	assert.Equal(t, build.OwnerUID{Namespace: "prod", Kind: "Deployment", Name: "web", UID: "abc"}, o)
+
+	for _, s := range []string{"web=abc", "deployment/web", "deployment/web=", "/web=abc", "a/b/c/d=abc", "prod//web=abc"} {
+		_, err := build.ParseOwnerUID(s)
+		require.Error(t, err, s)
+		assert.Contains(t, err.Error(), "expected [namespace/]kind/name=uid")
+	}
+}
+
+func TestOwnerReferences_GeneratedName(t *testing.T) {
+	// Owners without a metadata name are referred to by their generated name
+	owned, err := build.SetOwnerReferences([]discover.Resource{
+		{Name: "ApiJob", Type: "batchv1.Job"},
+		{Name: "ApiConfig", Type: "corev1.ConfigMap", Dependencies: []string{"ApiJob"}},
+	}, []build.OwnerUID{{Kind: "Job", Name: "api-job", UID: "5c3e2a1b-7d4f-4e6a-b8c9-0a1b2c3d4e5f"}})
+	require.NoError(t, err)
+
+	require.Len(t, owned[1].OwnerReferences, 1)
+	assert.Equal(t, "api-job", owned[1].OwnerReferences[0].Name)
+	assert.Equal(t, "batch/v1", owned[1].OwnerReferences[0].APIVersion)
+}
@@ -0,0 +1,352 @@
+package build
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+)
+
+// TargetIssue is a selector or target reference that matches no resource in
+// the package.
+type TargetIssue struct {
+	Resource string // Variable of the referencing resource
+	File     string // Source file of the reference
+	Line     int    // Line of the reference
+	Message  string // Description of the problem
+}
+
+// podLabelPaths maps workload kinds to the field path of their pod labels.
+var podLabelPaths = map[string][]string{
+	"Deployment":  {"Spec", "Template", "ObjectMeta", "Labels"},
+	"StatefulSet": {"Spec", "Template", "ObjectMeta", "Labels"},
+	"DaemonSet":   {"Spec", "Template", "ObjectMeta", "Labels"},
+	"ReplicaSet":  {"Spec", "Template", "ObjectMeta", "Labels"},
+	"Job":         {"Spec", "Template", "ObjectMeta", "Labels"},
+	"CronJob":     {"Spec", "JobTemplate", "Spec", "Template", "ObjectMeta", "Labels"},
+	"Pod":         {"ObjectMeta", "Labels"},
+}
+
+// ValidateTargets checks references between resources that are made by
+// name or label rather than by Go variable:
+//
+//   - a Service selector must match the pod labels of a workload
+//   - an HPA ScaleTargetRef must name an existing workload of its kind
+//   - an Ingress backend must name an existing Service
+//
+// Values set through package-level variables and constants, or through
+// fields of other resources (e.g. WebApp.Spec.Selector.MatchLabels), are
+// followed. References whose values cannot be determined are not reported.
+func ValidateTargets(resources []discover.Resource) ([]TargetIssue, error) {
+	fset := token.NewFileSet()
+	syms, err := parseSymbols(fset, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &targetValidator{fset: fset, syms: syms}
+	for _, r := range resources {
+		_, kind := resourceAPIVersionKind(r.Type)
+		target := &targetResource{Resource: r, kind: kind, value: syms[r.Name]}
+		if target.value == nil {
+			continue
+		}
+		target.name, _ = syms.resolveString(syms.selectPath(target.value, "Name"))
+		target.namespace, _ = syms.resolveString(syms.selectPath(target.value, "Namespace"))
+		v.resources = append(v.resources, target)
+	}
+
+	for _, r := range v.resources {
+		switch r.kind {
+		case "Service":
+			v.checkServiceSelector(r)
+		case "HorizontalPodAutoscaler":
+			v.checkScaleTarget(r)
+		case "Ingress":
+			v.checkIngressBackends(r)
+		}
+	}
+
+	return v.issues, nil
+}
+
+// targetResource is a resource with its initializer and resolved metadata.
+type targetResource struct {
+	discover.Resource
+	kind      string
+	value     ast.Expr
+	name      string // metadata.name, empty if unknown
+	namespace string // metadata.namespace, empty if unset or unknown
+}
+
+// targetValidator checks the references of a set of resources.
+type targetValidator struct {
+	fset      *token.FileSet
+	syms      symbols
+	resources []*targetResource
+	issues    []TargetIssue
+}
+
+// report records an issue for resource r at the position of node.
+func (v *targetValidator) report(r *targetResource, node ast.Node, format string, args ...any) {
+	pos := v.fset.Position(node.Pos())
+	v.issues = append(v.issues, TargetIssue{
+		Resource: r.Name,
+		File:     pos.Filename,
+		Line:     pos.Line,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// sameNamespace reports whether two resources may be in the same namespace.
+// An unset namespace is resolved at apply time and matches any namespace.
+func sameNamespace(a, b *targetResource) bool {
+	return a.namespace == "" || b.namespace == "" || a.namespace == b.namespace
+}
+
+// checkServiceSelector reports a Service whose selector matches the pod
+// labels of no workload. Services without a selector are not checked.
+func (v *targetValidator) checkServiceSelector(svc *targetResource) {
+	expr := v.syms.selectPath(svc.value, "Spec", "Selector")
+	selector, ok := v.syms.resolveStringMap(expr)
+	if !ok || len(selector) == 0 {
+		return
+	}
+
+	for _, r := range v.resources {
+		path, isWorkload := podLabelPaths[r.kind]
+		if !isWorkload || !sameNamespace(svc, r) {
+			continue
+		}
+		labels, ok := v.syms.resolveStringMap(v.syms.selectPath(r.value, path...))
+		if !ok {
+			return // Unknown or unset labels might match
+		}
+		if matchesSelector(selector, labels) {
+			return
+		}
+	}
+
+	v.report(svc, expr, "Service selector %s matches no Deployment, StatefulSet or other workload pod labels", formatLabels(selector))
+}
+
+// checkScaleTarget reports an HPA whose ScaleTargetRef names no resource of
+// the referenced kind.
+func (v *targetValidator) checkScaleTarget(hpa *targetResource) {
+	ref := v.syms.selectPath(hpa.value, "Spec", "ScaleTargetRef")
+	kind, ok := v.syms.resolveString(v.syms.selectPath(ref, "Kind"))
+	if !ok {
+		return
+	}
+	nameExpr := v.syms.selectPath(ref, "Name")
+	name, ok := v.syms.resolveString(nameExpr)
+	if !ok {
+		return
+	}
+
+	if !v.exists(hpa, kind, name) {
+		v.report(hpa, nameExpr, "ScaleTargetRef refers to %s %q, which is not defined", kind, name)
+	}
+}
+
+// checkIngressBackends reports Ingress backends that name no Service.
+func (v *targetValidator) checkIngressBackends(ing *targetResource) {
+	ast.Inspect(ing.value, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok || compositeTypeName(lit) != "IngressServiceBackend" {
+			return true
+		}
+
+		nameExpr := fieldValue(lit, "Name")
+		name, ok := v.syms.resolveString(nameExpr)
+		if ok && !v.exists(ing, "Service", name) {
+			v.report(ing, nameExpr, "backend refers to Service %q, which is not defined", name)
+		}
+		return true
+	})
+}
+
+// exists reports whether a resource of the given kind and metadata name is
+// defined in the namespace of from. Resources with an unknown name might be
+// the target, so they count as a match.
+func (v *targetValidator) exists(from *targetResource, kind, name string) bool {
+	for _, r := range v.resources {
+		if r.kind == kind && sameNamespace(from, r) && (r.name == "" || r.name == name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesSelector reports whether labels contain every selector entry.
+func matchesSelector(selector, labels map[string]string) bool {
+	for k, want := range selector {
+		if got, ok := labels[k]; !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// formatLabels formats a label map as {k=v, ...} in key order.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// symbols maps the top-level variables and constants of the resource files
+// to their initializers.
+type symbols map[string]ast.Expr
+
+// parseSymbols parses the source files of the resources and collects their
+// top-level variable and constant initializers.
+func parseSymbols(fset *token.FileSet, resources []discover.Resource) (symbols, error) {
+	syms := symbols{}
+	parsed := map[string]bool{}
+
+	for _, r := range resources {
+		if parsed[r.File] {
+			continue
+		}
+		parsed[r.File] = true
+
+		file, err := parser.ParseFile(fset, r.File, nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", r.File, err)
+		}
+
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || (genDecl.Tok != token.VAR && genDecl.Tok != token.CONST) {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for i, ident := range valueSpec.Names {
+					if i < len(valueSpec.Values) {
+						syms[ident.Name] = valueSpec.Values[i]
+					}
+				}
+			}
+		}
+	}
+
+	return syms, nil
+}
+
+// maxResolveDepth bounds how many identifiers resolve follows, so that
+// self-referential initializers cannot loop.
+const maxResolveDepth = 16
+
+// resolve follows identifiers, field selectors, pointers and single-argument
+// calls such as conversions or ptr helpers to the expression that defines a
+// value. It returns nil if the value cannot be determined.
+func (s symbols) resolve(expr ast.Expr) ast.Expr {
+	for depth := 0; depth < maxResolveDepth; depth++ {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.UnaryExpr:
+			if e.Op != token.AND {
+				return nil
+			}
+			expr = e.X
+		case *ast.CallExpr:
+			if len(e.Args) != 1 {
+				return nil
+			}
+			expr = e.Args[0]
+		case *ast.Ident:
+			value, ok := s[e.Name]
+			if !ok {
+				return nil
+			}
+			expr = value
+		case *ast.SelectorExpr:
+			lit, ok := s.resolve(e.X).(*ast.CompositeLit)
+			if !ok {
+				return nil
+			}
+			expr = s.selectField(lit, e.Sel.Name)
+		case *ast.BasicLit, *ast.CompositeLit:
+			return e
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// selectField returns the value of a field in a struct literal, including
+// metadata fields promoted from an embedded ObjectMeta (e.g. Deployment.Name).
+func (s symbols) selectField(lit *ast.CompositeLit, name string) ast.Expr {
+	if value := fieldValue(lit, name); value != nil {
+		return value
+	}
+	if meta, ok := s.resolve(fieldValue(lit, "ObjectMeta")).(*ast.CompositeLit); ok {
+		return fieldValue(meta, name)
+	}
+	return nil
+}
+
+// selectPath follows a path of fields from expr, returning nil if any field
+// along the path is unset or cannot be resolved.
+func (s symbols) selectPath(expr ast.Expr, path ...string) ast.Expr {
+	for _, name := range path {
+		lit, ok := s.resolve(expr).(*ast.CompositeLit)
+		if !ok {
+			return nil
+		}
+		expr = s.selectField(lit, name)
+	}
+	return expr
+}
+
+// resolveString resolves expr to a string literal value.
+func (s symbols) resolveString(expr ast.Expr) (string, bool) {
+	lit, ok := s.resolve(expr).(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// resolveStringMap resolves expr to a map literal with string keys and
+// values.
+func (s symbols) resolveStringMap(expr ast.Expr) (map[string]string, bool) {
+	lit, ok := s.resolve(expr).(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+
+	result := make(map[string]string, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, false
+		}
+		key, ok := s.resolveString(kv.Key)
+		if !ok {
+			return nil, false
+		}
+		value, ok := s.resolveString(kv.Value)
+		if !ok {
+			return nil, false
+		}
+		result[key] = value
+	}
+	return result, true
+}
@@ -0,0 +1,113 @@
+package build_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/lex00/wetwire-k8s-go/internal/discover"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validateExampleTargets copies an example's main.go with the given
+// replacements applied and validates its targets.
+func validateExampleTargets(t *testing.T, example string, replacements ...string) []build.TargetIssue {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join("..", "..", "examples", example, "main.go"))
+	require.NoError(t, err)
+
+	src := string(content)
+	for i := 0; i < len(replacements); i += 2 {
+		require.Contains(t, src, replacements[i])
+		src = strings.Replace(src, replacements[i], replacements[i+1], 1)
+	}
+
+	testFile := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(testFile, []byte(src), 0644))
+
+	resources, err := discover.DiscoverFile(testFile)
+	require.NoError(t, err)
+	issues, err := build.ValidateTargets(resources)
+	require.NoError(t, err)
+	return issues
+}
+
+func TestValidateTargets_Examples(t *testing.T) {
+	for _, example := range []string{"hpa", "web-service"} {
+		t.Run(example, func(t *testing.T) {
+			assert.Empty(t, validateExampleTargets(t, example))
+		})
+	}
+}
+
+func TestValidateTargets_HPAScaleTarget(t *testing.T) {
+	issues := validateExampleTargets(t, "hpa",
+		`Kind:       "Deployment",
+			Name:       "web-app",`,
+		`Kind:       "Deployment",
+			Name:       "api-server",`)
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, "WebAppHPA", issues[0].Resource)
+	assert.Equal(t, `ScaleTargetRef refers to Deployment "api-server", which is not defined`, issues[0].Message)
+	assert.Equal(t, 101, issues[0].Line)
+}
+
+func TestValidateTargets_ServiceSelector(t *testing.T) {
+	issues := validateExampleTargets(t, "web-service",
+		"Selector: WebAppDeployment.Spec.Selector.MatchLabels,",
+		`Selector: map[string]string{"app": "web", "tier": "frontend"},`)
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, "WebAppService", issues[0].Resource)
+	assert.Equal(t, "Service selector {app=web, tier=frontend} matches no Deployment, StatefulSet or other workload pod labels", issues[0].Message)
+	assert.Equal(t, 137, issues[0].Line)
+}
+
+func TestValidateTargets_IngressBackend(t *testing.T) {
+	issues := validateExampleTargets(t, "web-service",
+		"Name: WebAppService.Name,",
+		`Name: "webapp-svc",`)
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, "WebAppIngress", issues[0].Resource)
+	assert.Equal(t, `backend refers to Service "webapp-svc", which is not defined`, issues[0].Message)
+}
+
+func TestValidateTargets_Namespaces(t *testing.T) {
+	content := `package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var Web = corev1.Pod{
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "prod", Labels: map[string]string{"app": "web"}},
+}
+
+var WebService = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "staging"},
+	Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+}
+
+var Headless = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "headless", Namespace: "staging"},
+}
+`
+	testFile := filepath.Join(t.TempDir(), "ns.go")
+	require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+	resources, err := discover.DiscoverFile(testFile)
+	require.NoError(t, err)
+	issues, err := build.ValidateTargets(resources)
+	require.NoError(t, err)
+
+	// The pod is in another namespace; the selectorless Service is not checked
+	require.Len(t, issues, 1)
+	assert.Equal(t, "WebService", issues[0].Resource)
+}
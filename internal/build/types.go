@@ -22,6 +22,21 @@ type Options struct {
 	// For SeparateFiles mode, this is the output directory path.
 	OutputPath string
 
+	// ApplyOrder adds dependencies on the ConfigMaps, Secrets, claims and
+	// ServiceAccounts that resources refer to by name, so that the output
+	// can be applied in order. See AddApplyOrderDependencies.
+	ApplyOrder bool
+
+	// Namespace, when set, replaces the namespace of every namespaced
+	// resource. See OverrideNamespace.
+	Namespace string
+
+	// OwnerReferences sets ownerReferences on resources owned by a workload
+	// in the package, with the UIDs of the live owners in OwnerUIDs. See
+	// SetOwnerReferences.
+	OwnerReferences bool
+	OwnerUIDs       []OwnerUID
+
 	// Serializer is used to convert resources to YAML/JSON.
 	// If nil, a stub serializer will be used (for testing).
 	Serializer Serializer
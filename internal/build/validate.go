@@ -8,7 +8,8 @@ import (
 )
 
 // ValidateReferences checks that all resource dependencies reference existing resources.
-// It returns an error if any resource references a non-existent resource or itself.
+// It returns a *ValidationError if any resource references a non-existent
+// resource or itself.
 func ValidateReferences(resources []discover.Resource) error {
 	// Build a set of all resource names
 	resourceNames := make(map[string]bool)
@@ -17,36 +18,42 @@ func ValidateReferences(resources []discover.Resource) error {
 	}
 
 	// Check each resource's dependencies
-	var errors []string
+	var errors []ResourceError
 	for _, r := range resources {
 		for _, dep := range r.Dependencies {
 			// Check for self-reference
 			if dep == r.Name {
-				errors = append(errors, fmt.Sprintf("resource %q references itself", r.Name))
+				errors = append(errors, resourceError(r, CodeInvalidReference,
+					fmt.Sprintf("resource %q references itself", r.Name)))
 				continue
 			}
 
 			// Check if dependency exists
 			if !resourceNames[dep] {
-				errors = append(errors, fmt.Sprintf("resource %q references non-existent resource %q", r.Name, dep))
+				errors = append(errors, resourceError(r, CodeInvalidReference,
+					fmt.Sprintf("resource %q references non-existent resource %q", r.Name, dep)))
 			}
 		}
 	}
 
 	if len(errors) > 0 {
-		return fmt.Errorf("validation failed:\n  - %s", strings.Join(errors, "\n  - "))
+		return &ValidationError{Summary: "validation failed", Errors: errors}
 	}
 
 	return nil
 }
 
 // DetectCycles detects circular dependencies in the resource graph.
-// It returns an error if any cycles are found.
+// It returns a *ValidationError for the first cycle found, listing the
+// resources that form it, e.g. "cycle detected: A -> B -> C -> A", located at
+// the first of them.
 func DetectCycles(resources []discover.Resource) error {
 	// Build adjacency list
 	graph := make(map[string][]string)
+	byName := make(map[string]discover.Resource)
 	for _, r := range resources {
 		graph[r.Name] = r.Dependencies
+		byName[r.Name] = r
 	}
 
 	// Track visited nodes and nodes in current path
@@ -90,7 +97,9 @@ func DetectCycles(resources []discover.Resource) error {
 	for _, r := range resources {
 		if !visited[r.Name] {
 			if cycle, cyclePath := hasCycle(r.Name, []string{}); cycle {
-				return fmt.Errorf("cycle detected: %s", strings.Join(cyclePath, " -> "))
+				return &ValidationError{Summary: "cycle detected", Errors: []ResourceError{
+					resourceError(byName[cyclePath[0]], CodeCycle, strings.Join(cyclePath, " -> ")),
+				}}
 			}
 		}
 	}
@@ -0,0 +1,112 @@
+// Package config reads .wetwire.yaml, the project configuration written by
+// init, which sets the defaults of the build and lint commands.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the project configuration file.
+const FileName = ".wetwire.yaml"
+
+// Config is the project configuration:
+//
+//	source: k8s              # Directory of the Go resources
+//	output:
+//	  format: yaml           # yaml, json or helm
+//	  path: manifests.yaml   # Output file, or - for stdout
+//	build:
+//	  skip_validation: false # Skip the reference and cycle checks
+//	lint:
+//	  ...                    # See lint.LoadConfig
+//
+// The lint section is read by lint.LoadConfig from Path.
+type Config struct {
+	// Path is the file the configuration was loaded from.
+	Path string `yaml:"-"`
+
+	// Source is the directory of the Go resources, relative to the
+	// directory of the configuration file.
+	Source string `yaml:"source"`
+
+	Output struct {
+		Format string `yaml:"format"`
+		// Path is the file the manifests are written to, relative to the
+		// directory of the configuration file, or "-" for stdout.
+		Path string `yaml:"path"`
+	} `yaml:"output"`
+
+	Build struct {
+		// SkipValidation skips the checks that references point at declared
+		// resources and that resources do not depend on each other in a
+		// cycle.
+		SkipValidation bool `yaml:"skip_validation"`
+	} `yaml:"build"`
+}
+
+// formats are the valid values of output.format.
+var formats = map[string]bool{"yaml": true, "json": true, "helm": true}
+
+// Load reads the configuration file at path. Relative source and output
+// paths are resolved against the directory of the file, so a configuration
+// selected from elsewhere refers to the same files.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	config := &Config{Path: path}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if config.Output.Format != "" && !formats[config.Output.Format] {
+		return nil, fmt.Errorf("%s: output.format: unsupported format %q (supported: yaml, json, helm)", path, config.Output.Format)
+	}
+
+	dir := filepath.Dir(path)
+	if config.Source != "" {
+		config.Source = resolve(dir, config.Source)
+	}
+	if config.Output.Path != "" && config.Output.Path != "-" {
+		config.Output.Path = resolve(dir, config.Output.Path)
+	}
+	return config, nil
+}
+
+// Find looks for .wetwire.yaml in the directory of path (or path itself if
+// it is a directory) and its parents. It returns an empty string if no
+// configuration file is found.
+func Find(path string) string {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	for {
+		candidate := filepath.Join(dir, FileName)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// resolve returns path relative to dir, unless it is absolute.
+func resolve(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
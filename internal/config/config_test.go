@@ -0,0 +1,58 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("should resolve paths against the config directory", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "ci")
+		require.NoError(t, os.Mkdir(dir, 0755))
+		path := filepath.Join(dir, "prod.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`source: ../k8s
+output:
+  format: json
+  path: out/manifests.json
+build:
+  skip_validation: true
+lint:
+  min_severity: error
+`), 0644))
+
+		cfg, err := config.Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, path, cfg.Path)
+		assert.Equal(t, filepath.Join(filepath.Dir(dir), "k8s"), cfg.Source)
+		assert.Equal(t, "json", cfg.Output.Format)
+		assert.Equal(t, filepath.Join(dir, "out", "manifests.json"), cfg.Output.Path)
+		assert.True(t, cfg.Build.SkipValidation)
+	})
+
+	t.Run("should keep stdout and unset values", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), config.FileName)
+		require.NoError(t, os.WriteFile(path, []byte("output:\n  path: \"-\"\n"), 0644))
+
+		cfg, err := config.Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, "-", cfg.Output.Path)
+		assert.Empty(t, cfg.Source)
+		assert.Empty(t, cfg.Output.Format)
+		assert.False(t, cfg.Build.SkipValidation)
+	})
+
+	t.Run("should reject unknown formats and missing files", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), config.FileName)
+		require.NoError(t, os.WriteFile(path, []byte("output:\n  format: toml\n"), 0644))
+		_, err := config.Load(path)
+		assert.ErrorContains(t, err, "output.format")
+
+		_, err = config.Load(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.Error(t, err)
+	})
+}
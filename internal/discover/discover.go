@@ -3,16 +3,94 @@ package discover
 import (
 	"fmt"
 	"go/ast"
+	"go/parser"
 	"go/token"
+	"go/types"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"unicode"
 
 	coreast "github.com/lex00/wetwire-core-go/ast"
 	"github.com/lex00/wetwire-k8s-go/internal/registry"
 )
 
 // DiscoverFile discovers Kubernetes resources in a single Go source file.
+// References are resolved within the file only; use DiscoverDirectory to
+// resolve references to variables declared in other files of the package.
 func DiscoverFile(filePath string) ([]Resource, error) {
+	src, err := parseSource(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return src.discover(newPackageScope(src.file)), nil
+}
+
+// DiscoverSource discovers Kubernetes resources in Go source that is not
+// read from disk, such as a file piped to stdin. filename is the name
+// reported for the resources and in syntax errors, e.g. "stdin.go".
+// References are resolved within the source only.
+func DiscoverSource(src []byte, filename string) ([]Resource, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file %s: %w", filename, err)
+	}
+	s := &source{path: filename, file: file, fset: fset}
+	return s.discover(newPackageScope(file)), nil
+}
+
+// DiscoverDirectory discovers Kubernetes resources in all Go files within a directory recursively.
+// The files of each package are discovered together, so a resource in one file
+// may depend on a resource declared in another file of the same package.
+func DiscoverDirectory(dir string) ([]Resource, error) {
+	opts := coreast.ParseOptions{
+		SkipTests:  true,
+		SkipVendor: true,
+		SkipHidden: true,
+	}
+
+	var sources []*source
+	packages := make(map[string][]*ast.File)
+
+	err := coreast.WalkGoFiles(dir, opts, func(path string) error {
+		src, err := parseSource(path)
+		if err != nil {
+			// Log error but continue processing other files
+			return nil
+		}
+
+		sources = append(sources, src)
+		packages[src.packageKey()] = append(packages[src.packageKey()], src.file)
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory %s: %w", dir, err)
+	}
+
+	scopes := make(map[string]packageScope, len(packages))
+	for key, files := range packages {
+		scopes[key] = newPackageScope(files...)
+	}
+
+	var allResources []Resource
+	for _, src := range sources {
+		allResources = append(allResources, src.discover(scopes[src.packageKey()])...)
+	}
+
+	return allResources, nil
+}
+
+// source is a parsed Go source file.
+type source struct {
+	path string // Absolute path of the file, or the name given to DiscoverSource
+	file *ast.File
+	fset *token.FileSet
+}
+
+// parseSource parses a Go source file.
+func parseSource(filePath string) (*source, error) {
 	// Parse the Go source file using shared utility
 	file, fset, err := coreast.ParseFile(filePath)
 	if err != nil {
@@ -25,10 +103,22 @@ func DiscoverFile(filePath string) ([]Resource, error) {
 		absPath = filePath
 	}
 
+	return &source{path: absPath, file: file, fset: fset}, nil
+}
+
+// packageKey identifies the package a file belongs to: its directory and
+// package name.
+func (src *source) packageKey() string {
+	return filepath.Dir(src.path) + string(filepath.Separator) + src.file.Name.Name
+}
+
+// discover returns the resources declared in the file, resolving references
+// against the top-level declarations of its package.
+func (src *source) discover(scope packageScope) []Resource {
 	var resources []Resource
 
 	// Walk through all declarations in the file
-	for _, decl := range file.Decls {
+	for _, decl := range src.file.Decls {
 		// We're only interested in variable declarations
 		genDecl, ok := decl.(*ast.GenDecl)
 		if !ok || genDecl.Tok != token.VAR {
@@ -42,19 +132,37 @@ func DiscoverFile(filePath string) ([]Resource, error) {
 				continue
 			}
 
+			doc := docText(genDecl, valueSpec)
+
 			// Extract variable name
 			for i, name := range valueSpec.Names {
 				if name.Name == "_" {
 					continue
 				}
 
+				var value ast.Expr
+				if i < len(valueSpec.Values) {
+					value = valueSpec.Values[i]
+				}
+
+				// Slices and maps of objects declare one resource per element
+				if elements, ok := collectionElements(name.Name, value, scope); ok {
+					for _, e := range elements {
+						resource := src.newResource(e.name, e.typ, e.value, e.value.Pos(), scope)
+						resource.Expr = e.expr
+						resource.Doc = doc
+						resources = append(resources, resource)
+					}
+					continue
+				}
+
 				// Get the type - either from explicit type or from initializer
 				var resourceType string
 				if valueSpec.Type != nil {
 					resourceType = getResourceType(valueSpec.Type)
-				} else if i < len(valueSpec.Values) {
+				} else if value != nil {
 					// Type is inferred from initializer
-					resourceType = getResourceTypeFromExpr(valueSpec.Values[i])
+					resourceType = getResourceTypeFromExpr(value)
 				}
 
 				// Skip if not a Kubernetes resource
@@ -62,54 +170,171 @@ func DiscoverFile(filePath string) ([]Resource, error) {
 					continue
 				}
 
-				// Find dependencies in the initializer
-				var deps []string
-				if i < len(valueSpec.Values) {
-					deps = findDependencies(valueSpec.Values[i], file)
-				}
-
-				resource := Resource{
-					Name:         name.Name,
-					Type:         resourceType,
-					File:         absPath,
-					Line:         fset.Position(name.Pos()).Line,
-					Dependencies: deps,
-				}
+				resource := src.newResource(name.Name, resourceType, value, name.Pos(), scope)
+				resource.Doc = doc
 				resources = append(resources, resource)
 			}
 		}
 	}
 
-	return resources, nil
+	return resources
 }
 
-// DiscoverDirectory discovers Kubernetes resources in all Go files within a directory recursively.
-func DiscoverDirectory(dir string) ([]Resource, error) {
-	var allResources []Resource
+// docText returns the doc comment of a variable declared by spec in decl:
+// the comment above the spec in a parenthesized declaration, or above the
+// declaration otherwise.
+func docText(decl *ast.GenDecl, spec *ast.ValueSpec) string {
+	doc := spec.Doc
+	if doc == nil && !decl.Lparen.IsValid() {
+		doc = decl.Doc
+	}
+	return strings.TrimSpace(doc.Text())
+}
 
-	opts := coreast.ParseOptions{
-		SkipTests:  true,
-		SkipVendor: true,
-		SkipHidden: true,
+// newResource returns the resource named name, of type resourceType and
+// declared at pos, finding its dependencies and metadata in its initializer
+// value, which may be nil.
+func (src *source) newResource(name, resourceType string, value ast.Expr, pos token.Pos, scope packageScope) Resource {
+	resource := Resource{
+		Name: name,
+		Type: resourceType,
+		File: src.path,
+		Line: src.fset.Position(pos).Line,
 	}
+	if value != nil {
+		for _, dep := range findDependencies(value, scope) {
+			// An element may refer to other elements of its own collection
+			if dep != name {
+				resource.Dependencies = append(resource.Dependencies, dep)
+			}
+		}
+		resource.Namespace = findObjectMetaField(value, scope, "Namespace")
+		resource.MetadataName = findObjectMetaField(value, scope, "Name")
+		resource.NameRefs = findNameRefs(value, scope)
+	}
+	return resource
+}
 
-	err := coreast.WalkGoFiles(dir, opts, func(path string) error {
-		// Discover resources in this file
-		resources, err := DiscoverFile(path)
-		if err != nil {
-			// Log error but continue processing other files
-			return nil
+// element is a resource declared as an element of a slice, array or map.
+type element struct {
+	name  string   // Synthetic resource name
+	expr  string   // Go expression of the element, e.g. "Services[1]"
+	typ   string   // Element type, e.g. "corev1.Service"
+	value ast.Expr // Element literal
+}
+
+// collectionElements returns the elements of value when it is a slice, array
+// or map literal of Kubernetes objects (not helper types such as EnvVar or
+// Container), and reports whether it is one. Each element is named after the
+// variable followed by its metadata name, map key or index, in that order of
+// preference: the element of Services named "web-api" is ServicesWebApi.
+func collectionElements(varName string, value ast.Expr, scope packageScope) ([]element, bool) {
+	lit, ok := value.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+	var eltType ast.Expr
+	switch t := lit.Type.(type) {
+	case *ast.ArrayType:
+		eltType = t.Elt
+	case *ast.MapType:
+		eltType = t.Value
+	default:
+		return nil, false
+	}
+	typ := getResourceType(eltType)
+	if typ == "" || IsHelper(Resource{Type: typ}) {
+		return nil, false
+	}
+
+	elements := make([]element, 0, len(lit.Elts))
+	used := make(map[string]bool)
+	for i, elt := range lit.Elts {
+		var key string
+		expr := fmt.Sprintf("%s[%d]", varName, i)
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			key = resolveString(kv.Key, scope)
+			expr = fmt.Sprintf("%s[%s]", varName, types.ExprString(kv.Key))
+			elt = kv.Value
 		}
 
-		allResources = append(allResources, resources...)
-		return nil
-	})
+		suffix := findObjectMetaField(elt, scope, "Name")
+		if suffix == "" {
+			suffix = key
+		}
+		name := varName + exportedName(suffix)
+		if suffix == "" || used[name] {
+			name = varName + strconv.Itoa(i)
+		}
+		used[name] = true
+		elements = append(elements, element{name: name, expr: expr, typ: typ, value: elt})
+	}
+	return elements, true
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to walk directory %s: %w", dir, err)
+// exportedName converts a Kubernetes name or map key to an exported Go
+// identifier fragment, e.g. "web-api" -> "WebApi".
+func exportedName(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if upper {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+			upper = false
+		default:
+			upper = true
+		}
 	}
+	return b.String()
+}
 
-	return allResources, nil
+// packageScope maps the top-level variables and constants of a package to
+// their declarations.
+type packageScope map[string]declaration
+
+// declaration is a top-level variable or constant.
+type declaration struct {
+	tok   token.Token // token.VAR or token.CONST
+	spec  *ast.ValueSpec
+	index int // Index of the name in spec
+}
+
+// value returns the initializer of the declaration, or nil if it has none.
+func (d declaration) value() ast.Expr {
+	if d.spec != nil && d.index < len(d.spec.Values) {
+		return d.spec.Values[d.index]
+	}
+	return nil
+}
+
+// newPackageScope collects the top-level variables and constants declared in
+// the files of a package.
+func newPackageScope(files ...*ast.File) packageScope {
+	scope := make(packageScope)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || (genDecl.Tok != token.CONST && genDecl.Tok != token.VAR) {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for i, name := range valueSpec.Names {
+					if _, exists := scope[name.Name]; name.Name != "_" && !exists {
+						scope[name.Name] = declaration{tok: genDecl.Tok, spec: valueSpec, index: i}
+					}
+				}
+			}
+		}
+	}
+	return scope
 }
 
 // getResourceType extracts the Kubernetes resource type from an AST type expression.
@@ -173,24 +398,57 @@ func isKubernetesType(typeName string) bool {
 	return false
 }
 
-// findDependencies finds references to other top-level variables in an expression.
-// This identifies dependencies between resources.
-func findDependencies(expr ast.Expr, file *ast.File) []string {
+// supportingKinds are registered types that are parts of other objects
+// rather than objects of their own.
+var supportingKinds = map[string]bool{
+	"PodTemplateSpec": true,
+	"Container":       true,
+	"Volume":          true,
+}
+
+// IsHelper reports whether r is a helper value, such as a Container, PodSpec
+// or list of EnvVars declared as its own variable, rather than an API object.
+// The decision is made on the type alone: only registered kinds that are not
+// parts of other objects are API objects, while other types from a known
+// package are discovered so they can be referred to but are helpers.
+func IsHelper(r Resource) bool {
+	info, ok := registry.DefaultRegistry.GetTypeInfo(r.Type)
+	return !ok || supportingKinds[info.Kind]
+}
+
+// findDependencies finds references to other top-level resource variables in
+// an expression. This identifies dependencies between resources; unexported
+// helper values such as shared label maps are inlined and are not
+// dependencies. Exported variables are resources by convention, so a
+// reference to one that is not a Kubernetes resource is kept, and reported
+// by build.ValidateReferences.
+func findDependencies(expr ast.Expr, scope packageScope) []string {
 	deps := make(map[string]bool)
 
+	// A reference to a collection of objects depends on all of its elements
+	addDependency := func(name string) {
+		if elements, ok := collectionElements(name, scope[name].value(), scope); ok {
+			for _, e := range elements {
+				deps[e.name] = true
+			}
+			return
+		}
+		deps[name] = true
+	}
+
 	// Walk the expression tree
 	ast.Inspect(expr, func(n ast.Node) bool {
 		switch node := n.(type) {
 		case *ast.Ident:
-			// Check if this identifier is a top-level variable in the file
-			if isTopLevelVar(node.Name, file) {
-				deps[node.Name] = true
+			// Check if this identifier is a top-level resource in the file
+			if isDependency(node.Name, scope) {
+				addDependency(node.Name)
 			}
 		case *ast.SelectorExpr:
 			// Handle cases like AppConfig.Name
 			if ident, ok := node.X.(*ast.Ident); ok {
-				if isTopLevelVar(ident.Name, file) {
-					deps[ident.Name] = true
+				if isDependency(ident.Name, scope) {
+					addDependency(ident.Name)
 				}
 			}
 		}
@@ -206,27 +464,160 @@ func findDependencies(expr ast.Expr, file *ast.File) []string {
 	return result
 }
 
-// isTopLevelVar checks if a name is a top-level variable in the file.
-func isTopLevelVar(name string, file *ast.File) bool {
-	for _, decl := range file.Decls {
-		genDecl, ok := decl.(*ast.GenDecl)
-		if !ok || genDecl.Tok != token.VAR {
+// findObjectMetaField extracts a string field (e.g. Name or Namespace) of the
+// resource's ObjectMeta from its initializer. The value may be a string
+// literal or a top-level string constant or variable. Returns empty string if
+// the field is not set or not static.
+func findObjectMetaField(expr ast.Expr, scope packageScope, field string) string {
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	compLit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return ""
+	}
+
+	for _, elt := range compLit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
 			continue
 		}
-
-		for _, spec := range genDecl.Specs {
-			valueSpec, ok := spec.(*ast.ValueSpec)
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok || key.Name != "ObjectMeta" {
+			continue
+		}
+		metaLit, ok := kv.Value.(*ast.CompositeLit)
+		if !ok {
+			return ""
+		}
+		for _, metaElt := range metaLit.Elts {
+			metaKV, ok := metaElt.(*ast.KeyValueExpr)
 			if !ok {
 				continue
 			}
+			if metaKey, ok := metaKV.Key.(*ast.Ident); ok && metaKey.Name == field {
+				return resolveString(metaKV.Value, scope)
+			}
+		}
+	}
 
-			for _, varName := range valueSpec.Names {
-				if varName.Name == name {
-					return true
-				}
+	return ""
+}
+
+// nameRefTypes maps Kubernetes reference types to the kind they refer to and
+// the field holding the referenced name.
+var nameRefTypes = map[string]struct{ kind, field string }{
+	"SecretKeySelector":                 {"Secret", "Name"},
+	"SecretEnvSource":                   {"Secret", "Name"},
+	"SecretProjection":                  {"Secret", "Name"},
+	"SecretVolumeSource":                {"Secret", "SecretName"},
+	"ConfigMapKeySelector":              {"ConfigMap", "Name"},
+	"ConfigMapEnvSource":                {"ConfigMap", "Name"},
+	"ConfigMapProjection":               {"ConfigMap", "Name"},
+	"ConfigMapVolumeSource":             {"ConfigMap", "Name"},
+	"PersistentVolumeClaimVolumeSource": {"PersistentVolumeClaim", "ClaimName"},
+	"PodSpec":                           {"ServiceAccount", "ServiceAccountName"},
+}
+
+// findNameRefs finds references to other objects by metadata name, such as
+// the Secrets, ConfigMaps, PersistentVolumeClaims and ServiceAccount used by
+// a pod spec.
+func findNameRefs(expr ast.Expr, scope packageScope) []NameRef {
+	var refs []NameRef
+	seen := make(map[NameRef]bool)
+
+	ast.Inspect(expr, func(n ast.Node) bool {
+		compLit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		sel, ok := compLit.Type.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		refType, ok := nameRefTypes[sel.Sel.Name]
+		if !ok {
+			return true
+		}
+
+		name := findRefName(compLit, refType.field, scope)
+		ref := NameRef{Kind: refType.kind, Name: name}
+		if name != "" && !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+		return true
+	})
+
+	return refs
+}
+
+// findRefName returns the referenced name from a reference literal, looking
+// through an embedded LocalObjectReference when the field is Name.
+func findRefName(compLit *ast.CompositeLit, field string, scope packageScope) string {
+	for _, elt := range compLit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		if key.Name == field {
+			return resolveString(kv.Value, scope)
+		}
+		if key.Name == "LocalObjectReference" {
+			if inner, ok := kv.Value.(*ast.CompositeLit); ok {
+				return findRefName(inner, field, scope)
 			}
 		}
 	}
+	return ""
+}
 
+// resolveString returns the value of a string literal, or of an identifier
+// bound to a string literal by a top-level const or var declaration.
+func resolveString(expr ast.Expr, scope packageScope) string {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return ""
+		}
+		value, err := strconv.Unquote(e.Value)
+		if err != nil {
+			return ""
+		}
+		return value
+	case *ast.Ident:
+		if lit, ok := scope[e.Name].value().(*ast.BasicLit); ok {
+			return resolveString(lit, scope)
+		}
+	}
+	return ""
+}
+
+// isDependency checks if a name is a top-level variable that resources
+// depend on: a Kubernetes resource, or any exported variable.
+func isDependency(name string, scope packageScope) bool {
+	if decl, ok := scope[name]; ok && decl.tok == token.VAR && ast.IsExported(name) {
+		return true
+	}
+	return isTopLevelResource(name, scope)
+}
+
+// isTopLevelResource checks if a name is a top-level variable in the package
+// whose type is a Kubernetes resource.
+func isTopLevelResource(name string, scope packageScope) bool {
+	decl, ok := scope[name]
+	if !ok || decl.tok != token.VAR {
+		return false
+	}
+	if decl.spec.Type != nil {
+		return getResourceType(decl.spec.Type) != ""
+	}
+	if value := decl.value(); value != nil {
+		return getResourceTypeFromExpr(value) != ""
+	}
 	return false
 }
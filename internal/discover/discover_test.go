@@ -1,7 +1,10 @@
 package discover_test
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/lex00/wetwire-k8s-go/internal/discover"
@@ -82,6 +85,57 @@ func TestDiscover_Directory(t *testing.T) {
 	assert.NotNil(t, findResource(resources, "WebService"))
 }
 
+func TestDiscover_MultiFilePackage(t *testing.T) {
+	// References resolve across the files of a package
+	resources, err := discover.DiscoverDirectory(filepath.Join("testdata", "multifile"))
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	deployment := findResource(resources, "FrontendDeployment")
+	require.NotNil(t, deployment)
+	assert.Contains(t, deployment.File, "deployment.go")
+
+	service := findResource(resources, "FrontendService")
+	require.NotNil(t, service)
+	assert.Contains(t, service.File, "service.go")
+	assert.Equal(t, []string{"FrontendDeployment"}, service.Dependencies)
+	assert.Equal(t, "frontend", service.Namespace, "constant from deployment.go should resolve")
+}
+
+func TestDiscoverSource(t *testing.T) {
+	src := []byte(`package piped
+
+import corev1 "k8s.io/api/core/v1"
+
+var AppConfig = corev1.ConfigMap{}
+
+var AppPod = corev1.Pod{
+	Spec: corev1.PodSpec{
+		Volumes: []corev1.Volume{{Name: AppConfig.Name}},
+	},
+}
+`)
+
+	resources, err := discover.DiscoverSource(src, "stdin.go")
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, "stdin.go", resources[0].File)
+	assert.Equal(t, 5, resources[0].Line)
+	assert.Equal(t, []string{"AppConfig"}, findResource(resources, "AppPod").Dependencies)
+
+	_, err = discover.DiscoverSource([]byte("package piped\n\nvar {{{"), "stdin.go")
+	assert.ErrorContains(t, err, "stdin.go:3")
+}
+
+func TestDiscover_FileScope(t *testing.T) {
+	// A single file does not see declarations in other files
+	resources, err := discover.DiscoverFile(filepath.Join("testdata", "multifile", "service.go"))
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Empty(t, resources[0].Dependencies)
+	assert.Empty(t, resources[0].Namespace)
+}
+
 func TestDiscover_RecursiveDirectory(t *testing.T) {
 	// Test recursive discovery starting from parent directory
 	resources, err := discover.DiscoverDirectory(".")
@@ -151,7 +205,180 @@ func findResource(resources []discover.Resource, name string) *discover.Resource
 }
 
 func writeFile(path, content string) error {
-	// This will be implemented if needed by the test
-	// For now, using os.WriteFile
-	return nil
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+func TestResource_Namespace(t *testing.T) {
+	// Test that metadata.namespace is extracted from literals and constants
+	resources, err := discover.DiscoverFile(filepath.Join("testdata", "namespaces.go"))
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+
+	literal := findResource(resources, "LiteralNamespaceDeployment")
+	require.NotNil(t, literal)
+	assert.Equal(t, "production", literal.Namespace)
+
+	constant := findResource(resources, "ConstNamespaceService")
+	require.NotNil(t, constant)
+	assert.Equal(t, "team-alpha", constant.Namespace)
+
+	none := findResource(resources, "NoNamespaceConfig")
+	require.NotNil(t, none)
+	assert.Empty(t, none.Namespace)
+}
+
+func TestResource_NameRefs(t *testing.T) {
+	// Test that references to other objects by metadata name are recorded
+	resources, err := discover.DiscoverFile(filepath.Join("testdata", "namerefs.go"))
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+
+	deployment := resources[0]
+	assert.Equal(t, "name-ref-app", deployment.MetadataName)
+	assert.Equal(t, []discover.NameRef{
+		{Kind: "ServiceAccount", Name: "app-runner"},
+		{Kind: "Secret", Name: "database-credentials"},
+		{Kind: "ConfigMap", Name: "app-config"},
+		{Kind: "PersistentVolumeClaim", Name: "data-pvc"},
+	}, deployment.NameRefs)
+}
+
+func TestDiscover_Collections(t *testing.T) {
+	// Slices and maps of objects declare one resource per element, named
+	// after the variable and the element's metadata name or map key
+	resources, err := discover.DiscoverFile(filepath.Join("testdata", "collections.go"))
+	require.NoError(t, err)
+
+	names := make([]string, len(resources))
+	for i, r := range resources {
+		names[i] = r.Name
+	}
+	assert.Equal(t, []string{
+		"CollectionServicesWebApi",
+		"CollectionServicesWebAdmin",
+		"CollectionConfigsSettings",
+		"CollectionEnv",
+		"CollectionIngress",
+	}, names)
+
+	api := findResource(resources, "CollectionServicesWebApi")
+	require.NotNil(t, api)
+	assert.Equal(t, "corev1.Service", api.Type)
+	assert.Equal(t, "web-api", api.MetadataName)
+	assert.Equal(t, 11, api.Line, "line of the element, not the variable")
+
+	admin := findResource(resources, "CollectionServicesWebAdmin")
+	require.NotNil(t, admin)
+	assert.Equal(t, "corev1.Service", admin.Type)
+	assert.Equal(t, "web-admin", admin.MetadataName)
+	assert.Equal(t, "ops", admin.Namespace)
+
+	configs := findResource(resources, "CollectionConfigsSettings")
+	require.NotNil(t, configs)
+	assert.Equal(t, "corev1.ConfigMap", configs.Type)
+
+	env := findResource(resources, "CollectionEnv")
+	require.NotNil(t, env)
+	assert.True(t, discover.IsHelper(*env))
+
+	ingress := findResource(resources, "CollectionIngress")
+	require.NotNil(t, ingress)
+	assert.ElementsMatch(t, []string{"CollectionServicesWebApi", "CollectionServicesWebAdmin"}, ingress.Dependencies)
+}
+
+func TestResource_Doc(t *testing.T) {
+	resources, err := discover.DiscoverFile(filepath.Join("testdata", "simple.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "Simple deployment with no dependencies", findResource(resources, "SimpleDeployment").Doc)
+
+	testFile := filepath.Join(t.TempDir(), "docs.go")
+	require.NoError(t, writeFile(testFile, `package docs
+
+import corev1 "k8s.io/api/core/v1"
+
+// Shared settings of the web tier.
+//
+// Read by every web Deployment.
+var WebConfig = corev1.ConfigMap{}
+
+// Grouped declarations document each variable.
+var (
+	// WorkerConfig configures the queue workers.
+	WorkerConfig = corev1.ConfigMap{}
+
+	CronConfig = corev1.ConfigMap{}
+)
+
+var Undocumented = corev1.ConfigMap{}
+
+// A slice's elements share its doc comment.
+var Services = []corev1.Service{{}}
+`))
+
+	resources, err = discover.DiscoverFile(testFile)
+	require.NoError(t, err)
+	assert.Equal(t, "Shared settings of the web tier.\n\nRead by every web Deployment.", findResource(resources, "WebConfig").Doc)
+	assert.Equal(t, "WorkerConfig configures the queue workers.", findResource(resources, "WorkerConfig").Doc)
+	assert.Empty(t, findResource(resources, "CronConfig").Doc, "the doc comment of a group is not the doc of its variables")
+	assert.Empty(t, findResource(resources, "Undocumented").Doc)
+	assert.Equal(t, "A slice's elements share its doc comment.", findResource(resources, "Services0").Doc)
+}
+
+func TestIsHelper(t *testing.T) {
+	// Label maps, strings and functions are never discovered; values typed
+	// with API structs that are not objects are discovered as helpers
+	resources, err := discover.DiscoverFile(filepath.Join("testdata", "helpers.go"))
+	require.NoError(t, err)
+
+	helpers := make(map[string]bool)
+	for _, r := range resources {
+		helpers[r.Name] = discover.IsHelper(r)
+	}
+	assert.Equal(t, map[string]bool{
+		"HelperEnv":        true,
+		"HelperContainer":  true,
+		"HelperPodSpec":    true,
+		"HelperDeployment": false,
+	}, helpers)
+}
+
+func TestDiscover_WebServiceExample(t *testing.T) {
+	// Regression test: the helper values in examples/web-service (the
+	// appLabels map, the appName const, ingressClassName and ptr) must never
+	// be discovered as resources
+	resources, err := discover.DiscoverDirectory(filepath.Join("..", "..", "examples", "web-service"))
+	require.NoError(t, err)
+
+	var names []string
+	for _, r := range resources {
+		names = append(names, r.Name)
+		assert.False(t, discover.IsHelper(r), "%s should not be a helper", r.Name)
+	}
+	assert.ElementsMatch(t, []string{"WebAppDeployment", "WebAppService", "WebAppIngress"}, names)
+}
+
+func BenchmarkDiscoverDirectory(b *testing.B) {
+	// 20 files of 100 ConfigMaps and 100 Deployments, each Deployment
+	// reading the ConfigMap declared before it
+	dir := b.TempDir()
+	for f := 0; f < 20; f++ {
+		var src strings.Builder
+		src.WriteString("package k8s\n\nimport (\n\tappsv1 \"k8s.io/api/apps/v1\"\n\tcorev1 \"k8s.io/api/core/v1\"\n\tmetav1 \"k8s.io/apimachinery/pkg/apis/meta/v1\"\n)\n\n")
+		for i := f * 100; i < (f+1)*100; i++ {
+			fmt.Fprintf(&src, "var Config%d = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: \"config-%d\"}}\n\n", i, i)
+			fmt.Fprintf(&src, "var App%d = &appsv1.Deployment{\n\tObjectMeta: metav1.ObjectMeta{Name: \"app-%d\"},\n\tSpec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Volumes: []corev1.Volume{{\n\t\tName: \"config\",\n\t\tVolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: Config%d.Name}}},\n\t}}}}},\n}\n\n", i, i, i)
+		}
+		require.NoError(b, os.WriteFile(filepath.Join(dir, fmt.Sprintf("k8s%02d.go", f)), []byte(src.String()), 0644))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resources, err := discover.DiscoverDirectory(dir)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(resources) != 4000 {
+			b.Fatalf("discovered %d resources, want 4000", len(resources))
+		}
+	}
 }
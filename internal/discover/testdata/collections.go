@@ -0,0 +1,39 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A slice of Services is discovered as one resource per element
+var CollectionServices = []corev1.Service{
+	{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "web-api",
+		},
+	},
+	{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-admin",
+			Namespace: "ops",
+		},
+	},
+}
+
+// Map elements without a metadata name are named after their key
+var CollectionConfigs = map[string]*corev1.ConfigMap{
+	"settings": {},
+}
+
+// Helper slices are not expanded
+var CollectionEnv = []corev1.EnvVar{
+	{Name: "MODE", Value: "collection"},
+}
+
+// Referring to an element depends on every element of the collection
+var CollectionIngress = networkingv1.Ingress{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: CollectionServices[0].Name,
+	},
+}
@@ -0,0 +1,42 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+const helperName = "helper-app"
+
+var helperLabels = map[string]string{"app": helperName}
+
+var helperImage = "nginx:1.25"
+
+// Helper values typed with Kubernetes API structs
+var HelperEnv = []corev1.EnvVar{{Name: "MODE", Value: "production"}}
+
+var HelperContainer = corev1.Container{
+	Name:  helperName,
+	Image: helperImage,
+	Env:   HelperEnv,
+}
+
+var HelperPodSpec = corev1.PodSpec{
+	Containers: []corev1.Container{HelperContainer},
+}
+
+var HelperDeployment = &appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:   helperName,
+		Labels: helperLabels,
+	},
+	Spec: appsv1.DeploymentSpec{
+		Replicas: ptr(int32(2)),
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: helperLabels},
+			Spec:       HelperPodSpec,
+		},
+	},
+}
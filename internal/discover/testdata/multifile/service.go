@@ -0,0 +1,20 @@
+package multifile
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Service selecting the pods of FrontendDeployment in deployment.go
+var FrontendService = &corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "frontend",
+		Namespace: Namespace,
+	},
+	Spec: corev1.ServiceSpec{
+		Selector: FrontendDeployment.Spec.Selector.MatchLabels,
+		Ports: []corev1.ServicePort{
+			{Port: 80},
+		},
+	},
+}
@@ -0,0 +1,56 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const credentialsSecret = "database-credentials"
+
+// Deployment referencing a ServiceAccount, a Secret, a ConfigMap and a PVC
+// by name
+var NameRefDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "name-ref-app",
+	},
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				ServiceAccountName: "app-runner",
+				Containers: []corev1.Container{
+					{
+						Name: "app",
+						Env: []corev1.EnvVar{
+							{
+								Name: "DATABASE_URL",
+								ValueFrom: &corev1.EnvVarSource{
+									SecretKeyRef: &corev1.SecretKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: credentialsSecret},
+										Key:                  "url",
+									},
+								},
+							},
+						},
+					},
+				},
+				Volumes: []corev1.Volume{
+					{
+						Name: "config",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"},
+							},
+						},
+					},
+					{
+						Name: "data",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-pvc"},
+						},
+					},
+				},
+			},
+		},
+	},
+}
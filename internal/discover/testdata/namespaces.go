@@ -0,0 +1,32 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const teamNamespace = "team-alpha"
+
+// Namespace set with a string literal
+var LiteralNamespaceDeployment = &appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "literal-app",
+		Namespace: "production",
+	},
+}
+
+// Namespace set through a package-level constant
+var ConstNamespaceService = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "const-svc",
+		Namespace: teamNamespace,
+	},
+}
+
+// No namespace set
+var NoNamespaceConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "no-namespace-config",
+	},
+}
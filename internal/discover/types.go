@@ -1,10 +1,37 @@
 package discover
 
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 // Resource represents a discovered Kubernetes resource in the source code.
 type Resource struct {
 	Name         string   // Variable name
 	Type         string   // e.g., "apps/v1.Deployment"
 	File         string   // Source file path
 	Line         int      // Line number
+	Namespace    string   // metadata.namespace, empty if not set
+	MetadataName string   // metadata.name, empty if not set
 	Dependencies []string // Referenced resource names
+	NameRefs     []NameRef
+
+	// Expr is the Go expression of the value of a resource declared as an
+	// element of a slice, array or map, such as Services[1] or
+	// Services["web"]. It is empty for a variable, whose value is Name.
+	Expr string
+
+	// Doc is the doc comment of the variable, without comment markers, or
+	// "" if it has none. The elements of a slice or map share the doc
+	// comment of their variable.
+	Doc string
+
+	// OwnerReferences are set by the build when it infers owners from the
+	// dependency graph; see build.SetOwnerReferences.
+	OwnerReferences []metav1.OwnerReference
+}
+
+// NameRef is a reference to another Kubernetes object by its metadata name,
+// such as a Secret used by a SecretKeyRef or a PersistentVolumeClaim mounted
+// as a volume.
+type NameRef struct {
+	Kind string // e.g., "Secret"
+	Name string // e.g., "database-credentials"
 }
@@ -0,0 +1,376 @@
+// Package format canonicalizes Go files that declare Kubernetes resources.
+//
+// Formatting only changes layout, never behavior: within the top-level
+// variables that declare resources, struct literal fields are put in
+// canonical order and label maps are sorted by key, and the whole file is
+// then run through gofmt. It complements lint --fix, which changes what the
+// resources declare.
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	goformat "go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lex00/wetwire-k8s-go/internal/lint"
+)
+
+// fieldRank gives the canonical position of the fields that lead a resource
+// literal. Other fields follow them in their original order.
+var fieldRank = map[string]int{
+	"TypeMeta":   0,
+	"ObjectMeta": 1,
+	"Spec":       2,
+}
+
+// labelFields are the fields whose map literals are sorted by key.
+var labelFields = map[string]bool{
+	"Labels":      true,
+	"MatchLabels": true,
+	"Selector":    true,
+}
+
+// maxPasses bounds the number of rewrite passes. Nested literals are
+// rewritten in later passes than the literals that contain them.
+const maxPasses = 16
+
+// Source returns the canonical form of src: within resource declarations
+// the fields of struct literals are ordered TypeMeta, ObjectMeta, Spec and
+// then the remaining fields as written, and Labels, MatchLabels and Selector
+// map literals are sorted by key. The result is gofmt-ed. Literals that mix
+// fields and other code on the same line in ways that cannot be reordered
+// without moving comments are left as they are.
+func Source(src []byte) ([]byte, error) {
+	out, err := goformat.Source(src)
+	if err != nil {
+		return nil, err
+	}
+
+	for pass := 0; pass < maxPasses; pass++ {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "", out, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		edits := collectEdits(fset, file, out)
+		if len(edits) == 0 {
+			break
+		}
+		out = applyEdits(out, edits)
+	}
+
+	return goformat.Source(out)
+}
+
+// File formats the Go file at path in place and reports whether it changed.
+func File(path string) (bool, error) {
+	original, formatted, err := formatFile(path)
+	if err != nil || bytes.Equal(original, formatted) {
+		return false, err
+	}
+	if err := os.WriteFile(path, formatted, 0644); err != nil {
+		return false, fmt.Errorf("failed to write file %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// Diff returns a unified diff of the changes File would make to the file at
+// path, or nil if it is already formatted.
+func Diff(path string) (*lint.FileDiff, error) {
+	original, formatted, err := formatFile(path)
+	if err != nil || bytes.Equal(original, formatted) {
+		return nil, err
+	}
+	return lint.UnifiedDiff(path, original, formatted)
+}
+
+// formatFile reads the file at path and returns its original and formatted
+// source.
+func formatFile(path string) ([]byte, []byte, error) {
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+	formatted, err := Source(original)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to format file %s: %w", path, err)
+	}
+	return original, formatted, nil
+}
+
+// GoFiles returns the Go files to format under path in lexical order: path
+// itself if it is a file, or every non-test .go file below it.
+func GoFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access path %q: %w", path, err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.HasSuffix(p, ".go") && !strings.HasSuffix(p, "_test.go") {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory %s: %w", path, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// textEdit replaces src[start:end] with text.
+type textEdit struct {
+	start, end int
+	text       string
+}
+
+// applyEdits applies non-overlapping edits to src.
+func applyEdits(src []byte, edits []textEdit) []byte {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	out := append([]byte(nil), src...)
+	for _, e := range edits {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+	return out
+}
+
+// collectEdits returns the reorderings to apply to the resource declarations
+// in file. Literals are visited outside-in and an edit that overlaps one
+// already collected is left for the next pass.
+func collectEdits(fset *token.FileSet, file *ast.File, src []byte) []textEdit {
+	imports := importPaths(file)
+	tokFile := fset.File(file.Pos())
+
+	var edits []textEdit
+	add := func(lit *ast.CompositeLit, order []int) {
+		edit, ok := reorderEdit(tokFile, file, src, lit, order)
+		if !ok {
+			return
+		}
+		for _, e := range edits {
+			if edit.start < e.end && e.start < edit.end {
+				return
+			}
+		}
+		edits = append(edits, edit)
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for _, value := range valueSpec.Values {
+				lit := unwrapCompositeLit(value)
+				if lit == nil || !isResourceType(lit.Type, imports) {
+					continue
+				}
+				ast.Inspect(lit, func(n ast.Node) bool {
+					switch n := n.(type) {
+					case *ast.CompositeLit:
+						if order := fieldOrder(n); order != nil {
+							add(n, order)
+						}
+					case *ast.KeyValueExpr:
+						key, ok := n.Key.(*ast.Ident)
+						if !ok || !labelFields[key.Name] {
+							return true
+						}
+						if labels, ok := n.Value.(*ast.CompositeLit); ok {
+							if order := labelOrder(labels); order != nil {
+								add(labels, order)
+							}
+						}
+					}
+					return true
+				})
+			}
+		}
+	}
+	return edits
+}
+
+// fieldOrder returns the canonical order of the fields of a keyed struct
+// literal as indexes into lit.Elts, or nil if it is already in order or is
+// not a keyed literal.
+func fieldOrder(lit *ast.CompositeLit) []int {
+	keys := make([]string, len(lit.Elts))
+	for i, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		keys[i] = key.Name
+	}
+
+	rank := func(key string) int {
+		if r, ok := fieldRank[key]; ok {
+			return r
+		}
+		return len(fieldRank)
+	}
+	return sortedOrder(len(keys), func(a, b int) bool { return rank(keys[a]) < rank(keys[b]) })
+}
+
+// labelOrder returns the order of a map literal's entries sorted by key as
+// indexes into lit.Elts, or nil if it is already sorted or has keys that
+// are not string literals.
+func labelOrder(lit *ast.CompositeLit) []int {
+	if _, ok := lit.Type.(*ast.MapType); !ok {
+		return nil
+	}
+
+	keys := make([]string, len(lit.Elts))
+	for i, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil
+		}
+		basic, ok := kv.Key.(*ast.BasicLit)
+		if !ok || basic.Kind != token.STRING {
+			return nil
+		}
+		key, err := strconv.Unquote(basic.Value)
+		if err != nil {
+			return nil
+		}
+		keys[i] = key
+	}
+	return sortedOrder(len(keys), func(a, b int) bool { return keys[a] < keys[b] })
+}
+
+// sortedOrder stably sorts the indexes 0..n-1 with less and returns them,
+// or nil if they are already in order.
+func sortedOrder(n int, less func(a, b int) bool) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return less(order[i], order[j]) })
+	for i, idx := range order {
+		if i != idx {
+			return order
+		}
+	}
+	return nil
+}
+
+// reorderEdit returns an edit that rearranges the elements of lit into
+// order. Multi-line literals are rearranged line by line, so comments above
+// an element move with it. Single-line literals are rearranged only when they
+// contain no comments. Other layouts are not supported.
+func reorderEdit(tokFile *token.File, file *ast.File, src []byte, lit *ast.CompositeLit, order []int) (textEdit, bool) {
+	elts := lit.Elts
+	line := func(pos token.Pos) int { return tokFile.Line(pos) }
+	offset := func(pos token.Pos) int { return tokFile.Offset(pos) }
+	lineStart := func(l int) int { return offset(tokFile.LineStart(l)) }
+
+	if line(lit.Lbrace) == line(lit.Rbrace) {
+		for _, group := range file.Comments {
+			if group.Pos() > lit.Lbrace && group.End() < lit.Rbrace {
+				return textEdit{}, false
+			}
+		}
+		texts := make([]string, len(order))
+		for i, idx := range order {
+			texts[i] = string(src[offset(elts[idx].Pos()):offset(elts[idx].End())])
+		}
+		return textEdit{
+			start: offset(elts[0].Pos()),
+			end:   offset(elts[len(elts)-1].End()),
+			text:  strings.Join(texts, ", "),
+		}, true
+	}
+
+	// Each element must start on a new line and end before the next one
+	// starts, with the closing brace on a line of its own
+	if line(elts[0].Pos()) <= line(lit.Lbrace) || line(elts[len(elts)-1].End()) >= line(lit.Rbrace) {
+		return textEdit{}, false
+	}
+	starts := make([]int, len(elts)+1)
+	starts[0] = lineStart(line(lit.Lbrace) + 1)
+	for i := 1; i < len(elts); i++ {
+		if line(elts[i-1].End()) >= line(elts[i].Pos()) {
+			return textEdit{}, false
+		}
+		starts[i] = lineStart(line(elts[i-1].End()) + 1)
+	}
+	starts[len(elts)] = lineStart(line(elts[len(elts)-1].End()) + 1)
+
+	var b strings.Builder
+	for _, idx := range order {
+		b.Write(src[starts[idx]:starts[idx+1]])
+	}
+	return textEdit{start: starts[0], end: starts[len(elts)], text: b.String()}, true
+}
+
+// importPaths maps the names that file's imports are referred to by to their
+// import paths.
+func importPaths(file *ast.File) map[string]string {
+	paths := make(map[string]string)
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		paths[name] = path
+	}
+	return paths
+}
+
+// isResourceType reports whether typ is a type from a Kubernetes API package.
+func isResourceType(typ ast.Expr, imports map[string]string) bool {
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	path := imports[pkg.Name]
+	return strings.HasPrefix(path, "k8s.io/api/") || strings.HasPrefix(path, "k8s.io/apimachinery/")
+}
+
+// unwrapCompositeLit returns the composite literal expr is, or points to.
+func unwrapCompositeLit(expr ast.Expr) *ast.CompositeLit {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		return e
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return unwrapCompositeLit(e.X)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package format
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource(t *testing.T) {
+	before, err := os.ReadFile(filepath.Join("testdata", "resources_before.go"))
+	require.NoError(t, err)
+	after, err := os.ReadFile(filepath.Join("testdata", "resources_after.go"))
+	require.NoError(t, err)
+
+	formatted, err := Source(before)
+	require.NoError(t, err)
+	assert.Equal(t, string(after), string(formatted))
+
+	// Formatting is idempotent
+	again, err := Source(after)
+	require.NoError(t, err)
+	assert.Equal(t, string(after), string(again))
+}
+
+func TestSource_Unsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{
+			name: "comments inside a single-line literal",
+			src: `package k8s
+
+import corev1 "k8s.io/api/core/v1"
+
+var Config = corev1.ConfigMap{Data: map[string]string{"b": "2"} /* data */, ObjectMeta: metav1.ObjectMeta{Name: "config"}}
+`,
+		},
+		{
+			name: "fields sharing a line in a multi-line literal",
+			src: `package k8s
+
+import corev1 "k8s.io/api/core/v1"
+
+var Config = corev1.ConfigMap{
+	Data: map[string]string{"b": "2"}, ObjectMeta: metav1.ObjectMeta{Name: "config"},
+}
+`,
+		},
+		{
+			name: "label keys that are not string literals",
+			src: `package k8s
+
+import corev1 "k8s.io/api/core/v1"
+
+var Config = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{tierKey: "web", "app": "web"}},
+}
+`,
+		},
+		{
+			name: "types outside the Kubernetes API",
+			src: `package k8s
+
+import "example.com/widgets"
+
+var Widget = widgets.Widget{
+	Spec:       widgets.Spec{},
+	ObjectMeta: widgets.Meta{},
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatted, err := Source([]byte(tt.src))
+			require.NoError(t, err)
+			assert.Equal(t, tt.src, string(formatted))
+		})
+	}
+}
+
+func TestSource_ParseError(t *testing.T) {
+	_, err := Source([]byte("package k8s\n\nvar ="))
+	assert.Error(t, err)
+}
+
+func TestFileAndDiff(t *testing.T) {
+	before, err := os.ReadFile(filepath.Join("testdata", "resources_before.go"))
+	require.NoError(t, err)
+	after, err := os.ReadFile(filepath.Join("testdata", "resources_after.go"))
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "resources.go")
+	require.NoError(t, os.WriteFile(path, before, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "resources_test.go"), before, 0644))
+
+	files, err := GoFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{path}, files)
+
+	diff, err := Diff(path)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	assert.Contains(t, diff.Diff, "--- "+path+".orig\n+++ "+path+"\n")
+	assert.Contains(t, diff.Diff, `+	TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},`)
+
+	// Diff leaves the file untouched
+	unchanged, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(unchanged))
+
+	changed, err := File(path)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	formatted, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(after), string(formatted))
+
+	changed, err = File(path)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	diff, err = Diff(path)
+	require.NoError(t, err)
+	assert.Nil(t, diff)
+}
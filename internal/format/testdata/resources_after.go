@@ -0,0 +1,48 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WebDeployment runs the web frontend.
+var WebDeployment = appsv1.Deployment{
+	TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"app": "web", "tier": "frontend"}},
+	Spec: appsv1.DeploymentSpec{
+		Selector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "web", "tier": "frontend"},
+		},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					"app":  "web",
+					"tier": "frontend", // the presentation tier
+				},
+			},
+			// The pod spec moves below its metadata
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "web", Image: "nginx:1.27"}},
+			},
+		},
+	},
+}
+
+var WebService = &corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "web",
+	},
+	Spec: corev1.ServiceSpec{
+		Selector: map[string]string{"app": "web", "tier": "frontend"},
+		Ports:    []corev1.ServicePort{{Port: 80}},
+	},
+}
+
+var AppConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+	Data:       map[string]string{"b": "2", "a": "1"},
+}
+
+// Helper maps outside resource declarations are left alone
+var helperLabels = map[string]string{"tier": "frontend", "app": "web"}
@@ -0,0 +1,48 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WebDeployment runs the web frontend.
+var WebDeployment = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{
+		Selector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"tier": "frontend", "app": "web"},
+		},
+		Template: corev1.PodTemplateSpec{
+			// The pod spec moves below its metadata
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "web", Image: "nginx:1.27"}},
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					"tier": "frontend", // the presentation tier
+					"app":  "web",
+				},
+			},
+		},
+	},
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"tier": "frontend", "app": "web"}},
+	TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+}
+
+var WebService = &corev1.Service{
+	Spec: corev1.ServiceSpec{
+		Selector: map[string]string{"tier": "frontend", "app": "web"},
+		Ports:    []corev1.ServicePort{{Port: 80}},
+	},
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "web",
+	},
+}
+
+var AppConfig = corev1.ConfigMap{
+	Data:       map[string]string{"b": "2", "a": "1"},
+	ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+}
+
+// Helper maps outside resource declarations are left alone
+var helperLabels = map[string]string{"tier": "frontend", "app": "web"}
@@ -0,0 +1,113 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ClusterClient fetches objects from a Kubernetes cluster.
+type ClusterClient interface {
+	// Get returns the object with the given resource type (e.g. "deployment")
+	// and name as YAML or JSON. An empty namespace uses the namespace of the
+	// current kubeconfig context.
+	Get(resource, name, namespace string) ([]byte, error)
+}
+
+// KubectlClient is a ClusterClient that runs kubectl get.
+type KubectlClient struct {
+	// Kubeconfig overrides the kubeconfig file kubectl uses.
+	Kubeconfig string
+}
+
+// Get fetches the object with kubectl get -o yaml.
+func (c KubectlClient) Get(resource, name, namespace string) ([]byte, error) {
+	args := []string{"get", resource + "/" + name, "-o", "yaml"}
+	if namespace != "" {
+		args = append(args, "--namespace", namespace)
+	}
+	if c.Kubeconfig != "" {
+		args = append(args, "--kubeconfig", c.Kubeconfig)
+	}
+
+	cmd := exec.Command("kubectl", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("kubectl get: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// ImportFromCluster fetches the object named by ref, in the form
+// "deployment/web-app", and generates Go code for it. Fields populated by the
+// API server are removed first (see StripServerFields), so the generated code
+// only describes the desired state.
+func ImportFromCluster(client ClusterClient, ref, namespace string, opts Options) (*Result, error) {
+	resource, name, ok := strings.Cut(ref, "/")
+	if !ok || resource == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid resource %q: expected <type>/<name>, e.g. deployment/web-app", ref)
+	}
+
+	data, err := client.Get(resource, name, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", ref, err)
+	}
+
+	resources, err := ParseYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ref, err)
+	}
+	if len(resources) != 1 {
+		return nil, fmt.Errorf("expected one object for %s, got %d", ref, len(resources))
+	}
+	StripServerFields(resources[0].RawData)
+
+	if opts.PackageName == "" {
+		opts.PackageName = "main"
+	}
+	goCode, warnings := GenerateGoCode(resources, opts)
+	return &Result{GoCode: goCode, ResourceCount: len(resources), Warnings: warnings}, nil
+}
+
+// serverMetadataFields are metadata fields the API server populates.
+var serverMetadataFields = []string{
+	"uid",
+	"resourceVersion",
+	"generation",
+	"creationTimestamp",
+	"managedFields",
+	"selfLink",
+}
+
+// serverAnnotations are annotations that tools add to live objects.
+var serverAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"deployment.kubernetes.io/revision",
+}
+
+// StripServerFields removes status, the server-populated metadata fields
+// (uid, resourceVersion, generation, creationTimestamp, managedFields and
+// selfLink) and the annotations kubectl and controllers add to a live
+// object, modifying obj in place.
+func StripServerFields(obj map[string]interface{}) {
+	delete(obj, "status")
+
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, field := range serverMetadataFields {
+		delete(metadata, field)
+	}
+	if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+		for _, key := range serverAnnotations {
+			delete(annotations, key)
+		}
+		if len(annotations) == 0 {
+			delete(metadata, "annotations")
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package importer_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/internal/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClusterClient serves objects from memory, keyed by
+// "namespace/resource/name".
+type fakeClusterClient struct {
+	objects map[string][]byte
+	calls   []string
+}
+
+func (c *fakeClusterClient) Get(resource, name, namespace string) ([]byte, error) {
+	key := namespace + "/" + resource + "/" + name
+	c.calls = append(c.calls, key)
+	data, ok := c.objects[key]
+	if !ok {
+		return nil, errors.New(`deployments.apps "` + name + `" not found`)
+	}
+	return data, nil
+}
+
+func TestImportFromCluster(t *testing.T) {
+	live, err := os.ReadFile(filepath.Join("testdata", "live-deployment.yaml"))
+	require.NoError(t, err)
+	client := &fakeClusterClient{objects: map[string][]byte{"default/deployment/web-app": live}}
+
+	result, err := importer.ImportFromCluster(client, "deployment/web-app", "default", importer.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default/deployment/web-app"}, client.calls)
+	assert.Equal(t, 1, result.ResourceCount)
+
+	assert.Contains(t, result.GoCode, "package main")
+	assert.Contains(t, result.GoCode, "var WebAppDeployment = appsv1.Deployment{")
+	assert.Contains(t, result.GoCode, `Name: "web-app"`)
+	assert.Contains(t, result.GoCode, `Namespace: "default"`)
+	assert.Contains(t, result.GoCode, `Image: "nginx:1.27"`)
+
+	// Server-managed fields are not part of the generated code
+	for _, field := range []string{"Status", "ManagedFields", "UID", "ResourceVersion", "CreationTimestamp", "Generation", "last-applied-configuration", "deployment.kubernetes.io/revision", "Annotations"} {
+		assert.NotContains(t, result.GoCode, field)
+	}
+}
+
+func TestImportFromCluster_Errors(t *testing.T) {
+	client := &fakeClusterClient{}
+
+	for _, ref := range []string{"web-app", "deployment/", "/web-app", "deployment/web/app"} {
+		_, err := importer.ImportFromCluster(client, ref, "", importer.Options{})
+		assert.ErrorContains(t, err, "expected <type>/<name>", ref)
+	}
+	assert.Empty(t, client.calls, "invalid references are rejected before fetching")
+
+	_, err := importer.ImportFromCluster(client, "deployment/missing", "default", importer.Options{})
+	assert.ErrorContains(t, err, `failed to fetch deployment/missing: deployments.apps "missing" not found`)
+}
+
+func TestStripServerFields(t *testing.T) {
+	obj := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":            "settings",
+			"uid":             "abc",
+			"resourceVersion": "1",
+			"annotations": map[string]interface{}{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"team": "platform",
+			},
+		},
+		"data":   map[string]interface{}{"key": "value"},
+		"status": map[string]interface{}{},
+	}
+
+	importer.StripServerFields(obj)
+
+	assert.Equal(t, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":        "settings",
+			"annotations": map[string]interface{}{"team": "platform"},
+		},
+		"data": map[string]interface{}{"key": "value"},
+	}, obj)
+}
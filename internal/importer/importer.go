@@ -4,13 +4,20 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"math"
 	"os"
+	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func ImportFile(filePath string, opts Options) (*Result, error) {
@@ -33,6 +40,11 @@ func ImportBytes(data []byte, opts Options) (*Result, error) {
 	return &Result{GoCode: goCode, ResourceCount: len(resources), Warnings: warnings}, nil
 }
 
+// ParseYAML parses the Kubernetes resources in a YAML stream. Documents are
+// read with a YAML decoder rather than by splitting on "---", so separator
+// lines inside block scalars (e.g. Markdown rules or PEM blocks in ConfigMap
+// data) are kept as part of the value. Documents without an apiVersion and
+// kind are skipped.
 func ParseYAML(data []byte) ([]ResourceInfo, error) {
 	var resources []ResourceInfo
 	decoder := yaml.NewDecoder(bytes.NewReader(data))
@@ -63,10 +75,37 @@ func ParseYAML(data []byte) ([]ResourceInfo, error) {
 	return resources, nil
 }
 
+// GenerateGoCode generates a Go file declaring resources. The declarations
+// are grouped into sections by kind, each under a comment banner, and ordered
+// as described in sortResources. It also returns warnings about values that
+// could not be converted.
 func GenerateGoCode(resources []ResourceInfo, opts Options) (string, []string) {
-	var warnings []string
+	// Generate resource bodies first so the header knows whether the ptr
+	// helper is needed. A single resource gets no section banner
+	g := &codeGen{enumConstants: opts.EnumConstants}
+	usedNames := make(map[string]bool)
+	var body bytes.Buffer
+	section := ""
+	for _, res := range sortResources(resources) {
+		if title := sectionTitle(res.Kind); len(resources) > 1 && title != section {
+			body.WriteString(sectionBanner(title))
+			section = title
+		}
+		varName := uniqueVarName(GenerateVarName(res.Name, res.Kind, opts.VarPrefix), usedNames)
+		if _, _, ok := resolveType(res.APIVersion, res.Kind); ok {
+			body.WriteString(generateResourceCode(g, res, varName))
+		} else {
+			g.warnings = append(g.warnings, fmt.Sprintf("%s/%s: %s %s is not a built-in type, imported as unstructured.Unstructured", res.Kind, res.Name, res.APIVersion, res.Kind))
+			body.WriteString(generateUnstructuredCode(res, varName))
+		}
+		body.WriteString("\n")
+	}
+
 	var buf bytes.Buffer
 	imports := collectImports(resources)
+	for path, imp := range g.imports {
+		imports[path] = imp
+	}
 	buf.WriteString(fmt.Sprintf("package %s\n\n", opts.PackageName))
 	if len(imports) > 0 {
 		buf.WriteString("import (\n")
@@ -79,30 +118,237 @@ func GenerateGoCode(resources []ResourceInfo, opts Options) (string, []string) {
 		}
 		buf.WriteString(")\n\n")
 	}
-	for _, res := range resources {
-		varName := GenerateVarName(res.Name, res.Kind, opts.VarPrefix)
-		code, warns := generateResourceCode(res, varName)
-		warnings = append(warnings, warns...)
-		buf.WriteString(code)
-		buf.WriteString("\n")
+	if g.usesPtr {
+		buf.WriteString(ptrHelper)
 	}
-	return buf.String(), warnings
+	buf.Write(body.Bytes())
+	return buf.String(), g.warnings
 }
 
-func GenerateVarName(name, kind, prefix string) string {
-	words := regexp.MustCompile("[-_]+").Split(name, -1)
-	var result strings.Builder
-	if prefix != "" {
-		result.WriteString(prefix)
+// ptrHelper is the generic pointer helper emitted once per file, matching
+// the helper used throughout the examples.
+const ptrHelper = "// ptr returns a pointer to v.\nfunc ptr[T any](v T) *T { return &v }\n\n"
+
+// codeGen tracks state shared across a single GenerateGoCode run.
+type codeGen struct {
+	usesPtr       bool
+	enumConstants bool                  // Write enum values as typed constants
+	imports       map[string]importInfo // Imports needed by generated values
+	warnings      []string
+}
+
+// addImport records an import needed by the generated code.
+func (g *codeGen) addImport(path, alias string) {
+	if g.imports == nil {
+		g.imports = make(map[string]importInfo)
 	}
-	for _, word := range words {
-		if len(word) > 0 {
-			result.WriteString(strings.ToUpper(string(word[0])))
-			result.WriteString(word[1:])
+	g.imports[path] = importInfo{path, alias}
+}
+
+// Field types whose values need a constructor call rather than a literal.
+var (
+	// quantityType is the type of resource amounts such as CPU and memory
+	// requests, which are written as resource.MustParse calls.
+	quantityType = reflect.TypeOf(resource.Quantity{})
+
+	// intOrStringType is the type of fields such as a Service targetPort that
+	// hold a number or a name, written as intstr.FromInt32 or
+	// intstr.FromString depending on the YAML value.
+	intOrStringType = reflect.TypeOf(intstr.IntOrString{})
+)
+
+// valueLiteral returns the Go literal for a YAML value assigned to a field of
+// type t, adding any import the literal needs. It reports false if v cannot
+// be converted to t.
+func (g *codeGen) valueLiteral(v interface{}, t reflect.Type) (string, bool) {
+	switch t {
+	case quantityType:
+		var s string
+		switch val := v.(type) {
+		case string:
+			s = val
+		case int, float64:
+			s = fmt.Sprintf("%v", val)
+		default:
+			return "", false
+		}
+		if _, err := resource.ParseQuantity(s); err != nil {
+			return "", false
+		}
+		g.addImport("k8s.io/apimachinery/pkg/api/resource", "")
+		return fmt.Sprintf("resource.MustParse(%q)", s), true
+	case intOrStringType:
+		var literal string
+		switch val := v.(type) {
+		case int:
+			if val < math.MinInt32 || val > math.MaxInt32 {
+				return "", false
+			}
+			literal = fmt.Sprintf("intstr.FromInt32(%d)", val)
+		case string:
+			literal = fmt.Sprintf("intstr.FromString(%q)", val)
+		default:
+			return "", false
 		}
+		g.addImport("k8s.io/apimachinery/pkg/util/intstr", "")
+		return literal, true
 	}
-	result.WriteString(kind)
-	return result.String()
+	return "", false
+}
+
+// enumField identifies a value of a field of a Kubernetes enum type.
+type enumField struct {
+	typ   string // Go type holding the field, e.g. "ServiceSpec"
+	field string // Go field name, e.g. "Type"
+	value string // YAML value, e.g. "ClusterIP"
+}
+
+// enumConstant is the typed constant for an enum value and the package that
+// declares it.
+type enumConstant struct {
+	path  string // Import path of the package
+	alias string // Import alias used in the generated code
+	name  string // Constant name, e.g. "ServiceTypeClusterIP"
+}
+
+// enumConstants maps the values of enum fields the importer writes to their
+// typed constants.
+var enumConstants = func() map[enumField]enumConstant {
+	const core = "k8s.io/api/core/v1"
+	constants := make(map[enumField]enumConstant)
+	add := func(typ, field string, values map[string]string) {
+		for value, name := range values {
+			constants[enumField{typ, field, value}] = enumConstant{core, "corev1", name}
+		}
+	}
+	protocols := map[string]string{
+		"TCP":  "ProtocolTCP",
+		"UDP":  "ProtocolUDP",
+		"SCTP": "ProtocolSCTP",
+	}
+	add("ContainerPort", "Protocol", protocols)
+	add("ServicePort", "Protocol", protocols)
+	add("ServiceSpec", "Type", map[string]string{
+		"ClusterIP":    "ServiceTypeClusterIP",
+		"NodePort":     "ServiceTypeNodePort",
+		"LoadBalancer": "ServiceTypeLoadBalancer",
+		"ExternalName": "ServiceTypeExternalName",
+	})
+	add("PodSpec", "RestartPolicy", map[string]string{
+		"Always":    "RestartPolicyAlways",
+		"OnFailure": "RestartPolicyOnFailure",
+		"Never":     "RestartPolicyNever",
+	})
+	add("Container", "ImagePullPolicy", map[string]string{
+		"Always":       "PullAlways",
+		"IfNotPresent": "PullIfNotPresent",
+		"Never":        "PullNever",
+	})
+	return constants
+}()
+
+// enumLiteral returns the Go literal for value assigned to field of typ, an
+// enum type: its typed constant when EnumConstants is set and the value is
+// known, adding the import the constant needs, or else a string literal,
+// which Go converts to the enum type.
+func (g *codeGen) enumLiteral(typ, field, value string) string {
+	if c, ok := enumConstants[enumField{typ, field, value}]; ok && g.enumConstants {
+		g.addImport(c.path, c.alias)
+		return c.alias + "." + c.name
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+// ptrField maps a YAML key to a pointer-typed Go field.
+type ptrField struct {
+	key    string // YAML key, e.g. "replicas"
+	field  string // Go field name, e.g. "Replicas"
+	goType string // Pointer element type, e.g. "int32"
+}
+
+var workloadPtrFields = []ptrField{
+	{"replicas", "Replicas", "int32"},
+	{"revisionHistoryLimit", "RevisionHistoryLimit", "int32"},
+	{"progressDeadlineSeconds", "ProgressDeadlineSeconds", "int32"},
+}
+
+var podSpecPtrFields = []ptrField{
+	{"terminationGracePeriodSeconds", "TerminationGracePeriodSeconds", "int64"},
+	{"activeDeadlineSeconds", "ActiveDeadlineSeconds", "int64"},
+	{"automountServiceAccountToken", "AutomountServiceAccountToken", "bool"},
+}
+
+var podSecurityContextPtrFields = []ptrField{
+	{"runAsNonRoot", "RunAsNonRoot", "bool"},
+	{"runAsUser", "RunAsUser", "int64"},
+	{"runAsGroup", "RunAsGroup", "int64"},
+	{"fsGroup", "FSGroup", "int64"},
+}
+
+var securityContextPtrFields = []ptrField{
+	{"runAsNonRoot", "RunAsNonRoot", "bool"},
+	{"runAsUser", "RunAsUser", "int64"},
+	{"runAsGroup", "RunAsGroup", "int64"},
+	{"readOnlyRootFilesystem", "ReadOnlyRootFilesystem", "bool"},
+	{"allowPrivilegeEscalation", "AllowPrivilegeEscalation", "bool"},
+	{"privileged", "Privileged", "bool"},
+}
+
+// generatePtrFields writes the pointer-typed fields present in data, wrapping
+// each value with the ptr helper. Values that cannot be converted to the
+// field's type are skipped with a warning.
+func generatePtrFields(g *codeGen, buf *bytes.Buffer, data map[string]interface{}, fields []ptrField, path, indent string) {
+	for _, f := range fields {
+		v, ok := data[f.key]
+		if !ok {
+			continue
+		}
+		literal, ok := ptrLiteral(v, f.goType)
+		if !ok {
+			g.warnings = append(g.warnings, fmt.Sprintf("%s.%s: cannot convert %v to *%s, field skipped", path, f.key, v, f.goType))
+			continue
+		}
+		g.usesPtr = true
+		buf.WriteString(fmt.Sprintf("%s%s: ptr(%s),\n", indent, f.field, literal))
+	}
+}
+
+// ptrLiteral returns the Go literal for v as goType, e.g. "int32(3)".
+func ptrLiteral(v interface{}, goType string) (string, bool) {
+	switch goType {
+	case "bool":
+		if b, ok := v.(bool); ok {
+			return fmt.Sprintf("%t", b), true
+		}
+	case "int32", "int64":
+		if n, ok := v.(int); ok {
+			return fmt.Sprintf("%s(%d)", goType, n), true
+		}
+	}
+	return "", false
+}
+
+// GenerateVarName returns the variable name for a resource: the prefix, the
+// PascalCase metadata name and the kind, e.g. "webapp" + "Service" ->
+// "WebappService".
+func GenerateVarName(name, kind, prefix string) string {
+	pascal := toPascalCase(name)
+	if prefix == "" && pascal != "" && unicode.IsDigit([]rune(pascal)[0]) {
+		// Identifiers cannot start with a digit, so lead with the kind
+		return kind + pascal
+	}
+	return prefix + pascal + kind
+}
+
+// uniqueVarName returns name, or name with the smallest numeric suffix
+// starting at 2 that is not yet in used, and records the result.
+func uniqueVarName(name string, used map[string]bool) string {
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+	used[candidate] = true
+	return candidate
 }
 
 func APIVersionToImport(apiVersion string) (string, string) {
@@ -134,37 +380,31 @@ type importInfo struct{ path, alias string }
 
 func collectImports(resources []ResourceInfo) map[string]importInfo {
 	imports := make(map[string]importInfo)
-	if len(resources) > 0 {
+	var builtins []ResourceInfo
+	for _, res := range resources {
+		importPath, alias, ok := resolveType(res.APIVersion, res.Kind)
+		if !ok {
+			imports[unstructuredImport] = importInfo{unstructuredImport, ""}
+			continue
+		}
+		imports[importPath] = importInfo{importPath, alias}
+		builtins = append(builtins, res)
+	}
+	if len(builtins) > 0 {
 		imports["k8s.io/apimachinery/pkg/apis/meta/v1"] = importInfo{"k8s.io/apimachinery/pkg/apis/meta/v1", "metav1"}
 	}
-	needsPtr, needsIntstr, needsCorev1 := false, false, false
-	for _, res := range resources {
-		if spec, ok := res.RawData["spec"].(map[string]interface{}); ok {
-			if _, ok := spec["replicas"]; ok {
-				needsPtr = true
-			}
-			// Deployments need corev1 for PodTemplateSpec
+	needsCorev1 := false
+	for _, res := range builtins {
+		if spec, ok := res.RawData["spec"].(map[string]interface{}); ok && podTemplateKinds[res.Kind] {
+			// Workloads need corev1 for PodTemplateSpec
 			if _, ok := spec["template"]; ok {
 				needsCorev1 = true
 			}
 		}
-		if res.Kind == "Service" {
-			needsIntstr = true
-		}
-	}
-	if needsPtr {
-		imports["k8s.io/utils/ptr"] = importInfo{"k8s.io/utils/ptr", ""}
-	}
-	if needsIntstr {
-		imports["k8s.io/apimachinery/pkg/util/intstr"] = importInfo{"k8s.io/apimachinery/pkg/util/intstr", ""}
 	}
 	if needsCorev1 {
 		imports["k8s.io/api/core/v1"] = importInfo{"k8s.io/api/core/v1", "corev1"}
 	}
-	for _, res := range resources {
-		importPath, alias := APIVersionToImport(res.APIVersion)
-		imports[importPath] = importInfo{importPath, alias}
-	}
 	return imports
 }
 
@@ -184,9 +424,8 @@ func sortImports(imports map[string]importInfo) []importInfo {
 	return result
 }
 
-func generateResourceCode(res ResourceInfo, varName string) (string, []string) {
+func generateResourceCode(g *codeGen, res ResourceInfo, varName string) string {
 	var buf bytes.Buffer
-	var warnings []string
 	_, alias := APIVersionToImport(res.APIVersion)
 	buf.WriteString(fmt.Sprintf("var %s = %s.%s{\n", varName, alias, res.Kind))
 	buf.WriteString("\tTypeMeta: metav1.TypeMeta{\n")
@@ -200,7 +439,7 @@ func generateResourceCode(res ResourceInfo, varName string) (string, []string) {
 	}
 	if spec, ok := res.RawData["spec"].(map[string]interface{}); ok {
 		buf.WriteString(fmt.Sprintf("\tSpec: %s.%sSpec{\n", alias, res.Kind))
-		generateSpec(&buf, spec, alias, res.Kind, "\t\t")
+		generateSpec(g, &buf, spec, res.Kind+"/"+res.Name, res.Kind, "\t\t")
 		buf.WriteString("\t},\n")
 	}
 	if data, ok := res.RawData["data"].(map[string]interface{}); ok && (res.Kind == "ConfigMap" || res.Kind == "Secret") {
@@ -209,7 +448,73 @@ func generateResourceCode(res ResourceInfo, varName string) (string, []string) {
 		buf.WriteString("\t},\n")
 	}
 	buf.WriteString("}\n")
-	return buf.String(), warnings
+	return buf.String()
+}
+
+const unstructuredImport = "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+// generateUnstructuredCode generates an unstructured.Unstructured literal that
+// preserves the full manifest, for kinds without a built-in Go type.
+func generateUnstructuredCode(res ResourceInfo, varName string) string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("var %s = unstructured.Unstructured{\n", varName))
+	buf.WriteString("\tObject: ")
+	generateUnstructuredValue(&buf, res.RawData, "\t")
+	buf.WriteString(",\n}\n")
+	return buf.String()
+}
+
+// generateUnstructuredValue writes v as a Go literal of the JSON-compatible
+// types unstructured objects expect. Integers are written as int64 since
+// unstructured deep copies reject plain int values.
+func generateUnstructuredValue(buf *bytes.Buffer, v interface{}, indent string) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 0 {
+			buf.WriteString("map[string]interface{}{}")
+			return
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteString("map[string]interface{}{\n")
+		for _, k := range keys {
+			buf.WriteString(fmt.Sprintf("%s\t%q: ", indent, k))
+			generateUnstructuredValue(buf, val[k], indent+"\t")
+			buf.WriteString(",\n")
+		}
+		buf.WriteString(indent + "}")
+	case []interface{}:
+		if len(val) == 0 {
+			buf.WriteString("[]interface{}{}")
+			return
+		}
+		buf.WriteString("[]interface{}{\n")
+		for _, item := range val {
+			buf.WriteString(indent + "\t")
+			generateUnstructuredValue(buf, item, indent+"\t")
+			buf.WriteString(",\n")
+		}
+		buf.WriteString(indent + "}")
+	case string:
+		buf.WriteString(stringLiteral(val))
+	case int:
+		buf.WriteString(fmt.Sprintf("int64(%d)", val))
+	case int64:
+		buf.WriteString(fmt.Sprintf("int64(%d)", val))
+	case uint64:
+		buf.WriteString(fmt.Sprintf("int64(%d)", val))
+	case float64:
+		buf.WriteString(fmt.Sprintf("float64(%v)", val))
+	case bool:
+		buf.WriteString(fmt.Sprintf("%t", val))
+	case nil:
+		buf.WriteString("nil")
+	default:
+		buf.WriteString(fmt.Sprintf("%q", fmt.Sprintf("%v", val)))
+	}
 }
 
 func generateObjectMeta(buf *bytes.Buffer, metadata map[string]interface{}, indent string) {
@@ -233,23 +538,45 @@ func generateStringMap(buf *bytes.Buffer, data map[string]interface{}, indent st
 	}
 	sort.Strings(keys)
 	for _, k := range keys {
-		buf.WriteString(fmt.Sprintf("%s%q: %q,\n", indent, k, fmt.Sprintf("%v", data[k])))
+		buf.WriteString(fmt.Sprintf("%s%q: %s,\n", indent, k, stringLiteral(fmt.Sprintf("%v", data[k]))))
 	}
 }
 
-func generateSpec(buf *bytes.Buffer, spec map[string]interface{}, alias, kind, indent string) {
-	switch kind {
-	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet":
-		generateDeploymentSpec(buf, spec, indent)
-	case "Service":
-		generateServiceSpec(buf, spec, indent)
+// stringLiteral returns the Go literal for s. Multi-line values, such as
+// configuration files in a ConfigMap, are written as raw string literals so
+// they stay readable; values a raw literal cannot hold exactly, those with a
+// backtick, a carriage return or other control characters, are quoted.
+func stringLiteral(s string) string {
+	if !strings.Contains(s, "\n") || !utf8.ValidString(s) {
+		return strconv.Quote(s)
+	}
+	for _, r := range s {
+		if r == '`' || r == '\uFEFF' || (unicode.IsControl(r) && r != '\n' && r != '\t') {
+			return strconv.Quote(s)
+		}
 	}
+	return "`" + s + "`"
 }
 
-func generateDeploymentSpec(buf *bytes.Buffer, spec map[string]interface{}, indent string) {
-	if replicas, ok := spec["replicas"].(int); ok {
-		buf.WriteString(fmt.Sprintf("%sReplicas: ptr.To[int32](%d),\n", indent, replicas))
+// podTemplateKinds are the workload kinds whose pod template is generated.
+var podTemplateKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+	"ReplicaSet":  true,
+}
+
+func generateSpec(g *codeGen, buf *bytes.Buffer, spec map[string]interface{}, ref, kind, indent string) {
+	switch {
+	case podTemplateKinds[kind]:
+		generateDeploymentSpec(g, buf, spec, ref+".spec", indent)
+	case kind == "Service":
+		generateServiceSpec(g, buf, spec, ref+".spec", indent)
 	}
+}
+
+func generateDeploymentSpec(g *codeGen, buf *bytes.Buffer, spec map[string]interface{}, path, indent string) {
+	generatePtrFields(g, buf, spec, workloadPtrFields, path, indent)
 	if selector, ok := spec["selector"].(map[string]interface{}); ok {
 		buf.WriteString(fmt.Sprintf("%sSelector: &metav1.LabelSelector{\n", indent))
 		if matchLabels, ok := selector["matchLabels"].(map[string]interface{}); ok {
@@ -268,20 +595,29 @@ func generateDeploymentSpec(buf *bytes.Buffer, spec map[string]interface{}, inde
 		}
 		if templateSpec, ok := template["spec"].(map[string]interface{}); ok {
 			buf.WriteString(fmt.Sprintf("%s\tSpec: corev1.PodSpec{\n", indent))
-			generatePodSpec(buf, templateSpec, indent+"\t\t")
+			generatePodSpec(g, buf, templateSpec, path+".template.spec", indent+"\t\t")
 			buf.WriteString(fmt.Sprintf("%s\t},\n", indent))
 		}
 		buf.WriteString(fmt.Sprintf("%s},\n", indent))
 	}
 }
 
-func generatePodSpec(buf *bytes.Buffer, spec map[string]interface{}, indent string) {
+func generatePodSpec(g *codeGen, buf *bytes.Buffer, spec map[string]interface{}, path, indent string) {
+	generatePtrFields(g, buf, spec, podSpecPtrFields, path, indent)
+	if restartPolicy, ok := spec["restartPolicy"].(string); ok {
+		buf.WriteString(fmt.Sprintf("%sRestartPolicy: %s,\n", indent, g.enumLiteral("PodSpec", "RestartPolicy", restartPolicy)))
+	}
+	if securityContext, ok := spec["securityContext"].(map[string]interface{}); ok {
+		buf.WriteString(fmt.Sprintf("%sSecurityContext: &corev1.PodSecurityContext{\n", indent))
+		generatePtrFields(g, buf, securityContext, podSecurityContextPtrFields, path+".securityContext", indent+"\t")
+		buf.WriteString(fmt.Sprintf("%s},\n", indent))
+	}
 	if containers, ok := spec["containers"].([]interface{}); ok {
 		buf.WriteString(fmt.Sprintf("%sContainers: []corev1.Container{\n", indent))
-		for _, c := range containers {
+		for i, c := range containers {
 			if container, ok := c.(map[string]interface{}); ok {
 				buf.WriteString(fmt.Sprintf("%s\t{\n", indent))
-				generateContainer(buf, container, indent+"\t\t")
+				generateContainer(g, buf, container, fmt.Sprintf("%s.containers[%d]", path, i), indent+"\t\t")
 				buf.WriteString(fmt.Sprintf("%s\t},\n", indent))
 			}
 		}
@@ -289,13 +625,16 @@ func generatePodSpec(buf *bytes.Buffer, spec map[string]interface{}, indent stri
 	}
 }
 
-func generateContainer(buf *bytes.Buffer, container map[string]interface{}, indent string) {
+func generateContainer(g *codeGen, buf *bytes.Buffer, container map[string]interface{}, path, indent string) {
 	if name, ok := container["name"].(string); ok {
 		buf.WriteString(fmt.Sprintf("%sName:  %q,\n", indent, name))
 	}
 	if image, ok := container["image"].(string); ok {
 		buf.WriteString(fmt.Sprintf("%sImage: %q,\n", indent, image))
 	}
+	if pullPolicy, ok := container["imagePullPolicy"].(string); ok {
+		buf.WriteString(fmt.Sprintf("%sImagePullPolicy: %s,\n", indent, g.enumLiteral("Container", "ImagePullPolicy", pullPolicy)))
+	}
 	if ports, ok := container["ports"].([]interface{}); ok {
 		buf.WriteString(fmt.Sprintf("%sPorts: []corev1.ContainerPort{\n", indent))
 		for _, p := range ports {
@@ -304,11 +643,24 @@ func generateContainer(buf *bytes.Buffer, container map[string]interface{}, inde
 				if containerPort, ok := port["containerPort"].(int); ok {
 					buf.WriteString(fmt.Sprintf("%s\t\tContainerPort: %d,\n", indent, containerPort))
 				}
+				if protocol, ok := port["protocol"].(string); ok {
+					buf.WriteString(fmt.Sprintf("%s\t\tProtocol: %s,\n", indent, g.enumLiteral("ContainerPort", "Protocol", protocol)))
+				}
 				buf.WriteString(fmt.Sprintf("%s\t},\n", indent))
 			}
 		}
 		buf.WriteString(fmt.Sprintf("%s},\n", indent))
 	}
+	if resources, ok := container["resources"].(map[string]interface{}); ok {
+		buf.WriteString(fmt.Sprintf("%sResources: corev1.ResourceRequirements{\n", indent))
+		generateResourceRequirements(g, buf, resources, path+".resources", indent+"\t")
+		buf.WriteString(fmt.Sprintf("%s},\n", indent))
+	}
+	if securityContext, ok := container["securityContext"].(map[string]interface{}); ok {
+		buf.WriteString(fmt.Sprintf("%sSecurityContext: &corev1.SecurityContext{\n", indent))
+		generatePtrFields(g, buf, securityContext, securityContextPtrFields, path+".securityContext", indent+"\t")
+		buf.WriteString(fmt.Sprintf("%s},\n", indent))
+	}
 	if envFrom, ok := container["envFrom"].([]interface{}); ok {
 		buf.WriteString(fmt.Sprintf("%sEnvFrom: []corev1.EnvFromSource{\n", indent))
 		for _, ef := range envFrom {
@@ -328,9 +680,54 @@ func generateContainer(buf *bytes.Buffer, container map[string]interface{}, inde
 	}
 }
 
-func generateServiceSpec(buf *bytes.Buffer, spec map[string]interface{}, indent string) {
+// resourceNames maps well-known resource names to their corev1 constants.
+var resourceNames = map[string]string{
+	"cpu":               "corev1.ResourceCPU",
+	"memory":            "corev1.ResourceMemory",
+	"storage":           "corev1.ResourceStorage",
+	"ephemeral-storage": "corev1.ResourceEphemeralStorage",
+}
+
+// generateResourceRequirements writes the limits and requests of a container.
+func generateResourceRequirements(g *codeGen, buf *bytes.Buffer, resources map[string]interface{}, path, indent string) {
+	for _, f := range []struct{ key, field string }{{"limits", "Limits"}, {"requests", "Requests"}} {
+		list, ok := resources[f.key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		buf.WriteString(fmt.Sprintf("%s%s: corev1.ResourceList{\n", indent, f.field))
+		generateResourceList(g, buf, list, path+"."+f.key, indent+"\t")
+		buf.WriteString(fmt.Sprintf("%s},\n", indent))
+	}
+}
+
+// generateResourceList writes the entries of a ResourceList in key order.
+// Amounts that are not valid quantities are skipped with a warning.
+func generateResourceList(g *codeGen, buf *bytes.Buffer, list map[string]interface{}, path, indent string) {
+	keys := make([]string, 0, len(list))
+	for k := range list {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	elemType := reflect.TypeOf(corev1.ResourceList{}).Elem()
+	for _, k := range keys {
+		literal, ok := g.valueLiteral(list[k], elemType)
+		if !ok {
+			g.warnings = append(g.warnings, fmt.Sprintf("%s.%s: cannot convert %v to a resource quantity, field skipped", path, k, list[k]))
+			continue
+		}
+		name, ok := resourceNames[k]
+		if !ok {
+			name = fmt.Sprintf("corev1.ResourceName(%q)", k)
+		}
+		buf.WriteString(fmt.Sprintf("%s%s: %s,\n", indent, name, literal))
+	}
+}
+
+func generateServiceSpec(g *codeGen, buf *bytes.Buffer, spec map[string]interface{}, path, indent string) {
 	if svcType, ok := spec["type"].(string); ok {
-		buf.WriteString(fmt.Sprintf("%sType: corev1.ServiceType%s,\n", indent, svcType))
+		buf.WriteString(fmt.Sprintf("%sType: %s,\n", indent, g.enumLiteral("ServiceSpec", "Type", svcType)))
 	}
 	if selector, ok := spec["selector"].(map[string]interface{}); ok {
 		buf.WriteString(fmt.Sprintf("%sSelector: map[string]string{\n", indent))
@@ -339,7 +736,8 @@ func generateServiceSpec(buf *bytes.Buffer, spec map[string]interface{}, indent
 	}
 	if ports, ok := spec["ports"].([]interface{}); ok {
 		buf.WriteString(fmt.Sprintf("%sPorts: []corev1.ServicePort{\n", indent))
-		for _, p := range ports {
+		targetPortType, _ := reflect.TypeOf(corev1.ServicePort{}).FieldByName("TargetPort")
+		for i, p := range ports {
 			if port, ok := p.(map[string]interface{}); ok {
 				buf.WriteString(fmt.Sprintf("%s\t{\n", indent))
 				if name, ok := port["name"].(string); ok {
@@ -348,8 +746,15 @@ func generateServiceSpec(buf *bytes.Buffer, spec map[string]interface{}, indent
 				if portNum, ok := port["port"].(int); ok {
 					buf.WriteString(fmt.Sprintf("%s\t\tPort: %d,\n", indent, portNum))
 				}
-				if targetPort, ok := port["targetPort"].(int); ok {
-					buf.WriteString(fmt.Sprintf("%s\t\tTargetPort: intstr.FromInt32(%d),\n", indent, targetPort))
+				if protocol, ok := port["protocol"].(string); ok {
+					buf.WriteString(fmt.Sprintf("%s\t\tProtocol: %s,\n", indent, g.enumLiteral("ServicePort", "Protocol", protocol)))
+				}
+				if targetPort, ok := port["targetPort"]; ok {
+					if literal, ok := g.valueLiteral(targetPort, targetPortType.Type); ok {
+						buf.WriteString(fmt.Sprintf("%s\t\tTargetPort: %s,\n", indent, literal))
+					} else {
+						g.warnings = append(g.warnings, fmt.Sprintf("%s.ports[%d].targetPort: cannot convert %v to a port number or name, field skipped", path, i, targetPort))
+					}
 				}
 				buf.WriteString(fmt.Sprintf("%s\t},\n", indent))
 			}
@@ -359,7 +764,9 @@ func generateServiceSpec(buf *bytes.Buffer, spec map[string]interface{}, indent
 }
 
 func toPascalCase(s string) string {
-	words := regexp.MustCompile("[-_]+").Split(s, -1)
+	// Split on anything that cannot appear in a Go identifier, e.g. the
+	// dots in "widgets.example.com"
+	words := regexp.MustCompile(`[^\p{L}\p{N}]+`).Split(s, -1)
 	var result strings.Builder
 	for _, word := range words {
 		if len(word) > 0 {
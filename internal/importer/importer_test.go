@@ -1,8 +1,12 @@
 package importer_test
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -134,6 +138,275 @@ metadata:
 	assert.Equal(t, "config2", resources[1].Name)
 }
 
+func TestImportBytes_SeparatorInBlockScalar(t *testing.T) {
+	// The "---" lines inside the block scalars are content, not document
+	// separators
+	yamlContent := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: docs
+data:
+  README.md: |
+    # Title
+    ---
+    Body after the rule.
+  tls.crt: |
+    -----BEGIN CERTIFICATE-----
+    MIIB
+    -----END CERTIFICATE-----
+`)
+	resources, err := importer.ParseYAML(yamlContent)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	data := resources[0].RawData["data"].(map[string]interface{})
+	assert.Equal(t, "# Title\n---\nBody after the rule.\n", data["README.md"])
+	assert.Equal(t, "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n", data["tls.crt"])
+
+	result, err := importer.ImportBytes(yamlContent, importer.DefaultOptions())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ResourceCount)
+	assert.Contains(t, result.GoCode, "\"README.md\": `# Title\n---\nBody after the rule.\n`,")
+}
+
+func TestImportBytes_MultiLineStrings(t *testing.T) {
+	yamlContent := []byte("apiVersion: v1\n" +
+		"kind: ConfigMap\n" +
+		"metadata:\n" +
+		"  name: nginx-config\n" +
+		"data:\n" +
+		"  nginx.conf: |\n" +
+		"    events {\n" +
+		"        worker_connections 1024;\n" +
+		"    }\n" +
+		"    http {\n" +
+		"        server {\n" +
+		"            listen 80;\n" +
+		"            return 200 \"ok\\n\";\n" +
+		"        }\n" +
+		"    }\n" +
+		"  motd.sh: |\n" +
+		"    echo `hostname`\n" +
+		"    echo done\n" +
+		"  windows.txt: \"line one\\r\\nline two\\r\\n\"\n" +
+		"  level: debug\n")
+
+	result, err := importer.ImportBytes(yamlContent, importer.DefaultOptions())
+	require.NoError(t, err)
+
+	// Multi-line values are raw strings, kept as written
+	assert.Contains(t, result.GoCode, "\"nginx.conf\": `events {\n    worker_connections 1024;\n}\nhttp {\n")
+	assert.Contains(t, result.GoCode, "return 200 \"ok\\n\";\n")
+	// Values a raw string cannot hold exactly are quoted
+	assert.Contains(t, result.GoCode, `"motd.sh": "echo `+"`hostname`"+`\necho done\n",`)
+	assert.Contains(t, result.GoCode, `"windows.txt": "line one\r\nline two\r\n",`)
+	// Single-line values are unchanged
+	assert.Contains(t, result.GoCode, `"level": "debug",`)
+
+	// Every literal decodes to the original value
+	file, err := parser.ParseFile(token.NewFileSet(), "config.go", result.GoCode, 0)
+	require.NoError(t, err)
+	data := map[string]string{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if kv, ok := n.(*ast.KeyValueExpr); ok {
+			key, keyOK := kv.Key.(*ast.BasicLit)
+			value, valueOK := kv.Value.(*ast.BasicLit)
+			if keyOK && valueOK && key.Kind == token.STRING && value.Kind == token.STRING {
+				k, _ := strconv.Unquote(key.Value)
+				v, err := strconv.Unquote(value.Value)
+				require.NoError(t, err)
+				data[k] = v
+			}
+		}
+		return true
+	})
+	parsed, err := importer.ParseYAML(yamlContent)
+	require.NoError(t, err)
+	for k, v := range parsed[0].RawData["data"].(map[string]interface{}) {
+		assert.Equal(t, v, data[k], k)
+	}
+}
+
+func TestImportBytes_ResourceQuantities(t *testing.T) {
+	yamlContent := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+        - name: web
+          image: nginx:1.25
+          resources:
+            limits:
+              cpu: 500m
+              memory: 512Mi
+              nvidia.com/gpu: 1
+            requests:
+              cpu: "0.25"
+              memory: lots
+`)
+	result, err := importer.ImportBytes(yamlContent, importer.DefaultOptions())
+	require.NoError(t, err)
+
+	_, err = parser.ParseFile(token.NewFileSet(), "imported.go", result.GoCode, 0)
+	require.NoError(t, err, "generated code should parse")
+
+	assert.Contains(t, result.GoCode, `"k8s.io/apimachinery/pkg/api/resource"`)
+	assert.Contains(t, result.GoCode, "Resources: corev1.ResourceRequirements{")
+	assert.Contains(t, result.GoCode, `corev1.ResourceCPU: resource.MustParse("500m"),`)
+	assert.Contains(t, result.GoCode, `corev1.ResourceMemory: resource.MustParse("512Mi"),`)
+	assert.Contains(t, result.GoCode, `corev1.ResourceName("nvidia.com/gpu"): resource.MustParse("1"),`)
+	assert.Contains(t, result.GoCode, `corev1.ResourceCPU: resource.MustParse("0.25"),`)
+
+	// Invalid quantities are skipped with a warning instead of emitting a
+	// MustParse call that panics
+	assert.NotContains(t, result.GoCode, "lots")
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "resources.requests.memory")
+}
+
+func TestImportBytes_NoResourceImportWhenUnused(t *testing.T) {
+	result, err := importer.ImportFile(filepath.Join("testdata", "service.yaml"), importer.DefaultOptions())
+	require.NoError(t, err)
+	assert.NotContains(t, result.GoCode, "api/resource")
+}
+
+func TestImportBytes_TargetPorts(t *testing.T) {
+	yamlContent := []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+    - name: http
+      port: 80
+      targetPort: 8080
+    - name: metrics
+      port: 9090
+      targetPort: metrics
+    - name: admin
+      port: 8443
+      targetPort: "8443"
+`)
+	result, err := importer.ImportBytes(yamlContent, importer.DefaultOptions())
+	require.NoError(t, err)
+
+	_, err = parser.ParseFile(token.NewFileSet(), "imported.go", result.GoCode, 0)
+	require.NoError(t, err, "generated code should parse")
+
+	assert.Contains(t, result.GoCode, `"k8s.io/apimachinery/pkg/util/intstr"`)
+	assert.Contains(t, result.GoCode, "TargetPort: intstr.FromInt32(8080),")
+	assert.Contains(t, result.GoCode, `TargetPort: intstr.FromString("metrics"),`)
+	// A quoted number is a string in YAML, as the API server reads it
+	assert.Contains(t, result.GoCode, `TargetPort: intstr.FromString("8443"),`)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestImportBytes_NamedTargetPortOnly(t *testing.T) {
+	yamlContent := []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+    - port: 80
+      targetPort: http
+`)
+	result, err := importer.ImportBytes(yamlContent, importer.DefaultOptions())
+	require.NoError(t, err)
+	assert.Contains(t, result.GoCode, `TargetPort: intstr.FromString("http"),`)
+	assert.Contains(t, result.GoCode, `"k8s.io/apimachinery/pkg/util/intstr"`, "named ports need the intstr import too")
+}
+
+func TestImportBytes_InvalidTargetPort(t *testing.T) {
+	yamlContent := []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+    - port: 80
+      targetPort: [8080]
+`)
+	result, err := importer.ImportBytes(yamlContent, importer.DefaultOptions())
+	require.NoError(t, err)
+	assert.NotContains(t, result.GoCode, "TargetPort")
+	assert.NotContains(t, result.GoCode, "intstr")
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "Service/web.spec.ports[0].targetPort")
+}
+
+func TestImportBytes_EnumConstants(t *testing.T) {
+	yamlContent := []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: dns
+spec:
+  type: NodePort
+  ports:
+    - port: 53
+      protocol: UDP
+    - port: 9153
+      protocol: QUIC
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dns
+spec:
+  template:
+    spec:
+      containers:
+        - name: coredns
+          image: coredns:1.11
+          imagePullPolicy: IfNotPresent
+          ports:
+            - containerPort: 53
+              protocol: UDP
+`)
+	result, err := importer.ImportBytes(yamlContent, importer.DefaultOptions())
+	require.NoError(t, err)
+	_, err = parser.ParseFile(token.NewFileSet(), "imported.go", result.GoCode, 0)
+	require.NoError(t, err, "generated code should parse")
+	assert.Contains(t, result.GoCode, "Type: corev1.ServiceTypeNodePort,")
+	assert.Contains(t, result.GoCode, "\t\tProtocol: corev1.ProtocolUDP,", "service port protocol")
+	assert.Contains(t, result.GoCode, "\t\t\t\t\t\tProtocol: corev1.ProtocolUDP,", "container port protocol")
+	assert.Contains(t, result.GoCode, "ImagePullPolicy: corev1.PullIfNotPresent,")
+	// Values without a constant are written as strings, which Go converts
+	assert.Contains(t, result.GoCode, `Protocol: "QUIC",`)
+
+	t.Run("disabled", func(t *testing.T) {
+		opts := importer.DefaultOptions()
+		opts.EnumConstants = false
+		result, err := importer.ImportBytes(yamlContent, opts)
+		require.NoError(t, err)
+		assert.Contains(t, result.GoCode, `Type: "NodePort",`)
+		assert.Contains(t, result.GoCode, `Protocol: "UDP",`)
+		assert.NotContains(t, result.GoCode, "corev1.Protocol")
+	})
+}
+
+func TestImportBytes_JobHasNoUnusedImports(t *testing.T) {
+	// Job specs are not generated, so their pod template must not pull in
+	// corev1, which would leave an unused import
+	yamlContent := []byte(`apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+        - name: migrate
+          image: migrate:1.0
+`)
+	result, err := importer.ImportBytes(yamlContent, importer.DefaultOptions())
+	require.NoError(t, err)
+	assert.NotContains(t, result.GoCode, "k8s.io/api/core/v1")
+}
+
 func TestGenerateVarName(t *testing.T) {
 	tests := []struct {
 		name, kind, prefix, expected string
@@ -141,6 +414,8 @@ func TestGenerateVarName(t *testing.T) {
 		{"nginx-deployment", "Deployment", "", "NginxDeploymentDeployment"},
 		{"my-service", "Service", "", "MyServiceService"},
 		{"app-config", "ConfigMap", "Prod", "ProdAppConfigConfigMap"},
+		{"widgets.example.com", "CustomResourceDefinition", "", "WidgetsExampleComCustomResourceDefinition"},
+		{"1st-app", "Deployment", "", "Deployment1stApp"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -163,7 +438,35 @@ func TestImportFile_PointerFields(t *testing.T) {
 	testFile := filepath.Join("testdata", "deployment.yaml")
 	result, err := importer.ImportFile(testFile, importer.DefaultOptions())
 	require.NoError(t, err)
-	assert.Contains(t, result.GoCode, "ptr.To[int32](3)")
+	assert.Contains(t, result.GoCode, "Replicas: ptr(int32(3))")
+	assert.NotContains(t, result.GoCode, "k8s.io/utils/ptr")
+	assert.Equal(t, 1, strings.Count(result.GoCode, "func ptr[T any](v T) *T"), "ptr helper should be emitted once")
+}
+
+func TestImportFile_PointerFieldsNested(t *testing.T) {
+	testFile := filepath.Join("testdata", "secure-deployment.yaml")
+	result, err := importer.ImportFile(testFile, importer.DefaultOptions())
+	require.NoError(t, err)
+	assert.Contains(t, result.GoCode, "TerminationGracePeriodSeconds: ptr(int64(30))")
+	assert.Contains(t, result.GoCode, "SecurityContext: &corev1.PodSecurityContext{")
+	assert.Contains(t, result.GoCode, "RunAsNonRoot: ptr(true)")
+	assert.Contains(t, result.GoCode, "FSGroup: ptr(int64(2000))")
+	assert.Contains(t, result.GoCode, "RunAsUser: ptr(int64(1000))")
+	assert.Contains(t, result.GoCode, "ReadOnlyRootFilesystem: ptr(true)")
+	assert.Contains(t, result.GoCode, "AllowPrivilegeEscalation: ptr(false)")
+
+	// revisionHistoryLimit is a string and cannot become *int32
+	assert.NotContains(t, result.GoCode, "RevisionHistoryLimit")
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "Deployment/secure-app.spec.revisionHistoryLimit")
+	assert.Contains(t, result.Warnings[0], "*int32")
+}
+
+func TestImportFile_NoPointerHelperWhenUnused(t *testing.T) {
+	testFile := filepath.Join("testdata", "configmap.yaml")
+	result, err := importer.ImportFile(testFile, importer.DefaultOptions())
+	require.NoError(t, err)
+	assert.NotContains(t, result.GoCode, "func ptr")
 }
 
 func TestAPIVersionToImport(t *testing.T) {
@@ -182,3 +485,154 @@ func TestAPIVersionToImport(t *testing.T) {
 		})
 	}
 }
+
+func TestImportFile_CustomResourceFallsBackToUnstructured(t *testing.T) {
+	testFile := filepath.Join("testdata", "certificate.yaml")
+	result, err := importer.ImportFile(testFile, importer.DefaultOptions())
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.ResourceCount)
+
+	assert.Contains(t, result.GoCode, `"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"`)
+	assert.NotContains(t, result.GoCode, "k8s.io/api/cert-manager")
+	assert.Contains(t, result.GoCode, "var WebTlsCertificate = unstructured.Unstructured{")
+	assert.Contains(t, result.GoCode, `"apiVersion": "cert-manager.io/v1",`)
+	assert.Contains(t, result.GoCode, `"size": int64(2048),`)
+	assert.Contains(t, result.GoCode, `"isCA": false,`)
+
+	// Built-in kinds in the same file are still generated as typed code
+	assert.Contains(t, result.GoCode, "var WebConfigConfigMap = corev1.ConfigMap{")
+
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "cert-manager.io/v1 Certificate")
+}
+
+func TestImportBytes_CustomResourceDefinition(t *testing.T) {
+	yamlData := `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+`
+	result, err := importer.ImportBytes([]byte(yamlData), importer.DefaultOptions())
+	require.NoError(t, err)
+	assert.Contains(t, result.GoCode, "unstructured.Unstructured{")
+	// metav1 is only imported for typed resources
+	assert.NotContains(t, result.GoCode, "metav1")
+	assert.Len(t, result.Warnings, 1)
+}
+
+func TestImportBytes_VariableNameCollisions(t *testing.T) {
+	yamlData := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: app
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+  namespace: other
+`
+	for _, prefix := range []string{"", "Prod"} {
+		t.Run("prefix="+prefix, func(t *testing.T) {
+			result, err := importer.ImportBytes([]byte(yamlData), importer.Options{VarPrefix: prefix})
+			require.NoError(t, err)
+
+			file, err := parser.ParseFile(token.NewFileSet(), "imported.go", result.GoCode, 0)
+			require.NoError(t, err, "generated code should parse")
+
+			var names []string
+			for _, decl := range file.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range genDecl.Specs {
+					for _, name := range spec.(*ast.ValueSpec).Names {
+						names = append(names, name.Name)
+					}
+				}
+			}
+			assert.Equal(t, []string{
+				prefix + "AppDeployment",
+				prefix + "AppService",
+				prefix + "AppService2",
+				prefix + "AppIngress",
+			}, names)
+		})
+	}
+}
+
+func TestImportBytes_OrdersResourcesByKind(t *testing.T) {
+	docs := []string{
+		"apiVersion: networking.k8s.io/v1\nkind: Ingress\nmetadata:\n  name: web\n",
+		"apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n",
+		"apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n",
+		"apiVersion: v1\nkind: Secret\nmetadata:\n  name: web-credentials\n",
+		"apiVersion: rbac.authorization.k8s.io/v1\nkind: RoleBinding\nmetadata:\n  name: web\n",
+		"apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: web\n",
+		"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web-config\n",
+		"apiVersion: v1\nkind: Namespace\nmetadata:\n  name: shop\n",
+	}
+
+	var expected string
+	for i, order := range [][]int{{0, 1, 2, 3, 4, 5, 6, 7}, {7, 6, 5, 4, 3, 2, 1, 0}, {3, 0, 6, 2, 7, 1, 5, 4}} {
+		var shuffled []string
+		for _, j := range order {
+			shuffled = append(shuffled, docs[j])
+		}
+		result, err := importer.ImportBytes([]byte(strings.Join(shuffled, "---\n")), importer.DefaultOptions())
+		require.NoError(t, err)
+
+		if i == 0 {
+			expected = result.GoCode
+			continue
+		}
+		assert.Equal(t, expected, result.GoCode, "output should not depend on input order")
+	}
+
+	var order []string
+	for _, line := range strings.Split(expected, "\n") {
+		if strings.HasPrefix(line, "var ") || (strings.HasPrefix(line, "// ") && !strings.HasPrefix(line, "// ==")) {
+			order = append(order, line)
+		}
+	}
+	assert.Equal(t, []string{
+		"// Namespaces",
+		"var ShopNamespace = corev1.Namespace{",
+		"// Configuration",
+		"var WebConfigConfigMap = corev1.ConfigMap{",
+		"var WebCredentialsSecret = corev1.Secret{",
+		"// RBAC",
+		"var WebServiceAccount = corev1.ServiceAccount{",
+		"var WebRoleBinding = rbacv1.RoleBinding{",
+		"// Workloads",
+		"var WebDeployment = appsv1.Deployment{",
+		"// Services",
+		"var WebService = corev1.Service{",
+		"// Ingress",
+		"var WebIngress = networkingv1.Ingress{",
+	}, order)
+	assert.Contains(t, expected, "// =============================================================================\n// Workloads\n// =============================================================================\n\nvar WebDeployment")
+}
+
+func TestImportBytes_SingleResourceHasNoBanner(t *testing.T) {
+	result, err := importer.ImportFile(filepath.Join("testdata", "deployment.yaml"), importer.DefaultOptions())
+	require.NoError(t, err)
+	assert.NotContains(t, result.GoCode, "// ====")
+}
@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// importSections groups kinds into the sections of a generated file, in the
+// order they are written: namespaces first, then policies, configuration,
+// storage, identities and RBAC, workloads, and the Services and Ingresses that
+// expose them. Kinds that are not listed go in a final "Other Resources"
+// section.
+var importSections = []struct {
+	title string
+	kinds []string
+}{
+	{"Namespaces", []string{"Namespace"}},
+	{"Policies", []string{"ResourceQuota", "LimitRange", "NetworkPolicy", "PriorityClass"}},
+	{"Configuration", []string{"ConfigMap", "Secret"}},
+	{"Storage", []string{"StorageClass", "PersistentVolume", "PersistentVolumeClaim"}},
+	{"RBAC", []string{"ServiceAccount", "ClusterRole", "Role", "ClusterRoleBinding", "RoleBinding"}},
+	{"Workloads", []string{"Pod", "ReplicaSet", "Deployment", "StatefulSet", "DaemonSet", "Job", "CronJob"}},
+	{"Scaling and Availability", []string{"HorizontalPodAutoscaler", "PodDisruptionBudget"}},
+	{"Services", []string{"Service"}},
+	{"Ingress", []string{"IngressClass", "Ingress"}},
+}
+
+// otherSection is the title of the section for kinds not in importSections.
+const otherSection = "Other Resources"
+
+// kindPriority maps each kind in importSections to its position, counting
+// across sections, so kinds sort by section and then by their order within
+// the section.
+var kindPriority = func() map[string]int {
+	priority := make(map[string]int)
+	for _, section := range importSections {
+		for _, kind := range section.kinds {
+			priority[kind] = len(priority)
+		}
+	}
+	return priority
+}()
+
+// sectionTitle returns the title of the section a kind is written in.
+func sectionTitle(kind string) string {
+	for _, section := range importSections {
+		for _, k := range section.kinds {
+			if k == kind {
+				return section.title
+			}
+		}
+	}
+	return otherSection
+}
+
+// sortResources returns resources in the order they are generated: by kind
+// priority, then by kind, name and namespace, so the output does not depend
+// on the order of the input documents.
+func sortResources(resources []ResourceInfo) []ResourceInfo {
+	priority := func(kind string) int {
+		if p, ok := kindPriority[kind]; ok {
+			return p
+		}
+		return len(kindPriority)
+	}
+
+	sorted := append([]ResourceInfo(nil), resources...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if pa, pb := priority(a.Kind), priority(b.Kind); pa != pb {
+			return pa < pb
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Namespace < b.Namespace
+	})
+	return sorted
+}
+
+// sectionBanner returns the comment banner that starts a section, in the
+// style of the examples.
+func sectionBanner(title string) string {
+	rule := "// " + strings.Repeat("=", 77)
+	return fmt.Sprintf("%s\n// %s\n%s\n\n", rule, title, rule)
+}
@@ -5,14 +5,20 @@ type Options struct {
 	PackageName string
 	VarPrefix   string
 	Optimize    bool
+
+	// EnumConstants writes fields of Kubernetes enum types, such as a
+	// Service type or a port protocol, as typed constants like
+	// corev1.ProtocolTCP rather than string literals.
+	EnumConstants bool
 }
 
 // DefaultOptions returns the default import options.
 func DefaultOptions() Options {
 	return Options{
-		PackageName: "main",
-		VarPrefix:   "",
-		Optimize:    true,
+		PackageName:   "main",
+		VarPrefix:     "",
+		Optimize:      true,
+		EnumConstants: true,
 	}
 }
 
@@ -0,0 +1,243 @@
+package kiro
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	corekiro "github.com/lex00/wetwire-core-go/kiro"
+	"github.com/lex00/wetwire-core-go/providers"
+)
+
+// ProviderName is the name of the Kiro provider.
+const ProviderName = "kiro"
+
+// Transport sends a prompt to Kiro and returns its reply.
+type Transport interface {
+	Run(ctx context.Context, prompt string) (string, error)
+}
+
+// CLITransport runs each prompt through a non-interactive Kiro CLI chat with
+// the wetwire-k8s agent.
+type CLITransport struct {
+	WorkDir string // Working directory of the Kiro CLI
+}
+
+// Run implements Transport.
+func (t *CLITransport) Run(ctx context.Context, prompt string) (string, error) {
+	if err := EnsureInstalled(); err != nil {
+		return "", fmt.Errorf("installing kiro config: %w", err)
+	}
+
+	args, err := corekiro.BuildCommand(AgentName, prompt, true)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = t.WorkDir
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return "", fmt.Errorf("kiro-cli failed: %w\n%s", err, stderr.String())
+		}
+		return "", fmt.Errorf("kiro-cli failed: %w", err)
+	}
+	return ansiEscape.ReplaceAllString(string(output), ""), nil
+}
+
+// ansiEscape matches the terminal escape sequences in Kiro CLI output.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+// Provider implements providers.Provider on top of Kiro.
+//
+// Kiro has no tool-calling API, so each request is rendered as a single
+// prompt that lists the tools and the conversation so far, and asks for tool
+// calls as fenced tool_use blocks. Those blocks are parsed back into tool_use
+// content, which lets the agent loop execute the tools itself.
+type Provider struct {
+	Transport Transport
+}
+
+// NewProvider creates a Kiro provider that runs the Kiro CLI in workDir.
+// An empty workDir uses the current directory.
+func NewProvider(workDir string) *Provider {
+	if workDir == "" {
+		workDir, _ = os.Getwd()
+	}
+	return &Provider{Transport: &CLITransport{WorkDir: workDir}}
+}
+
+// Name implements providers.Provider.
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// CreateMessage implements providers.Provider.
+func (p *Provider) CreateMessage(ctx context.Context, req providers.MessageRequest) (*providers.MessageResponse, error) {
+	reply, err := p.Transport.Run(ctx, renderPrompt(req))
+	if err != nil {
+		return nil, err
+	}
+	return parseReply(reply, countToolUses(req.Messages))
+}
+
+// StreamMessage implements providers.Provider. The Kiro CLI replies all at
+// once, so the text of the reply is passed to the handler in one piece.
+func (p *Provider) StreamMessage(ctx context.Context, req providers.MessageRequest, handler providers.StreamHandler) (*providers.MessageResponse, error) {
+	resp, err := p.CreateMessage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if handler != nil {
+		for _, block := range resp.Content {
+			if block.Type == "text" {
+				handler(block.Text + "\n")
+			}
+		}
+	}
+	return resp, nil
+}
+
+// toolCall is the JSON body of a tool_use or tool_result block in a prompt.
+type toolCall struct {
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+}
+
+// toolUseBlock matches a fenced tool_use block in a reply.
+var toolUseBlock = regexp.MustCompile("(?s)```tool_use[ \t]*\n(.*?)\n[ \t]*```")
+
+// toolInstructions tells Kiro how to call the tools of a request.
+const toolInstructions = "To call a tool, reply with one fenced block per call, " +
+	"containing the tool name and its input as JSON:\n\n" +
+	"```tool_use\n{\"name\": \"<tool>\", \"input\": {...}}\n```\n\n" +
+	"Stop after your tool calls; the results are sent in the next message. " +
+	"Reply without any tool_use block when the task is complete. " +
+	"Only use the tools listed here."
+
+// renderPrompt renders a request as a single prompt.
+func renderPrompt(req providers.MessageRequest) string {
+	var b strings.Builder
+
+	if req.System != "" {
+		b.WriteString(req.System)
+		b.WriteString("\n\n")
+	}
+
+	if len(req.Tools) > 0 {
+		b.WriteString("## Tools\n\n")
+		for _, tool := range req.Tools {
+			schema, _ := json.Marshal(map[string]any{
+				"type":       "object",
+				"properties": tool.InputSchema.Properties,
+				"required":   tool.InputSchema.Required,
+			})
+			fmt.Fprintf(&b, "- %s: %s\n  Input schema: %s\n", tool.Name, tool.Description, schema)
+		}
+		b.WriteString("\n")
+		b.WriteString(toolInstructions)
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString("## Conversation\n")
+	for _, msg := range req.Messages {
+		role := "User"
+		if msg.Role == "assistant" {
+			role = "Assistant"
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", role)
+		for _, block := range msg.Content {
+			writeBlock(&b, block)
+		}
+	}
+
+	b.WriteString("\n### Assistant\n")
+	return b.String()
+}
+
+// writeBlock renders one content block of a message.
+func writeBlock(b *strings.Builder, block providers.ContentBlock) {
+	switch block.Type {
+	case "text":
+		b.WriteString(block.Text)
+		b.WriteString("\n")
+	case "tool_use":
+		call, _ := json.Marshal(toolCall{ID: block.ID, Name: block.Name, Input: block.Input})
+		fmt.Fprintf(b, "```tool_use\n%s\n```\n", call)
+	case "tool_result":
+		status := "ok"
+		if block.IsError {
+			status = "error"
+		}
+		fmt.Fprintf(b, "```tool_result id=%s status=%s\n%s\n```\n", block.ToolUseID, status, block.Content)
+	}
+}
+
+// parseReply converts a Kiro reply into response content. Tool calls are
+// numbered from prior, the number of tool calls earlier in the conversation,
+// so that their IDs are unique within it.
+func parseReply(reply string, prior int) (*providers.MessageResponse, error) {
+	resp := &providers.MessageResponse{StopReason: providers.StopReasonEndTurn}
+
+	addText := func(text string) {
+		if text = strings.TrimSpace(text); text != "" {
+			resp.Content = append(resp.Content, providers.ContentBlock{Type: "text", Text: text})
+		}
+	}
+
+	last := 0
+	for _, match := range toolUseBlock.FindAllStringSubmatchIndex(reply, -1) {
+		addText(reply[last:match[0]])
+		last = match[1]
+
+		var call toolCall
+		if err := json.Unmarshal([]byte(reply[match[2]:match[3]]), &call); err != nil {
+			return nil, fmt.Errorf("invalid tool call from kiro: %w", err)
+		}
+		if call.Name == "" {
+			return nil, fmt.Errorf("invalid tool call from kiro: missing tool name")
+		}
+		if len(call.Input) == 0 {
+			call.Input = json.RawMessage("{}")
+		}
+
+		prior++
+		resp.Content = append(resp.Content, providers.ContentBlock{
+			Type:  "tool_use",
+			ID:    fmt.Sprintf("kiro_tool_%d", prior),
+			Name:  call.Name,
+			Input: call.Input,
+		})
+		resp.StopReason = providers.StopReasonToolUse
+	}
+	addText(reply[last:])
+
+	return resp, nil
+}
+
+// countToolUses returns the number of tool calls in a conversation.
+func countToolUses(messages []providers.Message) int {
+	n := 0
+	for _, msg := range messages {
+		for _, block := range msg.Content {
+			if block.Type == "tool_use" {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,188 @@
+package kiro
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lex00/wetwire-core-go/providers"
+)
+
+// fakeTransport returns scripted replies and records the prompts it receives.
+type fakeTransport struct {
+	replies []string
+	prompts []string
+}
+
+func (f *fakeTransport) Run(ctx context.Context, prompt string) (string, error) {
+	f.prompts = append(f.prompts, prompt)
+	if len(f.replies) == 0 {
+		return "", errors.New("no scripted reply")
+	}
+	reply := f.replies[0]
+	f.replies = f.replies[1:]
+	return reply, nil
+}
+
+var writeFileTool = providers.Tool{
+	Name:        "write_file",
+	Description: "Write a file",
+	InputSchema: providers.ToolInputSchema{
+		Properties: map[string]any{
+			"path":    map[string]any{"type": "string"},
+			"content": map[string]any{"type": "string"},
+		},
+		Required: []string{"path", "content"},
+	},
+}
+
+func TestProvider_ToolCallCycle(t *testing.T) {
+	transport := &fakeTransport{replies: []string{
+		"I'll write the deployment.\n\n```tool_use\n{\"name\": \"write_file\", \"input\": {\"path\": \"main.go\", \"content\": \"package main\"}}\n```\n",
+		"The deployment is written and lint passed.",
+	}}
+	provider := &Provider{Transport: transport}
+
+	req := providers.MessageRequest{
+		System:   "You generate Kubernetes manifests.",
+		Messages: []providers.Message{providers.NewUserMessage("Create nginx")},
+		Tools:    []providers.Tool{writeFileTool},
+	}
+
+	// First turn: Kiro asks for a tool call
+	resp, err := provider.CreateMessage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if resp.StopReason != providers.StopReasonToolUse {
+		t.Fatalf("StopReason = %q, want %q", resp.StopReason, providers.StopReasonToolUse)
+	}
+	if len(resp.Content) != 2 {
+		t.Fatalf("got %d content blocks, want 2: %+v", len(resp.Content), resp.Content)
+	}
+	if resp.Content[0].Type != "text" || resp.Content[0].Text != "I'll write the deployment." {
+		t.Errorf("first block = %+v, want the text before the tool call", resp.Content[0])
+	}
+
+	call := resp.Content[1]
+	if call.Type != "tool_use" || call.Name != "write_file" || call.ID != "kiro_tool_1" {
+		t.Fatalf("second block = %+v, want write_file tool_use kiro_tool_1", call)
+	}
+	var input map[string]string
+	if err := json.Unmarshal(call.Input, &input); err != nil {
+		t.Fatalf("tool input is not JSON: %v", err)
+	}
+	if input["path"] != "main.go" {
+		t.Errorf("input path = %q, want main.go", input["path"])
+	}
+
+	prompt := transport.prompts[0]
+	for _, want := range []string{"You generate Kubernetes manifests.", "- write_file: Write a file", "```tool_use", "### User\n\nCreate nginx"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("first prompt does not contain %q:\n%s", want, prompt)
+		}
+	}
+
+	// Second turn: the agent loop returns the tool result
+	req.Messages = append(req.Messages,
+		providers.NewAssistantMessage(resp.Content),
+		providers.NewToolResultMessage([]providers.ContentBlock{
+			providers.NewToolResult(call.ID, "Wrote main.go", false),
+		}),
+	)
+
+	resp, err = provider.CreateMessage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if resp.StopReason != providers.StopReasonEndTurn {
+		t.Errorf("StopReason = %q, want %q", resp.StopReason, providers.StopReasonEndTurn)
+	}
+	if len(resp.Content) != 1 || resp.Content[0].Text != "The deployment is written and lint passed." {
+		t.Errorf("content = %+v, want the final text", resp.Content)
+	}
+
+	prompt = transport.prompts[1]
+	for _, want := range []string{
+		`{"id":"kiro_tool_1","name":"write_file","input":{"path":"main.go","content":"package main"}}`,
+		"```tool_result id=kiro_tool_1 status=ok\nWrote main.go\n```",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("second prompt does not contain %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestProvider_ToolCallIDsAreUnique(t *testing.T) {
+	reply := "```tool_use\n{\"name\": \"run_lint\"}\n```\n```tool_use\n{\"name\": \"run_build\", \"input\": {}}\n```"
+	provider := &Provider{Transport: &fakeTransport{replies: []string{reply}}}
+
+	req := providers.MessageRequest{Messages: []providers.Message{
+		providers.NewUserMessage("Create nginx"),
+		providers.NewAssistantMessage([]providers.ContentBlock{{Type: "tool_use", ID: "kiro_tool_1", Name: "write_file"}}),
+	}}
+	resp, err := provider.CreateMessage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+
+	var ids []string
+	for _, block := range resp.Content {
+		ids = append(ids, block.ID)
+		if string(block.Input) != "{}" {
+			t.Errorf("%s input = %s, want {}", block.Name, block.Input)
+		}
+	}
+	if strings.Join(ids, ",") != "kiro_tool_2,kiro_tool_3" {
+		t.Errorf("tool IDs = %v, want [kiro_tool_2 kiro_tool_3]", ids)
+	}
+}
+
+func TestProvider_InvalidToolCall(t *testing.T) {
+	provider := &Provider{Transport: &fakeTransport{replies: []string{"```tool_use\n{not json}\n```"}}}
+
+	_, err := provider.CreateMessage(context.Background(), providers.MessageRequest{})
+	if err == nil || !strings.Contains(err.Error(), "invalid tool call") {
+		t.Errorf("CreateMessage() error = %v, want invalid tool call", err)
+	}
+}
+
+func TestProvider_StreamMessage(t *testing.T) {
+	provider := &Provider{Transport: &fakeTransport{replies: []string{"Done."}}}
+
+	var streamed strings.Builder
+	resp, err := provider.StreamMessage(context.Background(), providers.MessageRequest{}, func(text string) {
+		streamed.WriteString(text)
+	})
+	if err != nil {
+		t.Fatalf("StreamMessage() error = %v", err)
+	}
+	if streamed.String() != "Done.\n" {
+		t.Errorf("streamed %q, want %q", streamed.String(), "Done.\n")
+	}
+	if resp.StopReason != providers.StopReasonEndTurn {
+		t.Errorf("StopReason = %q, want %q", resp.StopReason, providers.StopReasonEndTurn)
+	}
+}
+
+func TestProvider_TransportError(t *testing.T) {
+	provider := &Provider{Transport: &fakeTransport{}}
+
+	_, err := provider.CreateMessage(context.Background(), providers.MessageRequest{})
+	if err == nil {
+		t.Error("CreateMessage() should return the transport error")
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	provider := NewProvider("/tmp/out")
+	if provider.Name() != "kiro" {
+		t.Errorf("Name() = %q, want kiro", provider.Name())
+	}
+	transport, ok := provider.Transport.(*CLITransport)
+	if !ok || transport.WorkDir != "/tmp/out" {
+		t.Errorf("Transport = %#v, want CLITransport in /tmp/out", provider.Transport)
+	}
+}
@@ -0,0 +1,105 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Rule categories used in the rule catalog.
+const (
+	CategorySecurity = "security"
+	CategoryWorkload = "workload"
+	CategoryStyle    = "style"
+)
+
+// RuleInfo describes a lint rule for the rule catalog.
+type RuleInfo struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+	Fixable     bool   `json:"fixable"`
+	Category    string `json:"category"`
+}
+
+// Catalog returns catalog entries for the given rules, in the same order.
+func Catalog(rules []Rule) []RuleInfo {
+	infos := make([]RuleInfo, 0, len(rules))
+	for _, rule := range rules {
+		infos = append(infos, RuleInfo{
+			ID:          rule.ID,
+			Name:        rule.Name,
+			Description: rule.Description,
+			Severity:    rule.Severity.String(),
+			Fixable:     rule.Fix != nil || isFixableRule(rule.ID),
+			Category:    RuleCategory(rule.ID),
+		})
+	}
+	return infos
+}
+
+// RuleCategory infers a rule's category from its ID range:
+//
+//	WK8001-WK8004  style     (structure)
+//	WK8007, WK8011 style     (naming and references)
+//	WK8013         style     (recommended labels)
+//	WK8018, WK8019 style     (structure)
+//	WK8012         workload  (autoscaling)
+//	WK8016         workload  (service ports)
+//	WK8017         workload  (update strategy)
+//	WK8005-WK8099  security  (secrets, images, network, volumes)
+//	WK81xx         workload  (workload configuration)
+//	WK82xx         security  (security context)
+//	WK83xx         workload  (availability)
+//	WK84xx         style     (organization)
+func RuleCategory(id string) string {
+	n, err := strconv.Atoi(strings.TrimPrefix(id, "WK"))
+	if err != nil {
+		return CategoryStyle
+	}
+
+	switch {
+	case n < 8005, n == 8007, n == 8011, n == 8013, n == 8018, n == 8019:
+		return CategoryStyle
+	case n == 8012, n == 8016, n == 8017:
+		return CategoryWorkload
+	case n < 8100:
+		return CategorySecurity
+	case n < 8200:
+		return CategoryWorkload
+	case n < 8300:
+		return CategorySecurity
+	case n < 8400:
+		return CategoryWorkload
+	default:
+		return CategoryStyle
+	}
+}
+
+// WriteCatalogJSON writes the catalog as an indented JSON array.
+func WriteCatalogJSON(w io.Writer, infos []RuleInfo) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(infos)
+}
+
+// WriteCatalogMarkdown writes the catalog as a Markdown table.
+func WriteCatalogMarkdown(w io.Writer, infos []RuleInfo) error {
+	fmt.Fprintln(w, "| Rule | Name | Description | Severity | Category | Auto-fix |")
+	fmt.Fprintln(w, "|------|------|-------------|----------|----------|----------|")
+	for _, info := range infos {
+		fix := "No"
+		if info.Fixable {
+			fix = "Yes"
+		}
+		_, err := fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s |\n",
+			info.ID, info.Name, info.Description, info.Severity, info.Category, fix)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog(t *testing.T) {
+	rules := AllRules()
+	infos := Catalog(rules)
+
+	t.Run("should include every rule", func(t *testing.T) {
+		require.Len(t, infos, len(rules))
+		for i, rule := range rules {
+			assert.Equal(t, rule.ID, infos[i].ID)
+			assert.Equal(t, rule.Severity.String(), infos[i].Severity)
+		}
+	})
+
+	t.Run("should flag fixable rules", func(t *testing.T) {
+		for _, info := range infos {
+			switch info.ID {
+			case "WK8105", "WK8002", "WK8007", "WK8011", "WK8013", "WK8102", "WK8201", "WK8015":
+				assert.True(t, info.Fixable, "%s should be fixable", info.ID)
+			default:
+				assert.False(t, info.Fixable, "%s should not be fixable", info.ID)
+			}
+		}
+	})
+}
+
+func TestRuleCategory(t *testing.T) {
+	tests := []struct {
+		id       string
+		expected string
+	}{
+		{"WK8001", CategoryStyle},
+		{"WK8004", CategoryStyle},
+		{"WK8005", CategorySecurity},
+		{"WK8007", CategoryStyle},
+		{"WK8011", CategoryStyle},
+		{"WK8012", CategoryWorkload},
+		{"WK8013", CategoryStyle},
+		{"WK8017", CategoryWorkload},
+		{"WK8099", CategorySecurity},
+		{"WK8101", CategoryWorkload},
+		{"WK8202", CategorySecurity},
+		{"WK8302", CategoryWorkload},
+		{"WK8401", CategoryStyle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RuleCategory(tt.id))
+		})
+	}
+}
+
+func TestWriteCatalog(t *testing.T) {
+	infos := Catalog([]Rule{RuleWK8105()})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteCatalogJSON(&buf, infos))
+
+		var decoded []map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		require.Len(t, decoded, 1)
+		assert.Equal(t, "WK8105", decoded[0]["id"])
+		assert.Equal(t, true, decoded[0]["fixable"])
+		assert.Equal(t, CategoryWorkload, decoded[0]["category"])
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteCatalogMarkdown(&buf, infos))
+		assert.Contains(t, buf.String(), "| Rule | Name |")
+		assert.Contains(t, buf.String(), "| WK8105 |")
+		assert.Contains(t, buf.String(), "| Yes |")
+	})
+}
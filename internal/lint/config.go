@@ -0,0 +1,99 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lex00/wetwire-k8s-go/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileName is the project configuration file that holds lint settings.
+const ConfigFileName = config.FileName
+
+// fileConfig is the lint section of .wetwire.yaml:
+//
+//	lint:
+//	  min_severity: warning   # error, warning or info (default info)
+//	  disabled_rules: [WK8401]
+//	  enabled_rules: [WK8014]
+//	  severity:
+//	    WK8302: error
+//	    WK8201: info
+//	  recommended_labels: [app.kubernetes.io/name, app.kubernetes.io/part-of]
+//	  external_config_refs: [vault-db-credentials]
+type fileConfig struct {
+	Lint struct {
+		MinSeverity        string            `yaml:"min_severity"`
+		Disable            []string          `yaml:"disabled_rules"`
+		Enable             []string          `yaml:"enabled_rules"`
+		Severity           map[string]string `yaml:"severity"`
+		RecommendedLabels  []string          `yaml:"recommended_labels"`
+		ExternalConfigRefs []string          `yaml:"external_config_refs"`
+	} `yaml:"lint"`
+}
+
+// ParseSeverity parses a severity name (error, warning or info).
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "error":
+		return SeverityError, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "info":
+		return SeverityInfo, nil
+	default:
+		return SeverityInfo, fmt.Errorf("unknown severity %q (expected error, warning or info)", s)
+	}
+}
+
+// LoadConfig reads the lint section of a .wetwire.yaml file. Settings that
+// are not present keep their defaults (all rules except optional ones
+// enabled, info and above).
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	var fc fileConfig
+	if err := yaml.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	config := &Config{
+		MinSeverity:        SeverityInfo,
+		DisabledRules:      fc.Lint.Disable,
+		EnabledRules:       fc.Lint.Enable,
+		RecommendedLabels:  fc.Lint.RecommendedLabels,
+		ExternalConfigRefs: fc.Lint.ExternalConfigRefs,
+	}
+
+	if fc.Lint.MinSeverity != "" {
+		config.MinSeverity, err = ParseSeverity(fc.Lint.MinSeverity)
+		if err != nil {
+			return nil, fmt.Errorf("%s: lint.min_severity: %w", path, err)
+		}
+	}
+
+	if len(fc.Lint.Severity) > 0 {
+		config.RuleSeverity = make(map[string]Severity, len(fc.Lint.Severity))
+		for id, value := range fc.Lint.Severity {
+			severity, err := ParseSeverity(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: lint.severity.%s: %w", path, id, err)
+			}
+			config.RuleSeverity[id] = severity
+		}
+	}
+
+	return config, nil
+}
+
+// FindConfigFile looks for .wetwire.yaml in the directory of path (or path
+// itself if it is a directory) and its parents. It returns an empty string
+// if no config file is found.
+func FindConfigFile(path string) string {
+	return config.Find(path)
+}
@@ -0,0 +1,99 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Severity
+	}{
+		{"error", SeverityError},
+		{"Warning", SeverityWarning},
+		{"warn", SeverityWarning},
+		{" info ", SeverityInfo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			severity, err := ParseSeverity(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, severity)
+		})
+	}
+
+	_, err := ParseSeverity("fatal")
+	assert.Error(t, err)
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("should parse the lint section", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ConfigFileName)
+		content := `source: k8s
+lint:
+  min_severity: warning
+  disabled_rules: [WK8401]
+  enabled_rules: [WK8014]
+  severity:
+    WK8302: error
+    WK8201: info
+  recommended_labels: [app.kubernetes.io/name, app.kubernetes.io/part-of]
+  external_config_refs: [vault-db-credentials]
+`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		config, err := LoadConfig(path)
+		require.NoError(t, err)
+		assert.Equal(t, SeverityWarning, config.MinSeverity)
+		assert.Equal(t, []string{"WK8401"}, config.DisabledRules)
+		assert.Equal(t, []string{"WK8014"}, config.EnabledRules)
+		assert.Equal(t, map[string]Severity{
+			"WK8302": SeverityError,
+			"WK8201": SeverityInfo,
+		}, config.RuleSeverity)
+		assert.Equal(t, []string{"app.kubernetes.io/name", "app.kubernetes.io/part-of"}, config.RecommendedLabels)
+		assert.Equal(t, []string{"vault-db-credentials"}, config.ExternalConfigRefs)
+	})
+
+	t.Run("should default to info without a lint section", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ConfigFileName)
+		require.NoError(t, os.WriteFile(path, []byte("source: k8s\n"), 0644))
+
+		config, err := LoadConfig(path)
+		require.NoError(t, err)
+		assert.Equal(t, SeverityInfo, config.MinSeverity)
+		assert.Empty(t, config.RuleSeverity)
+		assert.Nil(t, config.RecommendedLabels)
+	})
+
+	t.Run("should reject unknown severities", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ConfigFileName)
+		content := "lint:\n  severity:\n    WK8302: critical\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		_, err := LoadConfig(path)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "WK8302")
+	})
+}
+
+func TestFindConfigFile(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "k8s", "apps")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+	configPath := filepath.Join(root, ConfigFileName)
+	require.NoError(t, os.WriteFile(configPath, []byte("lint: {}\n"), 0644))
+
+	assert.Equal(t, configPath, FindConfigFile(root))
+	assert.Equal(t, configPath, FindConfigFile(nested))
+
+	file := filepath.Join(nested, "app.go")
+	require.NoError(t, os.WriteFile(file, []byte("package apps\n"), 0644))
+	assert.Equal(t, configPath, FindConfigFile(file))
+}
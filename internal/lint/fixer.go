@@ -4,20 +4,25 @@ import (
 	"bytes"
 	"fmt"
 	"go/ast"
+	"go/format"
 	"go/parser"
 	"go/printer"
 	"go/token"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
 )
 
 // FixResult represents the result of a fix operation.
 type FixResult struct {
-	File         string // File that was fixed
-	Rule         string // Rule that was applied
-	Fixed        bool   // Whether the fix was applied
-	Error        error  // Error if fix failed
-	Description  string // Description of what was fixed
+	File        string // File that was fixed
+	Rule        string // Rule that was applied
+	Fixed       bool   // Whether the fix was applied
+	Error       error  // Error if fix failed
+	Description string // Description of what was fixed
 }
 
 // Fixer handles auto-fix operations for lint issues.
@@ -35,42 +40,117 @@ func NewFixer(config *Config) *Fixer {
 	return &Fixer{config: config}
 }
 
+// FileDiff is a unified diff of the fixes proposed for a file.
+type FileDiff struct {
+	File string // File the fixes apply to
+	Diff string // Unified diff from the original to the fixed source
+}
+
 // FixFile attempts to fix all fixable issues in a file.
 // Returns the list of fixes that were applied.
 func (f *Fixer) FixFile(filePath string) ([]FixResult, error) {
+	results, _, fixed, err := f.fixSource(filePath)
+	if err != nil {
+		return results, err
+	}
+
+	// Write the modified file if any fixes were made
+	if fixed != nil {
+		if err := os.WriteFile(filePath, fixed, 0644); err != nil {
+			return results, fmt.Errorf("failed to write file %s: %w", filePath, err)
+		}
+	}
+
+	return results, nil
+}
+
+// DiffFile returns the fixes that FixFile would apply to a file and a
+// unified diff of the resulting changes, without writing the file. The diff
+// is nil when there is nothing to fix.
+func (f *Fixer) DiffFile(filePath string) ([]FixResult, *FileDiff, error) {
+	results, original, fixed, err := f.fixSource(filePath)
+	if err != nil || fixed == nil || bytes.Equal(original, fixed) {
+		return results, nil, err
+	}
+
+	diff, err := UnifiedDiff(filePath, original, fixed)
+	if err != nil {
+		return results, nil, err
+	}
+
+	return results, diff, nil
+}
+
+// UnifiedDiff returns a unified diff from the original to the changed
+// source of filePath, in the form used for fix previews.
+func UnifiedDiff(filePath string, original, changed []byte) (*FileDiff, error) {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(original),
+		B:        splitLines(changed),
+		FromFile: filePath + ".orig",
+		ToFile:   filePath,
+		Context:  3,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff file %s: %w", filePath, err)
+	}
+	return &FileDiff{File: filePath, Diff: diff}, nil
+}
+
+// splitLines splits source into lines, keeping their line endings.
+func splitLines(src []byte) []string {
+	lines := strings.SplitAfter(string(src), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// fixSource applies all enabled fixes to a file's source. It returns the
+// original source and the fixed, formatted source, which is nil when no
+// fixes were made.
+func (f *Fixer) fixSource(filePath string) ([]FixResult, []byte, []byte, error) {
 	var results []FixResult
 
 	// Read the original file
 	content, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+		return nil, nil, nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
 	}
 
 	// Parse the file
 	fset := token.NewFileSet()
 	file, err := parser.ParseFile(fset, filePath, content, parser.ParseComments)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
+		return nil, nil, nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
 	}
 
 	// Track if any fixes were made
 	modified := false
 
-	// Apply WK8105 fixes (ImagePullPolicy)
-	fixResults, changed := f.fixWK8105(file, fset, filePath)
-	results = append(results, fixResults...)
-	if changed {
-		modified = true
+	// Apply AST fixes in order. WK8011 runs before WK8007 so renamed
+	// resources keep their references.
+	astFixes := []struct {
+		rule string
+		fix  func(*ast.File, *token.FileSet, string) ([]FixResult, bool)
+	}{
+		{"WK8105", f.fixWK8105}, // ImagePullPolicy
+		{"WK8002", f.fixWK8002}, // Deeply nested structures
+		{"WK8011", f.fixWK8011}, // String references to resources in the same file
+		{"WK8007", f.fixWK8007}, // Metadata name consistent with variable name
 	}
-
-	// Apply WK8002 fixes (deeply nested structures)
-	fixResults, changed = f.fixWK8002(file, fset, filePath)
-	results = append(results, fixResults...)
-	if changed {
-		modified = true
+	for _, astFix := range astFixes {
+		if isRuleDisabled(astFix.rule, f.config.DisabledRules) {
+			continue
+		}
+		fixResults, changed := astFix.fix(file, fset, filePath)
+		results = append(results, fixResults...)
+		if changed {
+			modified = true
+		}
 	}
 
-	// Write the modified file if any fixes were made
+	output := content
 	if modified {
 		var buf bytes.Buffer
 		cfg := printer.Config{
@@ -78,15 +158,48 @@ func (f *Fixer) FixFile(filePath string) ([]FixResult, error) {
 			Tabwidth: 8,
 		}
 		if err := cfg.Fprint(&buf, fset, file); err != nil {
-			return results, fmt.Errorf("failed to format file %s: %w", filePath, err)
+			return results, content, nil, fmt.Errorf("failed to format file %s: %w", filePath, err)
 		}
+		output = buf.Bytes()
+	}
 
-		if err := os.WriteFile(filePath, buf.Bytes(), 0644); err != nil {
-			return results, fmt.Errorf("failed to write file %s: %w", filePath, err)
+	// Apply source fixes, which insert multi-line blocks and so edit the
+	// printed source rather than the AST
+	sourceFixes := []struct {
+		rule string
+		fix  func([]byte, string) ([]FixResult, []byte, error)
+	}{
+		{"WK8102", f.fixWK8102}, // Missing labels
+		{"WK8013", f.fixWK8013}, // Recommended labels
+		{"WK8201", f.fixWK8201}, // Missing resource limits
+		{"WK8015", f.fixWK8015}, // EmptyDir size limit
+	}
+	for _, sourceFix := range sourceFixes {
+		if isRuleDisabled(sourceFix.rule, f.config.DisabledRules) {
+			continue
+		}
+		fixResults, edited, err := sourceFix.fix(output, filePath)
+		if err != nil {
+			return results, content, nil, err
+		}
+		if len(fixResults) > 0 {
+			results = append(results, fixResults...)
+			output = edited
+			modified = true
 		}
 	}
 
-	return results, nil
+	if !modified {
+		return results, content, nil, nil
+	}
+
+	// Reformat so inserted code is indented and imports are sorted
+	formatted, err := format.Source(output)
+	if err != nil {
+		return results, content, nil, fmt.Errorf("failed to format file %s: %w", filePath, err)
+	}
+
+	return results, content, formatted, nil
 }
 
 // fixWK8105 fixes missing ImagePullPolicy on containers.
@@ -192,6 +305,73 @@ func determineImagePullPolicy(image string) string {
 	return "IfNotPresent"
 }
 
+// fixWK8007 renames metadata names that diverge from their variable name to
+// the name derived from the variable (e.g. WebAppDeployment -> "web-app").
+func (f *Fixer) fixWK8007(file *ast.File, fset *token.FileSet, filePath string) ([]FixResult, bool) {
+	var results []FixResult
+	modified := false
+
+	forEachMetadataName(file, func(varName, kind string, lit *ast.BasicLit) {
+		name, err := strconv.Unquote(lit.Value)
+		if err != nil || nameMatchesVar(name, varName, kind) {
+			return
+		}
+
+		expected := expectedResourceName(varName, kind)
+		lit.Value = strconv.Quote(expected)
+		modified = true
+
+		pos := fset.Position(lit.Pos())
+		results = append(results, FixResult{
+			File:        filePath,
+			Rule:        "WK8007",
+			Fixed:       true,
+			Description: fmt.Sprintf("Renamed metadata name %q to %q for %s at line %d", name, expected, varName, pos.Line),
+		})
+	})
+
+	return results, modified
+}
+
+// fixWK8011 replaces hardcoded names of resources declared in the same file
+// with a reference to the resource's Name (e.g. "app-config" -> AppConfig.Name).
+func (f *Fixer) fixWK8011(file *ast.File, fset *token.FileSet, filePath string) ([]FixResult, bool) {
+	var results []FixResult
+
+	refs := findStringReferences(file)
+	if len(refs) == 0 {
+		return nil, false
+	}
+
+	// Map each literal to its replacement, then swap them in their parents
+	replacements := make(map[ast.Expr]ast.Expr, len(refs))
+	for _, ref := range refs {
+		replacements[ref.lit] = &ast.SelectorExpr{
+			X:   &ast.Ident{Name: ref.target, NamePos: ref.lit.Pos()},
+			Sel: ast.NewIdent("Name"),
+		}
+
+		pos := fset.Position(ref.lit.Pos())
+		results = append(results, FixResult{
+			File:        filePath,
+			Rule:        "WK8011",
+			Fixed:       true,
+			Description: fmt.Sprintf("Replaced %s %s with %s.Name at line %d", ref.field, ref.lit.Value, ref.target, pos.Line),
+		})
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if kv, ok := n.(*ast.KeyValueExpr); ok {
+			if replacement, ok := replacements[kv.Value]; ok {
+				kv.Value = replacement
+			}
+		}
+		return true
+	})
+
+	return results, true
+}
+
 // fixWK8002 fixes deeply nested structures by extracting them to variables.
 // This is a more complex fix that extracts nested composite literals.
 func (f *Fixer) fixWK8002(file *ast.File, fset *token.FileSet, filePath string) ([]FixResult, bool) {
@@ -289,6 +469,9 @@ func (f *Fixer) extractNestedStructures(expr ast.Expr, parentName string, fset *
 	extractRecursive = func(e ast.Expr, depth int, fieldName string) ast.Expr {
 		switch node := e.(type) {
 		case *ast.CompositeLit:
+			// Extracted elements need the type elided in the parent literal
+			setElidedTypes(node)
+
 			// Process children first
 			for i, elt := range node.Elts {
 				switch elem := elt.(type) {
@@ -343,49 +526,117 @@ func (f *Fixer) extractNestedStructures(expr ast.Expr, parentName string, fset *
 	return extracted, result
 }
 
+// setElidedTypes sets the type of slice elements and map values whose type
+// is elided in a composite literal, e.g. the {...} in []corev1.EnvVar{{...}}.
+func setElidedTypes(lit *ast.CompositeLit) {
+	var elemType ast.Expr
+	switch t := lit.Type.(type) {
+	case *ast.ArrayType:
+		elemType = t.Elt
+	case *ast.MapType:
+		elemType = t.Value
+	default:
+		return
+	}
+
+	// Elided pointer types stand for &T{...}
+	typed := func(e ast.Expr) ast.Expr {
+		elemLit, ok := e.(*ast.CompositeLit)
+		if !ok || elemLit.Type != nil {
+			return e
+		}
+		if star, ok := elemType.(*ast.StarExpr); ok {
+			elemLit.Type = star.X
+			return &ast.UnaryExpr{Op: token.AND, X: elemLit}
+		}
+		elemLit.Type = elemType
+		return elemLit
+	}
+
+	for i, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			kv.Value = typed(kv.Value)
+			continue
+		}
+		lit.Elts[i] = typed(elt)
+	}
+}
+
 // FixDirectory attempts to fix all fixable issues in all Go files in a directory.
 func (f *Fixer) FixDirectory(dir string) ([]FixResult, error) {
 	var allResults []FixResult
 
-	// Get list of Go files
-	linter := NewLinter(f.config)
-	result, err := linter.LintWithResult(dir)
+	files, err := f.fixableFiles(dir)
 	if err != nil {
 		return nil, err
 	}
 
-	// Group issues by file
-	fileIssues := make(map[string][]Issue)
-	for _, issue := range result.Issues {
-		fileIssues[issue.File] = append(fileIssues[issue.File], issue)
+	for _, filePath := range files {
+		results, err := f.FixFile(filePath)
+		if err != nil {
+			allResults = append(allResults, FixResult{
+				File:  filePath,
+				Fixed: false,
+				Error: err,
+			})
+			continue
+		}
+		allResults = append(allResults, results...)
 	}
 
-	// Fix each file that has fixable issues
-	for filePath, issues := range fileIssues {
-		// Check if file has fixable issues
-		hasFixable := false
-		for _, issue := range issues {
-			if isFixableRule(issue.Rule) {
-				hasFixable = true
-				break
-			}
+	return allResults, nil
+}
+
+// DiffDirectory returns the fixes that FixDirectory would apply and a
+// unified diff for every file that would change, without writing any files.
+func (f *Fixer) DiffDirectory(dir string) ([]FixResult, []FileDiff, error) {
+	var allResults []FixResult
+	var diffs []FileDiff
+
+	files, err := f.fixableFiles(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, filePath := range files {
+		results, diff, err := f.DiffFile(filePath)
+		if err != nil {
+			allResults = append(allResults, FixResult{
+				File:  filePath,
+				Fixed: false,
+				Error: err,
+			})
+			continue
+		}
+		allResults = append(allResults, results...)
+		if diff != nil {
+			diffs = append(diffs, *diff)
 		}
+	}
 
-		if hasFixable {
-			results, err := f.FixFile(filePath)
-			if err != nil {
-				allResults = append(allResults, FixResult{
-					File:  filePath,
-					Fixed: false,
-					Error: err,
-				})
-				continue
-			}
-			allResults = append(allResults, results...)
+	return allResults, diffs, nil
+}
+
+// fixableFiles lints dir and returns the files with fixable issues in
+// lexical order.
+func (f *Fixer) fixableFiles(dir string) ([]string, error) {
+	linter := NewLinter(f.config)
+	result, err := linter.LintWithResult(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, issue := range result.Issues {
+		if isFixableRule(issue.Rule) && !seen[issue.File] {
+			seen[issue.File] = true
+			files = append(files, issue.File)
 		}
 	}
+	sort.Strings(files)
 
-	return allResults, nil
+	return files, nil
 }
 
 // isFixableRule returns true if the rule supports auto-fix.
@@ -393,6 +644,12 @@ func isFixableRule(ruleID string) bool {
 	fixableRules := map[string]bool{
 		"WK8105": true, // ImagePullPolicy
 		"WK8002": true, // Deeply nested structures
+		"WK8007": true, // Metadata name consistent with variable name
+		"WK8011": true, // String references to resources in the same file
+		"WK8013": true, // Recommended labels
+		"WK8102": true, // Missing labels
+		"WK8201": true, // Missing resource limits
+		"WK8015": true, // EmptyDir size limit
 		// WK8006 is NOT fixable - it just warns about :latest, user must choose version
 	}
 	return fixableRules[ruleID]
@@ -400,5 +657,5 @@ func isFixableRule(ruleID string) bool {
 
 // FixableRules returns a list of rule IDs that support auto-fix.
 func FixableRules() []string {
-	return []string{"WK8002", "WK8105"}
+	return []string{"WK8002", "WK8007", "WK8011", "WK8013", "WK8102", "WK8105", "WK8201", "WK8015"}
 }
@@ -0,0 +1,411 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Source fixes edit the source text instead of the AST, so that inserted
+// blocks can span several lines. FixFile formats the result afterwards, so
+// inserted text only needs to be syntactically correct.
+
+// Default limits inserted by the WK8201 fix. They are deliberately
+// conservative; users are expected to tune them per workload.
+const (
+	defaultCPULimit    = "500m"
+	defaultMemoryLimit = "512Mi"
+)
+
+// defaultEmptyDirSizeLimit is the SizeLimit inserted by the WK8015 fix.
+const defaultEmptyDirSizeLimit = "1Gi"
+
+// Import paths used by the WK8201 fix.
+const (
+	coreV1ImportPath   = "k8s.io/api/core/v1"
+	resourceImportPath = "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// fixWK8201 adds default resource limits to containers without limits and
+// returns the edited source. Existing Requests are kept. Containers whose
+// Resources or Limits is not a composite literal (e.g. a shared variable) are
+// left alone. The edited source is not formatted.
+func (f *Fixer) fixWK8201(src []byte, filePath string) ([]FixResult, []byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
+	}
+
+	corev1 := importName(file, coreV1ImportPath)
+	if corev1 == "" {
+		return nil, src, nil
+	}
+	resourcePkg := importName(file, resourceImportPath)
+	if resourcePkg == "" {
+		resourcePkg = "resource"
+	}
+
+	entries := fmt.Sprintf("\n%[1]s.ResourceCPU: %[2]s.MustParse(%[3]q),\n%[1]s.ResourceMemory: %[2]s.MustParse(%[4]q),\n",
+		corev1, resourcePkg, defaultCPULimit, defaultMemoryLimit)
+	limits := corev1 + ".ResourceList{" + entries + "}"
+
+	var results []FixResult
+	var edits []textEdit
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		compLit, ok := n.(*ast.CompositeLit)
+		if !ok || !isContainerType(compLit) {
+			return true
+		}
+
+		resources := getFieldValue(compLit, "Resources")
+		resourcesLit := unwrapCompositeLit(resources)
+		switch {
+		case resources == nil:
+			edits = append(edits, appendFieldEdit(fset, compLit,
+				"Resources: "+corev1+".ResourceRequirements{\nLimits: "+limits+",\n}"))
+		case resourcesLit == nil:
+			return true
+		default:
+			existing := getFieldValue(resourcesLit, "Limits")
+			if existing == nil {
+				edits = append(edits, appendFieldEdit(fset, resourcesLit, "Limits: "+limits))
+				break
+			}
+
+			// Fill in an empty Limits literal
+			limitsLit := unwrapCompositeLit(existing)
+			if limitsLit == nil || len(limitsLit.Elts) > 0 {
+				return true
+			}
+			offset := fset.Position(limitsLit.Lbrace).Offset + 1
+			edits = append(edits, insertEdit(offset, entries))
+		}
+
+		pos := fset.Position(compLit.Pos())
+		results = append(results, FixResult{
+			File:        filePath,
+			Rule:        "WK8201",
+			Fixed:       true,
+			Description: fmt.Sprintf("Added resource limits (cpu: %s, memory: %s) at line %d", defaultCPULimit, defaultMemoryLimit, pos.Line),
+		})
+		return true
+	})
+
+	if len(results) == 0 {
+		return nil, src, nil
+	}
+	if importName(file, resourceImportPath) == "" {
+		edits = append(edits, importEdit(src, fset, file, resourceImportPath))
+	}
+	return results, applyEdits(src, edits), nil
+}
+
+// nameLabel is the label added by the WK8102 fix.
+const nameLabel = "app.kubernetes.io/name"
+
+// fixWK8102 labels resources that have no labels with nameLabel, set to
+// their metadata.Name, and returns the edited source. An existing empty
+// Labels map is filled in rather than replaced. Resources without a Name,
+// or with Labels set from a variable, are left alone.
+func (f *Fixer) fixWK8102(src []byte, filePath string) ([]FixResult, []byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
+	}
+
+	var results []FixResult
+	var edits []textEdit
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		compLit, ok := n.(*ast.CompositeLit)
+		if !ok || !labeledResourceTypes[getResourceType(compLit)] {
+			return true
+		}
+
+		metaLit := metadataLiteral(compLit)
+		if metaLit == nil {
+			return true
+		}
+		name := getFieldValue(metaLit, "Name")
+		if name == nil {
+			return true
+		}
+
+		// Use the Name expression as written, which may be a constant
+		nameSrc := string(src[fset.Position(name.Pos()).Offset:fset.Position(name.End()).Offset])
+		entry := fmt.Sprintf("%q: %s", nameLabel, nameSrc)
+
+		switch labels := getFieldValue(metaLit, "Labels").(type) {
+		case nil:
+			edits = append(edits, appendFieldEdit(fset, metaLit, "Labels: map[string]string{"+entry+"}"))
+		case *ast.Ident:
+			if labels.Name != "nil" {
+				return true
+			}
+			edits = append(edits, textEdit{
+				start: fset.Position(labels.Pos()).Offset,
+				end:   fset.Position(labels.End()).Offset,
+				text:  "map[string]string{" + entry + "}",
+			})
+		case *ast.CompositeLit:
+			if len(labels.Elts) > 0 {
+				return true
+			}
+			edits = append(edits, insertEdit(fset.Position(labels.Lbrace).Offset+1, entry))
+		default:
+			return true
+		}
+
+		pos := fset.Position(compLit.Pos())
+		results = append(results, FixResult{
+			File:        filePath,
+			Rule:        "WK8102",
+			Fixed:       true,
+			Description: fmt.Sprintf("Added label %s: %s at line %d", nameLabel, nameSrc, pos.Line),
+		})
+		return true
+	})
+
+	if len(results) == 0 {
+		return nil, src, nil
+	}
+	return results, applyEdits(src, edits), nil
+}
+
+// Label and value added by the WK8013 fix.
+const (
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "wetwire-k8s"
+)
+
+// fixWK8013 adds the managedByLabel to resources that lack it and returns
+// the edited source. Labels set from a variable get the label added to the
+// variable's map literal, once for all resources sharing it. It does nothing
+// when the configured recommended labels do not include managedByLabel.
+func (f *Fixer) fixWK8013(src []byte, filePath string) ([]FixResult, []byte, error) {
+	if !containsString(f.config.recommendedLabels(), managedByLabel) {
+		return nil, src, nil
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
+	}
+
+	values := make(map[string]ast.Expr)
+	forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+		values[varName] = value
+	})
+
+	entry := fmt.Sprintf("%q: %q", managedByLabel, managedByValue)
+	edited := make(map[*ast.CompositeLit]bool)
+
+	var results []FixResult
+	var edits []textEdit
+
+	forEachLabeledResource(file, values, func(varName string, compLit *ast.CompositeLit, labels map[string]bool) {
+		if labels[managedByLabel] {
+			return
+		}
+
+		metaLit := metadataLiteral(compLit)
+		switch expr := getFieldValue(metaLit, "Labels"); {
+		case expr == nil:
+			edits = append(edits, appendFieldEdit(fset, metaLit, "Labels: map[string]string{"+entry+"}"))
+		case isNilIdent(expr):
+			edits = append(edits, textEdit{
+				start: fset.Position(expr.Pos()).Offset,
+				end:   fset.Position(expr.End()).Offset,
+				text:  "map[string]string{" + entry + "}",
+			})
+		default:
+			labelsLit := resolveCompositeLit(expr, values)
+			if edited[labelsLit] {
+				return
+			}
+			edited[labelsLit] = true
+			edits = append(edits, appendFieldEdit(fset, labelsLit, entry))
+		}
+
+		pos := fset.Position(compLit.Pos())
+		results = append(results, FixResult{
+			File:        filePath,
+			Rule:        "WK8013",
+			Fixed:       true,
+			Description: fmt.Sprintf("Added label %s: %s for %s at line %d", managedByLabel, managedByValue, varName, pos.Line),
+		})
+	})
+
+	if len(results) == 0 {
+		return nil, src, nil
+	}
+	return results, applyEdits(src, edits), nil
+}
+
+// ptrHelper is the pointer helper the WK8015 fix adds to files whose
+// package does not declare one, as in the examples.
+const ptrHelper = `
+// Helper function for pointer values
+func ptr[T any](v T) *T { return &v }
+`
+
+// fixWK8015 sets defaultEmptyDirSizeLimit on emptyDir volumes without a
+// SizeLimit and returns the edited source. The quantity is wrapped in the
+// package's generic ptr helper, which is added to the file if the package
+// has none. Files whose package declares a ptr function that is not generic
+// are left alone.
+func (f *Fixer) fixWK8015(src []byte, filePath string) ([]FixResult, []byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
+	}
+
+	emptyDirs := unboundedEmptyDirs(file)
+	if len(emptyDirs) == 0 {
+		return nil, src, nil
+	}
+	hasPtr, generic := findPtrHelper(parsePackageFiles(fset, filePath, file))
+	if hasPtr && !generic {
+		return nil, src, nil
+	}
+
+	resourcePkg := importName(file, resourceImportPath)
+	if resourcePkg == "" {
+		resourcePkg = "resource"
+	}
+	field := fmt.Sprintf("SizeLimit: ptr(%s.MustParse(%q))", resourcePkg, defaultEmptyDirSizeLimit)
+
+	var results []FixResult
+	var edits []textEdit
+	for _, emptyDir := range emptyDirs {
+		edits = append(edits, appendFieldEdit(fset, emptyDir.lit, field))
+
+		pos := fset.Position(emptyDir.lit.Pos())
+		results = append(results, FixResult{
+			File:        filePath,
+			Rule:        "WK8015",
+			Fixed:       true,
+			Description: fmt.Sprintf("Added emptyDir size limit %s at line %d", defaultEmptyDirSizeLimit, pos.Line),
+		})
+	}
+
+	if importName(file, resourceImportPath) == "" {
+		edits = append(edits, importEdit(src, fset, file, resourceImportPath))
+	}
+	if !hasPtr {
+		edits = append(edits, insertEdit(len(src), ptrHelper))
+	}
+	return results, applyEdits(src, edits), nil
+}
+
+// findPtrHelper reports whether files declare a package-level ptr function,
+// and whether it is generic.
+func findPtrHelper(files []*ast.File) (found, generic bool) {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if ok && funcDecl.Recv == nil && funcDecl.Name.Name == "ptr" {
+				return true, funcDecl.Type.TypeParams != nil
+			}
+		}
+	}
+	return false, false
+}
+
+// isNilIdent reports whether expr is the identifier nil.
+func isNilIdent(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "nil"
+}
+
+// textEdit replaces the bytes in [start, end) of a source file with text.
+type textEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+// insertEdit returns an edit inserting text at a byte offset.
+func insertEdit(offset int, text string) textEdit {
+	return textEdit{start: offset, end: offset, text: text}
+}
+
+// applyEdits applies non-overlapping edits to src.
+func applyEdits(src []byte, edits []textEdit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start > edits[j].start
+	})
+
+	out := append([]byte(nil), src...)
+	for _, edit := range edits {
+		out = append(out[:edit.start], append([]byte(edit.text), out[edit.end:]...)...)
+	}
+	return out
+}
+
+// appendFieldEdit returns an edit adding a field after the last element of a
+// composite literal. The field goes on its own line when the closing brace
+// is on its own line.
+func appendFieldEdit(fset *token.FileSet, lit *ast.CompositeLit, field string) textEdit {
+	rbrace := fset.Position(lit.Rbrace)
+	if len(lit.Elts) == 0 {
+		return insertEdit(rbrace.Offset, field)
+	}
+
+	last := fset.Position(lit.Elts[len(lit.Elts)-1].End())
+	if last.Line == rbrace.Line {
+		return insertEdit(rbrace.Offset, ", "+field)
+	}
+	return insertEdit(rbrace.Offset, field+",\n")
+}
+
+// importEdit returns an edit adding an import of path to the file's last
+// import declaration.
+func importEdit(src []byte, fset *token.FileSet, file *ast.File, path string) textEdit {
+	spec := strconv.Quote(path)
+
+	var last *ast.GenDecl
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			last = genDecl
+		}
+	}
+
+	switch {
+	case last == nil:
+		return insertEdit(fset.Position(file.Name.End()).Offset, "\n\nimport "+spec)
+	case last.Lparen.IsValid():
+		// Keep the new import in the last group rather than starting a new one
+		offset := fset.Position(last.Rparen).Offset
+		if offset > 0 && src[offset-1] == '\n' {
+			return insertEdit(offset, spec+"\n")
+		}
+		return insertEdit(offset, "\n"+spec+"\n")
+	default:
+		return insertEdit(fset.Position(last.End()).Offset, "\nimport "+spec)
+	}
+}
+
+// importName returns the name a file uses for an import path, or "" if the
+// path is not imported. Unnamed imports use the last path element.
+func importName(file *ast.File, path string) string {
+	for _, imp := range file.Imports {
+		if importPath, err := strconv.Unquote(imp.Path.Value); err != nil || importPath != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return path[strings.LastIndex(path, "/")+1:]
+	}
+	return ""
+}
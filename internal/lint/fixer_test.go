@@ -1,6 +1,9 @@
 package lint
 
 import (
+	"go/format"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
 	"strings"
@@ -64,6 +67,12 @@ func TestIsFixableRule(t *testing.T) {
 	}{
 		{"WK8105", true},  // ImagePullPolicy - fixable
 		{"WK8002", true},  // Deeply nested - fixable
+		{"WK8007", true},  // Metadata name - fixable
+		{"WK8011", true},  // String references - fixable
+		{"WK8013", true},  // Recommended labels - fixable
+		{"WK8102", true},  // Missing labels - fixable
+		{"WK8201", true},  // Resource limits - fixable
+		{"WK8015", true},  // EmptyDir size limit - fixable
 		{"WK8001", false}, // Top-level declarations - not fixable
 		{"WK8003", false}, // Duplicate names - not fixable
 		{"WK8006", false}, // :latest tags - not fixable (user must choose version)
@@ -81,6 +90,12 @@ func TestFixableRules(t *testing.T) {
 	rules := FixableRules()
 	assert.Contains(t, rules, "WK8002")
 	assert.Contains(t, rules, "WK8105")
+	assert.Contains(t, rules, "WK8007")
+	assert.Contains(t, rules, "WK8011")
+	assert.Contains(t, rules, "WK8013")
+	assert.Contains(t, rules, "WK8102")
+	assert.Contains(t, rules, "WK8201")
+	assert.Contains(t, rules, "WK8015")
 	assert.NotContains(t, rules, "WK8006") // :latest is not fixable
 }
 
@@ -114,7 +129,7 @@ var ContainerNoTag = corev1.Container{
 	require.NoError(t, err)
 
 	// Apply fixes
-	fixer := NewFixer(nil)
+	fixer := NewFixer(&Config{DisabledRules: []string{"WK8201"}})
 	results, err := fixer.FixFile(testFile)
 	require.NoError(t, err)
 
@@ -135,7 +150,7 @@ var ContainerNoTag = corev1.Container{
 
 	// Verify correct policies
 	assert.Contains(t, fixedContent, `"IfNotPresent"`) // For nginx:1.21
-	assert.Contains(t, fixedContent, `"Always"`)        // For nginx:latest and nginx
+	assert.Contains(t, fixedContent, `"Always"`)       // For nginx:latest and nginx
 }
 
 func TestFixer_FixFile_WK8105_AlreadySet(t *testing.T) {
@@ -159,7 +174,7 @@ var ContainerWithPolicy = corev1.Container{
 	require.NoError(t, err)
 
 	// Apply fixes
-	fixer := NewFixer(nil)
+	fixer := NewFixer(&Config{DisabledRules: []string{"WK8201"}})
 	results, err := fixer.FixFile(testFile)
 	require.NoError(t, err)
 
@@ -229,6 +244,383 @@ var DeeplyNestedDeployment = appsv1.Deployment{
 	}
 }
 
+func TestFixer_FixFile_WK8007(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "test_wk8007.go")
+
+	content := `package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const apiName = "api-server"
+
+var WebAppDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "api-server"},
+}
+
+var ApiDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-app"},
+}
+
+var WorkerDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: apiName},
+}
+`
+	err := os.WriteFile(testFile, []byte(content), 0644)
+	require.NoError(t, err)
+
+	fixer := fixerOnly("WK8007")
+	results, err := fixer.FixFile(testFile)
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+	for _, r := range results {
+		assert.Equal(t, "WK8007", r.Rule)
+		assert.True(t, r.Fixed)
+	}
+
+	fixed, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	fixedContent := string(fixed)
+	assert.Contains(t, fixedContent, `Name: "web-app"}`)
+	assert.Contains(t, fixedContent, `Name: "api"}`)
+	// Names set from constants are left alone
+	assert.Contains(t, fixedContent, `Name: apiName}`)
+}
+
+func TestFixer_FixFile_WK8011(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "test_wk8011.go")
+
+	content := `package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var AppConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+}
+
+var ConfigVolume = corev1.Volume{
+	Name: "config",
+	VolumeSource: corev1.VolumeSource{
+		ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"},
+		},
+	},
+}
+`
+	err := os.WriteFile(testFile, []byte(content), 0644)
+	require.NoError(t, err)
+
+	fixer := fixerOnly("WK8011")
+	results, err := fixer.FixFile(testFile)
+	require.NoError(t, err)
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "WK8011", results[0].Rule)
+	assert.True(t, results[0].Fixed)
+
+	fixed, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	fixedContent := string(fixed)
+	assert.Contains(t, fixedContent, "corev1.LocalObjectReference{Name: AppConfig.Name}")
+	// The referenced resource keeps its literal name
+	assert.Contains(t, fixedContent, `ObjectMeta: metav1.ObjectMeta{Name: "app-config"}`)
+}
+
+// fixerOnly returns a fixer with every fixable rule except id disabled.
+func fixerOnly(id string) *Fixer {
+	var disabled []string
+	for _, rule := range FixableRules() {
+		if rule != id {
+			disabled = append(disabled, rule)
+		}
+	}
+	return NewFixer(&Config{MinSeverity: SeverityInfo, DisabledRules: disabled})
+}
+
+func TestFixer_FixFile_WK8201(t *testing.T) {
+	t.Run("should match the expected output", func(t *testing.T) {
+		before, err := os.ReadFile(filepath.Join("testdata", "fix", "wk8201_before.go"))
+		require.NoError(t, err)
+		after, err := os.ReadFile(filepath.Join("testdata", "fix", "wk8201_after.go"))
+		require.NoError(t, err)
+
+		testFile := filepath.Join(t.TempDir(), "wk8201.go")
+		require.NoError(t, os.WriteFile(testFile, before, 0644))
+
+		results, err := fixerOnly("WK8201").FixFile(testFile)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		for _, r := range results {
+			assert.Equal(t, "WK8201", r.Rule)
+			assert.True(t, r.Fixed)
+		}
+
+		fixed, err := os.ReadFile(testFile)
+		require.NoError(t, err)
+		assert.Equal(t, string(after), string(fixed))
+
+		// The fixed file no longer has WK8201 issues, except for shared resources
+		issues, err := NewLinter(nil).LintFile(testFile)
+		require.NoError(t, err)
+		var remaining int
+		for _, issue := range issues {
+			if issue.Rule == "WK8201" {
+				remaining++
+			}
+		}
+		assert.Equal(t, 1, remaining)
+	})
+
+	t.Run("should add the resource import", func(t *testing.T) {
+		testFile := filepath.Join(t.TempDir(), "wk8201.go")
+		content := `package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var Meta = metav1.ObjectMeta{Name: "app"}
+
+var Container = corev1.Container{Name: "app", Image: "nginx:1.21"}
+`
+		require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+		results, err := fixerOnly("WK8201").FixFile(testFile)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		fixed, err := os.ReadFile(testFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(fixed), `corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`)
+		assert.Contains(t, string(fixed), `corev1.ResourceMemory: resource.MustParse("512Mi"),`)
+
+		formatted, err := format.Source(fixed)
+		require.NoError(t, err)
+		assert.Equal(t, string(formatted), string(fixed), "fixed file should be gofmt-clean")
+	})
+
+	t.Run("should skip files without core/v1", func(t *testing.T) {
+		testFile := filepath.Join(t.TempDir(), "wk8201.go")
+		content := `package testdata
+
+var Name = "app"
+`
+		require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+		results, err := fixerOnly("WK8201").FixFile(testFile)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+func TestFixer_FixFile_WK8015(t *testing.T) {
+	t.Run("should match the expected output", func(t *testing.T) {
+		before, err := os.ReadFile(filepath.Join("testdata", "fix", "wk8015_before.go"))
+		require.NoError(t, err)
+		after, err := os.ReadFile(filepath.Join("testdata", "fix", "wk8015_after.go"))
+		require.NoError(t, err)
+
+		testFile := filepath.Join(t.TempDir(), "wk8015.go")
+		require.NoError(t, os.WriteFile(testFile, before, 0644))
+
+		results, err := fixerOnly("WK8015").FixFile(testFile)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		for _, r := range results {
+			assert.Equal(t, "WK8015", r.Rule)
+			assert.True(t, r.Fixed)
+		}
+
+		fixed, err := os.ReadFile(testFile)
+		require.NoError(t, err)
+		assert.Equal(t, string(after), string(fixed))
+	})
+
+	t.Run("should reuse a generic ptr helper from the package", func(t *testing.T) {
+		dir := t.TempDir()
+		helper := "package testdata\n\nfunc ptr[T any](v T) *T { return &v }\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "helpers.go"), []byte(helper), 0644))
+		testFile := filepath.Join(dir, "wk8015.go")
+		content := `package testdata
+
+import corev1 "k8s.io/api/core/v1"
+
+var Pod = corev1.PodSpec{
+	Volumes: []corev1.Volume{{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+}
+`
+		require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+		results, err := fixerOnly("WK8015").FixFile(testFile)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		fixed, err := os.ReadFile(testFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(fixed), `SizeLimit: ptr(resource.MustParse("1Gi"))`)
+		assert.NotContains(t, string(fixed), "func ptr")
+	})
+
+	t.Run("should skip packages with a non-generic ptr", func(t *testing.T) {
+		testFile := filepath.Join(t.TempDir(), "wk8015.go")
+		content := `package testdata
+
+import corev1 "k8s.io/api/core/v1"
+
+func ptr(v string) *string { return &v }
+
+var Pod = corev1.PodSpec{
+	Volumes: []corev1.Volume{{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+}
+`
+		require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+		results, err := fixerOnly("WK8015").FixFile(testFile)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+func TestFixer_FixFile_WK8102(t *testing.T) {
+	before, err := os.ReadFile(filepath.Join("testdata", "fix", "wk8102_before.go"))
+	require.NoError(t, err)
+	after, err := os.ReadFile(filepath.Join("testdata", "fix", "wk8102_after.go"))
+	require.NoError(t, err)
+
+	testFile := filepath.Join(t.TempDir(), "wk8102.go")
+	require.NoError(t, os.WriteFile(testFile, before, 0644))
+
+	results, err := fixerOnly("WK8102").FixFile(testFile)
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	for _, r := range results {
+		assert.Equal(t, "WK8102", r.Rule)
+		assert.True(t, r.Fixed)
+	}
+
+	fixed, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	assert.Equal(t, string(after), string(fixed))
+
+	formatted, err := format.Source(fixed)
+	require.NoError(t, err)
+	assert.Equal(t, string(formatted), string(fixed), "fixed file should be gofmt-clean")
+}
+
+func TestFixer_FixFile_WK8013(t *testing.T) {
+	t.Run("should match the expected output", func(t *testing.T) {
+		before, err := os.ReadFile(filepath.Join("testdata", "fix", "wk8013_before.go"))
+		require.NoError(t, err)
+		after, err := os.ReadFile(filepath.Join("testdata", "fix", "wk8013_after.go"))
+		require.NoError(t, err)
+
+		testFile := filepath.Join(t.TempDir(), "wk8013.go")
+		require.NoError(t, os.WriteFile(testFile, before, 0644))
+
+		results, err := fixerOnly("WK8013").FixFile(testFile)
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+		for _, r := range results {
+			assert.Equal(t, "WK8013", r.Rule)
+			assert.True(t, r.Fixed)
+		}
+
+		fixed, err := os.ReadFile(testFile)
+		require.NoError(t, err)
+		assert.Equal(t, string(after), string(fixed))
+
+		// Only the name labels, which the fix cannot choose, are still missing
+		fset, file := parseTestFile(t, testFile)
+		for _, issue := range RuleWK8013().Check(file, fset) {
+			assert.NotContains(t, issue.Message, "managed-by")
+		}
+	})
+
+	t.Run("should do nothing when managed-by is not a recommended label", func(t *testing.T) {
+		testFile := filepath.Join(t.TempDir(), "wk8013.go")
+		content := `package testdata
+
+import corev1 "k8s.io/api/core/v1"
+
+var WebConfig = corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "web-config"}}
+`
+		require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+		fixer := fixerOnly("WK8013")
+		fixer.config.RecommendedLabels = []string{"app.kubernetes.io/part-of"}
+		results, err := fixer.FixFile(testFile)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+func TestFixer_FixFile_WK8002_ElidedTypes(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "wk8002.go")
+	content := `package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var Deployment = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "app",
+						Env:  []corev1.EnvVar{{Name: "A", Value: "b"}},
+					},
+				},
+			},
+		},
+	},
+}
+`
+	require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+	_, err := fixerOnly("WK8002").FixFile(testFile)
+	require.NoError(t, err)
+
+	fixed, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	_, err = parser.ParseFile(token.NewFileSet(), testFile, fixed, 0)
+	require.NoError(t, err, "extracted variables must keep their element type:\n%s", fixed)
+	assert.Contains(t, string(fixed), "= corev1.Container{")
+}
+
+func TestFixer_DisabledRules(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "disabled.go")
+	content := `package testdata
+
+import corev1 "k8s.io/api/core/v1"
+
+var Container = corev1.Container{Name: "app", Image: "nginx:1.21"}
+`
+	require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+	fixer := NewFixer(&Config{DisabledRules: FixableRules()})
+	results, err := fixer.FixFile(testFile)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	unchanged, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(unchanged))
+}
+
 func TestFixer_FixDirectory(t *testing.T) {
 	// Create a temporary directory with test files
 	tempDir := t.TempDir()
@@ -298,14 +690,124 @@ var Container2 = corev1.Container{
 	assert.Contains(t, string(fixed1), "IfNotPresent") // nginx:1.21 should get IfNotPresent
 	assert.Contains(t, string(fixed2), "Always")       // busybox:latest should get Always
 
-	// Check results
+	// Check results: ImagePullPolicy and resource limits for each container
 	fixedCount := 0
 	for _, r := range results {
 		if r.Fixed {
 			fixedCount++
 		}
 	}
-	assert.Equal(t, 2, fixedCount)
+	assert.Equal(t, 4, fixedCount)
+}
+
+func TestFixer_DiffFile(t *testing.T) {
+	content := `package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+var Container1 = corev1.Container{
+	Name:  "app",
+	Image: "nginx:1.21",
+}
+`
+
+	t.Run("should leave the file untouched", func(t *testing.T) {
+		testFile := filepath.Join(t.TempDir(), "test.go")
+		require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+		results, diff, err := fixerOnly("WK8105").DiffFile(testFile)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "WK8105", results[0].Rule)
+
+		unchanged, err := os.ReadFile(testFile)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(unchanged))
+
+		require.NotNil(t, diff)
+		assert.Equal(t, testFile, diff.File)
+		expected := "--- " + testFile + ".orig\n" +
+			"+++ " + testFile + "\n" +
+			"@@ -6,5 +6,5 @@\n" +
+			" \n" +
+			" var Container1 = corev1.Container{\n" +
+			" \tName:  \"app\",\n" +
+			"-\tImage: \"nginx:1.21\",\n" +
+			"+\tImage: \"nginx:1.21\", ImagePullPolicy: \"IfNotPresent\",\n" +
+			" }\n"
+		assert.Equal(t, expected, diff.Diff)
+	})
+
+	t.Run("should match the changes FixFile makes", func(t *testing.T) {
+		testFile := filepath.Join(t.TempDir(), "test.go")
+		require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+		_, diff, err := NewFixer(nil).DiffFile(testFile)
+		require.NoError(t, err)
+		require.NotNil(t, diff)
+
+		_, err = NewFixer(nil).FixFile(testFile)
+		require.NoError(t, err)
+		fixed, err := os.ReadFile(testFile)
+		require.NoError(t, err)
+
+		// Every added line of the diff is in the fixed file
+		for _, line := range strings.Split(diff.Diff, "\n") {
+			if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
+				assert.Contains(t, string(fixed), line[1:])
+			}
+		}
+	})
+
+	t.Run("should return no diff when nothing is fixable", func(t *testing.T) {
+		testFile := filepath.Join(t.TempDir(), "test.go")
+		require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+		results, diff, err := fixerOnly("WK8007").DiffFile(testFile)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+		assert.Nil(t, diff)
+	})
+}
+
+func TestFixer_DiffDirectory(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"b.go": `package testdata
+
+import corev1 "k8s.io/api/core/v1"
+
+var Container2 = corev1.Container{Name: "sidecar", Image: "busybox:latest"}
+`,
+		"a.go": `package testdata
+
+import corev1 "k8s.io/api/core/v1"
+
+var Container1 = corev1.Container{Name: "app", Image: "nginx:1.21"}
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644))
+	}
+
+	results, diffs, err := fixerOnly("WK8105").DiffDirectory(tempDir)
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	// Diffs are reported in file order and no file is written
+	require.Len(t, diffs, 2)
+	assert.Equal(t, filepath.Join(tempDir, "a.go"), diffs[0].File)
+	assert.Contains(t, diffs[0].Diff, `ImagePullPolicy: "IfNotPresent"`)
+	assert.Equal(t, filepath.Join(tempDir, "b.go"), diffs[1].File)
+	assert.Contains(t, diffs[1].Diff, `ImagePullPolicy: "Always"`)
+
+	for name, content := range files {
+		unchanged, err := os.ReadFile(filepath.Join(tempDir, name))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(unchanged))
+	}
 }
 
 func TestFixer_FixFile_NonExistent(t *testing.T) {
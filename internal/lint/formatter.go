@@ -17,6 +17,10 @@ const (
 	FormatJSON OutputFormat = "json"
 	// FormatGitHub outputs GitHub Actions format.
 	FormatGitHub OutputFormat = "github"
+	// FormatSARIF outputs a SARIF 2.1.0 log for code scanning.
+	FormatSARIF OutputFormat = "sarif"
+	// FormatJUnit outputs JUnit XML for CI test reporting.
+	FormatJUnit OutputFormat = "junit"
 )
 
 // Formatter formats lint results for output.
@@ -31,6 +35,10 @@ func NewFormatter(format OutputFormat) Formatter {
 		return &JSONFormatter{}
 	case FormatGitHub:
 		return &GitHubFormatter{}
+	case FormatSARIF:
+		return &SARIFFormatter{}
+	case FormatJUnit:
+		return &JUnitFormatter{}
 	default:
 		return &TextFormatter{}
 	}
@@ -46,18 +54,10 @@ func (f *TextFormatter) Format(result *LintResult, w io.Writer) error {
 		return nil
 	}
 
-	// Sort issues by file, then line
-	sortedIssues := make([]Issue, len(result.Issues))
-	copy(sortedIssues, result.Issues)
-	sort.Slice(sortedIssues, func(i, j int) bool {
-		if sortedIssues[i].File != sortedIssues[j].File {
-			return sortedIssues[i].File < sortedIssues[j].File
-		}
-		return sortedIssues[i].Line < sortedIssues[j].Line
-	})
+	issues := sortedIssues(result.Issues)
 
 	// Print issues
-	for _, issue := range sortedIssues {
+	for _, issue := range issues {
 		fmt.Fprintf(w, "%s:%d:%d: %s [%s] %s\n",
 			issue.File,
 			issue.Line,
@@ -92,23 +92,23 @@ type JSONFormatter struct{}
 
 // JSONOutput represents the JSON output structure.
 type JSONOutput struct {
-	Issues       []Issue `json:"issues"`
-	TotalFiles   int     `json:"total_files"`
+	Issues          []Issue `json:"issues"`
+	TotalFiles      int     `json:"total_files"`
 	FilesWithIssues int     `json:"files_with_issues"`
-	ErrorCount   int     `json:"error_count"`
-	WarningCount int     `json:"warning_count"`
-	InfoCount    int     `json:"info_count"`
+	ErrorCount      int     `json:"error_count"`
+	WarningCount    int     `json:"warning_count"`
+	InfoCount       int     `json:"info_count"`
 }
 
 // Format implements Formatter.
 func (f *JSONFormatter) Format(result *LintResult, w io.Writer) error {
 	output := JSONOutput{
-		Issues:       result.Issues,
-		TotalFiles:   result.TotalFiles,
+		Issues:          result.Issues,
+		TotalFiles:      result.TotalFiles,
 		FilesWithIssues: result.FilesWithIssues,
-		ErrorCount:   result.ErrorCount,
-		WarningCount: result.WarningCount,
-		InfoCount:    result.InfoCount,
+		ErrorCount:      result.ErrorCount,
+		WarningCount:    result.WarningCount,
+		InfoCount:       result.InfoCount,
 	}
 
 	// Ensure Issues is not nil (empty array instead)
@@ -131,18 +131,10 @@ func (f *GitHubFormatter) Format(result *LintResult, w io.Writer) error {
 		return nil
 	}
 
-	// Sort issues by file, then line
-	sortedIssues := make([]Issue, len(result.Issues))
-	copy(sortedIssues, result.Issues)
-	sort.Slice(sortedIssues, func(i, j int) bool {
-		if sortedIssues[i].File != sortedIssues[j].File {
-			return sortedIssues[i].File < sortedIssues[j].File
-		}
-		return sortedIssues[i].Line < sortedIssues[j].Line
-	})
+	issues := sortedIssues(result.Issues)
 
 	// Print GitHub Actions annotations
-	for _, issue := range sortedIssues {
+	for _, issue := range issues {
 		level := "error"
 		switch issue.Severity {
 		case SeverityWarning:
@@ -171,6 +163,19 @@ func (f *GitHubFormatter) Format(result *LintResult, w io.Writer) error {
 	return nil
 }
 
+// sortedIssues returns a copy of issues sorted by file, then line.
+func sortedIssues(issues []Issue) []Issue {
+	sorted := make([]Issue, len(issues))
+	copy(sorted, issues)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].File != sorted[j].File {
+			return sorted[i].File < sorted[j].File
+		}
+		return sorted[i].Line < sorted[j].Line
+	})
+	return sorted
+}
+
 // IssueSummary represents a summary of issues by rule.
 type IssueSummary struct {
 	RuleID      string
@@ -26,6 +26,16 @@ func TestNewFormatter(t *testing.T) {
 		assert.IsType(t, &GitHubFormatter{}, formatter)
 	})
 
+	t.Run("should create SARIF formatter", func(t *testing.T) {
+		formatter := NewFormatter(FormatSARIF)
+		assert.IsType(t, &SARIFFormatter{}, formatter)
+	})
+
+	t.Run("should create JUnit formatter", func(t *testing.T) {
+		formatter := NewFormatter(FormatJUnit)
+		assert.IsType(t, &JUnitFormatter{}, formatter)
+	})
+
 	t.Run("should default to text formatter for unknown format", func(t *testing.T) {
 		formatter := NewFormatter("unknown")
 		assert.IsType(t, &TextFormatter{}, formatter)
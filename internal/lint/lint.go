@@ -1,8 +1,11 @@
 package lint
 
 import (
+	"errors"
 	"fmt"
+	"go/ast"
 	"go/parser"
+	"go/scanner"
 	"go/token"
 	"os"
 	"path/filepath"
@@ -23,15 +26,22 @@ func NewLinter(config *Config) *Linter {
 		}
 	}
 
-	// Get all available rules
-	allRules := AllRules()
+	// Get all registered rules
+	allRules := Rules()
 
-	// Filter out disabled rules
+	// Filter out disabled rules and optional rules that are not enabled
 	var enabledRules []Rule
 	for _, rule := range allRules {
-		if !isRuleDisabled(rule.ID, config.DisabledRules) {
-			enabledRules = append(enabledRules, rule)
+		if isRuleDisabled(rule.ID, config.DisabledRules) {
+			continue
 		}
+		if rule.Optional && !isRuleEnabled(rule.ID, config.EnabledRules) {
+			continue
+		}
+		if rule.Configure != nil {
+			rule = rule.Configure(config)
+		}
+		enabledRules = append(enabledRules, rule)
 	}
 
 	return &Linter{
@@ -50,6 +60,16 @@ func isRuleDisabled(ruleID string, disabledRules []string) bool {
 	return false
 }
 
+// isRuleEnabled checks if an optional rule ID is in the enabled list.
+func isRuleEnabled(ruleID string, enabledRules []string) bool {
+	for _, enabled := range enabledRules {
+		if enabled == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
 // LintFile lints a single Go source file.
 func (l *Linter) LintFile(filePath string) ([]Issue, error) {
 	// Create a new file set for position information
@@ -61,57 +81,209 @@ func (l *Linter) LintFile(filePath string) ([]Issue, error) {
 		return nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
 	}
 
+	// Package rules see the other files of the package too
+	return l.lintParsed(fset, file, filePath, func() map[string][]Issue {
+		return l.checkPackage(parsePackageFiles(fset, filePath, file), fset)[filePath]
+	}), nil
+}
+
+// LintSource lints Go source that is not read from disk, such as generated
+// code. filename is used in issue positions. Package rules see only this
+// source as the package.
+func (l *Linter) LintSource(filename string, src []byte) ([]Issue, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	return l.lintParsed(fset, file, filename, func() map[string][]Issue {
+		return l.checkPackage([]*ast.File{file}, fset)[filename]
+	}), nil
+}
+
+// lintParsed runs the enabled rules on file, parsed as filePath.
+// packageIssues returns the issues of the package rules in file, keyed by
+// rule ID, and is only called if a package rule runs.
+func (l *Linter) lintParsed(fset *token.FileSet, file *ast.File, filePath string, packageIssues func() map[string][]Issue) []Issue {
 	var allIssues []Issue
+	suppressed := parseSuppressions(file, fset)
+
+	// Walk the file once for the rules that check it node by node
+	var visitRules []Rule
+	for _, rule := range l.rules {
+		if rule.CheckPackage == nil && rule.Visit != nil {
+			visitRules = append(visitRules, rule)
+		}
+	}
+	visited := walkFile(file, fset, visitRules)
 
 	// Run each rule
+	var pkgIssues map[string][]Issue
+	pkgChecked := false
 	for _, rule := range l.rules {
-		issues := rule.Check(file, fset)
+		var issues []Issue
+		switch {
+		case rule.CheckPackage != nil:
+			if !pkgChecked {
+				pkgIssues, pkgChecked = packageIssues(), true
+			}
+			issues = pkgIssues[rule.ID]
+		case rule.Visit != nil:
+			issues, visited = visited[0], visited[1:]
+		default:
+			issues = rule.Check(file, fset)
+		}
 
-		// Filter by minimum severity
+		// Apply severity overrides, then filter by minimum severity
 		// Note: Lower severity values are more severe (Error=0, Warning=1, Info=2)
+		override, hasOverride := l.config.RuleSeverity[rule.ID]
 		for _, issue := range issues {
+			if hasOverride {
+				issue.Severity = override
+			}
+			if suppressed.suppressed(issue) {
+				continue
+			}
 			if issue.Severity <= l.config.MinSeverity {
 				allIssues = append(allIssues, issue)
 			}
 		}
 	}
 
-	return allIssues, nil
+	return allIssues
 }
 
-// LintDirectory lints all Go files in a directory recursively.
+// parsePackageFiles returns file together with the other non-test Go files
+// in its directory that declare the same package. Files that cannot be
+// parsed are left out.
+func parsePackageFiles(fset *token.FileSet, filePath string, file *ast.File) []*ast.File {
+	files := []*ast.File{file}
+
+	dir := filepath.Dir(filePath)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return files
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == filepath.Base(filePath) {
+			continue
+		}
+		other, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil || other.Name.Name != file.Name.Name {
+			continue
+		}
+		files = append(files, other)
+	}
+	return files
+}
+
+// checkPackage runs the enabled package rules once on the files of a
+// package and returns their issues by file and then by rule ID.
+func (l *Linter) checkPackage(files []*ast.File, fset *token.FileSet) map[string]map[string][]Issue {
+	issues := make(map[string]map[string][]Issue)
+	for _, rule := range l.rules {
+		if rule.CheckPackage == nil {
+			continue
+		}
+		for _, issue := range rule.CheckPackage(files, fset) {
+			if issues[issue.File] == nil {
+				issues[issue.File] = make(map[string][]Issue)
+			}
+			issues[issue.File][rule.ID] = append(issues[issue.File][rule.ID], issue)
+		}
+	}
+	return issues
+}
+
+// ParseErrorRule is the rule ID of the issue LintDirectory reports for a file
+// that cannot be parsed. It is not a registered rule and cannot be disabled.
+const ParseErrorRule = "WK0001"
+
+// parseErrorIssue returns the issue for a file at path that failed to lint
+// with err, at the position of its first syntax error.
+func parseErrorIssue(path string, err error) Issue {
+	issue := Issue{
+		Rule:     ParseErrorRule,
+		Message:  "parse error: " + err.Error(),
+		File:     path,
+		Line:     1,
+		Severity: SeverityError,
+	}
+	var syntaxErrs scanner.ErrorList
+	if errors.As(err, &syntaxErrs) && len(syntaxErrs) > 0 {
+		first := syntaxErrs[0]
+		issue.Message = "parse error: " + first.Msg
+		if len(syntaxErrs) > 1 {
+			issue.Message += fmt.Sprintf(" (and %d more errors)", len(syntaxErrs)-1)
+		}
+		issue.Line = first.Pos.Line
+		issue.Column = first.Pos.Column
+	}
+	return issue
+}
+
+// LintDirectory lints all Go files in a directory recursively. A file that
+// cannot be parsed is reported as a ParseErrorRule issue, and the other files
+// are still linted.
+//
+// Each file is parsed once, and the package rules run once per package, on
+// the files of a directory that declare the same package.
 func (l *Linter) LintDirectory(dir string) ([]Issue, error) {
 	var allIssues []Issue
+	var paths []string
 
 	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
-		// Skip directories and non-Go files
-		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+		// Skip directories, non-Go files and test files
+		if info.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
 			return nil
 		}
+		paths = append(paths, path)
+		return nil
+	})
 
-		// Skip test files
-		if strings.HasSuffix(path, "_test.go") {
-			return nil
-		}
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory %s: %w", dir, err)
+	}
 
-		// Lint this file
-		issues, err := l.LintFile(path)
+	// Parse every file, grouping them into packages
+	fset := token.NewFileSet()
+	files := make(map[string]*ast.File, len(paths))
+	parseErrs := make(map[string]error)
+	packages := make(map[string][]*ast.File)
+	for _, path := range paths {
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
 		if err != nil {
-			// Log error but continue processing other files
-			fmt.Fprintf(os.Stderr, "Warning: failed to lint %s: %v\n", path, err)
-			return nil
+			parseErrs[path] = fmt.Errorf("failed to parse file %s: %w", path, err)
+			continue
 		}
+		files[path] = file
+		pkg := filepath.Join(filepath.Dir(path), file.Name.Name)
+		packages[pkg] = append(packages[pkg], file)
+	}
 
-		allIssues = append(allIssues, issues...)
-		return nil
-	})
+	// Run the package rules once per package
+	pkgIssues := make(map[string]map[string][]Issue)
+	for _, pkgFiles := range packages {
+		for path, issues := range l.checkPackage(pkgFiles, fset) {
+			pkgIssues[path] = issues
+		}
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to walk directory %s: %w", dir, err)
+	// Lint the files in the order they were found
+	for _, path := range paths {
+		if err, ok := parseErrs[path]; ok {
+			allIssues = append(allIssues, parseErrorIssue(path, err))
+			continue
+		}
+		allIssues = append(allIssues, l.lintParsed(fset, files[path], path, func() map[string][]Issue {
+			return pkgIssues[path]
+		})...)
 	}
 
 	return allIssues, nil
@@ -135,12 +307,12 @@ func (l *Linter) Lint(path string) ([]Issue, error) {
 
 // LintResult represents the result of a lint operation.
 type LintResult struct {
-	Issues       []Issue
-	TotalFiles   int
+	Issues          []Issue
+	TotalFiles      int
 	FilesWithIssues int
-	ErrorCount   int
-	WarningCount int
-	InfoCount    int
+	ErrorCount      int
+	WarningCount    int
+	InfoCount       int
 }
 
 // LintWithResult lints a file or directory and returns a detailed result.
@@ -189,3 +361,18 @@ func (l *Linter) LintWithResult(path string) (*LintResult, error) {
 
 	return result, nil
 }
+
+// Summary returns the issue counts of the result as a single line, e.g.
+// "2 errors, 3 warnings, 1 info across 4 files".
+func (r *LintResult) Summary() string {
+	return fmt.Sprintf("%s, %s, %d info across %s",
+		plural(r.ErrorCount, "error"), plural(r.WarningCount, "warning"), r.InfoCount, plural(r.TotalFiles, "file"))
+}
+
+// plural formats a count with a noun that takes a plain "s" plural.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
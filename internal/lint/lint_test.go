@@ -1,7 +1,12 @@
 package lint
 
 import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -14,7 +19,7 @@ func TestNewLinter(t *testing.T) {
 		assert.NotNil(t, linter)
 		assert.NotNil(t, linter.config)
 		assert.Equal(t, SeverityInfo, linter.config.MinSeverity)
-		assert.Len(t, linter.rules, 26, "Should have all 26 rules enabled")
+		assert.Len(t, linter.rules, 45, "Should have all 45 non-optional rules enabled")
 	})
 
 	t.Run("should create linter with custom config", func(t *testing.T) {
@@ -31,7 +36,7 @@ func TestNewLinter(t *testing.T) {
 			DisabledRules: []string{"WK8001", "WK8002"},
 		}
 		linter := NewLinter(config)
-		assert.Len(t, linter.rules, 24, "Should have 24 rules enabled (2 disabled)")
+		assert.Len(t, linter.rules, 43, "Should have 43 rules enabled (2 disabled)")
 	})
 }
 
@@ -132,6 +137,76 @@ func TestLinter_LintDirectory(t *testing.T) {
 		assert.Greater(t, ruleCounts["WK8202"], 0, "Expected WK8202 issues")
 		assert.Greater(t, ruleCounts["WK8301"], 0, "Expected WK8301 issues")
 	})
+
+	t.Run("should report parse errors and lint the other files", func(t *testing.T) {
+		dir := t.TempDir()
+		valid := `package k8s
+
+import corev1 "k8s.io/api/core/v1"
+
+var %s = &corev1.ConfigMap{}
+`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.go"), []byte(fmt.Sprintf(valid, "FirstConfig")), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.go"), []byte("package k8s\n\nvar Broken = {{{\n"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "c.go"), []byte(fmt.Sprintf(valid, "SecondConfig")), 0644))
+
+		issues, err := linter.LintDirectory(dir)
+		require.NoError(t, err)
+
+		files := make(map[string][]string)
+		for _, issue := range issues {
+			files[filepath.Base(issue.File)] = append(files[filepath.Base(issue.File)], issue.Rule)
+		}
+		assert.Equal(t, []string{ParseErrorRule}, files["broken.go"])
+		assert.Contains(t, files["a.go"], "WK8102", "valid files are still linted")
+		assert.Contains(t, files["c.go"], "WK8102", "valid files are still linted")
+
+		for _, issue := range issues {
+			if issue.Rule == ParseErrorRule {
+				assert.Equal(t, SeverityError, issue.Severity)
+				assert.Equal(t, 3, issue.Line)
+				assert.Contains(t, issue.Message, "parse error: expected")
+			}
+		}
+	})
+
+	t.Run("should run package rules once per package", func(t *testing.T) {
+		dir := t.TempDir()
+		for _, name := range []string{"a.go", "b.go", "c.go", filepath.Join("sub", "d.go"), filepath.Join("sub", "e.go")} {
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("package k8s\n\nvar X = 1\n"), 0644))
+		}
+
+		// A package rule reporting each file of the package it is given
+		var calls []int
+		packageRule := Rule{
+			ID:       "WK8999",
+			Severity: SeverityInfo,
+			Check:    func(*ast.File, *token.FileSet) []Issue { return nil },
+			CheckPackage: func(files []*ast.File, fset *token.FileSet) []Issue {
+				calls = append(calls, len(files))
+				var issues []Issue
+				for _, file := range files {
+					issues = append(issues, Issue{Rule: "WK8999", File: fset.Position(file.Pos()).Filename, Line: 1, Severity: SeverityInfo})
+				}
+				return issues
+			},
+		}
+		linter := &Linter{config: &Config{MinSeverity: SeverityInfo}, rules: []Rule{packageRule}}
+
+		issues, err := linter.LintDirectory(dir)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int{3, 2}, calls)
+
+		// Each file gets the issues reported in it, once
+		var files []string
+		for _, issue := range issues {
+			rel, err := filepath.Rel(dir, issue.File)
+			require.NoError(t, err)
+			files = append(files, rel)
+		}
+		assert.Equal(t, []string{"a.go", "b.go", "c.go", filepath.Join("sub", "d.go"), filepath.Join("sub", "e.go")}, files)
+	})
 }
 
 func TestLinter_Lint(t *testing.T) {
@@ -223,14 +298,148 @@ func TestLinter_NonExistentPath(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestLinter_RuleSeverityOverride(t *testing.T) {
+	path := filepath.Join("testdata", "wk8302_bad.go")
+
+	t.Run("should promote a rule's severity", func(t *testing.T) {
+		linter := NewLinter(&Config{
+			MinSeverity:  SeverityInfo,
+			RuleSeverity: map[string]Severity{"WK8302": SeverityError},
+		})
+		issues, err := linter.LintFile(path)
+		require.NoError(t, err)
+
+		found := false
+		for _, issue := range issues {
+			if issue.Rule == "WK8302" {
+				found = true
+				assert.Equal(t, SeverityError, issue.Severity)
+			}
+		}
+		assert.True(t, found, "Expected WK8302 issues")
+	})
+
+	t.Run("should filter demoted issues by minimum severity", func(t *testing.T) {
+		linter := NewLinter(&Config{
+			MinSeverity:  SeverityWarning,
+			RuleSeverity: map[string]Severity{"WK8201": SeverityInfo},
+		})
+		issues, err := linter.LintFile(filepath.Join("testdata", "wk8201_bad.go"))
+		require.NoError(t, err)
+
+		for _, issue := range issues {
+			assert.NotEqual(t, "WK8201", issue.Rule, "demoted WK8201 issues should be filtered out")
+		}
+	})
+
+	t.Run("should count overridden severities in results", func(t *testing.T) {
+		linter := NewLinter(&Config{
+			MinSeverity:   SeverityInfo,
+			DisabledRules: disabledExcept(t, "WK8302"),
+			RuleSeverity:  map[string]Severity{"WK8302": SeverityError},
+		})
+		result, err := linter.LintWithResult(path)
+		require.NoError(t, err)
+		assert.Greater(t, result.ErrorCount, 0)
+		assert.Equal(t, 0, result.InfoCount)
+	})
+}
+
+// disabledExcept returns the IDs of all rules other than the given one.
+func disabledExcept(t *testing.T, id string) []string {
+	t.Helper()
+	var ids []string
+	for _, rule := range AllRules() {
+		if rule.ID != id {
+			ids = append(ids, rule.ID)
+		}
+	}
+	return ids
+}
+
+func TestLinter_InlineSuppression(t *testing.T) {
+	linter := NewLinter(&Config{
+		MinSeverity:   SeverityInfo,
+		DisabledRules: disabledExcept(t, "WK8006"),
+	})
+	issues, err := linter.LintFile(filepath.Join("testdata", "suppress.go"))
+	require.NoError(t, err)
+
+	var flagged []string
+	for _, issue := range issues {
+		for _, image := range []string{
+			"trailing-nolint", "trailing-ignore", "multiple-rules", "bare-nolint",
+			"doc-comment", "other-rule", "other-line", "not-suppressed",
+		} {
+			if strings.Contains(issue.Message, fmt.Sprintf("%q", image)) {
+				flagged = append(flagged, image)
+			}
+		}
+	}
+
+	assert.ElementsMatch(t, []string{"other-rule", "other-line", "not-suppressed"}, flagged)
+}
+
+func TestLinter_Collections(t *testing.T) {
+	// Rules that inspect composite literals still apply inside slices of
+	// resources, and no rule fails on them
+	linter := NewLinter(&Config{MinSeverity: SeverityInfo})
+	issues, err := linter.LintFile(filepath.Join("testdata", "collections.go"))
+	require.NoError(t, err)
+
+	var latest []Issue
+	for _, issue := range issues {
+		if issue.Rule == "WK8006" {
+			latest = append(latest, issue)
+		}
+	}
+	require.Len(t, latest, 1)
+	assert.Contains(t, latest[0].Message, `"nginx:latest"`)
+	assert.Equal(t, 30, latest[0].Line)
+}
+
+func TestParseDirective(t *testing.T) {
+	tests := []struct {
+		comment string
+		isDir   bool
+		rules   []string // nil means all rules
+	}{
+		{"// nolint", true, nil},
+		{"//nolint", true, nil},
+		{"// nolint:all", true, nil},
+		{"// nolint:WK8006", true, []string{"WK8006"}},
+		{"// nolint:WK8006,wk8201", true, []string{"WK8006", "WK8201"}},
+		{"// nolint:WK8006 // reason", true, []string{"WK8006"}},
+		{"// wetwire:ignore", true, nil},
+		{"// wetwire:ignore WK8006 WK8201", true, []string{"WK8006", "WK8201"}},
+		{"// nolintish", false, nil},
+		{"// regular comment", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.comment, func(t *testing.T) {
+			rules, ok := parseDirective(tt.comment)
+			assert.Equal(t, tt.isDir, ok)
+			if tt.rules == nil {
+				assert.Nil(t, rules)
+				return
+			}
+			for _, id := range tt.rules {
+				assert.True(t, rules[id], "expected %s to be suppressed", id)
+			}
+			assert.Len(t, rules, len(tt.rules))
+		})
+	}
+}
+
 func TestIsRuleDisabled(t *testing.T) {
 	config := &Config{
 		DisabledRules: []string{"WK8001", "WK8003"},
 	}
 	linter := NewLinter(config)
 
-	// The linter should have 24 rules (26 - 2 disabled)
-	assert.Len(t, linter.rules, 24)
+	// The linter should have 43 rules (45 non-optional - 2 disabled)
+	assert.Len(t, linter.rules, 43)
 }
 
 func TestLintResult_CountsIssuesBySeverity(t *testing.T) {
@@ -265,6 +474,14 @@ func TestLintResult_CountsIssuesBySeverity(t *testing.T) {
 	assert.Equal(t, 1, infoCount)
 }
 
+func TestLintResult_Summary(t *testing.T) {
+	result := &LintResult{ErrorCount: 2, WarningCount: 3, InfoCount: 1, TotalFiles: 4}
+	assert.Equal(t, "2 errors, 3 warnings, 1 info across 4 files", result.Summary())
+
+	result = &LintResult{ErrorCount: 1, WarningCount: 1, TotalFiles: 1}
+	assert.Equal(t, "1 error, 1 warning, 0 info across 1 file", result.Summary())
+}
+
 func TestSeverityString(t *testing.T) {
 	tests := []struct {
 		severity Severity
@@ -314,21 +531,64 @@ func TestConfig_DefaultValues(t *testing.T) {
 
 func TestLinter_AllRulesEnabled(t *testing.T) {
 	linter := NewLinter(nil)
-	// Should have all 26 rules enabled by default
-	assert.Len(t, linter.rules, 26)
+	// Should have all 43 non-optional rules enabled by default
+	assert.Len(t, linter.rules, 45)
 }
 
 func TestLinter_DisableAllRules(t *testing.T) {
 	config := &Config{
 		DisabledRules: []string{
-			"WK8001", "WK8002", "WK8003", "WK8004", "WK8005", "WK8006",
-			"WK8041", "WK8042",
-			"WK8101", "WK8102", "WK8103", "WK8104", "WK8105",
-			"WK8201", "WK8202", "WK8203", "WK8204", "WK8205", "WK8207", "WK8208", "WK8209",
-			"WK8301", "WK8302", "WK8303", "WK8304",
+			"WK8001", "WK8002", "WK8003", "WK8004", "WK8005", "WK8006", "WK8007", "WK8011", "WK8012", "WK8013", "WK8014", "WK8015", "WK8016", "WK8017", "WK8018", "WK8019",
+			"WK8041", "WK8042", "WK8043", "WK8099",
+			"WK8101", "WK8102", "WK8103", "WK8104", "WK8105", "WK8107", "WK8108",
+			"WK8201", "WK8202", "WK8203", "WK8204", "WK8205", "WK8206", "WK8207", "WK8208", "WK8209", "WK8210", "WK8211",
+			"WK8301", "WK8302", "WK8303", "WK8304", "WK8305", "WK8306", "WK8307",
 			"WK8401",
 		},
 	}
 	linter := NewLinter(config)
 	assert.Len(t, linter.rules, 0)
 }
+
+// largeSource returns a file declaring n ConfigMaps and n Deployments, each
+// Deployment reading the ConfigMap declared before it, the shape of the
+// single generated file of a large imported repository.
+func largeSource(n int) []byte {
+	var b strings.Builder
+	b.WriteString("package k8s\n\nimport (\n\tappsv1 \"k8s.io/api/apps/v1\"\n\tcorev1 \"k8s.io/api/core/v1\"\n\tmetav1 \"k8s.io/apimachinery/pkg/apis/meta/v1\"\n)\n\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "var Config%d = &corev1.ConfigMap{\n\tObjectMeta: metav1.ObjectMeta{Name: \"config-%d\"},\n\tData: map[string]string{\"index\": \"%d\"},\n}\n\n", i, i, i)
+		fmt.Fprintf(&b, "var App%d = &appsv1.Deployment{\n\tObjectMeta: metav1.ObjectMeta{Name: \"app-%d\"},\n\tSpec: appsv1.DeploymentSpec{\n\t\tTemplate: corev1.PodTemplateSpec{\n\t\t\tSpec: corev1.PodSpec{\n\t\t\t\tContainers: []corev1.Container{{\n\t\t\t\t\tName: \"app\",\n\t\t\t\t\tImage: \"app:1.0\",\n\t\t\t\t\tEnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: Config%d.Name}}}},\n\t\t\t\t}},\n\t\t\t},\n\t\t},\n\t},\n}\n\n", i, i, i)
+	}
+	return []byte(b.String())
+}
+
+func BenchmarkLintSource(b *testing.B) {
+	src := largeSource(500)
+	linter := NewLinter(nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := linter.LintSource("k8s.go", src); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkLintDirectory lints a package of 80 files, where the package rules
+// see every file.
+func BenchmarkLintDirectory(b *testing.B) {
+	dir := b.TempDir()
+	for i := 0; i < 80; i++ {
+		src := strings.NewReplacer("Config", fmt.Sprintf("F%dConfig", i), "App", fmt.Sprintf("F%dApp", i)).Replace(string(largeSource(5)))
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("file%d.go", i)), []byte(src), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	linter := NewLinter(nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := linter.LintDirectory(dir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package lint
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// JUnitFormatter formats results as JUnit XML for CI test reporting. Each
+// file with issues becomes a test suite and each issue a failed test case.
+type JUnitFormatter struct {
+	// BaseDir makes file names relative to it, if set.
+	BaseDir string
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string       `xml:"name,attr"`
+	ClassName string       `xml:"classname,attr"`
+	Failure   junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// Format implements Formatter.
+func (f *JUnitFormatter) Format(result *LintResult, w io.Writer) error {
+	suites := junitTestSuites{Name: "wetwire-k8s lint"}
+
+	// Issues are sorted by file, so each file's issues are contiguous
+	for _, issue := range sortedIssues(result.Issues) {
+		file := filepath.ToSlash(relativePath(f.BaseDir, issue.File))
+		if n := len(suites.Suites); n == 0 || suites.Suites[n-1].Name != file {
+			suites.Suites = append(suites.Suites, junitTestSuite{Name: file})
+		}
+
+		suite := &suites.Suites[len(suites.Suites)-1]
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      fmt.Sprintf("%s:%d:%d %s", file, issue.Line, issue.Column, issue.Rule),
+			ClassName: issue.Rule,
+			Failure: junitFailure{
+				Message: issue.Message,
+				Type:    issue.Severity.String(),
+				Text: fmt.Sprintf("%s:%d:%d: %s [%s] %s",
+					file, issue.Line, issue.Column, issue.Severity.String(), issue.Rule, issue.Message),
+			},
+		})
+		suite.Tests++
+		suite.Failures++
+		suites.Tests++
+		suites.Failures++
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
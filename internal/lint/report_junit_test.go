@@ -0,0 +1,65 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJUnitFormatter_Format(t *testing.T) {
+	t.Run("should write well-formed XML with a suite per file", func(t *testing.T) {
+		formatter := &JUnitFormatter{BaseDir: "/src"}
+
+		var buf bytes.Buffer
+		require.NoError(t, formatter.Format(sampleReportResult(), &buf))
+		assert.Contains(t, buf.String(), `<?xml version="1.0" encoding="UTF-8"?>`)
+
+		var suites junitTestSuites
+		require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+		assert.Equal(t, 3, suites.Tests)
+		assert.Equal(t, 3, suites.Failures)
+
+		require.Len(t, suites.Suites, 2)
+		assert.Equal(t, "k8s/api.go", suites.Suites[0].Name)
+		assert.Equal(t, 1, suites.Suites[0].Tests)
+		assert.Equal(t, "k8s/web.go", suites.Suites[1].Name)
+		assert.Equal(t, 2, suites.Suites[1].Failures)
+
+		testCase := suites.Suites[1].TestCases[0]
+		assert.Equal(t, "WK8006", testCase.ClassName)
+		assert.Equal(t, "k8s/web.go:12:14 WK8006", testCase.Name)
+		assert.Equal(t, "error", testCase.Failure.Type)
+		assert.Contains(t, testCase.Failure.Message, `"nginx:latest"`)
+		assert.Contains(t, testCase.Failure.Text, "k8s/web.go:12:14")
+	})
+
+	t.Run("should escape special characters", func(t *testing.T) {
+		result := &LintResult{Issues: []Issue{{
+			Rule:     "WK8001",
+			Message:  `a <b> & "c"`,
+			File:     "x.go",
+			Line:     1,
+			Severity: SeverityError,
+		}}}
+
+		var buf bytes.Buffer
+		require.NoError(t, (&JUnitFormatter{}).Format(result, &buf))
+
+		var suites junitTestSuites
+		require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+		assert.Equal(t, `a <b> & "c"`, suites.Suites[0].TestCases[0].Failure.Message)
+	})
+
+	t.Run("should write an empty report when there are no issues", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, (&JUnitFormatter{}).Format(&LintResult{}, &buf))
+
+		var suites junitTestSuites
+		require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+		assert.Equal(t, 0, suites.Tests)
+		assert.Empty(t, suites.Suites)
+	})
+}
@@ -0,0 +1,188 @@
+package lint
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SARIF 2.1.0 identifiers written to every report.
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// SARIFFormatter formats results as a SARIF 2.1.0 log, suitable for upload
+// to GitHub code scanning.
+type SARIFFormatter struct {
+	// ToolVersion is reported as the driver version, if set.
+	ToolVersion string
+
+	// BaseDir makes file locations relative to it, if set. Code scanning
+	// expects paths relative to the repository root.
+	BaseDir string
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name,omitempty"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// Format implements Formatter.
+func (f *SARIFFormatter) Format(result *LintResult, w io.Writer) error {
+	issues := sortedIssues(result.Issues)
+
+	// Describe each rule that reported an issue, in ID order
+	known := make(map[string]Rule)
+	for _, rule := range AllRules() {
+		known[rule.ID] = rule
+	}
+	ruleIndex := make(map[string]int)
+	var ruleIDs []string
+	for _, issue := range issues {
+		if _, ok := ruleIndex[issue.Rule]; !ok {
+			ruleIndex[issue.Rule] = 0
+			ruleIDs = append(ruleIDs, issue.Rule)
+		}
+	}
+	sort.Strings(ruleIDs)
+
+	rules := make([]sarifRule, 0, len(ruleIDs))
+	for i, id := range ruleIDs {
+		ruleIndex[id] = i
+		rule, ok := known[id]
+		if !ok {
+			rule = Rule{ID: id, Description: id}
+		}
+		rules = append(rules, sarifRule{
+			ID:                   id,
+			Name:                 rule.Name,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Severity)},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(issues))
+	for _, issue := range issues {
+		results = append(results, sarifResult{
+			RuleID:    issue.Rule,
+			RuleIndex: ruleIndex[issue.Rule],
+			Level:     sarifLevel(issue.Severity),
+			Message:   sarifMessage{Text: issue.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: f.uri(issue.File)},
+					Region:           sarifRegion{StartLine: issue.Line, StartColumn: issue.Column},
+				},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "wetwire-k8s",
+				Version:        f.ToolVersion,
+				InformationURI: "https://github.com/lex00/wetwire-k8s-go",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+// uri returns the artifact location of a file, relative to BaseDir when it
+// is inside it, using forward slashes.
+func (f *SARIFFormatter) uri(file string) string {
+	return filepath.ToSlash(relativePath(f.BaseDir, file))
+}
+
+// relativePath returns file relative to base when base is set and contains
+// it, and file unchanged otherwise.
+func relativePath(base, file string) string {
+	if base == "" || !filepath.IsAbs(file) {
+		return file
+	}
+	if rel, err := filepath.Rel(base, file); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return file
+}
+
+// sarifLevel maps a severity to a SARIF result level.
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,102 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+func sampleReportResult() *LintResult {
+	return &LintResult{
+		Issues: []Issue{
+			{
+				Rule:     "WK8201",
+				Message:  "Container app has no resource limits",
+				File:     "/src/k8s/web.go",
+				Line:     14,
+				Column:   5,
+				Severity: SeverityWarning,
+			},
+			{
+				Rule:     "WK8006",
+				Message:  `Image "nginx:latest" uses :latest tag or no tag (defaults to :latest), specify a version tag`,
+				File:     "/src/k8s/web.go",
+				Line:     12,
+				Column:   14,
+				Severity: SeverityError,
+			},
+			{
+				Rule:     "WK8302",
+				Message:  "Deployment has 1 replica, consider at least 2",
+				File:     "/src/k8s/api.go",
+				Line:     8,
+				Column:   2,
+				Severity: SeverityInfo,
+			},
+		},
+		TotalFiles:      2,
+		FilesWithIssues: 2,
+		ErrorCount:      1,
+		WarningCount:    1,
+		InfoCount:       1,
+	}
+}
+
+func TestSARIFFormatter_Golden(t *testing.T) {
+	formatter := &SARIFFormatter{ToolVersion: "1.0.0", BaseDir: "/src"}
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(sampleReportResult(), &buf))
+
+	golden := filepath.Join("testdata", "report.sarif.golden")
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(golden, buf.Bytes(), 0644))
+	}
+
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestSARIFFormatter_Format(t *testing.T) {
+	t.Run("should reference rules by index", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, (&SARIFFormatter{}).Format(sampleReportResult(), &buf))
+
+		var log sarifLog
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+		require.Len(t, log.Runs, 1)
+
+		run := log.Runs[0]
+		require.Len(t, run.Results, 3)
+		for _, result := range run.Results {
+			assert.Equal(t, result.RuleID, run.Tool.Driver.Rules[result.RuleIndex].ID)
+		}
+	})
+
+	t.Run("should keep paths outside the base directory", func(t *testing.T) {
+		formatter := &SARIFFormatter{BaseDir: "/other"}
+		assert.Equal(t, "/src/k8s/web.go", formatter.uri("/src/k8s/web.go"))
+		assert.Equal(t, "k8s/web.go", (&SARIFFormatter{BaseDir: "/src"}).uri("/src/k8s/web.go"))
+	})
+
+	t.Run("should write empty results when there are no issues", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, (&SARIFFormatter{}).Format(&LintResult{}, &buf))
+
+		var raw map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &raw))
+		runs := raw["runs"].([]interface{})
+		run := runs[0].(map[string]interface{})
+		assert.Equal(t, []interface{}{}, run["results"])
+		assert.Equal(t, "2.1.0", raw["version"])
+	})
+}
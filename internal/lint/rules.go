@@ -1,7 +1,65 @@
 package lint
 
-// AllRules returns all available lint rules.
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   []Rule
+)
+
+func init() {
+	for _, rule := range builtinRules() {
+		Register(rule)
+	}
+}
+
+// Register adds a rule to the registry, so that linters created afterwards
+// run it. Programs embedding this package can register their own rules,
+// typically from an init function, and configure them like built-in rules:
+// DisabledRules and RuleSeverity apply to them by ID.
+//
+// Register panics if the rule has no ID or neither a Check nor a Visit
+// function, or if a rule with the same ID is already registered.
+func Register(rule Rule) {
+	if rule.ID == "" {
+		panic("lint: Register rule without an ID")
+	}
+	if rule.Check == nil && rule.Visit != nil {
+		rule.Check = visitorCheck(rule.Visit, rule.Nodes)
+	}
+	if rule.Check == nil {
+		panic(fmt.Sprintf("lint: Register rule %s without a Check function", rule.ID))
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, registered := range registry {
+		if strings.EqualFold(registered.ID, rule.ID) {
+			panic(fmt.Sprintf("lint: Register called twice for rule %s", rule.ID))
+		}
+	}
+	registry = append(registry, rule)
+}
+
+// Rules returns the registered rules: the built-in rules followed by custom
+// rules in the order they were registered.
+func Rules() []Rule {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return append([]Rule(nil), registry...)
+}
+
+// AllRules returns all available lint rules. It is the same as Rules.
 func AllRules() []Rule {
+	return Rules()
+}
+
+// builtinRules returns the rules that ship with wetwire-k8s.
+func builtinRules() []Rule {
 	return []Rule{
 		RuleWK8001(),
 		RuleWK8002(),
@@ -9,25 +67,55 @@ func AllRules() []Rule {
 		RuleWK8004(),
 		RuleWK8005(),
 		RuleWK8006(),
+		RuleWK8007(),
+		RuleWK8011(),
+		RuleWK8012(),
+		RuleWK8013(),
+		RuleWK8014(),
+		RuleWK8015(),
+		RuleWK8016(),
+		RuleWK8017(),
+		RuleWK8018(),
+		RuleWK8019(),
 		RuleWK8041(),
 		RuleWK8042(),
+		RuleWK8043(),
+		RuleWK8099(),
 		RuleWK8101(),
 		RuleWK8102(),
 		RuleWK8103(),
 		RuleWK8104(),
 		RuleWK8105(),
+		RuleWK8107(),
+		RuleWK8108(),
 		RuleWK8201(),
 		RuleWK8202(),
 		RuleWK8203(),
 		RuleWK8204(),
 		RuleWK8205(),
+		RuleWK8206(),
 		RuleWK8207(),
 		RuleWK8208(),
 		RuleWK8209(),
+		RuleWK8210(),
+		RuleWK8211(),
 		RuleWK8301(),
 		RuleWK8302(),
 		RuleWK8303(),
 		RuleWK8304(),
+		RuleWK8305(),
+		RuleWK8306(),
+		RuleWK8307(),
 		RuleWK8401(),
 	}
 }
+
+// RuleByID returns the rule with the given ID, ignoring case.
+func RuleByID(id string) (Rule, bool) {
+	for _, rule := range AllRules() {
+		if strings.EqualFold(rule.ID, id) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
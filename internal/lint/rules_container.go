@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"regexp"
 	"strings"
 )
 
@@ -14,14 +15,46 @@ func RuleWK8006() Rule {
 		Name:        "Flag :latest image tags",
 		Description: "Flag :latest image tags",
 		Severity:    SeverityError,
+		Rationale:   "The latest tag changes without notice, so the same manifest can deploy different code on each rollout and rollbacks do not restore the previous version.",
 		Check:       checkWK8006,
 		Fix:         nil, // No auto-fix available - user must specify version
+		Example: RuleExample{
+			Bad: `var Web = corev1.Container{
+	Name:  "web",
+	Image: "nginx:latest",
+}`,
+			Good: `var Web = corev1.Container{
+	Name:  "web",
+	Image: "nginx:1.25.3",
+}`,
+		},
 	}
 }
 
 func checkWK8006(file *ast.File, fset *token.FileSet) []Issue {
 	var issues []Issue
 
+	forEachContainerImage(file, func(lit *ast.BasicLit, imageValue string) {
+		// Check if the image value uses :latest
+		if strings.HasSuffix(imageValue, ":latest") || !strings.Contains(imageValue, ":") && !strings.Contains(imageValue, "@") {
+			pos := fset.Position(lit.Pos())
+			issues = append(issues, Issue{
+				Rule:     "WK8006",
+				Message:  fmt.Sprintf("Image %q uses :latest tag or no tag (defaults to :latest), specify a version tag", imageValue),
+				File:     pos.Filename,
+				Line:     pos.Line,
+				Column:   pos.Column,
+				Severity: SeverityError,
+			})
+		}
+	})
+
+	return issues
+}
+
+// forEachContainerImage calls fn with each string literal set as the Image
+// of a Container literal in file, and its unquoted value.
+func forEachContainerImage(file *ast.File, fn func(lit *ast.BasicLit, image string)) {
 	ast.Inspect(file, func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
@@ -45,25 +78,60 @@ func checkWK8006(file *ast.File, fset *token.FileSet) []Issue {
 				continue
 			}
 
-			// Check if the image value uses :latest
 			if lit, ok := kv.Value.(*ast.BasicLit); ok && lit.Kind == token.STRING {
-				imageValue := strings.Trim(lit.Value, `"`)
-				if strings.HasSuffix(imageValue, ":latest") || !strings.Contains(imageValue, ":") && !strings.Contains(imageValue, "@") {
-					pos := fset.Position(lit.Pos())
-					issues = append(issues, Issue{
-						Rule:     "WK8006",
-						Message:  fmt.Sprintf("Image %q uses :latest tag or no tag (defaults to :latest), specify a version tag", imageValue),
-						File:     pos.Filename,
-						Line:     pos.Line,
-						Column:   pos.Column,
-						Severity: SeverityError,
-					})
-				}
+				fn(lit, strings.Trim(lit.Value, `"`))
 			}
 		}
 
 		return true
 	})
+}
+
+// RuleWK8014 requires images to be pinned by digest. It is optional: teams
+// that deploy only digest-pinned images enable it with lint.enabled_rules.
+func RuleWK8014() Rule {
+	return Rule{
+		ID:          "WK8014",
+		Name:        "Pin images by digest",
+		Description: "Images should be pinned by digest (@sha256:...)",
+		Severity:    SeverityWarning,
+		Rationale:   "A tag can be moved to a different image at any time, while a digest always refers to the same image, so digest-pinned manifests deploy exactly what was reviewed and tested.",
+		Check:       checkWK8014,
+		Fix:         nil, // No auto-fix available - resolving a digest needs the registry
+		Optional:    true,
+		Example: RuleExample{
+			Bad: `var Web = corev1.Container{
+	Name:  "web",
+	Image: "nginx:1.25.3",
+}`,
+			Good: `var Web = corev1.Container{
+	Name:  "web",
+	Image: "nginx:1.25.3@sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31",
+}`,
+		},
+	}
+}
+
+// imageDigest matches the digest of an image reference pinned by digest.
+var imageDigest = regexp.MustCompile(`@sha256:[0-9a-f]{64}$`)
+
+func checkWK8014(file *ast.File, fset *token.FileSet) []Issue {
+	var issues []Issue
+
+	forEachContainerImage(file, func(lit *ast.BasicLit, imageValue string) {
+		if imageDigest.MatchString(imageValue) {
+			return
+		}
+		pos := fset.Position(lit.Pos())
+		issues = append(issues, Issue{
+			Rule:     "WK8014",
+			Message:  fmt.Sprintf("Image %q is not pinned by digest, use a reference ending in @sha256:<digest>", imageValue),
+			File:     pos.Filename,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Severity: SeverityWarning,
+		})
+	})
 
 	return issues
 }
@@ -75,15 +143,27 @@ func RuleWK8103() Rule {
 		Name:        "Container name required",
 		Description: "All containers must have a Name field",
 		Severity:    SeverityError,
-		Check:       checkWK8103,
+		Rationale:   "Kubernetes requires every container to have a name; it is used in logs, exec, probes and status.",
+		Check:       visitorCheck(visitWK8103, compositeLits),
+		Visit:       visitWK8103,
+		Nodes:       compositeLits,
 		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var Web = corev1.Container{
+	Image: "nginx:1.25.3",
+}`,
+			Good: `var Web = corev1.Container{
+	Name:  "web",
+	Image: "nginx:1.25.3",
+}`,
+		},
 	}
 }
 
-func checkWK8103(file *ast.File, fset *token.FileSet) []Issue {
+func visitWK8103(file *ast.File, fset *token.FileSet) Visitor {
 	var issues []Issue
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	visit := func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
@@ -133,9 +213,9 @@ func checkWK8103(file *ast.File, fset *token.FileSet) []Issue {
 		}
 
 		return true
-	})
+	}
 
-	return issues
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
 }
 
 // RuleWK8104 checks that container and service ports are named.
@@ -145,15 +225,26 @@ func RuleWK8104() Rule {
 		Name:        "Port name recommended",
 		Description: "Container and Service ports should be named",
 		Severity:    SeverityWarning,
-		Check:       checkWK8104,
+		Rationale:   "Named ports can be referenced by name from Services, probes and NetworkPolicies, so the port number can change in one place.",
+		Check:       visitorCheck(visitWK8104, compositeLits),
+		Visit:       visitWK8104,
+		Nodes:       compositeLits,
 		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var Ports = []corev1.ContainerPort{
+	{ContainerPort: 8080},
+}`,
+			Good: `var Ports = []corev1.ContainerPort{
+	{Name: "http", ContainerPort: 8080},
+}`,
+		},
 	}
 }
 
-func checkWK8104(file *ast.File, fset *token.FileSet) []Issue {
+func visitWK8104(file *ast.File, fset *token.FileSet) Visitor {
 	var issues []Issue
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	visit := func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
@@ -203,9 +294,9 @@ func checkWK8104(file *ast.File, fset *token.FileSet) []Issue {
 		}
 
 		return true
-	})
+	}
 
-	return issues
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
 }
 
 // getPortType returns the port type name if it's a port type.
@@ -237,15 +328,29 @@ func RuleWK8105() Rule {
 		Name:        "ImagePullPolicy explicit",
 		Description: "ImagePullPolicy should be explicitly set",
 		Severity:    SeverityWarning,
-		Check:       checkWK8105,
+		Rationale:   "The default pull policy depends on the image tag, which makes behavior on restarts hard to predict. Setting it explicitly documents the intent.",
+		Check:       visitorCheck(visitWK8105, compositeLits),
+		Visit:       visitWK8105,
+		Nodes:       compositeLits,
 		Fix:         nil, // Fix implemented in fixer.go
+		Example: RuleExample{
+			Bad: `var Web = corev1.Container{
+	Name:  "web",
+	Image: "nginx:1.25.3",
+}`,
+			Good: `var Web = corev1.Container{
+	Name:            "web",
+	Image:           "nginx:1.25.3",
+	ImagePullPolicy: corev1.PullIfNotPresent,
+}`,
+		},
 	}
 }
 
-func checkWK8105(file *ast.File, fset *token.FileSet) []Issue {
+func visitWK8105(file *ast.File, fset *token.FileSet) Visitor {
 	var issues []Issue
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	visit := func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
@@ -295,9 +400,9 @@ func checkWK8105(file *ast.File, fset *token.FileSet) []Issue {
 		}
 
 		return true
-	})
+	}
 
-	return issues
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
 }
 
 // RuleWK8201 checks for missing resource limits on containers.
@@ -307,15 +412,34 @@ func RuleWK8201() Rule {
 		Name:        "Missing resource limits",
 		Description: "Containers should have resource limits",
 		Severity:    SeverityWarning,
-		Check:       checkWK8201,
-		Fix:         nil,
+		Rationale:   "Without limits a container can use all the CPU and memory of its node, starving other workloads and getting other pods evicted.",
+		Check:       visitorCheck(visitWK8201, compositeLits),
+		Visit:       visitWK8201,
+		Nodes:       compositeLits,
+		Fix:         nil, // Fixed by Fixer.fixWK8201
+		Example: RuleExample{
+			Bad: `var Web = corev1.Container{
+	Name:  "web",
+	Image: "nginx:1.25.3",
+}`,
+			Good: `var Web = corev1.Container{
+	Name:  "web",
+	Image: "nginx:1.25.3",
+	Resources: corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+	},
+}`,
+		},
 	}
 }
 
-func checkWK8201(file *ast.File, fset *token.FileSet) []Issue {
+func visitWK8201(file *ast.File, fset *token.FileSet) Visitor {
 	var issues []Issue
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	visit := func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
@@ -375,11 +499,111 @@ func checkWK8201(file *ast.File, fset *token.FileSet) []Issue {
 		}
 
 		return true
-	})
+	}
+
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
+}
+
+// RuleWK8015 checks for emptyDir volumes without a size limit.
+func RuleWK8015() Rule {
+	return Rule{
+		ID:          "WK8015",
+		Name:        "EmptyDir size limit",
+		Description: "EmptyDir volumes should set SizeLimit",
+		Severity:    SeverityInfo,
+		Rationale:   "An emptyDir without a size limit can grow until it fills the node's disk, or its memory for a Memory-backed volume, and get other pods on the node evicted.",
+		Check:       checkWK8015,
+		Fix:         nil, // Fixed by Fixer.fixWK8015
+		Example: RuleExample{
+			Bad: `Volumes: []corev1.Volume{{
+	Name:         "scratch",
+	VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+}}`,
+			Good: `Volumes: []corev1.Volume{{
+	Name: "scratch",
+	VolumeSource: corev1.VolumeSource{
+		EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: ptr(resource.MustParse("1Gi"))},
+	},
+}}`,
+		},
+	}
+}
+
+func checkWK8015(file *ast.File, fset *token.FileSet) []Issue {
+	var issues []Issue
+
+	for _, emptyDir := range unboundedEmptyDirs(file) {
+		message := "EmptyDir volume should set SizeLimit, an unbounded emptyDir can fill the node's disk"
+		if emptyDir.name != "" {
+			message = fmt.Sprintf("EmptyDir volume %s should set SizeLimit, an unbounded emptyDir can fill the node's disk", emptyDir.name)
+		}
+
+		pos := fset.Position(emptyDir.lit.Pos())
+		issues = append(issues, Issue{
+			Rule:     "WK8015",
+			Message:  message,
+			File:     pos.Filename,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Severity: SeverityInfo,
+		})
+	}
 
 	return issues
 }
 
+// emptyDirVolume is an EmptyDirVolumeSource literal in a pod's volumes.
+type emptyDirVolume struct {
+	name string // Quoted volume name, or "" if not a string literal
+	lit  *ast.CompositeLit
+}
+
+// unboundedEmptyDirs returns the EmptyDirVolumeSource literals in the
+// Volumes of PodSpec literals in file that do not set SizeLimit. Volumes and
+// emptyDirs set from variables are not followed.
+func unboundedEmptyDirs(file *ast.File) []emptyDirVolume {
+	var emptyDirs []emptyDirVolume
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		compLit, ok := n.(*ast.CompositeLit)
+		if !ok || !isPodSpecType(compLit) {
+			return true
+		}
+
+		volumesLit := unwrapCompositeLit(getFieldValue(compLit, "Volumes"))
+		if volumesLit == nil {
+			return true
+		}
+
+		for _, volElt := range volumesLit.Elts {
+			volumeLit := unwrapCompositeLit(volElt)
+			if volumeLit == nil {
+				continue
+			}
+
+			sourceLit := unwrapCompositeLit(getFieldValue(volumeLit, "VolumeSource"))
+			if sourceLit == nil {
+				continue
+			}
+
+			emptyDirLit := unwrapCompositeLit(getFieldValue(sourceLit, "EmptyDir"))
+			if emptyDirLit == nil || getFieldValue(emptyDirLit, "SizeLimit") != nil {
+				continue
+			}
+
+			var name string
+			if lit, ok := getFieldValue(volumeLit, "Name").(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				name = lit.Value
+			}
+			emptyDirs = append(emptyDirs, emptyDirVolume{name: name, lit: emptyDirLit})
+		}
+
+		return true
+	})
+
+	return emptyDirs
+}
+
 // RuleWK8301 checks for missing health probes on containers.
 func RuleWK8301() Rule {
 	return Rule{
@@ -387,15 +611,38 @@ func RuleWK8301() Rule {
 		Name:        "Missing health probes",
 		Description: "Containers should have liveness and readiness probes",
 		Severity:    SeverityWarning,
-		Check:       checkWK8301,
+		Rationale:   "Without probes Kubernetes cannot tell a hung container from a healthy one: traffic is sent before the app is ready and deadlocked containers are never restarted.",
+		Check:       visitorCheck(visitWK8301, compositeLits),
+		Visit:       visitWK8301,
+		Nodes:       compositeLits,
 		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var Web = corev1.Container{
+	Name:  "web",
+	Image: "nginx:1.25.3",
+}`,
+			Good: `var Web = corev1.Container{
+	Name:  "web",
+	Image: "nginx:1.25.3",
+	LivenessProbe: &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromString("http")},
+		},
+	},
+	ReadinessProbe: &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Port: intstr.FromString("http")},
+		},
+	},
+}`,
+		},
 	}
 }
 
-func checkWK8301(file *ast.File, fset *token.FileSet) []Issue {
+func visitWK8301(file *ast.File, fset *token.FileSet) Visitor {
 	var issues []Issue
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	visit := func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
@@ -446,7 +693,7 @@ func checkWK8301(file *ast.File, fset *token.FileSet) []Issue {
 		}
 
 		return true
-	})
+	}
 
-	return issues
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
 }
@@ -1,6 +1,7 @@
 package lint
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
 )
@@ -12,15 +13,26 @@ func RuleWK8302() Rule {
 		Name:        "Replicas minimum",
 		Description: "Deployments should have at least 2 replicas for high availability",
 		Severity:    SeverityInfo,
-		Check:       checkWK8302,
+		Rationale:   "A single replica means downtime on every rollout, node drain or node failure.",
+		Check:       visitorCheck(visitWK8302, compositeLits),
+		Visit:       visitWK8302,
+		Nodes:       compositeLits,
 		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var Web = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{Replicas: ptr(int32(1))},
+}`,
+			Good: `var Web = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{Replicas: ptr(int32(3))},
+}`,
+		},
 	}
 }
 
-func checkWK8302(file *ast.File, fset *token.FileSet) []Issue {
+func visitWK8302(file *ast.File, fset *token.FileSet) Visitor {
 	var issues []Issue
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	visit := func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
@@ -83,6 +95,128 @@ func checkWK8302(file *ast.File, fset *token.FileSet) []Issue {
 		}
 
 		return true
+	}
+
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
+}
+
+// RuleWK8012 checks for fixed replicas on Deployments scaled by an HPA.
+func RuleWK8012() Rule {
+	return Rule{
+		ID:          "WK8012",
+		Name:        "Replicas with HPA",
+		Description: "Deployments targeted by a HorizontalPodAutoscaler should not set replicas",
+		Severity:    SeverityWarning,
+		Rationale:   "Every apply resets the replica count to the fixed value, undoing the autoscaler's scaling until it reacts again.",
+		Check:       checkWK8012,
+		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var Web = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{Replicas: ptr(int32(3))},
+}
+
+var WebHPA = autoscalingv2.HorizontalPodAutoscaler{
+	Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: Web.Name},
+	},
+}`,
+			Good: `var Web = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{Selector: webSelector},
+}
+
+var WebHPA = autoscalingv2.HorizontalPodAutoscaler{
+	Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: Web.Name},
+		MinReplicas:    ptr(int32(3)),
+	},
+}`,
+		},
+	}
+}
+
+func checkWK8012(file *ast.File, fset *token.FileSet) []Issue {
+	var issues []Issue
+
+	// First, collect the Deployments targeted by HPAs in the file, by metadata
+	// name for string references and by variable for Var.Name references
+	targetNames := make(map[string]bool)
+	targetVars := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		compLit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+
+		if getResourceType(compLit) != "HorizontalPodAutoscaler" {
+			return true
+		}
+
+		spec := unwrapCompositeLit(getFieldValue(compLit, "Spec"))
+		if spec == nil {
+			return true
+		}
+		ref := unwrapCompositeLit(getFieldValue(spec, "ScaleTargetRef"))
+		if ref == nil {
+			return true
+		}
+		if kind := stringLiteral(getFieldValue(ref, "Kind")); kind != "Deployment" {
+			return true
+		}
+
+		switch name := getFieldValue(ref, "Name").(type) {
+		case *ast.BasicLit:
+			if value := stringLiteral(name); value != "" {
+				targetNames[value] = true
+			}
+		case *ast.SelectorExpr:
+			if varName := selectorRoot(name); varName != "" {
+				targetVars[varName] = true
+			}
+		}
+		return true
+	})
+	if len(targetNames) == 0 && len(targetVars) == 0 {
+		return nil
+	}
+
+	// Then check the Deployments for replicas
+	forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+		compLit := unwrapCompositeLit(value)
+		if compLit == nil || getResourceType(compLit) != "Deployment" {
+			return
+		}
+
+		targeted := targetVars[varName]
+		if meta := metadataLiteral(compLit); meta != nil {
+			if name := stringLiteral(getFieldValue(meta, "Name")); name != "" && targetNames[name] {
+				targeted = true
+			}
+		}
+		if !targeted {
+			return
+		}
+
+		spec := unwrapCompositeLit(getFieldValue(compLit, "Spec"))
+		if spec == nil {
+			return
+		}
+		replicas := getFieldValue(spec, "Replicas")
+		if replicas == nil {
+			return
+		}
+		if ident, ok := replicas.(*ast.Ident); ok && ident.Name == "nil" {
+			return
+		}
+
+		pos := fset.Position(replicas.Pos())
+		issues = append(issues, Issue{
+			Rule:     "WK8012",
+			Message:  fmt.Sprintf("%s is scaled by a HorizontalPodAutoscaler; remove Replicas or set the initial count through the HPA's MinReplicas", varName),
+			File:     pos.Filename,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Severity: SeverityWarning,
+		})
 	})
 
 	return issues
@@ -95,8 +229,20 @@ func RuleWK8303() Rule {
 		Name:        "PodDisruptionBudget",
 		Description: "HA deployments should have a PodDisruptionBudget",
 		Severity:    SeverityInfo,
+		Rationale:   "Without a PodDisruptionBudget a node drain or cluster upgrade may evict all replicas at once.",
 		Check:       checkWK8303,
 		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var Web = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{Replicas: ptr(int32(3))},
+}`,
+			Good: `var WebPDB = policyv1.PodDisruptionBudget{
+	Spec: policyv1.PodDisruptionBudgetSpec{
+		MinAvailable: ptr(intstr.FromInt(2)),
+		Selector:     &metav1.LabelSelector{MatchLabels: webLabels},
+	},
+}`,
+		},
 	}
 }
 
@@ -273,15 +419,36 @@ func RuleWK8304() Rule {
 		Name:        "Anti-affinity recommended",
 		Description: "HA deployments should use pod anti-affinity",
 		Severity:    SeverityInfo,
-		Check:       checkWK8304,
+		Rationale:   "Without anti-affinity the scheduler may place every replica on the same node, so one node failure takes them all down.",
+		Check:       visitorCheck(visitWK8304, compositeLits),
+		Visit:       visitWK8304,
+		Nodes:       compositeLits,
 		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var WebPodSpec = corev1.PodSpec{
+	Containers: []corev1.Container{WebContainer},
+}`,
+			Good: `var WebPodSpec = corev1.PodSpec{
+	Affinity: &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+				Weight: 100,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: webLabels},
+					TopologyKey:   "kubernetes.io/hostname",
+				},
+			}},
+		},
+	},
+}`,
+		},
 	}
 }
 
-func checkWK8304(file *ast.File, fset *token.FileSet) []Issue {
+func visitWK8304(file *ast.File, fset *token.FileSet) Visitor {
 	var issues []Issue
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	visit := func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
@@ -315,9 +482,9 @@ func checkWK8304(file *ast.File, fset *token.FileSet) []Issue {
 		}
 
 		return true
-	})
+	}
 
-	return issues
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
 }
 
 // checkForPodAntiAffinity checks if a Deployment has PodAntiAffinity configured.
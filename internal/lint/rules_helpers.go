@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"strconv"
 	"strings"
 )
 
@@ -55,6 +56,22 @@ func getResourceType(compLit *ast.CompositeLit) string {
 	return ""
 }
 
+// getFieldValue returns the value of a keyed field in a composite literal,
+// or nil if the field is not set.
+func getFieldValue(compLit *ast.CompositeLit, name string) ast.Expr {
+	for _, elt := range compLit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+
+		if key, ok := kv.Key.(*ast.Ident); ok && key.Name == name {
+			return kv.Value
+		}
+	}
+	return nil
+}
+
 // extractMapLiteral extracts string key-value pairs from a map literal.
 func extractMapLiteral(expr ast.Expr) map[string]string {
 	result := make(map[string]string)
@@ -107,6 +124,24 @@ func isTrue(expr ast.Expr) bool {
 	return false
 }
 
+// isFalse checks if an expression evaluates to false.
+func isFalse(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name == "false"
+	case *ast.CallExpr:
+		// Check for ptrBool(false) pattern
+		if len(e.Args) == 1 {
+			if ident, ok := e.Args[0].(*ast.Ident); ok {
+				return ident.Name == "false"
+			}
+		}
+	case *ast.UnaryExpr:
+		return isFalse(e.X)
+	}
+	return false
+}
+
 // isPodSpecType checks if a composite literal is a PodSpec type.
 func isPodSpecType(compLit *ast.CompositeLit) bool {
 	if compLit.Type == nil {
@@ -167,3 +202,38 @@ func isK8sResourceType(expr ast.Expr) bool {
 
 	return false
 }
+
+// metadataLiteral returns the ObjectMeta (or Metadata) composite literal of a
+// resource, or nil if it is not set to a literal.
+func metadataLiteral(compLit *ast.CompositeLit) *ast.CompositeLit {
+	if meta := getFieldValue(compLit, "ObjectMeta"); meta != nil {
+		return unwrapCompositeLit(meta)
+	}
+	return unwrapCompositeLit(getFieldValue(compLit, "Metadata"))
+}
+
+// stringLiteral returns the value of a string literal, or "" if expr is not
+// one.
+func stringLiteral(expr ast.Expr) string {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// selectorRoot returns the variable at the root of a selector such as
+// Web.Name or Web.ObjectMeta.Name, or "" if it is not an identifier.
+func selectorRoot(sel *ast.SelectorExpr) string {
+	switch x := sel.X.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.SelectorExpr:
+		return selectorRoot(x)
+	}
+	return ""
+}
@@ -0,0 +1,352 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RuleWK8007 checks that metadata.Name is consistent with the Go variable name.
+func RuleWK8007() Rule {
+	return Rule{
+		ID:          "WK8007",
+		Name:        "Metadata name matches variable",
+		Description: "metadata.Name should be consistent with the Go variable name",
+		Severity:    SeverityWarning,
+		Rationale:   "A name that has nothing to do with its variable is usually a copy-paste bug, and two resources may end up with the same name.",
+		Check:       checkWK8007,
+		Fix:         nil, // Fixed by Fixer.fixWK8007
+		Example: RuleExample{
+			Bad: `var WebAppDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "api-server"},
+}`,
+			Good: `var WebAppDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-app"},
+}`,
+		},
+	}
+}
+
+func checkWK8007(file *ast.File, fset *token.FileSet) []Issue {
+	var issues []Issue
+
+	forEachMetadataName(file, func(varName, kind string, lit *ast.BasicLit) {
+		name, err := strconv.Unquote(lit.Value)
+		if err != nil || nameMatchesVar(name, varName, kind) {
+			return
+		}
+
+		pos := fset.Position(lit.Pos())
+		issues = append(issues, Issue{
+			Rule:     "WK8007",
+			Message:  fmt.Sprintf("Resource %s has metadata name %q, expected something like %q", varName, name, expectedResourceName(varName, kind)),
+			File:     pos.Filename,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Severity: SeverityWarning,
+		})
+	})
+
+	return issues
+}
+
+// RuleWK8011 checks for hardcoded names that refer to resources declared in
+// the same file.
+func RuleWK8011() Rule {
+	return Rule{
+		ID:          "WK8011",
+		Name:        "Direct resource references",
+		Description: "Reference resources declared in the same package directly instead of by name",
+		Severity:    SeverityWarning,
+		Rationale:   "Direct references keep names in one place and let wetwire order resources by dependency. A renamed ConfigMap cannot silently leave a Deployment pointing at the old name.",
+		Check:       checkWK8011,
+		Fix:         nil, // Fixed by Fixer.fixWK8011
+		Example: RuleExample{
+			Bad: `var ConfigSource = corev1.ConfigMapEnvSource{
+	LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"},
+}`,
+			Good: `var ConfigSource = corev1.ConfigMapEnvSource{
+	LocalObjectReference: corev1.LocalObjectReference{Name: AppConfig.Name},
+}`,
+		},
+	}
+}
+
+func checkWK8011(file *ast.File, fset *token.FileSet) []Issue {
+	var issues []Issue
+
+	for _, ref := range findStringReferences(file) {
+		pos := fset.Position(ref.lit.Pos())
+		issues = append(issues, Issue{
+			Rule:     "WK8011",
+			Message:  fmt.Sprintf("%s %s refers to %s by name, use %s.Name instead", ref.field, ref.lit.Value, ref.target, ref.target),
+			File:     pos.Filename,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Severity: SeverityWarning,
+		})
+	}
+
+	return issues
+}
+
+// stringReference is a string literal naming another resource in the file.
+type stringReference struct {
+	lit    *ast.BasicLit
+	field  string // Field holding the literal, e.g. "SecretName"
+	target string // Variable of the referenced resource
+}
+
+// referenceFields maps fields that hold the name of another object to the
+// kinds they can refer to.
+var referenceFields = map[string][]string{
+	"SecretName":         {"Secret"},
+	"ClaimName":          {"PersistentVolumeClaim"},
+	"ServiceName":        {"Service"},
+	"ServiceAccountName": {"ServiceAccount"},
+}
+
+// nameReferenceTypes maps types whose Name field refers to another object to
+// the kinds it can refer to. A nil kind list accepts any kind.
+var nameReferenceTypes = map[string][]string{
+	"LocalObjectReference":        nil,
+	"ObjectReference":             nil,
+	"CrossVersionObjectReference": nil,
+	"RoleRef":                     {"Role", "ClusterRole"},
+	"Subject":                     {"ServiceAccount"},
+	"ConfigMapKeySelector":        {"ConfigMap"},
+	"ConfigMapEnvSource":          {"ConfigMap"},
+	"ConfigMapVolumeSource":       {"ConfigMap"},
+	"ConfigMapProjection":         {"ConfigMap"},
+	"SecretKeySelector":           {"Secret"},
+	"SecretEnvSource":             {"Secret"},
+	"SecretProjection":            {"Secret"},
+}
+
+// findStringReferences finds string literals in reference fields that match
+// the metadata name of exactly one other resource declared in the file. It
+// runs in two passes: first collecting resource names, then checking the
+// references in each resource.
+func findStringReferences(file *ast.File) []stringReference {
+	// First pass: metadata name -> declaring variables and their kinds
+	type declared struct {
+		varName string
+		kind    string
+	}
+	byName := make(map[string][]declared)
+	forEachMetadataName(file, func(varName, kind string, lit *ast.BasicLit) {
+		if name, err := strconv.Unquote(lit.Value); err == nil {
+			byName[name] = append(byName[name], declared{varName, kind})
+		}
+	})
+	if len(byName) == 0 {
+		return nil
+	}
+
+	// Second pass: reference fields holding one of those names
+	var refs []stringReference
+	forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+		ast.Inspect(value, func(n ast.Node) bool {
+			compLit, ok := n.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+
+			for _, elt := range compLit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+
+				key, ok := kv.Key.(*ast.Ident)
+				if !ok {
+					continue
+				}
+
+				kinds, isRefField := referenceFields[key.Name]
+				if !isRefField && key.Name == "Name" {
+					kinds, isRefField = nameReferenceTypes[getResourceType(compLit)]
+				}
+				if !isRefField {
+					continue
+				}
+
+				lit, ok := kv.Value.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				name, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					continue
+				}
+
+				// Only rewrite references that resolve to a single other resource
+				var matches []string
+				for _, d := range byName[name] {
+					if d.varName != varName && (kinds == nil || containsString(kinds, d.kind)) {
+						matches = append(matches, d.varName)
+					}
+				}
+				if len(matches) == 1 {
+					refs = append(refs, stringReference{lit: lit, field: key.Name, target: matches[0]})
+				}
+			}
+
+			return true
+		})
+	})
+
+	return refs
+}
+
+// forEachTopLevelValue calls fn for every initialized top-level variable.
+func forEachTopLevelValue(file *ast.File, fn func(varName string, value ast.Expr)) {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+
+			for i, name := range valueSpec.Names {
+				if name.Name != "_" && i < len(valueSpec.Values) {
+					fn(name.Name, valueSpec.Values[i])
+				}
+			}
+		}
+	}
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// forEachMetadataName calls fn for every top-level resource whose
+// ObjectMeta.Name is a string literal. Names set from variables or constants
+// are skipped.
+func forEachMetadataName(file *ast.File, fn func(varName, kind string, lit *ast.BasicLit)) {
+	forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+		compLit := unwrapCompositeLit(value)
+		if compLit == nil {
+			return
+		}
+
+		if lit := objectMetaNameLiteral(compLit); lit != nil {
+			fn(varName, getResourceType(compLit), lit)
+		}
+	})
+}
+
+// objectMetaNameLiteral returns the ObjectMeta.Name string literal of a
+// resource composite literal, or nil if it is not set to a literal.
+func objectMetaNameLiteral(compLit *ast.CompositeLit) *ast.BasicLit {
+	for _, elt := range compLit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok || key.Name != "ObjectMeta" {
+			continue
+		}
+
+		metaLit := unwrapCompositeLit(kv.Value)
+		if metaLit == nil {
+			return nil
+		}
+
+		for _, metaElt := range metaLit.Elts {
+			metaKV, ok := metaElt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			if metaKey, ok := metaKV.Key.(*ast.Ident); ok && metaKey.Name == "Name" {
+				if lit, ok := metaKV.Value.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					return lit
+				}
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+// nameMatchesVar reports whether a metadata name is consistent with the
+// variable it is declared on. Separators and case are ignored, and either
+// name may extend the other, so WebAppDeployment accepts "web-app", "webapp",
+// "web" and "web-app-v2" but not "api-server".
+func nameMatchesVar(name, varName, kind string) bool {
+	normalized := normalizeName(name)
+	if normalized == "" {
+		return true
+	}
+
+	full := normalizeName(varName)
+	base := normalizeName(trimKind(varName, kind))
+
+	return strings.Contains(full, normalized) || strings.Contains(normalized, base)
+}
+
+// expectedResourceName derives a Kubernetes name from a variable name, with
+// the kind suffix removed (e.g. WebAppDeployment -> web-app).
+func expectedResourceName(varName, kind string) string {
+	return toKebabCase(trimKind(varName, kind))
+}
+
+// trimKind removes a trailing kind from a variable name, unless nothing
+// would be left.
+func trimKind(varName, kind string) string {
+	if trimmed := strings.TrimSuffix(varName, kind); trimmed != "" {
+		return trimmed
+	}
+	return varName
+}
+
+// normalizeName lowercases a name and drops everything except letters and digits.
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// toKebabCase converts a Go identifier to a lowercase, hyphenated name,
+// keeping acronyms together (e.g. WebAppHPA -> web-app-hpa).
+func toKebabCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteRune('-')
+			}
+		}
+		if r == '_' {
+			r = '-'
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,342 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// RuleWK8099 checks that NetworkPolicies declare their PolicyTypes explicitly.
+func RuleWK8099() Rule {
+	return Rule{
+		ID:          "WK8099",
+		Name:        "NetworkPolicy policy types",
+		Description: "NetworkPolicies should set PolicyTypes explicitly",
+		Severity:    SeverityInfo,
+		Rationale:   "When PolicyTypes is omitted, Kubernetes always applies Ingress and only applies Egress if egress rules are present. Listing the types makes the policy's intent clear.",
+		Check:       visitorCheck(visitWK8099, compositeLits),
+		Visit:       visitWK8099,
+		Nodes:       compositeLits,
+		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var DenyAll = networkingv1.NetworkPolicy{
+	Spec: networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{},
+	},
+}`,
+			Good: `var DenyAll = networkingv1.NetworkPolicy{
+	Spec: networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+	},
+}`,
+		},
+	}
+}
+
+func visitWK8099(file *ast.File, fset *token.FileSet) Visitor {
+	var issues []Issue
+
+	visit := func(n ast.Node) bool {
+		compLit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+
+		if getResourceType(compLit) != "NetworkPolicy" {
+			return true
+		}
+
+		hasPolicyTypes := false
+		for _, elt := range compLit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok || key.Name != "Spec" {
+				continue
+			}
+
+			specLit := unwrapCompositeLit(kv.Value)
+			if specLit == nil {
+				continue
+			}
+
+			for _, specElt := range specLit.Elts {
+				specKV, ok := specElt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+
+				if specKey, ok := specKV.Key.(*ast.Ident); ok && specKey.Name == "PolicyTypes" {
+					hasPolicyTypes = true
+				}
+			}
+		}
+
+		// Without PolicyTypes, Kubernetes infers Egress only when egress rules
+		// are present, which makes the policy's intent easy to misread.
+		if !hasPolicyTypes {
+			pos := fset.Position(compLit.Pos())
+			issues = append(issues, Issue{
+				Rule:     "WK8099",
+				Message:  "NetworkPolicy should set PolicyTypes explicitly (Ingress and/or Egress)",
+				File:     pos.Filename,
+				Line:     pos.Line,
+				Column:   pos.Column,
+				Severity: SeverityInfo,
+			})
+		}
+
+		return true
+	}
+
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
+}
+
+// RuleWK8306 checks that Service selectors match a pod template in the file.
+func RuleWK8306() Rule {
+	return Rule{
+		ID:          "WK8306",
+		Name:        "Service selector matches pods",
+		Description: "Service selectors should match the pod template labels of a workload in the same file",
+		Severity:    SeverityWarning,
+		Rationale:   "A Service whose selector matches no pods has no endpoints, so connections to it fail even though it applies cleanly.",
+		Check:       checkWK8306,
+		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var webLabels = map[string]string{"app": "web"}
+
+var Web = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: webLabels}},
+	},
+}
+
+var WebService = corev1.Service{
+	Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "frontend"}},
+}`,
+			Good: `var WebService = corev1.Service{
+	Spec: corev1.ServiceSpec{Selector: webLabels},
+}`,
+		},
+	}
+}
+
+// podTemplateKinds are the kinds whose Spec.Template holds pod labels.
+var podTemplateKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+	"ReplicaSet":  true,
+}
+
+func checkWK8306(file *ast.File, fset *token.FileSet) []Issue {
+	var issues []Issue
+
+	values := make(map[string]ast.Expr)
+	forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+		values[varName] = value
+	})
+
+	// First, collect the pod labels of every workload in the file. If any of
+	// them cannot be resolved statically, a selector might match them, so
+	// nothing is reported.
+	var podLabels []map[string]string
+	resolved := true
+	forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+		compLit := unwrapCompositeLit(value)
+		if compLit == nil {
+			return
+		}
+
+		var meta ast.Expr
+		switch kind := getResourceType(compLit); {
+		case kind == "Pod", kind == "PodTemplateSpec":
+			meta = objectMetaField(compLit)
+		case podTemplateKinds[kind]:
+			spec := getFieldValue(compLit, "Spec")
+			if spec == nil {
+				return
+			}
+			specLit := resolveCompositeLit(spec, values)
+			if specLit == nil {
+				resolved = false
+				return
+			}
+			template := getFieldValue(specLit, "Template")
+			if template == nil {
+				return
+			}
+			templateLit := resolveCompositeLit(template, values)
+			if templateLit == nil {
+				resolved = false
+				return
+			}
+			meta = objectMetaField(templateLit)
+		default:
+			return
+		}
+
+		labels, ok := resolveObjectLabels(meta, values)
+		if !ok {
+			resolved = false
+			return
+		}
+		podLabels = append(podLabels, labels)
+	})
+	if !resolved || len(podLabels) == 0 {
+		return nil
+	}
+
+	// Then check each Service selector against them
+	forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+		compLit := unwrapCompositeLit(value)
+		if compLit == nil || getResourceType(compLit) != "Service" {
+			return
+		}
+
+		spec := resolveCompositeLit(getFieldValue(compLit, "Spec"), values)
+		if spec == nil || isHeadlessOrExternalName(spec) {
+			return
+		}
+
+		// Services without a selector have manually managed endpoints
+		selectorExpr := getFieldValue(spec, "Selector")
+		if selectorExpr == nil {
+			return
+		}
+		selector, ok := resolveLabelMap(selectorExpr, values)
+		if !ok || len(selector) == 0 {
+			return
+		}
+
+		for _, labels := range podLabels {
+			if labelsMatch(selector, labels) {
+				return
+			}
+		}
+
+		pos := fset.Position(selectorExpr.Pos())
+		issues = append(issues, Issue{
+			Rule:     "WK8306",
+			Message:  fmt.Sprintf("Service %s selector %s matches no pod template labels in this file", varName, formatLabels(selector)),
+			File:     pos.Filename,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Severity: SeverityWarning,
+		})
+	})
+
+	return issues
+}
+
+// isHeadlessOrExternalName reports whether a ServiceSpec literal sets
+// ClusterIP to None or Type to ExternalName.
+func isHeadlessOrExternalName(spec *ast.CompositeLit) bool {
+	matches := func(expr ast.Expr, constant, value string) bool {
+		if sel, ok := expr.(*ast.SelectorExpr); ok {
+			return sel.Sel.Name == constant
+		}
+		return expr != nil && stringLiteral(expr) == value
+	}
+	return matches(getFieldValue(spec, "ClusterIP"), "ClusterIPNone", "None") ||
+		matches(getFieldValue(spec, "Type"), "ServiceTypeExternalName", "ExternalName")
+}
+
+// resolveCompositeLit returns the composite literal expr evaluates to,
+// following identifiers of top-level variables in values, or nil if it is
+// not a literal.
+func resolveCompositeLit(expr ast.Expr, values map[string]ast.Expr) *ast.CompositeLit {
+	// Bound the hops so that self-referencing declarations terminate
+	for range 10 {
+		if compLit := unwrapCompositeLit(expr); compLit != nil {
+			return compLit
+		}
+		ident, ok := expr.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		if expr, ok = values[ident.Name]; !ok {
+			return nil
+		}
+	}
+	return nil
+}
+
+// objectMetaField returns the ObjectMeta (or Metadata) field of a literal, or
+// nil if it is not set.
+func objectMetaField(compLit *ast.CompositeLit) ast.Expr {
+	if meta := getFieldValue(compLit, "ObjectMeta"); meta != nil {
+		return meta
+	}
+	return getFieldValue(compLit, "Metadata")
+}
+
+// resolveObjectLabels returns the labels of an ObjectMeta expression. An
+// unset ObjectMeta or Labels field has no labels.
+func resolveObjectLabels(meta ast.Expr, values map[string]ast.Expr) (map[string]string, bool) {
+	if meta == nil {
+		return map[string]string{}, true
+	}
+	metaLit := resolveCompositeLit(meta, values)
+	if metaLit == nil {
+		return nil, false
+	}
+	labels := getFieldValue(metaLit, "Labels")
+	if labels == nil {
+		return map[string]string{}, true
+	}
+	return resolveLabelMap(labels, values)
+}
+
+// resolveLabelMap returns the entries of a map[string]string literal,
+// following identifiers of top-level variables in values. It reports false if
+// the map or any of its entries is not a string literal.
+func resolveLabelMap(expr ast.Expr, values map[string]ast.Expr) (map[string]string, bool) {
+	compLit := resolveCompositeLit(expr, values)
+	if compLit == nil {
+		return nil, false
+	}
+
+	labels := make(map[string]string, len(compLit.Elts))
+	for _, elt := range compLit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, false
+		}
+		key, value := stringLiteral(kv.Key), stringLiteral(kv.Value)
+		if key == "" {
+			return nil, false
+		}
+		if _, isLit := kv.Value.(*ast.BasicLit); !isLit {
+			return nil, false
+		}
+		labels[key] = value
+	}
+	return labels, true
+}
+
+// labelsMatch reports whether labels contains every entry of selector.
+func labelsMatch(selector, labels map[string]string) bool {
+	for key, value := range selector {
+		if got, ok := labels[key]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
+
+// formatLabels formats labels as sorted key=value pairs.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -16,8 +16,15 @@ func RuleWK8401() Rule {
 		Name:        "File size limits",
 		Description: "Files should not exceed 20 resources",
 		Severity:    SeverityWarning,
+		Rationale:   "Large files are hard to navigate and review. Splitting resources by concern (networking, compute, storage) keeps each file focused.",
 		Check:       checkWK8401,
 		Fix:         nil, // No auto-fix available
+		Example: RuleExample{
+			Bad: `// all.go: 35 resources for every component of the app`,
+			Good: `// network.go: Services, Ingresses and NetworkPolicies
+// compute.go: Deployments and their ConfigMaps
+// storage.go: PersistentVolumeClaims`,
+		},
 	}
 }
 
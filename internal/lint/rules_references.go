@@ -0,0 +1,694 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// builtinConfigRefs are ConfigMaps and Secrets that Kubernetes creates in
+// every namespace, keyed by kind.
+var builtinConfigRefs = map[string][]string{
+	"ConfigMap": {"kube-root-ca.crt"},
+}
+
+// RuleWK8107 checks that the ConfigMaps and Secrets pods refer to by name
+// are defined in the package.
+func RuleWK8107() Rule {
+	rule := configRefsRule(nil)
+	rule.Configure = func(config *Config) Rule {
+		return configRefsRule(config.ExternalConfigRefs)
+	}
+	return rule
+}
+
+// configRefsRule returns WK8107 accepting the given externally managed
+// ConfigMap and Secret names as defined.
+func configRefsRule(external []string) Rule {
+	checkPackage := func(files []*ast.File, fset *token.FileSet) []Issue {
+		return checkWK8107(files, fset, external)
+	}
+	return Rule{
+		ID:          "WK8107",
+		Name:        "Config references defined",
+		Description: "ConfigMaps and Secrets referenced by name should be defined in the package",
+		Severity:    SeverityWarning,
+		Rationale:   "A pod that refers to a ConfigMap or Secret that does not exist never starts: it is stuck in CreateContainerConfigError or ContainerCreating. A typo in the name applies cleanly and only fails at runtime. Names managed outside the package can be listed in lint.external_config_refs in .wetwire.yaml.",
+		Check: func(file *ast.File, fset *token.FileSet) []Issue {
+			return checkPackage([]*ast.File{file}, fset)
+		},
+		CheckPackage: checkPackage,
+		Fix:          nil,
+		Example: RuleExample{
+			Bad: `var AppConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+}
+
+var AppEnv = corev1.EnvFromSource{
+	ConfigMapRef: &corev1.ConfigMapEnvSource{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "app-confg"},
+	},
+}`,
+			Good: `var AppEnv = corev1.EnvFromSource{
+	ConfigMapRef: &corev1.ConfigMapEnvSource{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"},
+	},
+}`,
+		},
+	}
+}
+
+// configRefType describes a type that refers to a ConfigMap or Secret by
+// name: the kind it refers to and the field holding the name.
+type configRefType struct {
+	kind  string
+	field string
+}
+
+// configRefTypes are the types that refer to a ConfigMap or Secret by name.
+var configRefTypes = map[string]configRefType{
+	"ConfigMapKeySelector":  {"ConfigMap", "Name"},
+	"ConfigMapEnvSource":    {"ConfigMap", "Name"},
+	"ConfigMapVolumeSource": {"ConfigMap", "Name"},
+	"ConfigMapProjection":   {"ConfigMap", "Name"},
+	"SecretKeySelector":     {"Secret", "Name"},
+	"SecretEnvSource":       {"Secret", "Name"},
+	"SecretProjection":      {"Secret", "Name"},
+	"SecretVolumeSource":    {"Secret", "SecretName"},
+}
+
+func checkWK8107(files []*ast.File, fset *token.FileSet, external []string) []Issue {
+	var issues []Issue
+	consts := stringValues(files)
+
+	// First, collect the names of the ConfigMaps and Secrets in the package.
+	// If the name of one cannot be resolved statically, any reference to that
+	// kind might be to it, so references to the kind are not checked.
+	defined := map[string]map[string]bool{
+		"ConfigMap": make(map[string]bool),
+		"Secret":    make(map[string]bool),
+	}
+	unresolved := make(map[string]bool)
+	for _, file := range files {
+		forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+			compLit := unwrapCompositeLit(value)
+			if compLit == nil {
+				return
+			}
+			kind := getResourceType(compLit)
+			if defined[kind] == nil {
+				return
+			}
+
+			var name string
+			if meta := metadataLiteral(compLit); meta != nil {
+				name = resolveStringValue(getFieldValue(meta, "Name"), consts)
+			}
+			if name == "" {
+				unresolved[kind] = true
+				return
+			}
+			defined[kind][name] = true
+		})
+	}
+	for kind, names := range builtinConfigRefs {
+		for _, name := range names {
+			defined[kind][name] = true
+		}
+	}
+	for _, name := range external {
+		defined["ConfigMap"][name] = true
+		defined["Secret"][name] = true
+	}
+
+	// Then check every reference by name. References through another
+	// resource's variable, such as AppConfig.Name, are defined by construction
+	for _, file := range files {
+		forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+			ast.Inspect(value, func(n ast.Node) bool {
+				compLit, ok := n.(*ast.CompositeLit)
+				if !ok {
+					return true
+				}
+				ref, ok := configRefTypes[getResourceType(compLit)]
+				if !ok || unresolved[ref.kind] {
+					return true
+				}
+				if optional := getFieldValue(compLit, "Optional"); optional != nil && isTrue(optional) {
+					return true
+				}
+
+				nameExpr := refNameExpr(compLit, ref.field)
+				name := resolveStringValue(nameExpr, consts)
+				if name == "" || defined[ref.kind][name] {
+					return true
+				}
+
+				pos := fset.Position(nameExpr.Pos())
+				issues = append(issues, Issue{
+					Rule:     "WK8107",
+					Message:  fmt.Sprintf("%s refers to %s %q, which is not defined in the package", varName, ref.kind, name),
+					File:     pos.Filename,
+					Line:     pos.Line,
+					Column:   pos.Column,
+					Severity: SeverityWarning,
+				})
+				return true
+			})
+		})
+	}
+
+	return issues
+}
+
+// refNameExpr returns the value of the name field of a reference literal,
+// looking through an embedded LocalObjectReference, or nil if it is not set.
+func refNameExpr(compLit *ast.CompositeLit, field string) ast.Expr {
+	if value := getFieldValue(compLit, field); value != nil {
+		return value
+	}
+	if inner := unwrapCompositeLit(getFieldValue(compLit, "LocalObjectReference")); inner != nil {
+		return getFieldValue(inner, field)
+	}
+	return nil
+}
+
+// stringValues maps the top-level constants and variables of files that are
+// set to a string literal to their values.
+func stringValues(files []*ast.File) map[string]string {
+	values := make(map[string]string)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || (genDecl.Tok != token.CONST && genDecl.Tok != token.VAR) {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for i, name := range valueSpec.Names {
+					if i < len(valueSpec.Values) {
+						if value := stringLiteral(valueSpec.Values[i]); value != "" {
+							values[name.Name] = value
+						}
+					}
+				}
+			}
+		}
+	}
+	return values
+}
+
+// resolveStringValue returns the value of a string literal, or of an
+// identifier naming a string constant or variable in values. It returns ""
+// for other expressions.
+func resolveStringValue(expr ast.Expr, values map[string]string) string {
+	if ident, ok := expr.(*ast.Ident); ok {
+		return values[ident.Name]
+	}
+	return stringLiteral(expr)
+}
+
+// RuleWK8108 checks that Ingress backends refer to Services and ports
+// defined in the package.
+func RuleWK8108() Rule {
+	return Rule{
+		ID:          "WK8108",
+		Name:        "Ingress backend defined",
+		Description: "Ingress backends should refer to a Service and port defined in the package",
+		Severity:    SeverityError,
+		Rationale:   "An Ingress whose backend names a missing Service or port applies cleanly, but the controller has nowhere to send the traffic and answers with 503s. Renaming a Service or its port without updating the Ingress breaks routing silently.",
+		Check: func(file *ast.File, fset *token.FileSet) []Issue {
+			return checkWK8108([]*ast.File{file}, fset)
+		},
+		CheckPackage: checkWK8108,
+		Fix:          nil,
+		Example: RuleExample{
+			Bad: `var Web = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "web"},
+	Spec: corev1.ServiceSpec{
+		Ports: []corev1.ServicePort{{Name: "http", Port: 80}},
+	},
+}
+
+var WebBackend = networkingv1.IngressBackend{
+	Service: &networkingv1.IngressServiceBackend{
+		Name: "web",
+		Port: networkingv1.ServiceBackendPort{Name: "https"},
+	},
+}`,
+			Good: `var WebBackend = networkingv1.IngressBackend{
+	Service: &networkingv1.IngressServiceBackend{
+		Name: Web.Name,
+		Port: networkingv1.ServiceBackendPort{Name: "http"},
+	},
+}`,
+		},
+	}
+}
+
+// servicePorts are the ports a Service declares, by name and number. Ports
+// that cannot be resolved statically leave known false.
+type servicePorts struct {
+	known   bool
+	names   map[string]bool
+	numbers map[int64]bool
+}
+
+func checkWK8108(files []*ast.File, fset *token.FileSet) []Issue {
+	var issues []Issue
+	consts := stringValues(files)
+
+	// First, collect the Services in the package by name, and the names of
+	// the variables declaring them. If the name of one cannot be resolved
+	// statically, a backend might refer to it, so missing Services are not
+	// reported.
+	services := make(map[string]*servicePorts)
+	serviceVars := make(map[string]string)
+	unresolved := false
+	for _, file := range files {
+		forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+			compLit := unwrapCompositeLit(value)
+			if compLit == nil || getResourceType(compLit) != "Service" {
+				return
+			}
+
+			var name string
+			if meta := metadataLiteral(compLit); meta != nil {
+				name = resolveStringValue(getFieldValue(meta, "Name"), consts)
+			}
+			if name == "" {
+				unresolved = true
+				return
+			}
+			services[name] = collectServicePorts(compLit, consts)
+			serviceVars[varName] = name
+		})
+	}
+
+	// Then check the Service and port of every backend
+	for _, file := range files {
+		forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+			ast.Inspect(value, func(n ast.Node) bool {
+				compLit, ok := n.(*ast.CompositeLit)
+				if !ok || getResourceType(compLit) != "IngressServiceBackend" {
+					return true
+				}
+
+				nameExpr := getFieldValue(compLit, "Name")
+				name := resolveStringValue(nameExpr, consts)
+				if sel, ok := nameExpr.(*ast.SelectorExpr); ok && sel.Sel.Name == "Name" {
+					name = serviceVars[selectorRoot(sel)]
+				}
+				if name == "" {
+					return true
+				}
+
+				var message string
+				ports, defined := services[name]
+				switch {
+				case !defined && !unresolved:
+					message = fmt.Sprintf("%s routes to Service %q, which is not defined in the package", varName, name)
+				case defined && ports.known:
+					message = missingBackendPort(compLit, ports, name, varName, consts)
+				}
+				if message == "" {
+					return true
+				}
+
+				pos := fset.Position(nameExpr.Pos())
+				issues = append(issues, Issue{
+					Rule:     "WK8108",
+					Message:  message,
+					File:     pos.Filename,
+					Line:     pos.Line,
+					Column:   pos.Column,
+					Severity: SeverityError,
+				})
+				return true
+			})
+		})
+	}
+
+	return issues
+}
+
+// collectServicePorts returns the ports of a Service literal.
+func collectServicePorts(service *ast.CompositeLit, consts map[string]string) *servicePorts {
+	ports := &servicePorts{names: make(map[string]bool), numbers: make(map[int64]bool)}
+
+	spec := unwrapCompositeLit(getFieldValue(service, "Spec"))
+	if spec == nil {
+		return ports
+	}
+	list := unwrapCompositeLit(getFieldValue(spec, "Ports"))
+	if list == nil {
+		return ports
+	}
+	for _, elt := range list.Elts {
+		port := unwrapCompositeLit(elt)
+		if port == nil {
+			return ports
+		}
+		if nameExpr := getFieldValue(port, "Name"); nameExpr != nil {
+			name := resolveStringValue(nameExpr, consts)
+			if name == "" {
+				return ports
+			}
+			ports.names[name] = true
+		}
+		number := extractIntValue(getFieldValue(port, "Port"))
+		if number < 0 {
+			return ports
+		}
+		ports.numbers[number] = true
+	}
+	ports.known = true
+	return ports
+}
+
+// missingBackendPort returns a message if the port of a backend is not one
+// of the Service's ports, or "" if it is or cannot be resolved.
+func missingBackendPort(backend *ast.CompositeLit, ports *servicePorts, service, varName string, consts map[string]string) string {
+	port := unwrapCompositeLit(getFieldValue(backend, "Port"))
+	if port == nil {
+		return ""
+	}
+	if nameExpr := getFieldValue(port, "Name"); nameExpr != nil {
+		if name := resolveStringValue(nameExpr, consts); name != "" && !ports.names[name] {
+			return fmt.Sprintf("%s routes to port %q of Service %q, which has no port with that name", varName, name, service)
+		}
+		return ""
+	}
+	if number := extractIntValue(getFieldValue(port, "Number")); number >= 0 && !ports.numbers[number] {
+		return fmt.Sprintf("%s routes to port %d of Service %q, which has no such port", varName, number, service)
+	}
+	return ""
+}
+
+// RuleWK8016 checks that Service target ports are exposed by the containers
+// of the workloads the Service selects.
+func RuleWK8016() Rule {
+	return Rule{
+		ID:          "WK8016",
+		Name:        "Service target port exposed",
+		Description: "Service target ports should be container ports of the pods the Service selects",
+		Severity:    SeverityWarning,
+		Rationale:   "A Service forwards traffic to its targetPort whether or not a container listens on it. When the targetPort names a port the containers do not declare, the Service has no endpoints; when it is a number no container exposes, connections are refused. Both apply cleanly and only fail at runtime, typically after a container port is renamed or moved.",
+		Check: func(file *ast.File, fset *token.FileSet) []Issue {
+			return checkWK8016([]*ast.File{file}, fset)
+		},
+		CheckPackage: checkWK8016,
+		Fix:          nil,
+		Example: RuleExample{
+			Bad: `var Web = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:  "web",
+					Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+				}},
+			},
+		},
+	},
+}
+
+var WebService = corev1.Service{
+	Spec: corev1.ServiceSpec{
+		Selector: map[string]string{"app": "web"},
+		Ports:    []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromInt(80)}},
+	},
+}`,
+			Good: `var WebService = corev1.Service{
+	Spec: corev1.ServiceSpec{
+		Selector: map[string]string{"app": "web"},
+		Ports:    []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromString("http")}},
+	},
+}`,
+		},
+	}
+}
+
+// podPorts are the pod labels of a workload and the ports its containers
+// declare. Ports that cannot be resolved statically leave known false.
+type podPorts struct {
+	varName string
+	labels  map[string]string
+	known   bool
+	names   map[string]bool
+	numbers map[int64]bool
+}
+
+func checkWK8016(files []*ast.File, fset *token.FileSet) []Issue {
+	var issues []Issue
+	consts := stringValues(files)
+	values := make(map[string]ast.Expr)
+	for _, file := range files {
+		forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+			values[varName] = value
+		})
+	}
+
+	// First, collect the pod labels and container ports of every workload in
+	// the package. If the labels of one cannot be resolved statically, a
+	// Service might select it, so nothing is reported.
+	var workloads []*podPorts
+	resolved := true
+	for _, file := range files {
+		forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+			compLit := unwrapCompositeLit(value)
+			if compLit == nil {
+				return
+			}
+
+			var meta, podSpec ast.Expr
+			switch kind := getResourceType(compLit); {
+			case kind == "Pod":
+				meta, podSpec = objectMetaField(compLit), getFieldValue(compLit, "Spec")
+			case podTemplateKinds[kind]:
+				spec := resolveCompositeLit(getFieldValue(compLit, "Spec"), values)
+				if spec == nil {
+					resolved = false
+					return
+				}
+				template := resolveCompositeLit(getFieldValue(spec, "Template"), values)
+				if template == nil {
+					resolved = false
+					return
+				}
+				meta, podSpec = objectMetaField(template), getFieldValue(template, "Spec")
+			default:
+				return
+			}
+
+			labels, ok := resolveObjectLabels(meta, values)
+			if !ok {
+				resolved = false
+				return
+			}
+			workload := collectContainerPorts(podSpec, values, consts)
+			workload.varName, workload.labels = varName, labels
+			workloads = append(workloads, workload)
+		})
+	}
+	if !resolved {
+		return nil
+	}
+
+	// Then check the target port of every port of each Service against the
+	// workloads it selects. Services selecting no workload are left to WK8306
+	for _, file := range files {
+		forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+			compLit := unwrapCompositeLit(value)
+			if compLit == nil || getResourceType(compLit) != "Service" {
+				return
+			}
+			spec := resolveCompositeLit(getFieldValue(compLit, "Spec"), values)
+			if spec == nil || getFieldValue(spec, "Selector") == nil {
+				return
+			}
+			selector, ok := resolveLabelMap(getFieldValue(spec, "Selector"), values)
+			if !ok || len(selector) == 0 {
+				return
+			}
+
+			var selected []*podPorts
+			for _, workload := range workloads {
+				if labelsMatch(selector, workload.labels) {
+					if !workload.known {
+						return
+					}
+					selected = append(selected, workload)
+				}
+			}
+			if len(selected) == 0 {
+				return
+			}
+
+			ports := resolveCompositeLit(getFieldValue(spec, "Ports"), values)
+			if ports == nil {
+				return
+			}
+			for _, elt := range ports.Elts {
+				port := resolveCompositeLit(elt, values)
+				if port == nil {
+					continue
+				}
+				targetExpr := getFieldValue(port, "TargetPort")
+				if targetExpr == nil {
+					// The target port defaults to the Service port
+					targetExpr = getFieldValue(port, "Port")
+				}
+				name, number := targetPortValue(targetExpr, consts)
+				if (name != "" && exposesPortName(selected, name)) || (name == "" && (number < 0 || exposesPortNumber(selected, number))) {
+					continue
+				}
+
+				target := fmt.Sprintf("%d", number)
+				if name != "" {
+					target = fmt.Sprintf("%q", name)
+				}
+				pos := fset.Position(targetExpr.Pos())
+				issues = append(issues, Issue{
+					Rule:     "WK8016",
+					Message:  fmt.Sprintf("Service %s targets port %s, which no container of %s exposes", varName, target, workloadNames(selected)),
+					File:     pos.Filename,
+					Line:     pos.Line,
+					Column:   pos.Column,
+					Severity: SeverityWarning,
+				})
+			}
+		})
+	}
+
+	return issues
+}
+
+// collectContainerPorts returns the ports declared by the containers of a
+// PodSpec expression.
+func collectContainerPorts(podSpec ast.Expr, values map[string]ast.Expr, consts map[string]string) *podPorts {
+	ports := &podPorts{names: make(map[string]bool), numbers: make(map[int64]bool)}
+
+	spec := resolveCompositeLit(podSpec, values)
+	if spec == nil {
+		return ports
+	}
+	containers := getFieldValue(spec, "Containers")
+	if containers == nil {
+		ports.known = true
+		return ports
+	}
+	list := resolveCompositeLit(containers, values)
+	if list == nil {
+		return ports
+	}
+	for _, elt := range list.Elts {
+		container := resolveCompositeLit(elt, values)
+		if container == nil {
+			return ports
+		}
+		portsExpr := getFieldValue(container, "Ports")
+		if portsExpr == nil {
+			continue
+		}
+		portList := resolveCompositeLit(portsExpr, values)
+		if portList == nil {
+			return ports
+		}
+		for _, portElt := range portList.Elts {
+			port := resolveCompositeLit(portElt, values)
+			if port == nil {
+				return ports
+			}
+			if nameExpr := getFieldValue(port, "Name"); nameExpr != nil {
+				name := resolveStringValue(nameExpr, consts)
+				if name == "" {
+					return ports
+				}
+				ports.names[name] = true
+			}
+			number := extractIntValue(getFieldValue(port, "ContainerPort"))
+			if number < 0 {
+				return ports
+			}
+			ports.numbers[number] = true
+		}
+	}
+	ports.known = true
+	return ports
+}
+
+// targetPortValue returns the port name or number of a Service target port
+// written as a number, intstr.FromInt, intstr.FromInt32, intstr.FromString or
+// an intstr.IntOrString literal. It returns "" and -1 for other expressions.
+func targetPortValue(expr ast.Expr, consts map[string]string) (string, int64) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind == token.INT {
+			return "", extractIntValue(e)
+		}
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok || len(e.Args) != 1 {
+			break
+		}
+		switch sel.Sel.Name {
+		case "FromInt", "FromInt32":
+			return "", extractIntValue(e.Args[0])
+		case "FromString":
+			if name := resolveStringValue(e.Args[0], consts); name != "" {
+				return name, -1
+			}
+		}
+	case *ast.CompositeLit:
+		if getResourceType(e) != "IntOrString" {
+			break
+		}
+		if strVal := getFieldValue(e, "StrVal"); strVal != nil {
+			if name := resolveStringValue(strVal, consts); name != "" {
+				return name, -1
+			}
+			break
+		}
+		if intVal := getFieldValue(e, "IntVal"); intVal != nil {
+			return "", extractIntValue(intVal)
+		}
+	}
+	return "", -1
+}
+
+// exposesPortName reports whether a container of any of workloads declares a
+// port with the given name.
+func exposesPortName(workloads []*podPorts, name string) bool {
+	for _, workload := range workloads {
+		if workload.names[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// exposesPortNumber reports whether a container of any of workloads declares
+// the given port number.
+func exposesPortNumber(workloads []*podPorts, number int64) bool {
+	for _, workload := range workloads {
+		if workload.numbers[number] {
+			return true
+		}
+	}
+	return false
+}
+
+// workloadNames returns the variable names of workloads, joined by commas.
+func workloadNames(workloads []*podPorts) string {
+	names := make([]string, len(workloads))
+	for i, workload := range workloads {
+		names[i] = workload.varName
+	}
+	return strings.Join(names, ", ")
+}
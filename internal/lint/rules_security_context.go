@@ -1,6 +1,7 @@
 package lint
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
 )
@@ -12,15 +13,26 @@ func RuleWK8203() Rule {
 		Name:        "ReadOnlyRootFilesystem",
 		Description: "Containers should set ReadOnlyRootFilesystem: true",
 		Severity:    SeverityWarning,
-		Check:       checkWK8203,
+		Rationale:   "A read-only root filesystem stops an attacker from modifying binaries or dropping tools into the container. Mount an emptyDir for paths that must be writable.",
+		Check:       visitorCheck(visitWK8203, compositeLits),
+		Visit:       visitWK8203,
+		Nodes:       compositeLits,
 		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var Web = corev1.Container{
+	SecurityContext: &corev1.SecurityContext{},
+}`,
+			Good: `var Web = corev1.Container{
+	SecurityContext: &corev1.SecurityContext{ReadOnlyRootFilesystem: ptr(true)},
+}`,
+		},
 	}
 }
 
-func checkWK8203(file *ast.File, fset *token.FileSet) []Issue {
+func visitWK8203(file *ast.File, fset *token.FileSet) Visitor {
 	var issues []Issue
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	visit := func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
@@ -78,9 +90,9 @@ func checkWK8203(file *ast.File, fset *token.FileSet) []Issue {
 		}
 
 		return true
-	})
+	}
 
-	return issues
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
 }
 
 // RuleWK8204 checks for RunAsNonRoot in SecurityContext.
@@ -90,15 +102,26 @@ func RuleWK8204() Rule {
 		Name:        "RunAsNonRoot",
 		Description: "Containers should set RunAsNonRoot: true",
 		Severity:    SeverityWarning,
-		Check:       checkWK8204,
+		Rationale:   "Running as root increases the damage a compromised container can do. RunAsNonRoot makes the kubelet refuse to start an image that runs as root.",
+		Check:       visitorCheck(visitWK8204, compositeLits),
+		Visit:       visitWK8204,
+		Nodes:       compositeLits,
 		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var Web = corev1.Container{
+	SecurityContext: &corev1.SecurityContext{},
+}`,
+			Good: `var Web = corev1.Container{
+	SecurityContext: &corev1.SecurityContext{RunAsNonRoot: ptr(true)},
+}`,
+		},
 	}
 }
 
-func checkWK8204(file *ast.File, fset *token.FileSet) []Issue {
+func visitWK8204(file *ast.File, fset *token.FileSet) Visitor {
 	var issues []Issue
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	visit := func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
@@ -156,9 +179,9 @@ func checkWK8204(file *ast.File, fset *token.FileSet) []Issue {
 		}
 
 		return true
-	})
+	}
 
-	return issues
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
 }
 
 // RuleWK8205 checks for dropping capabilities in SecurityContext.
@@ -168,15 +191,28 @@ func RuleWK8205() Rule {
 		Name:        "Drop capabilities",
 		Description: "Containers should drop unnecessary Linux capabilities",
 		Severity:    SeverityWarning,
-		Check:       checkWK8205,
+		Rationale:   "Containers get a default set of Linux capabilities that most applications never use. Dropping them reduces what an attacker can do.",
+		Check:       visitorCheck(visitWK8205, compositeLits),
+		Visit:       visitWK8205,
+		Nodes:       compositeLits,
 		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var Web = corev1.Container{
+	SecurityContext: &corev1.SecurityContext{},
+}`,
+			Good: `var Web = corev1.Container{
+	SecurityContext: &corev1.SecurityContext{
+		Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	},
+}`,
+		},
 	}
 }
 
-func checkWK8205(file *ast.File, fset *token.FileSet) []Issue {
+func visitWK8205(file *ast.File, fset *token.FileSet) Visitor {
 	var issues []Issue
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	visit := func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
@@ -254,9 +290,101 @@ func checkWK8205(file *ast.File, fset *token.FileSet) []Issue {
 		}
 
 		return true
-	})
+	}
 
-	return issues
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
+}
+
+// RuleWK8206 checks for containers and pods running as root via RunAsUser: 0.
+func RuleWK8206() Rule {
+	return Rule{
+		ID:          "WK8206",
+		Name:        "No root user",
+		Description: "Containers and pods must not set RunAsUser or RunAsGroup to 0",
+		Severity:    SeverityError,
+		Rationale:   "UID 0 is root. A process running as root inside the container has far more power if it escapes, and it overrides RunAsNonRoot image defaults.",
+		Check:       visitorCheck(visitWK8206, compositeLits),
+		Visit:       visitWK8206,
+		Nodes:       compositeLits,
+		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var Web = corev1.Container{
+	SecurityContext: &corev1.SecurityContext{RunAsUser: ptr(int64(0))},
+}`,
+			Good: `var Web = corev1.Container{
+	SecurityContext: &corev1.SecurityContext{
+		RunAsUser:  ptr(int64(1000)),
+		RunAsGroup: ptr(int64(3000)),
+	},
+}`,
+		},
+	}
+}
+
+func visitWK8206(file *ast.File, fset *token.FileSet) Visitor {
+	var issues []Issue
+
+	visit := func(n ast.Node) bool {
+		compLit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+
+		var owner string
+		switch {
+		case isContainerType(compLit):
+			owner = "Container"
+		case isPodSpecType(compLit):
+			owner = "Pod"
+		default:
+			return true
+		}
+
+		for _, elt := range compLit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok || key.Name != "SecurityContext" {
+				continue
+			}
+
+			securityLit := unwrapCompositeLit(kv.Value)
+			if securityLit == nil {
+				continue
+			}
+
+			for _, secElt := range securityLit.Elts {
+				secKV, ok := secElt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+
+				secKey, ok := secKV.Key.(*ast.Ident)
+				if !ok || (secKey.Name != "RunAsUser" && secKey.Name != "RunAsGroup") {
+					continue
+				}
+
+				if extractIntValue(secKV.Value) == 0 {
+					pos := fset.Position(secKV.Pos())
+					issues = append(issues, Issue{
+						Rule:     "WK8206",
+						Message:  fmt.Sprintf("%s should not set %s: 0, it runs with root privileges", owner, secKey.Name),
+						File:     pos.Filename,
+						Line:     pos.Line,
+						Column:   pos.Column,
+						Severity: SeverityError,
+					})
+				}
+			}
+		}
+
+		return true
+	}
+
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
 }
 
 // RuleWK8207 checks for HostNetwork usage in PodSpec.
@@ -266,15 +394,26 @@ func RuleWK8207() Rule {
 		Name:        "No host network",
 		Description: "Pods should not use HostNetwork: true",
 		Severity:    SeverityWarning,
-		Check:       checkWK8207,
+		Rationale:   "Host network access bypasses NetworkPolicies and exposes the pod to the node's network interfaces.",
+		Check:       visitorCheck(visitWK8207, compositeLits),
+		Visit:       visitWK8207,
+		Nodes:       compositeLits,
 		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var WebPodSpec = corev1.PodSpec{
+	HostNetwork: true,
+}`,
+			Good: `var WebPodSpec = corev1.PodSpec{
+	HostNetwork: false,
+}`,
+		},
 	}
 }
 
-func checkWK8207(file *ast.File, fset *token.FileSet) []Issue {
+func visitWK8207(file *ast.File, fset *token.FileSet) Visitor {
 	var issues []Issue
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	visit := func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
@@ -310,9 +449,9 @@ func checkWK8207(file *ast.File, fset *token.FileSet) []Issue {
 		}
 
 		return true
-	})
+	}
 
-	return issues
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
 }
 
 // RuleWK8208 checks for HostPID usage in PodSpec.
@@ -322,15 +461,26 @@ func RuleWK8208() Rule {
 		Name:        "No host PID",
 		Description: "Pods should not use HostPID: true",
 		Severity:    SeverityWarning,
-		Check:       checkWK8208,
+		Rationale:   "The host PID namespace lets the pod see and signal every process on the node.",
+		Check:       visitorCheck(visitWK8208, compositeLits),
+		Visit:       visitWK8208,
+		Nodes:       compositeLits,
 		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var WebPodSpec = corev1.PodSpec{
+	HostPID: true,
+}`,
+			Good: `var WebPodSpec = corev1.PodSpec{
+	HostPID: false,
+}`,
+		},
 	}
 }
 
-func checkWK8208(file *ast.File, fset *token.FileSet) []Issue {
+func visitWK8208(file *ast.File, fset *token.FileSet) Visitor {
 	var issues []Issue
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	visit := func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
@@ -366,9 +516,9 @@ func checkWK8208(file *ast.File, fset *token.FileSet) []Issue {
 		}
 
 		return true
-	})
+	}
 
-	return issues
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
 }
 
 // RuleWK8209 checks for HostIPC usage in PodSpec.
@@ -378,15 +528,26 @@ func RuleWK8209() Rule {
 		Name:        "No host IPC",
 		Description: "Pods should not use HostIPC: true",
 		Severity:    SeverityWarning,
-		Check:       checkWK8209,
+		Rationale:   "The host IPC namespace lets the pod read and write shared memory of processes on the node.",
+		Check:       visitorCheck(visitWK8209, compositeLits),
+		Visit:       visitWK8209,
+		Nodes:       compositeLits,
 		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var WebPodSpec = corev1.PodSpec{
+	HostIPC: true,
+}`,
+			Good: `var WebPodSpec = corev1.PodSpec{
+	HostIPC: false,
+}`,
+		},
 	}
 }
 
-func checkWK8209(file *ast.File, fset *token.FileSet) []Issue {
+func visitWK8209(file *ast.File, fset *token.FileSet) Visitor {
 	var issues []Issue
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	visit := func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
@@ -422,7 +583,93 @@ func checkWK8209(file *ast.File, fset *token.FileSet) []Issue {
 		}
 
 		return true
-	})
+	}
+
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
+}
+
+// RuleWK8210 checks for hostPath volumes in PodSpec.
+func RuleWK8210() Rule {
+	return Rule{
+		ID:          "WK8210",
+		Name:        "No hostPath volumes",
+		Description: "Pods should not mount hostPath volumes",
+		Severity:    SeverityWarning,
+		Rationale:   "A hostPath mount exposes the node's filesystem to the container (a mounted docker.sock is effectively root on the node) and ties the pod to whatever is on that node.",
+		Check:       visitorCheck(visitWK8210, compositeLits),
+		Visit:       visitWK8210,
+		Nodes:       compositeLits,
+		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var Volumes = []corev1.Volume{{
+	Name: "docker",
+	VolumeSource: corev1.VolumeSource{
+		HostPath: &corev1.HostPathVolumeSource{Path: "/var/run/docker.sock"},
+	},
+}}`,
+			Good: `var Volumes = []corev1.Volume{{
+	Name:         "scratch",
+	VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+}}`,
+		},
+	}
+}
+
+func visitWK8210(file *ast.File, fset *token.FileSet) Visitor {
+	var issues []Issue
+
+	visit := func(n ast.Node) bool {
+		compLit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+
+		if !isPodSpecType(compLit) {
+			return true
+		}
+
+		volumesLit := unwrapCompositeLit(getFieldValue(compLit, "Volumes"))
+		if volumesLit == nil {
+			return true
+		}
+
+		// Check each Volume for VolumeSource.HostPath
+		for _, volElt := range volumesLit.Elts {
+			volumeLit := unwrapCompositeLit(volElt)
+			if volumeLit == nil {
+				continue
+			}
+
+			sourceLit := unwrapCompositeLit(getFieldValue(volumeLit, "VolumeSource"))
+			if sourceLit == nil {
+				continue
+			}
+
+			hostPath := getFieldValue(sourceLit, "HostPath")
+			if hostPath == nil {
+				continue
+			}
+
+			message := "Pod should not use hostPath volumes, they expose the host filesystem and tie the pod to a node"
+			if hostPathLit := unwrapCompositeLit(hostPath); hostPathLit != nil {
+				if lit, ok := getFieldValue(hostPathLit, "Path").(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					message = fmt.Sprintf("Pod should not use hostPath volume %s, it exposes the host filesystem and ties the pod to a node", lit.Value)
+				}
+			}
+
+			pos := fset.Position(hostPath.Pos())
+			issues = append(issues, Issue{
+				Rule:     "WK8210",
+				Message:  message,
+				File:     pos.Filename,
+				Line:     pos.Line,
+				Column:   pos.Column,
+				Severity: SeverityWarning,
+			})
+		}
+
+		return true
+	}
 
-	return issues
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
 }
@@ -5,6 +5,8 @@ import (
 	"go/ast"
 	"go/token"
 	"strings"
+
+	"github.com/lex00/wetwire-k8s-go/internal/serialize"
 )
 
 // RuleWK8001 checks that resources are top-level variable declarations.
@@ -14,8 +16,19 @@ func RuleWK8001() Rule {
 		Name:        "Top-level resource declarations",
 		Description: "Resources must be top-level variable declarations",
 		Severity:    SeverityError,
+		Rationale:   "Resource discovery finds top-level variable declarations without executing code. Resources created inside functions cannot be discovered, ordered or built.",
 		Check:       checkWK8001,
 		Fix:         nil, // No auto-fix available
+		Example: RuleExample{
+			Bad: `func NewDeployment(name string) appsv1.Deployment {
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+}`,
+			Good: `var WebDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web"},
+}`,
+		},
 	}
 }
 
@@ -85,8 +98,34 @@ func RuleWK8002() Rule {
 		Name:        "Avoid deeply nested structures",
 		Description: "Avoid deeply nested inline structures (max depth 5)",
 		Severity:    SeverityError,
+		Rationale:   "Flat declarations are easier to read, review and modify, and deeply nested literals hide the parts of a resource that are worth naming and reusing.",
 		Check:       checkWK8002,
 		Fix:         nil, // Auto-fix could be implemented later
+		Example: RuleExample{
+			Bad: `var Web = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Ports: []corev1.ContainerPort{{ContainerPort: 8080}},
+				}},
+			},
+		},
+	},
+}`,
+			Good: `var WebContainer = corev1.Container{
+	Name:  "web",
+	Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+}
+
+var Web = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{WebContainer}},
+		},
+	},
+}`,
+		},
 	}
 }
 
@@ -163,8 +202,15 @@ func RuleWK8003() Rule {
 		Name:        "No duplicate resource names",
 		Description: "No duplicate resource names in same namespace",
 		Severity:    SeverityError,
+		Rationale:   "Two objects with the same kind, namespace and name overwrite each other when applied, so only the last one takes effect.",
 		Check:       checkWK8003,
 		Fix:         nil, // No auto-fix available
+		Example: RuleExample{
+			Bad: `var Web = corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+var WebCopy = corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web"}}`,
+			Good: `var Web = corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+var WebInternal = corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web-internal"}}`,
+		},
 	}
 }
 
@@ -289,8 +335,15 @@ func RuleWK8004() Rule {
 		Name:        "Circular dependency detection",
 		Description: "Circular dependency detection",
 		Severity:    SeverityError,
+		Rationale:   "Resources are ordered by their references. A cycle has no valid order and usually means two resources derive values from each other.",
 		Check:       checkWK8004,
 		Fix:         nil, // No auto-fix available
+		Example: RuleExample{
+			Bad: `var A = corev1.ConfigMap{Data: map[string]string{"peer": B.Name}}
+var B = corev1.ConfigMap{Data: map[string]string{"peer": A.Name}}`,
+			Good: `var A = corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "a"}}
+var B = corev1.ConfigMap{Data: map[string]string{"peer": A.Name}}`,
+		},
 	}
 }
 
@@ -298,35 +351,16 @@ func checkWK8004(file *ast.File, fset *token.FileSet) []Issue {
 	var issues []Issue
 
 	// Build dependency graph
+	vars := topLevelVars(file)
 	dependencies := make(map[string][]string)
 	varPositions := make(map[string]token.Position)
 
-	// Walk through all declarations
-	for _, decl := range file.Decls {
-		genDecl, ok := decl.(*ast.GenDecl)
-		if !ok || genDecl.Tok != token.VAR {
-			continue
-		}
-
-		for _, spec := range genDecl.Specs {
-			valueSpec, ok := spec.(*ast.ValueSpec)
-			if !ok {
-				continue
-			}
-
-			for i, name := range valueSpec.Names {
-				if name.Name == "_" {
-					continue
-				}
-
-				varPositions[name.Name] = fset.Position(name.Pos())
+	for name, decl := range vars {
+		varPositions[name] = fset.Position(decl.ident().Pos())
 
-				// Find dependencies in the initializer
-				if i < len(valueSpec.Values) {
-					deps := findVariableReferences(valueSpec.Values[i], file)
-					dependencies[name.Name] = deps
-				}
-			}
+		// Find dependencies in the initializer
+		if value := decl.value(); value != nil {
+			dependencies[name] = findVariableReferences(value, vars)
 		}
 	}
 
@@ -376,19 +410,20 @@ func detectCycle(current string, deps map[string][]string, visited map[string]bo
 	return nil
 }
 
-// findVariableReferences finds all variable references in an expression.
-func findVariableReferences(expr ast.Expr, file *ast.File) []string {
+// findVariableReferences finds all references to the top-level variables
+// vars in an expression.
+func findVariableReferences(expr ast.Expr, vars map[string]varDecl) []string {
 	refs := make(map[string]bool)
 
 	ast.Inspect(expr, func(n ast.Node) bool {
 		switch node := n.(type) {
 		case *ast.Ident:
-			if isTopLevelVar(node.Name, file) {
+			if _, ok := vars[node.Name]; ok {
 				refs[node.Name] = true
 			}
 		case *ast.SelectorExpr:
 			if ident, ok := node.X.(*ast.Ident); ok {
-				if isTopLevelVar(ident.Name, file) {
+				if _, ok := vars[ident.Name]; ok {
 					refs[ident.Name] = true
 				}
 			}
@@ -404,8 +439,29 @@ func findVariableReferences(expr ast.Expr, file *ast.File) []string {
 	return result
 }
 
-// isTopLevelVar checks if a name is a top-level variable in the file.
-func isTopLevelVar(name string, file *ast.File) bool {
+// varDecl is a top-level variable: the name at index in spec.
+type varDecl struct {
+	spec  *ast.ValueSpec
+	index int
+}
+
+// ident returns the name of the variable.
+func (d varDecl) ident() *ast.Ident {
+	return d.spec.Names[d.index]
+}
+
+// value returns the initializer of the variable, or nil if it has none.
+func (d varDecl) value() ast.Expr {
+	if d.index < len(d.spec.Values) {
+		return d.spec.Values[d.index]
+	}
+	return nil
+}
+
+// topLevelVars indexes the top-level variables of file by name, so that
+// resolving an identifier does not scan every declaration of the file.
+func topLevelVars(file *ast.File) map[string]varDecl {
+	vars := make(map[string]varDecl)
 	for _, decl := range file.Decls {
 		genDecl, ok := decl.(*ast.GenDecl)
 		if !ok || genDecl.Tok != token.VAR {
@@ -418,12 +474,153 @@ func isTopLevelVar(name string, file *ast.File) bool {
 				continue
 			}
 
-			for _, varName := range valueSpec.Names {
-				if varName.Name == name {
-					return true
+			for i, name := range valueSpec.Names {
+				if name.Name != "_" {
+					vars[name.Name] = varDecl{spec: valueSpec, index: i}
 				}
 			}
 		}
 	}
-	return false
+	return vars
+}
+
+// RuleWK8018 checks that cluster-scoped resources do not set a namespace.
+func RuleWK8018() Rule {
+	return Rule{
+		ID:          "WK8018",
+		Name:        "Namespace on cluster-scoped resource",
+		Description: "Cluster-scoped resources should not set metadata.namespace",
+		Severity:    SeverityWarning,
+		Rationale:   "Kinds such as Namespace, ClusterRole and PersistentVolume exist outside any namespace. A namespace set on them is dropped from the built manifest, so the code does not describe what is applied.",
+		Check:       checkWK8018,
+		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var NodeReader = rbacv1.ClusterRole{
+	ObjectMeta: metav1.ObjectMeta{Name: "node-reader", Namespace: "default"},
+}`,
+			Good: `var NodeReader = rbacv1.ClusterRole{
+	ObjectMeta: metav1.ObjectMeta{Name: "node-reader"},
+}`,
+		},
+	}
+}
+
+func checkWK8018(file *ast.File, fset *token.FileSet) []Issue {
+	var issues []Issue
+
+	forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+		compLit := unwrapCompositeLit(value)
+		if compLit == nil {
+			return
+		}
+		// Only package-qualified types, so that local types sharing a
+		// kind's name are not flagged
+		if _, ok := compLit.Type.(*ast.SelectorExpr); !ok {
+			return
+		}
+		kind := getResourceType(compLit)
+		if !serialize.IsClusterScoped(kind) {
+			return
+		}
+
+		meta := metadataLiteral(compLit)
+		if meta == nil {
+			return
+		}
+		namespace := getFieldValue(meta, "Namespace")
+		if namespace == nil {
+			return
+		}
+
+		pos := fset.Position(namespace.Pos())
+		issues = append(issues, Issue{
+			Rule:     "WK8018",
+			Message:  fmt.Sprintf("%s %s sets a namespace, but %s is cluster-scoped; the namespace is left out of the built manifest", kind, varName, kind),
+			File:     pos.Filename,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Severity: SeverityWarning,
+		})
+	})
+
+	return issues
+}
+
+// ownerReferenceFields are the fields the API server requires in an
+// OwnerReference.
+var ownerReferenceFields = []string{"APIVersion", "Kind", "Name", "UID"}
+
+// RuleWK8019 checks that hand-written owner references set every required
+// field.
+func RuleWK8019() Rule {
+	return Rule{
+		ID:          "WK8019",
+		Name:        "Incomplete owner reference",
+		Description: "OwnerReferences must set APIVersion, Kind, Name and UID",
+		Severity:    SeverityError,
+		Rationale:   "The API server rejects an owner reference that leaves out any of APIVersion, Kind, Name or UID. Owners declared in the same package can be wired by the build instead, with build --owner-references.",
+		Check:       visitorCheck(visitWK8019, compositeLits),
+		Visit:       visitWK8019,
+		Nodes:       compositeLits,
+		Fix:         nil,
+		Example: RuleExample{
+			Bad: `OwnerReferences: []metav1.OwnerReference{
+	{Kind: "Deployment", Name: "web"},
+}`,
+			Good: `OwnerReferences: []metav1.OwnerReference{
+	{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: webUID},
+}`,
+		},
+	}
+}
+
+func visitWK8019(file *ast.File, fset *token.FileSet) Visitor {
+	var issues []Issue
+
+	check := func(lit *ast.CompositeLit) {
+		var missing []string
+		for _, field := range ownerReferenceFields {
+			if getFieldValue(lit, field) == nil {
+				missing = append(missing, field)
+			}
+		}
+		if len(missing) == 0 {
+			return
+		}
+
+		pos := fset.Position(lit.Pos())
+		issues = append(issues, Issue{
+			Rule:     "WK8019",
+			Message:  fmt.Sprintf("OwnerReference is missing %s, which the API server requires; owners declared in this package can be set with build --owner-references", strings.Join(missing, ", ")),
+			File:     pos.Filename,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Severity: SeverityError,
+		})
+	}
+
+	visit := func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		switch t := lit.Type.(type) {
+		case *ast.SelectorExpr:
+			if t.Sel.Name == "OwnerReference" {
+				check(lit)
+			}
+		case *ast.ArrayType:
+			// Elements of []metav1.OwnerReference may leave out their type
+			if sel, ok := t.Elt.(*ast.SelectorExpr); ok && sel.Sel.Name == "OwnerReference" {
+				for _, elt := range lit.Elts {
+					if elem, ok := elt.(*ast.CompositeLit); ok && elem.Type == nil {
+						check(elem)
+					}
+				}
+			}
+		}
+		return true
+	}
+
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
 }
@@ -4,6 +4,9 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -198,11 +201,354 @@ func TestWK8006_FlagLatestImageTags(t *testing.T) {
 	})
 }
 
+func TestWK8007_MetadataNameMatchesVariable(t *testing.T) {
+	rule := RuleWK8007()
+
+	t.Run("should detect metadata names unrelated to the variable", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8007_bad.go")
+		issues := rule.Check(file, fset)
+
+		assert.Len(t, issues, 2, "Expected both mismatched names to be flagged")
+		for _, issue := range issues {
+			assert.Equal(t, "WK8007", issue.Rule)
+			assert.Equal(t, SeverityWarning, issue.Severity)
+		}
+		assert.Contains(t, issues[0].Message, `"api-server"`)
+		assert.Contains(t, issues[0].Message, `"web-app"`)
+		assert.Contains(t, issues[1].Message, `"cache"`)
+	})
+
+	t.Run("should pass for consistent names and non-literal names", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8007_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+}
+
+func TestExpectedResourceName(t *testing.T) {
+	tests := []struct {
+		varName  string
+		kind     string
+		expected string
+	}{
+		{"WebAppDeployment", "Deployment", "web-app"},
+		{"WebAppHPA", "HorizontalPodAutoscaler", "web-app-hpa"},
+		{"Service", "Service", "service"},
+		{"APIServerService", "Service", "api-server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.varName, func(t *testing.T) {
+			assert.Equal(t, tt.expected, expectedResourceName(tt.varName, tt.kind))
+		})
+	}
+}
+
+func TestWK8011_DirectResourceReferences(t *testing.T) {
+	rule := RuleWK8011()
+
+	t.Run("should detect hardcoded names of resources in the same file", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8011_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 3, "Expected Service, ConfigMap and Secret references")
+		for _, issue := range issues {
+			assert.Equal(t, "WK8011", issue.Rule)
+			assert.Equal(t, SeverityWarning, issue.Severity)
+		}
+		assert.Contains(t, issues[0].Message, "use AppHeadless.Name")
+		assert.Contains(t, issues[1].Message, "use AppConfig.Name")
+		assert.Contains(t, issues[2].Message, "use AppSecret.Name")
+	})
+
+	t.Run("should pass for direct references and external names", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8011_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+}
+
+func TestWK8012_ReplicasWithHPA(t *testing.T) {
+	rule := RuleWK8012()
+
+	t.Run("should detect replicas on Deployments targeted by an HPA", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8012_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 2, "Expected the variable and the name reference")
+		for _, issue := range issues {
+			assert.Equal(t, "WK8012", issue.Rule)
+			assert.Equal(t, SeverityWarning, issue.Severity)
+		}
+		assert.Contains(t, issues[0].Message, "APIDeployment")
+		assert.Contains(t, issues[1].Message, "WebDeployment")
+	})
+
+	t.Run("should pass when the HPA owns the replica count", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8012_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+}
+
+func TestWK8013_RecommendedLabels(t *testing.T) {
+	rule := RuleWK8013()
+
+	t.Run("should detect resources without the recommended labels", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8013_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 3)
+		for _, issue := range issues {
+			assert.Equal(t, "WK8013", issue.Rule)
+			assert.Equal(t, SeverityInfo, issue.Severity)
+		}
+		assert.Contains(t, issues[0].Message, "WebService8013 is missing recommended labels: app.kubernetes.io/name, app.kubernetes.io/managed-by")
+		assert.Contains(t, issues[1].Message, "WebConfig8013 is missing recommended labels: app.kubernetes.io/name, app.kubernetes.io/managed-by")
+		assert.Contains(t, issues[2].Message, "APIDeployment8013 is missing recommended labels: app.kubernetes.io/managed-by")
+	})
+
+	t.Run("should pass for resources with the recommended labels", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8013_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+
+	t.Run("should check the configured labels", func(t *testing.T) {
+		linter := NewLinter(&Config{
+			MinSeverity:       SeverityInfo,
+			RecommendedLabels: []string{"app"},
+		})
+
+		issues, err := linter.LintFile("testdata/wk8013_bad.go")
+		require.NoError(t, err)
+
+		var flagged []string
+		for _, issue := range issues {
+			if issue.Rule == "WK8013" {
+				flagged = append(flagged, issue.Message)
+			}
+		}
+		assert.Equal(t, []string{
+			"WebService8013 is missing recommended labels: app",
+			"APIDeployment8013 is missing recommended labels: app",
+		}, flagged)
+	})
+}
+
+func TestWK8014_PinImagesByDigest(t *testing.T) {
+	rule := RuleWK8014()
+
+	t.Run("should detect images not pinned by digest", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8014_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 4)
+		for _, issue := range issues {
+			assert.Equal(t, "WK8014", issue.Rule)
+			assert.Equal(t, SeverityWarning, issue.Severity)
+		}
+		assert.Equal(t, `Image "example.com/migrate:v2" is not pinned by digest, use a reference ending in @sha256:<digest>`, issues[0].Message)
+		assert.Contains(t, issues[1].Message, `"nginx:1.25.3"`)
+		assert.Contains(t, issues[2].Message, `"envoyproxy/envoy"`)
+		assert.Contains(t, issues[3].Message, `"busybox@sha256:0d17b565"`)
+	})
+
+	t.Run("should pass for images pinned by digest", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8014_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+
+	t.Run("should run only when enabled", func(t *testing.T) {
+		count := func(config *Config) int {
+			issues, err := NewLinter(config).LintFile("testdata/wk8014_bad.go")
+			require.NoError(t, err)
+			n := 0
+			for _, issue := range issues {
+				if issue.Rule == "WK8014" {
+					n++
+				}
+			}
+			return n
+		}
+
+		assert.Zero(t, count(nil), "WK8014 should be disabled by default")
+		assert.Equal(t, 4, count(&Config{MinSeverity: SeverityInfo, EnabledRules: []string{"WK8014"}}))
+		assert.Zero(t, count(&Config{
+			MinSeverity:   SeverityInfo,
+			EnabledRules:  []string{"WK8014"},
+			DisabledRules: []string{"WK8014"},
+		}), "disabled_rules should win over enabled_rules")
+	})
+}
+
+func TestWK8018_NamespaceOnClusterScopedResource(t *testing.T) {
+	rule := RuleWK8018()
+
+	t.Run("should detect namespaces on cluster-scoped resources", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8018_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 2)
+		for _, issue := range issues {
+			assert.Equal(t, "WK8018", issue.Rule)
+			assert.Equal(t, SeverityWarning, issue.Severity)
+		}
+		assert.Equal(t, "ClusterRole NodeReader8018 sets a namespace, but ClusterRole is cluster-scoped; the namespace is left out of the built manifest", issues[0].Message)
+		assert.Equal(t, 16, issues[0].Line)
+		assert.Contains(t, issues[1].Message, "Namespace Team8018 sets a namespace")
+	})
+
+	t.Run("should pass for namespaced resources and cluster-scoped resources without a namespace", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8018_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+}
+
+func TestWK8019_IncompleteOwnerReference(t *testing.T) {
+	rule := RuleWK8019()
+
+	t.Run("should detect owner references missing required fields", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8019_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 2)
+		for _, issue := range issues {
+			assert.Equal(t, "WK8019", issue.Rule)
+			assert.Equal(t, SeverityError, issue.Severity)
+		}
+		assert.Equal(t, "OwnerReference is missing APIVersion, UID, which the API server requires; owners declared in this package can be set with build --owner-references", issues[0].Message)
+		assert.Equal(t, 16, issues[0].Line)
+		assert.Contains(t, issues[1].Message, "missing UID,")
+		assert.Equal(t, 26, issues[1].Line)
+	})
+
+	t.Run("should pass for complete owner references", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8019_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+}
+
+func TestWK8017_ExplicitUpdateStrategy(t *testing.T) {
+	rule := RuleWK8017()
+
+	t.Run("should detect workloads relying on the default strategy", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8017_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 3)
+		for _, issue := range issues {
+			assert.Equal(t, "WK8017", issue.Rule)
+			assert.Equal(t, SeverityInfo, issue.Severity)
+		}
+		assert.Equal(t, "Deployment should set Strategy explicitly instead of relying on the default (RollingUpdate with 25% maxSurge and maxUnavailable)", issues[0].Message)
+		assert.True(t, strings.HasPrefix(issues[1].Message, "StatefulSet should set UpdateStrategy"))
+		assert.True(t, strings.HasPrefix(issues[2].Message, "DaemonSet should set UpdateStrategy"))
+	})
+
+	t.Run("should pass for workloads with an explicit strategy", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8017_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+
+	t.Run("should pass for examples that set their strategy", func(t *testing.T) {
+		for _, example := range []string{"web-service", "stateful-app"} {
+			fset, file := parseTestFile(t, filepath.Join("..", "..", "examples", example, "main.go"))
+			assert.Empty(t, rule.Check(file, fset), example)
+		}
+	})
+}
+
+func TestWK8043_SecretValuesInConfigMap(t *testing.T) {
+	rule := RuleWK8043()
+
+	t.Run("should detect credentials and encoded secrets in ConfigMaps", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8043_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 4)
+		for _, issue := range issues {
+			assert.Equal(t, "WK8043", issue.Rule)
+			assert.Equal(t, SeverityWarning, issue.Severity)
+		}
+		assert.Equal(t, `ConfigMap AppSettings8043 has a credential under key "DB_PASSWORD", use a Secret instead`, issues[0].Message)
+		assert.Equal(t, 16, issues[0].Line)
+		assert.Contains(t, issues[1].Message, `"authToken"`)
+		assert.Contains(t, issues[2].Message, `"api-key"`)
+		assert.Equal(t, `ConfigMap SigningConfig8043 has a base64-like value under key "signing.material" that looks like an encoded secret, use a Secret instead`, issues[3].Message)
+	})
+
+	t.Run("should pass for settings, digests and Secrets", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8043_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+}
+
+func TestWK8306_ServiceSelectorMatchesPods(t *testing.T) {
+	rule := RuleWK8306()
+
+	t.Run("should detect Services that select no pods", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8306_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 2)
+		for _, issue := range issues {
+			assert.Equal(t, "WK8306", issue.Rule)
+			assert.Equal(t, SeverityWarning, issue.Severity)
+		}
+		assert.Contains(t, issues[0].Message, "WebService8306 selector app=wbe")
+		assert.Contains(t, issues[1].Message, "APIService8306 selector app=api")
+	})
+
+	t.Run("should pass for matching, headless, ExternalName and selectorless Services", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8306_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+
+	t.Run("should skip files whose pod labels are not literals", func(t *testing.T) {
+		src := `package example
+
+var Web = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: mergeLabels(common, web)},
+		},
+	},
+}
+
+var WebService = corev1.Service{
+	Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+}
+`
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "example.go", src, parser.ParseComments)
+		require.NoError(t, err)
+
+		assert.Empty(t, rule.Check(file, fset))
+	})
+}
+
 func TestAllRules(t *testing.T) {
 	rules := AllRules()
 
-	t.Run("should have all 26 rules", func(t *testing.T) {
-		assert.Len(t, rules, 26, "Expected 26 rules")
+	t.Run("should have all 46 rules", func(t *testing.T) {
+		assert.Len(t, rules, 46, "Expected 46 rules")
 	})
 
 	t.Run("all rules should have required fields", func(t *testing.T) {
@@ -211,6 +557,9 @@ func TestAllRules(t *testing.T) {
 			assert.NotEmpty(t, rule.Name, "Rule name should not be empty")
 			assert.NotEmpty(t, rule.Description, "Rule description should not be empty")
 			assert.NotNil(t, rule.Check, "Rule Check function should not be nil")
+			assert.NotEmpty(t, rule.Rationale, "Rule %s should have a rationale", rule.ID)
+			assert.NotEmpty(t, rule.Example.Bad, "Rule %s should have a bad example", rule.ID)
+			assert.NotEmpty(t, rule.Example.Good, "Rule %s should have a good example", rule.ID)
 		}
 	})
 
@@ -229,6 +578,124 @@ func TestAllRules(t *testing.T) {
 	})
 }
 
+func TestRuleByID(t *testing.T) {
+	rule, ok := RuleByID("wk8006")
+	require.True(t, ok)
+	assert.Equal(t, "WK8006", rule.ID)
+
+	_, ok = RuleByID("WK9999")
+	assert.False(t, ok)
+}
+
+func TestRegister(t *testing.T) {
+	// Restore the built-in registry afterwards
+	saved := Rules()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = saved
+		registryMu.Unlock()
+	})
+
+	// A custom rule flagging variables named Forbidden
+	Register(Rule{
+		ID:          "WK8999",
+		Name:        "No forbidden variables",
+		Description: "Variables must not be named Forbidden",
+		Severity:    SeverityWarning,
+		Check: func(file *ast.File, fset *token.FileSet) []Issue {
+			var issues []Issue
+			forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+				if varName == "Forbidden" {
+					pos := fset.Position(value.Pos())
+					issues = append(issues, Issue{Rule: "WK8999", Message: "Forbidden is forbidden", File: pos.Filename, Line: pos.Line, Severity: SeverityWarning})
+				}
+			})
+			return issues
+		},
+	})
+
+	path := filepath.Join(t.TempDir(), "custom.go")
+	require.NoError(t, os.WriteFile(path, []byte("package custom\n\nvar Forbidden = \"x\"\n"), 0644))
+	custom := func(config *Config) []Issue {
+		issues, err := NewLinter(config).LintFile(path)
+		require.NoError(t, err)
+		var found []Issue
+		for _, issue := range issues {
+			if issue.Rule == "WK8999" {
+				found = append(found, issue)
+			}
+		}
+		return found
+	}
+
+	t.Run("custom rules are listed and run", func(t *testing.T) {
+		rules := Rules()
+		assert.Equal(t, "WK8999", rules[len(rules)-1].ID)
+		_, ok := RuleByID("wk8999")
+		assert.True(t, ok)
+
+		issues := custom(&Config{MinSeverity: SeverityInfo})
+		require.Len(t, issues, 1)
+		assert.Equal(t, "Forbidden is forbidden", issues[0].Message)
+		assert.Equal(t, 3, issues[0].Line)
+	})
+
+	t.Run("custom rules can be disabled", func(t *testing.T) {
+		assert.Empty(t, custom(&Config{MinSeverity: SeverityInfo, DisabledRules: []string{"WK8999"}}))
+	})
+
+	t.Run("custom rule severity can be overridden", func(t *testing.T) {
+		issues := custom(&Config{MinSeverity: SeverityInfo, RuleSeverity: map[string]Severity{"WK8999": SeverityError}})
+		require.Len(t, issues, 1)
+		assert.Equal(t, SeverityError, issues[0].Severity)
+
+		assert.Empty(t, custom(&Config{MinSeverity: SeverityWarning, RuleSeverity: map[string]Severity{"WK8999": SeverityInfo}}))
+	})
+
+	t.Run("invalid and duplicate rules panic", func(t *testing.T) {
+		check := func(*ast.File, *token.FileSet) []Issue { return nil }
+		assert.Panics(t, func() { Register(Rule{Check: check}) })
+		assert.Panics(t, func() { Register(Rule{ID: "WK9998"}) })
+		assert.Panics(t, func() { Register(Rule{ID: "WK8006", Check: check}) })
+		assert.Panics(t, func() { Register(Rule{ID: "wk8999", Check: check}) })
+	})
+}
+
+func TestRegister_Configure(t *testing.T) {
+	saved := Rules()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = saved
+		registryMu.Unlock()
+	})
+
+	// A custom rule reporting the external config refs it is configured with
+	checkRefs := func(refs []string) func(*ast.File, *token.FileSet) []Issue {
+		return func(file *ast.File, fset *token.FileSet) []Issue {
+			return []Issue{{Rule: "WK8997", Message: strings.Join(refs, ","), File: fset.Position(file.Pos()).Filename, Line: 1, Severity: SeverityWarning}}
+		}
+	}
+	Register(Rule{
+		ID:       "WK8997",
+		Severity: SeverityWarning,
+		Check:    checkRefs([]string{"unconfigured"}),
+		Configure: func(config *Config) Rule {
+			return Rule{ID: "WK8997", Severity: SeverityWarning, Check: checkRefs(config.ExternalConfigRefs)}
+		},
+	})
+
+	issues, err := NewLinter(&Config{MinSeverity: SeverityInfo, ExternalConfigRefs: []string{"vault-token", "ca-bundle"}}).
+		LintSource("custom.go", []byte("package custom\n"))
+	require.NoError(t, err)
+	var found []string
+	for _, issue := range issues {
+		if issue.Rule == "WK8997" {
+			found = append(found, issue.Message)
+		}
+	}
+	assert.Equal(t, []string{"vault-token,ca-bundle"}, found)
+}
+
 func TestWK8103_ContainerNameRequired(t *testing.T) {
 	rule := RuleWK8103()
 
@@ -310,6 +777,218 @@ func TestWK8105_ImagePullPolicyExplicit(t *testing.T) {
 	})
 }
 
+func TestWK8107_ConfigReferencesDefined(t *testing.T) {
+	rule := RuleWK8107()
+
+	t.Run("should detect references to undefined ConfigMaps and Secrets", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8107_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 4)
+		for _, issue := range issues {
+			assert.Equal(t, "WK8107", issue.Rule)
+			assert.Equal(t, SeverityWarning, issue.Severity)
+		}
+		assert.Equal(t, `App8107 refers to ConfigMap "app-confg-8107", which is not defined in the package`, issues[0].Message)
+		assert.Equal(t, `App8107 refers to Secret "db-credentials-8107", which is not defined in the package`, issues[1].Message)
+		assert.Equal(t, `App8107 refers to ConfigMap "app-secret-8107", which is not defined in the package`, issues[2].Message)
+		assert.Equal(t, `App8107 refers to Secret "app-tls-8107", which is not defined in the package`, issues[3].Message)
+	})
+
+	t.Run("should pass for defined, built-in and optional references", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8107_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+
+	t.Run("should accept configured external names", func(t *testing.T) {
+		// Lint a copy, so that the other testdata files are not part of the package
+		src, err := os.ReadFile("testdata/wk8107_bad.go")
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), "wk8107_bad.go")
+		require.NoError(t, os.WriteFile(path, src, 0644))
+
+		linter := NewLinter(&Config{
+			MinSeverity:        SeverityInfo,
+			ExternalConfigRefs: []string{"app-tls-8107", "db-credentials-8107"},
+		})
+
+		issues, err := linter.LintFile(path)
+		require.NoError(t, err)
+
+		var flagged []string
+		for _, issue := range issues {
+			if issue.Rule == "WK8107" {
+				flagged = append(flagged, issue.Message)
+			}
+		}
+		assert.Equal(t, []string{
+			`App8107 refers to ConfigMap "app-confg-8107", which is not defined in the package`,
+			`App8107 refers to ConfigMap "app-secret-8107", which is not defined in the package`,
+		}, flagged)
+	})
+
+	t.Run("should find resources in other files of the package", func(t *testing.T) {
+		dir := t.TempDir()
+		config := `package web
+
+var AppConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+}
+`
+		deployment := `package web
+
+var App = corev1.EnvFromSource{
+	ConfigMapRef: &corev1.ConfigMapEnvSource{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"},
+	},
+}
+
+var Other = corev1.EnvFromSource{
+	ConfigMapRef: &corev1.ConfigMapEnvSource{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "other-config"},
+	},
+}
+`
+		other := `package other
+
+var OtherConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "other-config"},
+}
+`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "config.go"), []byte(config), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "deployment.go"), []byte(deployment), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "other.go"), []byte(other), 0644))
+
+		issues, err := NewLinter(&Config{MinSeverity: SeverityInfo, DisabledRules: []string{"WK8001"}}).LintFile(filepath.Join(dir, "deployment.go"))
+		require.NoError(t, err)
+
+		var flagged []string
+		for _, issue := range issues {
+			if issue.Rule == "WK8107" {
+				flagged = append(flagged, issue.Message)
+			}
+		}
+		assert.Equal(t, []string{`Other refers to ConfigMap "other-config", which is not defined in the package`}, flagged)
+	})
+}
+
+func TestWK8108_IngressBackendDefined(t *testing.T) {
+	rule := RuleWK8108()
+
+	t.Run("should detect backends routing to missing Services and ports", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8108_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 3)
+		for _, issue := range issues {
+			assert.Equal(t, "WK8108", issue.Rule)
+			assert.Equal(t, SeverityError, issue.Severity)
+		}
+		assert.Equal(t, `ShopIngress8108 routes to Service "storefront-8108", which is not defined in the package`, issues[0].Message)
+		assert.Equal(t, `ShopIngress8108 routes to port "web" of Service "shop-8108", which has no port with that name`, issues[1].Message)
+		assert.Equal(t, `ShopIngress8108 routes to port 8080 of Service "shop-8108", which has no such port`, issues[2].Message)
+	})
+
+	t.Run("should pass for backends routing to defined Service ports", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8108_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+
+	t.Run("should catch routing drift in examples/web-service", func(t *testing.T) {
+		src, err := os.ReadFile(filepath.Join("..", "..", "examples", "web-service", "main.go"))
+		require.NoError(t, err)
+
+		check := func(src string) []string {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+			require.NoError(t, err)
+			var messages []string
+			for _, issue := range rule.Check(file, fset) {
+				messages = append(messages, issue.Message)
+			}
+			return messages
+		}
+
+		assert.Empty(t, check(string(src)))
+		assert.Equal(t, []string{`WebAppIngress routes to Service "web-app", which is not defined in the package`},
+			check(strings.Replace(string(src), "Name: WebAppService.Name,", `Name: "web-app",`, 1)))
+		assert.Equal(t, []string{`WebAppIngress routes to port "https" of Service "webapp", which has no port with that name`},
+			check(strings.Replace(string(src), `Name: "http",`, `Name: "https",`, 1)))
+	})
+}
+
+func TestWK8016_ServiceTargetPortExposed(t *testing.T) {
+	rule := RuleWK8016()
+
+	t.Run("should detect target ports no selected container exposes", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8016_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 3)
+		for _, issue := range issues {
+			assert.Equal(t, "WK8016", issue.Rule)
+			assert.Equal(t, SeverityWarning, issue.Severity)
+		}
+		assert.Equal(t, "Service APIService8016 targets port 80, which no container of APIDeployment8016 exposes", issues[0].Message)
+		assert.Equal(t, 44, issues[0].Line)
+		assert.Equal(t, `Service APIService8016 targets port "prometheus", which no container of APIDeployment8016 exposes`, issues[1].Message)
+		assert.Equal(t, "Service APIService8016 targets port 9000, which no container of APIDeployment8016 exposes", issues[2].Message)
+	})
+
+	t.Run("should pass for target ports exposed by name or number", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8016_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+
+	t.Run("should match Services and workloads across files", func(t *testing.T) {
+		fset := token.NewFileSet()
+		parse := func(name, src string) *ast.File {
+			file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+			require.NoError(t, err)
+			return file
+		}
+		workload := parse("workload.go", `package k8s
+
+var Web = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "web", Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}}}},
+			},
+		},
+	},
+}
+`)
+		service := parse("service.go", `package k8s
+
+var WebService = corev1.Service{
+	Spec: corev1.ServiceSpec{
+		Selector: map[string]string{"app": "web"},
+		Ports: []corev1.ServicePort{
+			{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+			{Name: "https", Port: 443, TargetPort: intstr.FromString("https")},
+		},
+	},
+}
+`)
+
+		issues := rule.CheckPackage([]*ast.File{workload, service}, fset)
+		require.Len(t, issues, 1)
+		assert.Equal(t, `Service WebService targets port "https", which no container of Web exposes`, issues[0].Message)
+		assert.Equal(t, "service.go", issues[0].File)
+
+		// Alone, the Service selects nothing to check against
+		assert.Empty(t, rule.Check(service, fset))
+	})
+}
+
 func TestWK8203_ReadOnlyRootFilesystem(t *testing.T) {
 	rule := RuleWK8203()
 
@@ -391,6 +1070,31 @@ func TestWK8205_DropCapabilities(t *testing.T) {
 	})
 }
 
+func TestWK8206_NoRootUser(t *testing.T) {
+	rule := RuleWK8206()
+
+	t.Run("should detect RunAsUser and RunAsGroup set to 0", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8206_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 3, "Expected container user, container group and pod user violations")
+		for _, issue := range issues {
+			assert.Equal(t, "WK8206", issue.Rule)
+			assert.Equal(t, SeverityError, issue.Severity)
+		}
+		assert.Contains(t, issues[0].Message, "Container should not set RunAsUser: 0")
+		assert.Contains(t, issues[1].Message, "Container should not set RunAsGroup: 0")
+		assert.Contains(t, issues[2].Message, "Pod should not set RunAsUser: 0")
+	})
+
+	t.Run("should pass for non-root users", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8206_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+}
+
 func TestWK8207_NoHostNetwork(t *testing.T) {
 	rule := RuleWK8207()
 
@@ -472,6 +1176,78 @@ func TestWK8209_NoHostIPC(t *testing.T) {
 	})
 }
 
+func TestWK8210_NoHostPathVolumes(t *testing.T) {
+	rule := RuleWK8210()
+
+	t.Run("should detect hostPath volumes", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8210_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 2, "Expected both hostPath volumes to be flagged")
+		for _, issue := range issues {
+			assert.Equal(t, "WK8210", issue.Rule)
+			assert.Equal(t, SeverityWarning, issue.Severity)
+		}
+		assert.Contains(t, issues[0].Message, `"/var/run/docker.sock"`, "Expected literal mount path in message")
+		assert.Contains(t, issues[1].Message, "hostPath volumes")
+	})
+
+	t.Run("should pass for pods without hostPath volumes", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8210_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+}
+
+func TestWK8211_DisableServiceAccountTokenAutomount(t *testing.T) {
+	rule := RuleWK8211()
+
+	t.Run("should detect pods and ServiceAccounts that automount the token", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8211_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 3, "Expected two pods and one ServiceAccount to be flagged")
+		for _, issue := range issues {
+			assert.Equal(t, "WK8211", issue.Rule)
+			assert.Equal(t, SeverityInfo, issue.Severity)
+			assert.Contains(t, issue.Message, "AutomountServiceAccountToken: false")
+		}
+		assert.Contains(t, issues[2].Message, "ServiceAccount")
+	})
+
+	t.Run("should pass when automount is set explicitly or a named account is used", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8211_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+}
+
+func TestWK8015_EmptyDirSizeLimit(t *testing.T) {
+	rule := RuleWK8015()
+
+	t.Run("should detect emptyDir volumes without a size limit", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8015_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 2, "Expected the disk and memory emptyDirs to be flagged")
+		for _, issue := range issues {
+			assert.Equal(t, "WK8015", issue.Rule)
+			assert.Equal(t, SeverityInfo, issue.Severity)
+			assert.Contains(t, issue.Message, "SizeLimit")
+		}
+		assert.Equal(t, 23, issues[0].Line)
+	})
+
+	t.Run("should pass for bounded emptyDirs and other volumes", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8015_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+}
+
 func TestWK8302_ReplicasMinimum(t *testing.T) {
 	rule := RuleWK8302()
 
@@ -553,6 +1329,56 @@ func TestWK8304_AntiAffinityRecommended(t *testing.T) {
 	})
 }
 
+func TestWK8305_CronJobSchedulingPolicy(t *testing.T) {
+	rule := RuleWK8305()
+
+	t.Run("should detect CronJobs missing scheduling fields", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8305_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 4, "Expected two issues for the first CronJob and one for each other")
+		for _, issue := range issues {
+			assert.Equal(t, "WK8305", issue.Rule)
+			assert.Equal(t, SeverityInfo, issue.Severity)
+		}
+		assert.Contains(t, issues[0].Message, "ConcurrencyPolicy")
+		assert.Contains(t, issues[1].Message, "StartingDeadlineSeconds")
+		assert.Contains(t, issues[2].Message, "StartingDeadlineSeconds")
+		assert.Contains(t, issues[3].Message, "ConcurrencyPolicy")
+	})
+
+	t.Run("should pass for CronJobs with both fields", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8305_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+}
+
+func TestWK8307_JobLifetime(t *testing.T) {
+	rule := RuleWK8307()
+
+	t.Run("should detect Jobs and CronJobs without a TTL or deadline", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8307_bad.go")
+		issues := rule.Check(file, fset)
+
+		require.Len(t, issues, 3, "Expected two Jobs and one CronJob to be flagged")
+		for _, issue := range issues {
+			assert.Equal(t, "WK8307", issue.Rule)
+			assert.Equal(t, SeverityInfo, issue.Severity)
+			assert.Contains(t, issue.Message, "TTLSecondsAfterFinished or ActiveDeadlineSeconds")
+		}
+		assert.True(t, strings.HasPrefix(issues[2].Message, "CronJob"))
+	})
+
+	t.Run("should pass for Jobs with a TTL or deadline", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8307_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+}
+
 func TestWK8401_FileSizeLimits(t *testing.T) {
 	rule := RuleWK8401()
 
@@ -579,3 +1405,31 @@ func TestWK8401_FileSizeLimits(t *testing.T) {
 		assert.Empty(t, issues, "Expected no issues in good file")
 	})
 }
+
+func TestWK8099_NetworkPolicyTypes(t *testing.T) {
+	rule := RuleWK8099()
+
+	t.Run("should detect NetworkPolicy without PolicyTypes", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8099_bad.go")
+		issues := rule.Check(file, fset)
+
+		assert.NotEmpty(t, issues, "Expected to find issues in bad file")
+
+		found := false
+		for _, issue := range issues {
+			if issue.Rule == "WK8099" {
+				found = true
+				assert.Contains(t, issue.Message, "PolicyTypes", "Expected message about PolicyTypes")
+				assert.Equal(t, SeverityInfo, issue.Severity)
+			}
+		}
+		assert.True(t, found, "Expected to find WK8099 violation")
+	})
+
+	t.Run("should pass for NetworkPolicy with PolicyTypes", func(t *testing.T) {
+		fset, file := parseTestFile(t, "testdata/wk8099_good.go")
+		issues := rule.Check(file, fset)
+
+		assert.Empty(t, issues, "Expected no issues in good file")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"strings"
 )
 
 // RuleWK8101 checks for selector label mismatch in Deployments/StatefulSets/DaemonSets.
@@ -13,15 +14,38 @@ func RuleWK8101() Rule {
 		Name:        "Selector label mismatch",
 		Description: "Deployment selector labels must match template labels",
 		Severity:    SeverityError,
-		Check:       checkWK8101,
+		Rationale:   "The API server rejects a workload whose selector does not match its pod template labels. Sharing one label map keeps them in sync.",
+		Check:       visitorCheck(visitWK8101, compositeLits),
+		Visit:       visitWK8101,
+		Nodes:       compositeLits,
 		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var Web = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "frontend"}},
+		},
+	},
+}`,
+			Good: `var webLabels = map[string]string{"app": "web"}
+
+var Web = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{
+		Selector: &metav1.LabelSelector{MatchLabels: webLabels},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: webLabels},
+		},
+	},
+}`,
+		},
 	}
 }
 
-func checkWK8101(file *ast.File, fset *token.FileSet) []Issue {
+func visitWK8101(file *ast.File, fset *token.FileSet) Visitor {
 	var issues []Issue
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	visit := func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
@@ -68,9 +92,9 @@ func checkWK8101(file *ast.File, fset *token.FileSet) []Issue {
 		}
 
 		return true
-	})
+	}
 
-	return issues
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
 }
 
 // extractSelectorLabels extracts selector labels from a resource spec.
@@ -210,36 +234,50 @@ func RuleWK8102() Rule {
 		Name:        "Missing labels",
 		Description: "Resources should have metadata labels",
 		Severity:    SeverityWarning,
-		Check:       checkWK8102,
-		Fix:         nil,
+		Rationale:   "Labels are how resources are queried, grouped and selected, by kubectl, by other resources and by monitoring.",
+		Check:       visitorCheck(visitWK8102, compositeLits),
+		Visit:       visitWK8102,
+		Nodes:       compositeLits,
+		Fix:         nil, // Fixed by Fixer.fixWK8102
+		Example: RuleExample{
+			Bad: `var Web = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "web"},
+}`,
+			Good: `var Web = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:   "web",
+		Labels: map[string]string{"app.kubernetes.io/name": "web"},
+	},
+}`,
+		},
 	}
 }
 
-func checkWK8102(file *ast.File, fset *token.FileSet) []Issue {
-	var issues []Issue
+// labeledResourceTypes are the resource types WK8102 expects to have labels.
+var labeledResourceTypes = map[string]bool{
+	"Deployment":  true,
+	"Service":     true,
+	"Pod":         true,
+	"ConfigMap":   true,
+	"Secret":      true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+	"Ingress":     true,
+	"Job":         true,
+	"CronJob":     true,
+}
 
-	// K8s resource types that should have labels
-	resourceTypes := map[string]bool{
-		"Deployment":  true,
-		"Service":     true,
-		"Pod":         true,
-		"ConfigMap":   true,
-		"Secret":      true,
-		"StatefulSet": true,
-		"DaemonSet":   true,
-		"Ingress":     true,
-		"Job":         true,
-		"CronJob":     true,
-	}
+func visitWK8102(file *ast.File, fset *token.FileSet) Visitor {
+	var issues []Issue
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	visit := func(n ast.Node) bool {
 		compLit, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
 		}
 
 		resourceType := getResourceType(compLit)
-		if !resourceTypes[resourceType] {
+		if !labeledResourceTypes[resourceType] {
 			return true
 		}
 
@@ -293,7 +331,366 @@ func checkWK8102(file *ast.File, fset *token.FileSet) []Issue {
 		}
 
 		return true
+	}
+
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
+}
+
+// DefaultRecommendedLabels are the labels WK8013 expects unless
+// .wetwire.yaml configures lint.recommended_labels.
+var DefaultRecommendedLabels = []string{nameLabel, managedByLabel}
+
+// RuleWK8013 checks for the recommended app.kubernetes.io labels on
+// top-level resources.
+func RuleWK8013() Rule {
+	rule := recommendedLabelsRule(DefaultRecommendedLabels)
+	rule.Configure = func(config *Config) Rule {
+		return recommendedLabelsRule(config.recommendedLabels())
+	}
+	return rule
+}
+
+// recommendedLabelsRule returns WK8013 checking for the given labels.
+func recommendedLabelsRule(labels []string) Rule {
+	return Rule{
+		ID:          "WK8013",
+		Name:        "Recommended labels",
+		Description: "Top-level resources should have the recommended app.kubernetes.io labels",
+		Severity:    SeverityInfo,
+		Rationale:   "Tools such as kubectl, dashboards and cost reports group resources by the recommended labels, and app.kubernetes.io/managed-by tells operators not to edit the resources by hand.",
+		Check: func(file *ast.File, fset *token.FileSet) []Issue {
+			return checkWK8013(file, fset, labels)
+		},
+		Fix: nil, // Fixed by Fixer.fixWK8013
+		Example: RuleExample{
+			Bad: `var Web = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:   "web",
+		Labels: map[string]string{"app": "web"},
+	},
+}`,
+			Good: `var Web = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "web",
+		Labels: map[string]string{
+			"app.kubernetes.io/name":       "web",
+			"app.kubernetes.io/managed-by": "wetwire-k8s",
+		},
+	},
+}`,
+		},
+	}
+}
+
+func checkWK8013(file *ast.File, fset *token.FileSet, required []string) []Issue {
+	var issues []Issue
+
+	values := make(map[string]ast.Expr)
+	forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+		values[varName] = value
+	})
+
+	forEachLabeledResource(file, values, func(varName string, compLit *ast.CompositeLit, labels map[string]bool) {
+		var missing []string
+		for _, label := range required {
+			if !labels[label] {
+				missing = append(missing, label)
+			}
+		}
+		if len(missing) == 0 {
+			return
+		}
+
+		pos := fset.Position(compLit.Pos())
+		issues = append(issues, Issue{
+			Rule:     "WK8013",
+			Message:  fmt.Sprintf("%s is missing recommended labels: %s", varName, strings.Join(missing, ", ")),
+			File:     pos.Filename,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Severity: SeverityInfo,
+		})
 	})
 
 	return issues
 }
+
+// forEachLabeledResource calls fn with the label keys of every top-level
+// resource, i.e. every top-level literal with a metadata Name. Labels set
+// from a variable are resolved through values; resources whose labels cannot
+// be resolved statically are skipped.
+func forEachLabeledResource(file *ast.File, values map[string]ast.Expr, fn func(varName string, compLit *ast.CompositeLit, labels map[string]bool)) {
+	forEachTopLevelValue(file, func(varName string, value ast.Expr) {
+		compLit := unwrapCompositeLit(value)
+		if compLit == nil {
+			return
+		}
+		meta := metadataLiteral(compLit)
+		if meta == nil || getFieldValue(meta, "Name") == nil {
+			return
+		}
+
+		labels := make(map[string]bool)
+		if expr := getFieldValue(meta, "Labels"); expr != nil {
+			labelsLit := resolveCompositeLit(expr, values)
+			if labelsLit == nil {
+				if !isNilIdent(expr) {
+					return
+				}
+			} else {
+				for _, elt := range labelsLit.Elts {
+					kv, ok := elt.(*ast.KeyValueExpr)
+					if !ok {
+						return
+					}
+					key := stringLiteral(kv.Key)
+					if key == "" {
+						return
+					}
+					labels[key] = true
+				}
+			}
+		}
+
+		fn(varName, compLit, labels)
+	})
+}
+
+// RuleWK8305 checks that CronJobs set ConcurrencyPolicy and StartingDeadlineSeconds.
+func RuleWK8305() Rule {
+	return Rule{
+		ID:          "WK8305",
+		Name:        "CronJob scheduling policy",
+		Description: "CronJobs should set ConcurrencyPolicy and StartingDeadlineSeconds",
+		Severity:    SeverityInfo,
+		Rationale:   "The default Allow policy starts a new run even if the previous one is still going, and without a starting deadline a run missed during controller downtime may start arbitrarily late.",
+		Check:       visitorCheck(visitWK8305, compositeLits),
+		Visit:       visitWK8305,
+		Nodes:       compositeLits,
+		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var Backup = batchv1.CronJob{
+	Spec: batchv1.CronJobSpec{Schedule: "0 2 * * *"},
+}`,
+			Good: `var Backup = batchv1.CronJob{
+	Spec: batchv1.CronJobSpec{
+		Schedule:                "0 2 * * *",
+		ConcurrencyPolicy:       batchv1.ForbidConcurrent,
+		StartingDeadlineSeconds: ptr(int64(600)),
+	},
+}`,
+		},
+	}
+}
+
+func visitWK8305(file *ast.File, fset *token.FileSet) Visitor {
+	var issues []Issue
+
+	visit := func(n ast.Node) bool {
+		compLit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+
+		if getResourceType(compLit) != "CronJob" {
+			return true
+		}
+
+		// A CronJob without a Spec is missing both fields
+		var specLit *ast.CompositeLit
+		if spec := getFieldValue(compLit, "Spec"); spec != nil {
+			specLit = unwrapCompositeLit(spec)
+			if specLit == nil {
+				// Spec is built elsewhere, nothing to check here
+				return true
+			}
+		}
+
+		missing := []struct {
+			field   string
+			message string
+		}{
+			{"ConcurrencyPolicy", "CronJob should set Spec.ConcurrencyPolicy (defaults to Allow, which lets runs overlap)"},
+			{"StartingDeadlineSeconds", "CronJob should set Spec.StartingDeadlineSeconds so missed runs are not started arbitrarily late"},
+		}
+
+		pos := fset.Position(compLit.Pos())
+		for _, m := range missing {
+			if specLit != nil && getFieldValue(specLit, m.field) != nil {
+				continue
+			}
+			issues = append(issues, Issue{
+				Rule:     "WK8305",
+				Message:  m.message,
+				File:     pos.Filename,
+				Line:     pos.Line,
+				Column:   pos.Column,
+				Severity: SeverityInfo,
+			})
+		}
+
+		return true
+	}
+
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
+}
+
+// RuleWK8307 checks that Jobs bound how long they run or are kept.
+func RuleWK8307() Rule {
+	return Rule{
+		ID:          "WK8307",
+		Name:        "Job lifetime",
+		Description: "Jobs should set TTLSecondsAfterFinished or ActiveDeadlineSeconds",
+		Severity:    SeverityInfo,
+		Rationale:   "Without a TTL a finished Job and its pods stay in the cluster until deleted by hand, and without a deadline a stuck Job runs forever.",
+		Check:       visitorCheck(visitWK8307, compositeLits),
+		Visit:       visitWK8307,
+		Nodes:       compositeLits,
+		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var Migrate = batchv1.Job{
+	Spec: batchv1.JobSpec{Template: migratePodTemplate},
+}`,
+			Good: `var Migrate = batchv1.Job{
+	Spec: batchv1.JobSpec{
+		ActiveDeadlineSeconds:   ptr(int64(600)),
+		TTLSecondsAfterFinished: ptr(int32(3600)),
+		Template:                migratePodTemplate,
+	},
+}`,
+		},
+	}
+}
+
+func visitWK8307(file *ast.File, fset *token.FileSet) Visitor {
+	var issues []Issue
+
+	visit := func(n ast.Node) bool {
+		compLit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+
+		// Descend to the JobSpec: Spec for a Job, Spec.JobTemplate.Spec for
+		// a CronJob. A missing field means the JobSpec is empty, while a
+		// field built elsewhere cannot be checked here.
+		resourceType := getResourceType(compLit)
+		var path []string
+		switch resourceType {
+		case "Job":
+			path = []string{"Spec"}
+		case "CronJob":
+			path = []string{"Spec", "JobTemplate", "Spec"}
+		default:
+			return true
+		}
+
+		jobSpec := compLit
+		for _, field := range path {
+			value := getFieldValue(jobSpec, field)
+			if value == nil {
+				jobSpec = nil
+				break
+			}
+			if jobSpec = unwrapCompositeLit(value); jobSpec == nil {
+				return true
+			}
+		}
+
+		if jobSpec != nil && (getFieldValue(jobSpec, "TTLSecondsAfterFinished") != nil || getFieldValue(jobSpec, "ActiveDeadlineSeconds") != nil) {
+			return true
+		}
+
+		pos := fset.Position(compLit.Pos())
+		issues = append(issues, Issue{
+			Rule:     "WK8307",
+			Message:  fmt.Sprintf("%s should set TTLSecondsAfterFinished or ActiveDeadlineSeconds so finished pods are cleaned up and a stuck run is stopped", resourceType),
+			File:     pos.Filename,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Severity: SeverityInfo,
+		})
+
+		return true
+	}
+
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
+}
+
+// RuleWK8017 checks that workloads choose how their pods are replaced.
+func RuleWK8017() Rule {
+	return Rule{
+		ID:          "WK8017",
+		Name:        "Explicit update strategy",
+		Description: "Deployments should set Strategy and StatefulSets and DaemonSets UpdateStrategy",
+		Severity:    SeverityInfo,
+		Rationale:   "The default rollout, RollingUpdate with 25% surge and unavailability for Deployments and one pod at a time for StatefulSets and DaemonSets, is rarely tuned for a stateful or large workload. Setting the strategy records the choice and makes a rollout that is too slow, or takes down too much capacity, easier to spot in review.",
+		Check:       visitorCheck(visitWK8017, compositeLits),
+		Visit:       visitWK8017,
+		Nodes:       compositeLits,
+		Fix:         nil,
+		Example: RuleExample{
+			Bad: `var Web = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{Template: webPodTemplate},
+}`,
+			Good: `var Web = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{
+		Strategy: appsv1.DeploymentStrategy{
+			Type: appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{
+				MaxSurge:       ptr(intstr.FromInt(1)),
+				MaxUnavailable: ptr(intstr.FromInt(0)),
+			},
+		},
+		Template: webPodTemplate,
+	},
+}`,
+		},
+	}
+}
+
+// strategyFields are the spec fields holding the update strategy of each
+// workload kind, with the default applied when they are unset.
+var strategyFields = map[string]struct{ field, defaults string }{
+	"Deployment":  {"Strategy", "RollingUpdate with 25% maxSurge and maxUnavailable"},
+	"StatefulSet": {"UpdateStrategy", "RollingUpdate, one pod at a time"},
+	"DaemonSet":   {"UpdateStrategy", "RollingUpdate with maxUnavailable 1"},
+}
+
+func visitWK8017(file *ast.File, fset *token.FileSet) Visitor {
+	var issues []Issue
+
+	visit := func(n ast.Node) bool {
+		compLit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		resourceType := getResourceType(compLit)
+		strategy, ok := strategyFields[resourceType]
+		if !ok {
+			return true
+		}
+
+		// A missing Spec has no strategy, while a Spec built elsewhere
+		// cannot be checked here
+		if spec := getFieldValue(compLit, "Spec"); spec != nil {
+			specLit := unwrapCompositeLit(spec)
+			if specLit == nil || getFieldValue(specLit, strategy.field) != nil {
+				return true
+			}
+		}
+
+		pos := fset.Position(compLit.Pos())
+		issues = append(issues, Issue{
+			Rule:     "WK8017",
+			Message:  fmt.Sprintf("%s should set %s explicitly instead of relying on the default (%s)", resourceType, strategy.field, strategy.defaults),
+			File:     pos.Filename,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Severity: SeverityInfo,
+		})
+		return true
+	}
+
+	return Visitor{Visit: visit, Issues: func() []Issue { return issues }}
+}
@@ -0,0 +1,107 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// suppression silences rules on a range of lines. A nil rule set
+// silences every rule.
+type suppression struct {
+	startLine int
+	endLine   int
+	rules     map[string]bool
+}
+
+// suppressions holds the inline suppression directives of a file.
+type suppressions []suppression
+
+// parseSuppressions collects suppression directives from the comments of a
+// file. Two forms are recognized, each with an optional list of rule IDs:
+//
+//	// nolint:WK8006,WK8201
+//	// wetwire:ignore WK8006 WK8201
+//
+// Without rule IDs all rules are suppressed. A directive applies to the line
+// it is on; in the doc comment of a top-level declaration it applies to the
+// whole declaration.
+func parseSuppressions(file *ast.File, fset *token.FileSet) suppressions {
+	var result suppressions
+
+	// Doc comments cover the declaration they document
+	docs := make(map[*ast.CommentGroup]ast.Node)
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Doc != nil {
+			docs[genDecl.Doc] = genDecl
+		}
+	}
+
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			rules, ok := parseDirective(comment.Text)
+			if !ok {
+				continue
+			}
+
+			line := fset.Position(comment.Pos()).Line
+			s := suppression{startLine: line, endLine: line, rules: rules}
+			if decl, ok := docs[group]; ok {
+				s.endLine = fset.Position(decl.End()).Line
+			}
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+// parseDirective parses a single comment. It returns the suppressed rule
+// IDs (nil for all rules) and whether the comment is a directive.
+func parseDirective(text string) (map[string]bool, bool) {
+	text = strings.TrimSpace(strings.TrimPrefix(text, "//"))
+
+	// Anything after a nested comment is an explanation
+	if i := strings.Index(text, "//"); i >= 0 {
+		text = strings.TrimSpace(text[:i])
+	}
+
+	var list string
+	switch {
+	case text == "nolint" || text == "wetwire:ignore":
+		return nil, true
+	case strings.HasPrefix(text, "nolint:"):
+		list = strings.TrimPrefix(text, "nolint:")
+	case strings.HasPrefix(text, "wetwire:ignore "):
+		list = strings.TrimPrefix(text, "wetwire:ignore ")
+	default:
+		return nil, false
+	}
+
+	rules := make(map[string]bool)
+	for _, id := range strings.FieldsFunc(list, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	}) {
+		if strings.EqualFold(id, "all") {
+			return nil, true
+		}
+		rules[strings.ToUpper(id)] = true
+	}
+	if len(rules) == 0 {
+		return nil, true
+	}
+	return rules, true
+}
+
+// suppressed reports whether an issue is silenced by a directive.
+func (s suppressions) suppressed(issue Issue) bool {
+	for _, sup := range s {
+		if issue.Line < sup.startLine || issue.Line > sup.endLine {
+			continue
+		}
+		if sup.rules == nil || sup.rules[issue.Rule] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Resources declared as elements of a slice are linted like top-level ones
+
+var CollectionDeployments = []appsv1.Deployment{
+	{
+		ObjectMeta: metav1.ObjectMeta{Name: "pinned"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						corev1.Container{Name: "app", Image: "nginx:1.25"},
+					},
+				},
+			},
+		},
+	},
+	{
+		ObjectMeta: metav1.ObjectMeta{Name: "latest"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						corev1.Container{Name: "app", Image: "nginx:latest"},
+					},
+				},
+			},
+		},
+	},
+}
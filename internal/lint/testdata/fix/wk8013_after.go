@@ -0,0 +1,61 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8013 fix: a managed-by label is added to resources without one
+
+var appLabels = map[string]string{
+	"app.kubernetes.io/name":       "web",
+	"app.kubernetes.io/managed-by": "wetwire-k8s",
+}
+
+// A shared label map is edited once for every resource using it
+var WebDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:   "web",
+		Labels: appLabels,
+	},
+}
+
+var WebService = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:   "web",
+		Labels: appLabels,
+	},
+}
+
+// No Labels field
+var WebConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:   "web-config",
+		Labels: map[string]string{"app.kubernetes.io/managed-by": "wetwire-k8s"},
+	},
+}
+
+// Inline labels
+var WebSecret = corev1.Secret{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:   "web-secret",
+		Labels: map[string]string{"app.kubernetes.io/name": "web", "app.kubernetes.io/managed-by": "wetwire-k8s"},
+	},
+}
+
+// nil Labels
+var WebAccount = corev1.ServiceAccount{
+	ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"app.kubernetes.io/managed-by": "wetwire-k8s"}},
+}
+
+// Already labeled
+var WorkerConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "worker-config",
+		Labels: map[string]string{
+			"app.kubernetes.io/name":       "worker",
+			"app.kubernetes.io/managed-by": "helm",
+		},
+	},
+}
@@ -0,0 +1,45 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// WK8015 fix: a default size limit is added to emptyDirs without one, with
+// the resource import and a ptr helper
+
+// An empty emptyDir literal is filled in
+var PodSpecScratch = corev1.PodSpec{
+	Containers: []corev1.Container{
+		{Name: "app", Image: "nginx:1.21"},
+	},
+	Volumes: []corev1.Volume{
+		{
+			Name: "scratch",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: ptr(resource.MustParse("1Gi"))},
+			},
+		},
+	},
+}
+
+// Other fields are kept
+var PodSpecMemoryCache = corev1.PodSpec{
+	Containers: []corev1.Container{
+		{Name: "app", Image: "nginx:1.21"},
+	},
+	Volumes: []corev1.Volume{
+		{
+			Name: "cache",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium:    corev1.StorageMediumMemory,
+					SizeLimit: ptr(resource.MustParse("1Gi")),
+				},
+			},
+		},
+	},
+}
+
+// Helper function for pointer values
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,71 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8102 fix: a name label is added to resources without labels
+
+const workerName = "worker"
+
+// No Labels field
+var WebDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "web",
+		Namespace: "default",
+	},
+}
+
+// An empty map is filled in
+var WebService = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:   "web",
+		Labels: map[string]string{},
+	},
+}
+
+// nil Labels
+var WebConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-config", Labels: nil},
+}
+
+// The name may be a constant
+var WorkerDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: workerName,
+	},
+}
+
+// Metadata is accepted as well as ObjectMeta
+var WebPod = corev1.Pod{
+	Metadata: metav1.ObjectMeta{
+		Name: "web-pod",
+	},
+}
+
+// Existing labels are left alone
+var LabeledSecret = corev1.Secret{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:   "labeled",
+		Labels: map[string]string{"app": "web"},
+	},
+}
+
+// Labels from a variable are left alone
+var commonLabels = map[string]string{"app": "web"}
+
+var SharedLabelsConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:   "shared",
+		Labels: commonLabels,
+	},
+}
+
+// Resources without a name are left alone
+var UnnamedConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+	},
+}
@@ -0,0 +1,66 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// WK8201 fix: default limits are added to containers without limits
+
+// No Resources at all
+var ContainerNoResources = corev1.Container{
+	Name:  "app",
+	Image: "nginx:1.21",
+	Resources: corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+	},
+}
+
+// Requests are kept when Limits are added
+var ContainerOnlyRequests = corev1.Container{
+	Name:  "app",
+	Image: "nginx:1.21",
+	Resources: corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+	},
+}
+
+// An empty Limits literal is filled in
+var ContainerEmptyLimits = corev1.Container{
+	Name:  "app",
+	Image: "nginx:1.21",
+	Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("512Mi"),
+	}},
+}
+
+// Existing limits are left alone
+var ContainerWithLimits = corev1.Container{
+	Name:  "app",
+	Image: "nginx:1.21",
+	Resources: corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("1"),
+		},
+	},
+}
+
+// Shared resource requirements are left alone
+var SharedResources = corev1.ResourceRequirements{}
+
+var ContainerSharedResources = corev1.Container{
+	Name:      "app",
+	Image:     "nginx:1.21",
+	Resources: SharedResources,
+}
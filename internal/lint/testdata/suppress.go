@@ -0,0 +1,49 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Inline suppression comments
+// Each container uses an untagged image, which WK8006 flags
+
+var TrailingNolint = corev1.Container{
+	Name:  "trailing-nolint",
+	Image: "trailing-nolint", // nolint:WK8006
+}
+
+var TrailingIgnore = corev1.Container{
+	Name:  "trailing-ignore",
+	Image: "trailing-ignore", // wetwire:ignore WK8006
+}
+
+var MultipleRules = corev1.Container{
+	Name:  "multiple-rules",
+	Image: "multiple-rules", //nolint:WK8201,WK8006 // pinned by the deploy pipeline
+}
+
+var BareNolint = corev1.Container{
+	Name:  "bare-nolint",
+	Image: "bare-nolint", // nolint
+}
+
+// nolint:WK8006
+var DocComment = corev1.Container{
+	Name:  "doc-comment",
+	Image: "doc-comment",
+}
+
+var OtherRule = corev1.Container{
+	Name:  "other-rule",
+	Image: "other-rule", // nolint:WK8201
+}
+
+var OtherLine = corev1.Container{ // nolint:WK8006
+	Name:  "other-line",
+	Image: "other-line",
+}
+
+var NotSuppressed = corev1.Container{
+	Name:  "not-suppressed",
+	Image: "not-suppressed",
+}
@@ -0,0 +1,18 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8007: metadata.Name does not match the variable name
+// These names look copy-pasted from another resource
+
+var WebAppDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "api-server"},
+}
+
+var CacheService = &corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-app"},
+}
@@ -0,0 +1,29 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8007: metadata.Name does not match the variable name
+// These names are consistent with their variables
+
+const cacheName = "shared-cache"
+
+var WebAppDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-app"},
+}
+
+var WebAppService = &corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "webapp"},
+}
+
+var WebAppConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-app-config-v2"},
+}
+
+// Names set from a constant are not checked
+var CacheService = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: cacheName},
+}
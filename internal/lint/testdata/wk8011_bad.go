@@ -0,0 +1,53 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8011: Direct resource references
+// This file contains violations
+
+var AppConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+}
+
+var AppSecret = corev1.Secret{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-secret"},
+}
+
+var AppHeadless = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-headless"},
+}
+
+// Bad: references by hardcoded name instead of AppConfig.Name etc.
+var AppStatefulSet = appsv1.StatefulSet{
+	ObjectMeta: metav1.ObjectMeta{Name: "app"},
+	Spec: appsv1.StatefulSetSpec{
+		ServiceName: "app-headless",
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  "app",
+						Image: "app:1.0",
+						EnvFrom: []corev1.EnvFromSource{
+							{ConfigMapRef: &corev1.ConfigMapEnvSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"},
+							}},
+						},
+					},
+				},
+				Volumes: []corev1.Volume{
+					{
+						Name: "creds",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: "app-secret"},
+						},
+					},
+				},
+			},
+		},
+	},
+}
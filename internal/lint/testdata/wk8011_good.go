@@ -0,0 +1,46 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8011: Direct resource references
+// This file contains compliant resources
+
+var WebConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-config"},
+}
+
+// Good: direct reference to a resource in the same file, and a hardcoded
+// name for a Secret managed outside of this package
+var WebDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web"},
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						// Container names are not references
+						Name:  "web-config",
+						Image: "web:1.0",
+						EnvFrom: []corev1.EnvFromSource{
+							{ConfigMapRef: &corev1.ConfigMapEnvSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: WebConfig.Name},
+							}},
+						},
+					},
+				},
+				Volumes: []corev1.Volume{
+					{
+						Name: "tls",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: "external-tls"},
+						},
+					},
+				},
+			},
+		},
+	},
+}
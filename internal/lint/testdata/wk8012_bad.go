@@ -0,0 +1,80 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8012: Replicas with HPA
+// This file contains violations - Deployments with fixed replicas scaled by an HPA
+
+// Helper function for int32 pointer
+func ptrInt32_8012(i int32) *int32 {
+	return &i
+}
+
+// Bad: HPA refers to the Deployment by variable
+var APIDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "api",
+	},
+	Spec: appsv1.DeploymentSpec{
+		Replicas: ptrInt32_8012(3),
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "api", Image: "api:1.0"},
+				},
+			},
+		},
+	},
+}
+
+var APIHPA = autoscalingv2.HorizontalPodAutoscaler{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "api",
+	},
+	Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       APIDeployment.Name,
+		},
+		MinReplicas: ptrInt32_8012(2),
+		MaxReplicas: 10,
+	},
+}
+
+// Bad: HPA refers to the Deployment by metadata name
+var WebDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "web",
+	},
+	Spec: appsv1.DeploymentSpec{
+		Replicas: ptrInt32_8012(2), // Initial replicas, HPA will manage this
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "web", Image: "nginx:1.25"},
+				},
+			},
+		},
+	},
+}
+
+var WebHPA = autoscalingv2.HorizontalPodAutoscaler{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "web",
+	},
+	Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       "web",
+		},
+		MinReplicas: ptrInt32_8012(2),
+		MaxReplicas: 20,
+	},
+}
@@ -0,0 +1,79 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8012: Replicas with HPA
+// This file contains no violations
+
+// Helper function for int32 pointer
+func ptrInt32_8012Good(i int32) *int32 {
+	return &i
+}
+
+// Good: the HPA owns the replica count
+var APIDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "api",
+	},
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "api", Image: "api:1.0"},
+				},
+			},
+		},
+	},
+}
+
+var APIHPA = autoscalingv2.HorizontalPodAutoscaler{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "api",
+	},
+	Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       APIDeployment.Name,
+		},
+		MinReplicas: ptrInt32_8012Good(3),
+		MaxReplicas: 10,
+	},
+}
+
+// Good: fixed replicas on a Deployment no HPA targets
+var WorkerDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "worker",
+	},
+	Spec: appsv1.DeploymentSpec{
+		Replicas: ptrInt32_8012Good(2),
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "worker", Image: "worker:1.0"},
+				},
+			},
+		},
+	},
+}
+
+// Good: an HPA scaling a StatefulSet with the same name does not target the Deployment
+var WorkerHPA = autoscalingv2.HorizontalPodAutoscaler{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "worker",
+	},
+	Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "StatefulSet",
+			Name:       "worker",
+		},
+		MaxReplicas: 5,
+	},
+}
@@ -0,0 +1,39 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8013: Recommended labels
+// This file contains violations - resources without the recommended labels
+
+// Bad: no labels at all
+var WebService8013 = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "web",
+	},
+}
+
+// Bad: ad-hoc labels only
+var WebConfig8013 = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "web-config",
+		Labels: map[string]string{
+			"app": "web",
+		},
+	},
+}
+
+var apiLabels8013 = map[string]string{
+	"app.kubernetes.io/name": "api",
+}
+
+// Bad: the shared label map lacks app.kubernetes.io/managed-by
+var APIDeployment8013 = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:   "api",
+		Labels: apiLabels8013,
+	},
+}
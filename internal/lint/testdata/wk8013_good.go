@@ -0,0 +1,64 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8013: Recommended labels
+// This file contains no violations
+
+var appLabels8013Good = map[string]string{
+	"app.kubernetes.io/name":       "web",
+	"app.kubernetes.io/managed-by": "wetwire-k8s",
+}
+
+// Good: recommended labels through a shared map
+var WebDeployment8013Good = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:   "web",
+		Labels: appLabels8013Good,
+	},
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			// Pod templates are not top-level resources
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"app": "web"},
+			},
+		},
+	},
+}
+
+// Good: recommended labels inline, alongside others
+var WebService8013Good = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "web",
+		Labels: map[string]string{
+			"app.kubernetes.io/name":       "web",
+			"app.kubernetes.io/managed-by": "wetwire-k8s",
+			"team":                         "storefront",
+		},
+	},
+}
+
+// Good: labels computed at runtime cannot be checked
+var WebConfig8013Good = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:   "web-config",
+		Labels: withComponent(appLabels8013Good, "config"),
+	},
+}
+
+func withComponent(labels map[string]string, component string) map[string]string {
+	out := map[string]string{"app.kubernetes.io/component": component}
+	for k, v := range labels {
+		out[k] = v
+	}
+	return out
+}
+
+// Good: shared ObjectMeta without a Name is not a resource
+var commonMeta8013Good = metav1.ObjectMeta{
+	Namespace: "web",
+}
@@ -0,0 +1,49 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WK8014: Pin images by digest
+// This file contains violations
+
+// Bad: Version tag without a digest
+var Deployment8014 = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{
+					corev1.Container{
+						Name:  "migrate",
+						Image: "example.com/migrate:v2", // Tag only
+					},
+				},
+				Containers: []corev1.Container{
+					corev1.Container{
+						Name:  "app",
+						Image: "nginx:1.25.3", // Tag only
+					},
+				},
+			},
+		},
+	},
+}
+
+// Bad: Helper container without a tag or digest
+var Sidecar8014 = corev1.Container{
+	Name:  "proxy",
+	Image: "envoyproxy/envoy",
+}
+
+// Bad: Truncated digest
+var Pod8014 = corev1.Pod{
+	Spec: corev1.PodSpec{
+		Containers: []corev1.Container{
+			corev1.Container{
+				Name:  "app",
+				Image: "busybox@sha256:0d17b565",
+			},
+		},
+	},
+}
@@ -0,0 +1,31 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WK8014: Pin images by digest
+// This file contains compliant code
+
+// Good: Pinned by digest
+var GoodDeployment8014 = appsv1.Deployment{
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					corev1.Container{
+						Name:  "app",
+						Image: "nginx@sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31",
+					},
+				},
+			},
+		},
+	},
+}
+
+// Good: Tag and digest, the digest is what is pulled
+var GoodSidecar8014 = corev1.Container{
+	Name:  "proxy",
+	Image: "envoyproxy/envoy:v1.28.0@sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31",
+}
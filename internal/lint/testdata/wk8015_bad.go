@@ -0,0 +1,47 @@
+package testdata
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WK8015: EmptyDir size limit
+// This file contains violations
+
+// Bad: Job scratch space without a size limit
+var JobScratch = batchv1.Job{
+	Spec: batchv1.JobSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "worker", Image: "busybox:1.36"},
+				},
+				Volumes: []corev1.Volume{
+					{
+						Name: "data",
+						VolumeSource: corev1.VolumeSource{
+							EmptyDir: &corev1.EmptyDirVolumeSource{},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+// Bad: Memory-backed emptyDir without a size limit
+var PodSpecMemoryCache = corev1.PodSpec{
+	Containers: []corev1.Container{
+		{Name: "app", Image: "nginx:1.21"},
+	},
+	Volumes: []corev1.Volume{
+		{
+			Name: "cache",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium: corev1.StorageMediumMemory,
+				},
+			},
+		},
+	},
+}
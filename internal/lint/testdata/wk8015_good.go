@@ -0,0 +1,48 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// WK8015: EmptyDir size limit
+// This file contains no violations
+
+// Helper function for Quantity pointer
+func ptrQuantity8015Good(q resource.Quantity) *resource.Quantity {
+	return &q
+}
+
+// Good: emptyDir with a size limit
+var PodSpecBoundedScratch = corev1.PodSpec{
+	Containers: []corev1.Container{
+		{Name: "app", Image: "nginx:1.21"},
+	},
+	Volumes: []corev1.Volume{
+		{
+			Name: "scratch",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					SizeLimit: ptrQuantity8015Good(resource.MustParse("1Gi")),
+				},
+			},
+		},
+	},
+}
+
+// Good: volumes that are not emptyDirs
+var PodSpecConfigVolume = corev1.PodSpec{
+	Containers: []corev1.Container{
+		{Name: "app", Image: "nginx:1.21"},
+	},
+	Volumes: []corev1.Volume{
+		{
+			Name: "config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,51 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// WK8016: Service target port exposed
+// This file contains violations - Services targeting ports that the
+// containers of the selected pods do not expose
+
+var apiLabels8016 = map[string]string{"app": "api-8016"}
+
+var APIContainer8016 = corev1.Container{
+	Name:  "api",
+	Image: "example/api:1.4.2",
+	Ports: []corev1.ContainerPort{
+		{Name: "http", ContainerPort: 8080},
+		{Name: "metrics", ContainerPort: 9090},
+	},
+}
+
+var APIDeployment8016 = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "api-8016"},
+	Spec: appsv1.DeploymentSpec{
+		Selector: &metav1.LabelSelector{MatchLabels: apiLabels8016},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: apiLabels8016},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{APIContainer8016},
+			},
+		},
+	},
+}
+
+var APIService8016 = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "api-8016"},
+	Spec: corev1.ServiceSpec{
+		Selector: apiLabels8016,
+		Ports: []corev1.ServicePort{
+			// Bad: the container listens on 8080, not 80
+			{Name: "http", Port: 80, TargetPort: intstr.FromInt(80)},
+			// Bad: the container port is named metrics
+			{Name: "metrics", Port: 9090, TargetPort: intstr.FromString("prometheus")},
+			// Bad: without a targetPort, traffic goes to the Service port
+			{Name: "grpc", Port: 9000},
+		},
+	},
+}
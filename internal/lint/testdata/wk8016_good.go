@@ -0,0 +1,84 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// WK8016: Service target port exposed
+// This file passes - every Service targets a port the selected containers
+// expose, by name or by number
+
+var webLabels8016 = map[string]string{"app": "web-8016"}
+
+var WebDeployment8016 = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-8016"},
+	Spec: appsv1.DeploymentSpec{
+		Selector: &metav1.LabelSelector{MatchLabels: webLabels8016},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: webLabels8016},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  "web",
+						Image: "nginx:1.27",
+						Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+					},
+					{
+						Name:  "exporter",
+						Image: "nginx/nginx-prometheus-exporter:1.3.0",
+						Ports: []corev1.ContainerPort{{ContainerPort: 9113}},
+					},
+				},
+			},
+		},
+	},
+}
+
+var WebService8016 = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-8016"},
+	Spec: corev1.ServiceSpec{
+		Selector: webLabels8016,
+		Ports: []corev1.ServicePort{
+			{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+			{Name: "metrics", Port: 9113, TargetPort: intstr.FromInt32(9113)},
+			{Name: "alt", Port: 8080},
+		},
+	},
+}
+
+// Good: ports of a container that cannot be resolved statically are not
+// checked
+var cacheContainers8016 = cacheContainers()
+
+var CacheDeployment8016 = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "cache-8016"},
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "cache-8016"}},
+			Spec:       corev1.PodSpec{Containers: cacheContainers8016},
+		},
+	},
+}
+
+var CacheService8016 = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "cache-8016"},
+	Spec: corev1.ServiceSpec{
+		Selector: map[string]string{"app": "cache-8016"},
+		Ports:    []corev1.ServicePort{{Port: 6379}},
+	},
+}
+
+// Good: a Service without a selector has manually managed endpoints
+var ExternalDB8016 = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "db-8016"},
+	Spec: corev1.ServiceSpec{
+		Ports: []corev1.ServicePort{{Port: 5432, TargetPort: intstr.FromInt(5432)}},
+	},
+}
+
+func cacheContainers() []corev1.Container {
+	return []corev1.Container{{Name: "redis", Image: "redis:7.4"}}
+}
@@ -0,0 +1,45 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func ptrInt32_8017(i int32) *int32 {
+	return &i
+}
+
+// WK8017: Explicit update strategy
+// This file contains violations - workloads relying on the default update
+// strategy
+
+var apiPodTemplate8017 = corev1.PodTemplateSpec{
+	ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "api-8017"}},
+	Spec: corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "api", Image: "example/api:1.4.2"}},
+	},
+}
+
+// Bad: no Strategy
+var APIDeployment8017 = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "api-8017"},
+	Spec: appsv1.DeploymentSpec{
+		Replicas: ptrInt32_8017(3),
+		Template: apiPodTemplate8017,
+	},
+}
+
+// Bad: no UpdateStrategy
+var DBStatefulSet8017 = appsv1.StatefulSet{
+	ObjectMeta: metav1.ObjectMeta{Name: "db-8017"},
+	Spec: appsv1.StatefulSetSpec{
+		ServiceName: "db-8017",
+		Template:    apiPodTemplate8017,
+	},
+}
+
+// Bad: no Spec at all
+var LogAgent8017 = appsv1.DaemonSet{
+	ObjectMeta: metav1.ObjectMeta{Name: "log-agent-8017"},
+}
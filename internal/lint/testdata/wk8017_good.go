@@ -0,0 +1,68 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func ptrGood8017[T any](v T) *T {
+	return &v
+}
+
+// WK8017: Explicit update strategy
+// This file passes - every workload sets its update strategy
+
+var webPodTemplate8017 = corev1.PodTemplateSpec{
+	ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web-8017"}},
+	Spec: corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "web", Image: "nginx:1.27"}},
+	},
+}
+
+var WebDeployment8017 = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-8017"},
+	Spec: appsv1.DeploymentSpec{
+		Strategy: appsv1.DeploymentStrategy{
+			Type: appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{
+				MaxSurge:       ptrGood8017(intstr.FromInt(1)),
+				MaxUnavailable: ptrGood8017(intstr.FromInt(0)),
+			},
+		},
+		Template: webPodTemplate8017,
+	},
+}
+
+var CacheStatefulSet8017 = appsv1.StatefulSet{
+	ObjectMeta: metav1.ObjectMeta{Name: "cache-8017"},
+	Spec: appsv1.StatefulSetSpec{
+		ServiceName: "cache-8017",
+		UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+			Type: appsv1.OnDeleteStatefulSetStrategyType,
+		},
+		Template: webPodTemplate8017,
+	},
+}
+
+var NodeExporter8017 = appsv1.DaemonSet{
+	ObjectMeta: metav1.ObjectMeta{Name: "node-exporter-8017"},
+	Spec: appsv1.DaemonSetSpec{
+		UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+			Type: appsv1.RollingUpdateDaemonSetStrategyType,
+		},
+		Template: webPodTemplate8017,
+	},
+}
+
+// Good: a spec built elsewhere cannot be checked
+var Worker8017 = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "worker-8017"},
+	Spec:       workerSpec8017,
+}
+
+var workerSpec8017 = appsv1.DeploymentSpec{
+	Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+	Template: webPodTemplate8017,
+}
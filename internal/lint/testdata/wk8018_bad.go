@@ -0,0 +1,23 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8018: Namespace on cluster-scoped resource
+// This file contains violations
+
+// Bad: ClusterRole with a namespace
+var NodeReader8018 = rbacv1.ClusterRole{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "node-reader",
+		Namespace: "default",
+	},
+}
+
+// Bad: Namespace with a namespace
+var Team8018 = &corev1.Namespace{
+	ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "kube-system"},
+}
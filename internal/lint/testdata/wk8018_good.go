@@ -0,0 +1,28 @@
+package testdata
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8018: Namespace on cluster-scoped resource
+// This file contains compliant code
+
+// Good: ClusterRole without a namespace
+var GoodNodeReader8018 = rbacv1.ClusterRole{
+	ObjectMeta: metav1.ObjectMeta{Name: "node-reader"},
+}
+
+// Good: namespaced Role with a namespace
+var GoodPodReader8018 = rbacv1.Role{
+	ObjectMeta: metav1.ObjectMeta{Name: "pod-reader", Namespace: "default"},
+}
+
+// Good: ClusterRoleBinding subjects carry the namespace of their
+// ServiceAccount, not the binding
+var GoodNodeReaderBinding8018 = rbacv1.ClusterRoleBinding{
+	ObjectMeta: metav1.ObjectMeta{Name: "node-reader"},
+	Subjects: []rbacv1.Subject{
+		{Kind: "ServiceAccount", Name: "app", Namespace: "default"},
+	},
+}
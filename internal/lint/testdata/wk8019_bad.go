@@ -0,0 +1,29 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8019: Incomplete owner reference
+// This file contains violations
+
+// Bad: owner reference without an APIVersion or UID
+var OwnedConfig8019 = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "owned-config",
+		OwnerReferences: []metav1.OwnerReference{
+			{Kind: "Deployment", Name: "web"},
+		},
+	},
+}
+
+// Bad: explicitly typed owner reference without a UID
+var OwnedService8019 = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "owned-service",
+		OwnerReferences: []metav1.OwnerReference{
+			metav1.OwnerReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+		},
+	},
+}
@@ -0,0 +1,27 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// WK8019: Incomplete owner reference
+// This file contains compliant code
+
+const webUID8019 = types.UID("6f1f5c1e-3c4b-4f7e-9a51-2d0e8b7c9a10")
+
+// Good: owner reference with every required field
+var OwnedConfig8019Good = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "owned-config",
+		OwnerReferences: []metav1.OwnerReference{
+			{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: webUID8019},
+		},
+	},
+}
+
+// Good: no owner references; the build can set them with --owner-references
+var UnownedService8019 = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "unowned-service"},
+}
@@ -0,0 +1,28 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8043: Secret values in ConfigMap
+// This file contains violations
+
+// Bad: credentials under password and token keys
+var AppSettings8043 = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-settings"},
+	Data: map[string]string{
+		"LOG_LEVEL":   "info",
+		"DB_PASSWORD": "hunter2-prod",
+		"authToken":   "ghp_8f3kLm2Qx9",
+		"api-key":     "ak-live-7731",
+	},
+}
+
+// Bad: base64-encoded key material under an innocuous key
+var SigningConfig8043 = &corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "signing-config"},
+	Data: map[string]string{
+		"signing.material": "q7Rz3VbN9xKp2LmW8yTf4HsJ6dGc1AeQ0uIo5PkXnM=",
+	},
+}
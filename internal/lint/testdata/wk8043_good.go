@@ -0,0 +1,34 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8043: Secret values in ConfigMap
+// This file contains compliant code
+
+// Good: settings about credentials, not the credentials themselves
+var AuthSettings8043 = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "auth-settings"},
+	Data: map[string]string{
+		"token-ttl":        "3600",
+		"token-expiry-ms":  "86400000",
+		"password-file":    "/etc/app/secrets/password",
+		"DB_PASSWORD":      "${DB_PASSWORD}",
+		"tokenizer":        "bert-base-uncased-whole-word",
+		"secret-rotation":  "weekly",
+		"api-key-header":   "X-API-Key",
+		"image-digest":     "3f29c1b8e0d74a6f9b2c5e8a1d4f7b0c3e6a9d2f5b8c1e4a7d0f3b6c9e2a5d8f",
+		"feature.flags":    "enableCheckoutV2AndDisableLegacyCartFlowForAllUsers",
+		"cluster.endpoint": "https://api.example.com:6443",
+	},
+}
+
+// Good: credentials in a Secret
+var AppCredentials8043 = corev1.Secret{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-credentials"},
+	StringData: map[string]string{
+		"DB_PASSWORD": "hunter2-prod",
+	},
+}
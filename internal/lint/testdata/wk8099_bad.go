@@ -0,0 +1,16 @@
+package testdata
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8099: NetworkPolicy without PolicyTypes
+// Kubernetes infers the policy types from the rules that are present
+
+var DenyAllIngress = networkingv1.NetworkPolicy{
+	ObjectMeta: metav1.ObjectMeta{Name: "deny-all-ingress"},
+	Spec: networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{},
+	},
+}
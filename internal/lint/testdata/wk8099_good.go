@@ -0,0 +1,17 @@
+package testdata
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8099: NetworkPolicy without PolicyTypes
+// This policy declares its policy types explicitly
+
+var DenyAllIngress = networkingv1.NetworkPolicy{
+	ObjectMeta: metav1.ObjectMeta{Name: "deny-all-ingress"},
+	Spec: networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+	},
+}
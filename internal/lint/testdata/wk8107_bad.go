@@ -0,0 +1,74 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8107: Config references defined
+// This file contains violations - references to ConfigMaps and Secrets that
+// are not defined
+
+const dbSecretName8107 = "db-credentials-8107"
+
+var AppConfig8107 = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-config-8107"},
+}
+
+var AppSecret8107 = corev1.Secret{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-secret-8107"},
+}
+
+var App8107 = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "app-8107"},
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  "app",
+						Image: "app:1.0",
+						Env: []corev1.EnvVar{
+							{
+								Name: "LOG_LEVEL",
+								ValueFrom: &corev1.EnvVarSource{
+									// Bad: typo in the ConfigMap name
+									ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: "app-confg-8107"},
+										Key:                  "log-level",
+									},
+								},
+							},
+							{
+								Name: "DB_PASSWORD",
+								ValueFrom: &corev1.EnvVarSource{
+									// Bad: the Secret named by the constant is not defined
+									SecretKeyRef: &corev1.SecretKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: dbSecretName8107},
+										Key:                  "password",
+									},
+								},
+							},
+						},
+						EnvFrom: []corev1.EnvFromSource{
+							// Bad: a Secret name used as a ConfigMap
+							{ConfigMapRef: &corev1.ConfigMapEnvSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "app-secret-8107"},
+							}},
+						},
+					},
+				},
+				Volumes: []corev1.Volume{
+					{
+						Name: "tls",
+						VolumeSource: corev1.VolumeSource{
+							// Bad: the TLS Secret is not defined
+							Secret: &corev1.SecretVolumeSource{SecretName: "app-tls-8107"},
+						},
+					},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,89 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8107: Config references defined
+// This file passes - every ConfigMap and Secret referenced is defined, built
+// in, optional or referred to through its variable
+
+const webSecretName8107 = "web-secret-8107"
+
+var WebConfig8107 = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-config-8107"},
+}
+
+var WebSecret8107 = corev1.Secret{
+	ObjectMeta: metav1.ObjectMeta{Name: webSecretName8107},
+}
+
+var Web8107 = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-8107"},
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  "web",
+						Image: "web:1.0",
+						Env: []corev1.EnvVar{
+							{
+								Name: "API_TOKEN",
+								ValueFrom: &corev1.EnvVarSource{
+									SecretKeyRef: &corev1.SecretKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: "web-secret-8107"},
+										Key:                  "token",
+									},
+								},
+							},
+							{
+								Name: "FEATURE_FLAGS",
+								ValueFrom: &corev1.EnvVarSource{
+									// Optional references may be missing
+									ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: "web-flags-8107"},
+										Key:                  "flags",
+										Optional:             ptrBool(true),
+									},
+								},
+							},
+						},
+						EnvFrom: []corev1.EnvFromSource{
+							{ConfigMapRef: &corev1.ConfigMapEnvSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: WebConfig8107.Name},
+							}},
+						},
+					},
+				},
+				Volumes: []corev1.Volume{
+					{
+						Name: "config",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "web-config-8107"},
+							},
+						},
+					},
+					{
+						Name: "ca",
+						VolumeSource: corev1.VolumeSource{
+							// Created by Kubernetes in every namespace
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "kube-root-ca.crt"},
+							},
+						},
+					},
+					{
+						Name: "secret",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: webSecretName8107},
+						},
+					},
+				},
+			},
+		},
+	},
+}
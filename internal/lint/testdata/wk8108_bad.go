@@ -0,0 +1,64 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8108: Ingress backend defined
+// This file contains violations - Ingress backends that route to missing
+// Services and ports
+
+var ShopService8108 = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "shop-8108"},
+	Spec: corev1.ServiceSpec{
+		Ports: []corev1.ServicePort{
+			{Name: "http", Port: 80},
+		},
+	},
+}
+
+var ShopIngress8108 = networkingv1.Ingress{
+	ObjectMeta: metav1.ObjectMeta{Name: "shop-8108"},
+	Spec: networkingv1.IngressSpec{
+		// Bad: the Service was renamed to shop-8108
+		DefaultBackend: &networkingv1.IngressBackend{
+			Service: &networkingv1.IngressServiceBackend{
+				Name: "storefront-8108",
+				Port: networkingv1.ServiceBackendPort{Number: 80},
+			},
+		},
+		Rules: []networkingv1.IngressRule{
+			{
+				Host: "shop.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{
+								Path: "/",
+								Backend: networkingv1.IngressBackend{
+									// Bad: the Service port is named http
+									Service: &networkingv1.IngressServiceBackend{
+										Name: ShopService8108.Name,
+										Port: networkingv1.ServiceBackendPort{Name: "web"},
+									},
+								},
+							},
+							{
+								Path: "/api",
+								Backend: networkingv1.IngressBackend{
+									// Bad: the Service does not listen on 8080
+									Service: &networkingv1.IngressServiceBackend{
+										Name: "shop-8108",
+										Port: networkingv1.ServiceBackendPort{Number: 8080},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,64 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8108: Ingress backend defined
+// This file passes - every backend routes to a Service port defined in the
+// package
+
+const blogName8108 = "blog-8108"
+
+var BlogService8108 = &corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: blogName8108},
+	Spec: corev1.ServiceSpec{
+		Ports: []corev1.ServicePort{
+			{Name: "http", Port: 80},
+			{Name: "admin", Port: 9000},
+		},
+	},
+}
+
+var BlogIngress8108 = &networkingv1.Ingress{
+	ObjectMeta: metav1.ObjectMeta{Name: "blog-8108"},
+	Spec: networkingv1.IngressSpec{
+		DefaultBackend: &networkingv1.IngressBackend{
+			Service: &networkingv1.IngressServiceBackend{
+				Name: blogName8108,
+				Port: networkingv1.ServiceBackendPort{Number: 80},
+			},
+		},
+		Rules: []networkingv1.IngressRule{
+			{
+				Host: "blog.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{
+								Path: "/",
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: BlogService8108.Name,
+										Port: networkingv1.ServiceBackendPort{Name: "http"},
+									},
+								},
+							},
+							{
+								Path: "/admin",
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: "blog-8108",
+										Port: networkingv1.ServiceBackendPort{Number: 9000},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}
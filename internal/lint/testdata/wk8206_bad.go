@@ -0,0 +1,41 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WK8206: No root user
+// This file contains violations
+
+func ptr8206[T any](v T) *T {
+	return &v
+}
+
+// Bad: Container running as root user
+var ContainerRootUser = corev1.Container{
+	Name:  "app",
+	Image: "nginx:1.21",
+	SecurityContext: &corev1.SecurityContext{
+		RunAsUser: ptr8206(int64(0)),
+	},
+}
+
+// Bad: Container running with root group
+var ContainerRootGroup = corev1.Container{
+	Name:  "app",
+	Image: "nginx:1.21",
+	SecurityContext: &corev1.SecurityContext{
+		RunAsUser:  ptr8206(int64(1000)),
+		RunAsGroup: ptr8206(int64(0)),
+	},
+}
+
+// Bad: Pod running as root user
+var PodSpecRootUser = corev1.PodSpec{
+	SecurityContext: &corev1.PodSecurityContext{
+		RunAsUser: ptr8206(int64(0)),
+	},
+	Containers: []corev1.Container{
+		{Name: "app", Image: "nginx:1.21"},
+	},
+}
@@ -0,0 +1,39 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WK8206: No root user
+// This file contains compliant resources
+
+func ptrGood8206[T any](v T) *T {
+	return &v
+}
+
+// Good: Container running as an unprivileged user and group
+var ContainerNonRootUser = corev1.Container{
+	Name:  "app",
+	Image: "nginx:1.21",
+	SecurityContext: &corev1.SecurityContext{
+		RunAsUser:  ptrGood8206(int64(1000)),
+		RunAsGroup: ptrGood8206(int64(3000)),
+	},
+}
+
+// Good: Pod running as an unprivileged user
+var PodSpecNonRootUser = corev1.PodSpec{
+	SecurityContext: &corev1.PodSecurityContext{
+		RunAsUser:  ptrGood8206(int64(1000)),
+		RunAsGroup: ptrGood8206(int64(3000)),
+	},
+	Containers: []corev1.Container{
+		{Name: "app", Image: "nginx:1.21"},
+	},
+}
+
+// Good: Container without an explicit user
+var ContainerDefaultUser = corev1.Container{
+	Name:  "app",
+	Image: "nginx:1.21",
+}
@@ -0,0 +1,42 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WK8210: No hostPath volumes
+// This file contains violations
+
+// Bad: PodSpec mounting the host's Docker socket
+var PodSpecHostPathSocket = corev1.PodSpec{
+	Containers: []corev1.Container{
+		{Name: "app", Image: "nginx:1.21"},
+	},
+	Volumes: []corev1.Volume{
+		{
+			Name: "docker-socket",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: "/var/run/docker.sock",
+				},
+			},
+		},
+	},
+}
+
+// Bad: PodSpec with a hostPath whose path is not a literal
+var hostLogDir = "/var/log"
+
+var PodSpecHostPathVariable = corev1.PodSpec{
+	Containers: []corev1.Container{
+		{Name: "app", Image: "nginx:1.21"},
+	},
+	Volumes: []corev1.Volume{
+		{
+			Name: "logs",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{Path: hostLogDir},
+			},
+		},
+	},
+}
@@ -0,0 +1,31 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WK8210: No hostPath volumes
+// This file contains compliant resources
+
+// Good: PodSpec using emptyDir and configMap volumes
+var PodSpecNoHostPath = corev1.PodSpec{
+	Containers: []corev1.Container{
+		{Name: "app", Image: "nginx:1.21"},
+	},
+	Volumes: []corev1.Volume{
+		{
+			Name: "cache",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		{
+			Name: "config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"},
+				},
+			},
+		},
+	},
+}
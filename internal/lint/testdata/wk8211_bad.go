@@ -0,0 +1,29 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8211: Disable service account token automount
+// This file contains violations
+
+// Bad: PodSpec using the default service account with the token mounted
+var PodSpecDefaultToken = corev1.PodSpec{
+	Containers: []corev1.Container{
+		{Name: "app", Image: "nginx:1.21"},
+	},
+}
+
+// Bad: PodSpec naming the default service account explicitly
+var PodSpecExplicitDefault = corev1.PodSpec{
+	ServiceAccountName: "default",
+	Containers: []corev1.Container{
+		{Name: "app", Image: "nginx:1.21"},
+	},
+}
+
+// Bad: ServiceAccount that automounts its token by default
+var AppServiceAccount = corev1.ServiceAccount{
+	ObjectMeta: metav1.ObjectMeta{Name: "app"},
+}
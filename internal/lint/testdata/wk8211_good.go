@@ -0,0 +1,49 @@
+package testdata
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8211: Disable service account token automount
+// This file contains compliant resources
+
+func ptrBoolGood8211(b bool) *bool {
+	return &b
+}
+
+// Good: PodSpec opting out of the token
+var PodSpecNoToken = corev1.PodSpec{
+	AutomountServiceAccountToken: ptrBoolGood8211(false),
+	Containers: []corev1.Container{
+		{Name: "app", Image: "nginx:1.21"},
+	},
+}
+
+// Good: PodSpec running as a dedicated service account
+var PodSpecNamedAccount = corev1.PodSpec{
+	ServiceAccountName: "controller",
+	Containers: []corev1.Container{
+		{Name: "controller", Image: "controller:1.0"},
+	},
+}
+
+// Good: ServiceAccount that does not automount its token
+var WorkerServiceAccount = corev1.ServiceAccount{
+	ObjectMeta:                   metav1.ObjectMeta{Name: "worker"},
+	AutomountServiceAccountToken: ptrBoolGood8211(false),
+}
+
+// Good: PodSpec of the default service account that needs its token
+var PodSpecNeedsToken = corev1.PodSpec{
+	AutomountServiceAccountToken: ptrBoolGood8211(true),
+	Containers: []corev1.Container{
+		{Name: "kubectl", Image: "bitnami/kubectl:1.30"},
+	},
+}
+
+// Good: ServiceAccount whose pods need the token
+var OperatorServiceAccount = corev1.ServiceAccount{
+	ObjectMeta:                   metav1.ObjectMeta{Name: "operator"},
+	AutomountServiceAccountToken: ptrBoolGood8211(true),
+}
@@ -0,0 +1,39 @@
+package testdata
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8305: CronJob scheduling policy
+// This file contains violations
+
+func ptrInt64_8305(i int64) *int64 {
+	return &i
+}
+
+// Bad: CronJob without ConcurrencyPolicy or StartingDeadlineSeconds
+var NightlyBackup = batchv1.CronJob{
+	ObjectMeta: metav1.ObjectMeta{Name: "nightly-backup"},
+	Spec: batchv1.CronJobSpec{
+		Schedule: "0 2 * * *",
+	},
+}
+
+// Bad: CronJob without StartingDeadlineSeconds
+var HourlyReport = &batchv1.CronJob{
+	ObjectMeta: metav1.ObjectMeta{Name: "hourly-report"},
+	Spec: batchv1.CronJobSpec{
+		Schedule:          "0 * * * *",
+		ConcurrencyPolicy: batchv1.ForbidConcurrent,
+	},
+}
+
+// Bad: CronJob without ConcurrencyPolicy
+var CleanupJob = batchv1.CronJob{
+	ObjectMeta: metav1.ObjectMeta{Name: "cleanup"},
+	Spec: batchv1.CronJobSpec{
+		Schedule:                "*/15 * * * *",
+		StartingDeadlineSeconds: ptrInt64_8305(300),
+	},
+}
@@ -0,0 +1,28 @@
+package testdata
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8305: CronJob scheduling policy
+// This file contains compliant resources
+
+func ptrInt64Good8305(i int64) *int64 {
+	return &i
+}
+
+// Good: CronJob with both fields set
+var NightlyBackupGood = batchv1.CronJob{
+	ObjectMeta: metav1.ObjectMeta{Name: "nightly-backup"},
+	Spec: batchv1.CronJobSpec{
+		Schedule:                "0 2 * * *",
+		ConcurrencyPolicy:       batchv1.ForbidConcurrent,
+		StartingDeadlineSeconds: ptrInt64Good8305(600),
+	},
+}
+
+// Good: plain Jobs are not checked
+var OneOffMigration = batchv1.Job{
+	ObjectMeta: metav1.ObjectMeta{Name: "migration"},
+}
@@ -0,0 +1,68 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8306: Service selector matches pods
+// This file contains violations - Services that select no pods
+
+var webLabels8306 = map[string]string{
+	"app":  "web",
+	"tier": "frontend",
+}
+
+var WebDeployment8306 = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "web",
+	},
+	Spec: appsv1.DeploymentSpec{
+		Selector: &metav1.LabelSelector{
+			MatchLabels: webLabels8306,
+		},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: webLabels8306,
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "web", Image: "nginx:1.25"},
+				},
+			},
+		},
+	},
+}
+
+// Bad: the selector value is misspelled
+var WebService8306 = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "web",
+	},
+	Spec: corev1.ServiceSpec{
+		Selector: map[string]string{
+			"app": "wbe",
+		},
+		Ports: []corev1.ServicePort{
+			{Name: "http", Port: 80},
+		},
+	},
+}
+
+var apiLabels8306 = map[string]string{
+	"app": "api",
+}
+
+// Bad: selects the labels of a workload that is not defined
+var APIService8306 = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "api",
+	},
+	Spec: corev1.ServiceSpec{
+		Selector: apiLabels8306,
+		Ports: []corev1.ServicePort{
+			{Name: "http", Port: 8080},
+		},
+	},
+}
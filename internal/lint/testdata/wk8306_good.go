@@ -0,0 +1,115 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8306: Service selector matches pods
+// This file contains no violations
+
+var webLabels8306Good = map[string]string{
+	"app":  "web",
+	"tier": "frontend",
+}
+
+var WebDeployment8306Good = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "web",
+	},
+	Spec: appsv1.DeploymentSpec{
+		Selector: &metav1.LabelSelector{
+			MatchLabels: webLabels8306Good,
+		},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: webLabels8306Good,
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "web", Image: "nginx:1.25"},
+				},
+			},
+		},
+	},
+}
+
+// Good: selects a subset of the Deployment's pod labels
+var WebService8306Good = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "web",
+	},
+	Spec: corev1.ServiceSpec{
+		Selector: map[string]string{
+			"app": "web",
+		},
+		Ports: []corev1.ServicePort{
+			{Name: "http", Port: 80},
+		},
+	},
+}
+
+// Good: pod template declared as its own variable
+var DBTemplate8306Good = corev1.PodTemplateSpec{
+	ObjectMeta: metav1.ObjectMeta{
+		Labels: map[string]string{"app": "db"},
+	},
+	Spec: corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: "db", Image: "postgres:16"},
+		},
+	},
+}
+
+var DBStatefulSet8306Good = appsv1.StatefulSet{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "db",
+	},
+	Spec: appsv1.StatefulSetSpec{
+		Template: DBTemplate8306Good,
+	},
+}
+
+var DBService8306Good = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "db",
+	},
+	Spec: corev1.ServiceSpec{
+		Selector: map[string]string{"app": "db"},
+	},
+}
+
+// Good: headless Services are excluded
+var DBHeadless8306Good = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "db-peers",
+	},
+	Spec: corev1.ServiceSpec{
+		ClusterIP: corev1.ClusterIPNone,
+		Selector:  map[string]string{"app": "db-peer"},
+	},
+}
+
+// Good: ExternalName Services are excluded
+var PaymentsService8306Good = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "payments",
+	},
+	Spec: corev1.ServiceSpec{
+		Type:         corev1.ServiceTypeExternalName,
+		ExternalName: "payments.example.com",
+	},
+}
+
+// Good: Services without a selector have manually managed endpoints
+var LegacyService8306Good = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "legacy",
+	},
+	Spec: corev1.ServiceSpec{
+		Ports: []corev1.ServicePort{
+			{Name: "http", Port: 80},
+		},
+	},
+}
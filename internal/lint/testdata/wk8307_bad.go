@@ -0,0 +1,46 @@
+package testdata
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8307: Job lifetime
+// This file contains violations
+
+func ptrInt32_8307(i int32) *int32 {
+	return &i
+}
+
+var migratePodTemplate8307 = corev1.PodTemplateSpec{
+	Spec: corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		Containers:    []corev1.Container{{Name: "migrate", Image: "myapp/migrations:v1.2.0"}},
+	},
+}
+
+// Bad: Job with neither a TTL nor a deadline
+var Migration = batchv1.Job{
+	ObjectMeta: metav1.ObjectMeta{Name: "migration"},
+	Spec: batchv1.JobSpec{
+		BackoffLimit: ptrInt32_8307(3),
+		Template:     migratePodTemplate8307,
+	},
+}
+
+// Bad: Job without a Spec
+var EmptyJob = &batchv1.Job{
+	ObjectMeta: metav1.ObjectMeta{Name: "empty"},
+}
+
+// Bad: CronJob whose jobs have neither a TTL nor a deadline
+var NightlyReport = batchv1.CronJob{
+	ObjectMeta: metav1.ObjectMeta{Name: "nightly-report"},
+	Spec: batchv1.CronJobSpec{
+		Schedule: "0 2 * * *",
+		JobTemplate: batchv1.JobTemplateSpec{
+			Spec: batchv1.JobSpec{Template: migratePodTemplate8307},
+		},
+	},
+}
@@ -0,0 +1,66 @@
+package testdata
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WK8307: Job lifetime
+// This file contains compliant resources
+
+func ptrInt32Good8307(i int32) *int32 {
+	return &i
+}
+
+func ptrInt64Good8307(i int64) *int64 {
+	return &i
+}
+
+var reportPodTemplate8307 = corev1.PodTemplateSpec{
+	Spec: corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		Containers:    []corev1.Container{{Name: "report", Image: "myapp/report:v2.0.0"}},
+	},
+}
+
+// Good: Job with both a TTL and a deadline
+var MigrationGood = batchv1.Job{
+	ObjectMeta: metav1.ObjectMeta{Name: "migration"},
+	Spec: batchv1.JobSpec{
+		ActiveDeadlineSeconds:   ptrInt64Good8307(600),
+		TTLSecondsAfterFinished: ptrInt32Good8307(3600),
+		Template:                reportPodTemplate8307,
+	},
+}
+
+// Good: a deadline alone is enough
+var ImportGood = batchv1.Job{
+	ObjectMeta: metav1.ObjectMeta{Name: "import"},
+	Spec: batchv1.JobSpec{
+		ActiveDeadlineSeconds: ptrInt64Good8307(3600),
+		Template:              reportPodTemplate8307,
+	},
+}
+
+// Good: CronJob whose jobs are cleaned up after a day
+var NightlyReportGood = batchv1.CronJob{
+	ObjectMeta: metav1.ObjectMeta{Name: "nightly-report"},
+	Spec: batchv1.CronJobSpec{
+		Schedule: "0 2 * * *",
+		JobTemplate: batchv1.JobTemplateSpec{
+			Spec: batchv1.JobSpec{
+				TTLSecondsAfterFinished: ptrInt32Good8307(86400),
+				Template:                reportPodTemplate8307,
+			},
+		},
+	},
+}
+
+// Good: a JobSpec built elsewhere cannot be checked
+var reportJobSpec8307 = batchv1.JobSpec{Template: reportPodTemplate8307}
+
+var WeeklyReportGood = batchv1.Job{
+	ObjectMeta: metav1.ObjectMeta{Name: "weekly-report"},
+	Spec:       reportJobSpec8307,
+}
@@ -22,16 +22,75 @@ type Issue = corelint.Issue
 
 // Rule represents a lint rule that can check Go source code.
 type Rule struct {
-	ID          string                                              // e.g., "WK8001"
-	Name        string                                              // Human-readable name
-	Description string                                              // Full description
-	Severity    Severity                                            // Error, Warning, or Info
-	Check       func(file *ast.File, fset *token.FileSet) []Issue  // Function to check the rule
-	Fix         func(file *ast.File, issue Issue) error            // Optional auto-fix function
+	ID          string                                            // e.g., "WK8001"
+	Name        string                                            // Human-readable name
+	Description string                                            // Full description
+	Severity    Severity                                          // Error, Warning, or Info
+	Rationale   string                                            // Why the rule matters
+	Check       func(file *ast.File, fset *token.FileSet) []Issue // Function to check the rule
+	Fix         func(file *ast.File, issue Issue) error           // Optional auto-fix function
+	Example     RuleExample                                       // Code that violates and satisfies the rule
+
+	// CheckPackage optionally checks the files of a package together, for
+	// rules that compare resources declared in different files. The linter
+	// calls it instead of Check with every file of the linted file's package
+	// and keeps the issues in the linted file; a directory is linted with a
+	// single call per package. Check treats a single file as the whole
+	// package.
+	CheckPackage func(files []*ast.File, fset *token.FileSet) []Issue
+
+	// Visit optionally checks the file node by node. The linter calls it
+	// instead of Check once per file and walks the file a single time for
+	// all such rules, passing each Visitor the nodes of the types in Nodes,
+	// or every node if Nodes is empty. Check should walk the file with the
+	// same Visitor; Register sets it if it is nil.
+	Visit func(file *ast.File, fset *token.FileSet) Visitor
+	Nodes []ast.Node // Node types Visit inspects, e.g. (*ast.CompositeLit)(nil)
+
+	// Configure optionally returns the rule adapted to the settings of a
+	// Config, such as the labels WK8013 expects. Linters run the rule it
+	// returns in place of this one.
+	Configure func(config *Config) Rule
+
+	// Optional rules are disabled by default and run only when listed in
+	// Config.EnabledRules.
+	Optional bool
 }
 
-// Config is an alias to the core lint Config type.
-type Config = corelint.Config
+// RuleExample shows code that violates a rule and code that satisfies it.
+type RuleExample struct {
+	Bad  string
+	Good string
+}
+
+// Config controls linting behavior.
+type Config struct {
+	// DisabledRules is a list of rule IDs to skip.
+	DisabledRules []string
+	// EnabledRules is a list of optional rule IDs to run.
+	EnabledRules []string
+	// MinSeverity is the minimum severity level to report.
+	// Issues with lower severity will be filtered out.
+	MinSeverity Severity
+	// RuleSeverity overrides the severity of issues reported by a rule,
+	// keyed by rule ID. Overrides apply before MinSeverity filtering.
+	RuleSeverity map[string]Severity
+	// RecommendedLabels are the labels WK8013 expects on top-level
+	// resources. Nil means DefaultRecommendedLabels.
+	RecommendedLabels []string
+	// ExternalConfigRefs are the names of ConfigMaps and Secrets that are
+	// created outside the linted code, e.g. by an operator, and that WK8107
+	// accepts as defined.
+	ExternalConfigRefs []string
+}
+
+// recommendedLabels returns the labels WK8013 checks for.
+func (c *Config) recommendedLabels() []string {
+	if c.RecommendedLabels == nil {
+		return DefaultRecommendedLabels
+	}
+	return c.RecommendedLabels
+}
 
 // Context provides context for rule execution.
 type Context struct {
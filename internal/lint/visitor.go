@@ -0,0 +1,97 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+)
+
+// Visitor checks a rule node by node while the linter walks a file. Rules
+// that set Rule.Visit return one for each file they check.
+type Visitor struct {
+	// Visit is called with the nodes of the types in Rule.Nodes, in the
+	// order of ast.Inspect. Returning false skips the children of the node
+	// for this rule only.
+	Visit func(n ast.Node) bool
+
+	// Issues returns the issues found once the file has been walked.
+	Issues func() []Issue
+}
+
+// compositeLits are the Nodes of rules that inspect composite literals,
+// which is where resources, containers and pod specs are declared.
+var compositeLits = []ast.Node{(*ast.CompositeLit)(nil)}
+
+// basicLits are the Nodes of rules that inspect literal values.
+var basicLits = []ast.Node{(*ast.BasicLit)(nil)}
+
+// visitorCheck returns the Check function of a rule with the given Visit
+// and Nodes, which walks the file for that rule alone.
+func visitorCheck(visit func(file *ast.File, fset *token.FileSet) Visitor, nodes []ast.Node) func(file *ast.File, fset *token.FileSet) []Issue {
+	rule := Rule{Visit: visit, Nodes: nodes}
+	return func(file *ast.File, fset *token.FileSet) []Issue {
+		return walkFile(file, fset, []Rule{rule})[0]
+	}
+}
+
+// walkFile runs the visitors of rules, which must all set Visit, over file
+// in a single ast.Inspect walk and returns the issues of each rule, in the
+// order of rules. Each node is dispatched by type to the rules listing that
+// type in Nodes, and to the rules that list no types.
+func walkFile(file *ast.File, fset *token.FileSet, rules []Rule) [][]Issue {
+	if len(rules) == 0 {
+		return nil
+	}
+	type ruleVisit struct {
+		visit     func(n ast.Node) bool
+		skipDepth int // Depth of the node whose children are skipped, or 0
+	}
+	visits := make([]*ruleVisit, len(rules))
+	visitors := make([]Visitor, len(rules))
+	byType := make(map[reflect.Type][]*ruleVisit)
+	var anyNode []*ruleVisit
+	for i, rule := range rules {
+		visitors[i] = rule.Visit(file, fset)
+		visits[i] = &ruleVisit{visit: visitors[i].Visit}
+		if len(rule.Nodes) == 0 {
+			anyNode = append(anyNode, visits[i])
+		}
+		for _, node := range rule.Nodes {
+			t := reflect.TypeOf(node)
+			byType[t] = append(byType[t], visits[i])
+		}
+	}
+
+	dispatch := func(v *ruleVisit, n ast.Node, depth int) {
+		if v.skipDepth == 0 && !v.visit(n) {
+			v.skipDepth = depth
+		}
+	}
+	depth := 0
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil {
+			// Leaving the node at depth: its skipped children are done
+			for _, v := range visits {
+				if v.skipDepth == depth {
+					v.skipDepth = 0
+				}
+			}
+			depth--
+			return true
+		}
+		depth++
+		for _, v := range byType[reflect.TypeOf(n)] {
+			dispatch(v, n, depth)
+		}
+		for _, v := range anyNode {
+			dispatch(v, n, depth)
+		}
+		return true
+	})
+
+	issues := make([][]Issue, len(rules))
+	for i, visitor := range visitors {
+		issues[i] = visitor.Issues()
+	}
+	return issues
+}
@@ -0,0 +1,140 @@
+package lint
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// visitRules returns the built-in rules that check files node by node.
+func visitRules() []Rule {
+	var rules []Rule
+	for _, rule := range builtinRules() {
+		if rule.Visit != nil {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func TestWalkFile(t *testing.T) {
+	t.Run("a shared walk reports what each rule reports alone", func(t *testing.T) {
+		rules := visitRules()
+		require.NotEmpty(t, rules)
+		paths, err := filepath.Glob(filepath.Join("testdata", "wk*.go"))
+		require.NoError(t, err)
+		for _, path := range paths {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+			require.NoError(t, err)
+
+			shared := walkFile(file, fset, rules)
+			for i, rule := range rules {
+				assert.Equal(t, rule.Check(file, fset), shared[i], "%s on %s", rule.ID, path)
+			}
+		}
+	})
+
+	t.Run("returning false skips children for that rule only", func(t *testing.T) {
+		src := `package k8s
+
+var Outer = Pod{Spec: PodSpec{Containers: []Container{{Name: "app"}}}}
+`
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "k8s.go", src, 0)
+		require.NoError(t, err)
+
+		// counter counts the composite literals it visits, and skips the
+		// children of the outermost one if prune is set
+		counter := func(prune bool) (Rule, *int) {
+			count := new(int)
+			return Rule{
+				Nodes: compositeLits,
+				Visit: func(*ast.File, *token.FileSet) Visitor {
+					return Visitor{
+						Visit: func(n ast.Node) bool {
+							*count++
+							return !prune
+						},
+						Issues: func() []Issue { return nil },
+					}
+				},
+			}, count
+		}
+		pruned, prunedCount := counter(true)
+		all, allCount := counter(false)
+		walkFile(file, fset, []Rule{pruned, all})
+		assert.Equal(t, 1, *prunedCount)
+		assert.Equal(t, 4, *allCount)
+	})
+}
+
+func TestRegister_Visit(t *testing.T) {
+	saved := Rules()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = saved
+		registryMu.Unlock()
+	})
+
+	// A custom rule flagging the string literal "forbidden"
+	Register(Rule{
+		ID:       "WK8998",
+		Name:     "No forbidden strings",
+		Severity: SeverityWarning,
+		Nodes:    basicLits,
+		Visit: func(file *ast.File, fset *token.FileSet) Visitor {
+			var issues []Issue
+			return Visitor{
+				Visit: func(n ast.Node) bool {
+					if lit := n.(*ast.BasicLit); lit.Value == `"forbidden"` {
+						pos := fset.Position(lit.Pos())
+						issues = append(issues, Issue{Rule: "WK8998", Message: "forbidden string", File: pos.Filename, Line: pos.Line, Severity: SeverityWarning})
+					}
+					return true
+				},
+				Issues: func() []Issue { return issues },
+			}
+		},
+	})
+
+	rule, ok := RuleByID("WK8998")
+	require.True(t, ok)
+	require.NotNil(t, rule.Check, "Register sets Check from Visit")
+
+	issues, err := NewLinter(nil).LintSource("custom.go", []byte("package custom\n\nvar X = \"forbidden\"\n"))
+	require.NoError(t, err)
+	var found []Issue
+	for _, issue := range issues {
+		if issue.Rule == "WK8998" {
+			found = append(found, issue)
+		}
+	}
+	require.Len(t, found, 1)
+	assert.Equal(t, 3, found[0].Line)
+}
+
+func BenchmarkWalkFile(b *testing.B) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "k8s.go", largeSource(500), parser.ParseComments)
+	require.NoError(b, err)
+	rules := visitRules()
+
+	b.Run("single walk", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			walkFile(file, fset, rules)
+		}
+	})
+	b.Run("walk per rule", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, rule := range rules {
+				rule.Check(file, fset)
+			}
+		}
+	})
+}
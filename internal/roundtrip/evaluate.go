@@ -0,0 +1,49 @@
+package roundtrip
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/lex00/wetwire-k8s-go/internal/serialize"
+)
+
+// Evaluate compiles and runs a main package file that declares Kubernetes
+// resources and returns the resources as multi-document YAML, in the order
+// wetwire-k8s build emits them. Resources without TypeMeta get the apiVersion
+// and kind of their Go type.
+//
+// The file is run with the go command in a temporary module, see
+// build.Evaluate, so a Go toolchain is required.
+func Evaluate(goCode string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "roundtrip-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	resourceFile := filepath.Join(dir, "resources.go")
+	if err := os.WriteFile(resourceFile, []byte(goCode), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write Go file: %w", err)
+	}
+
+	built, err := build.Build(resourceFile, build.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("build failed: %w", err)
+	}
+	if len(built.OrderedResources) == 0 {
+		return []byte{}, nil
+	}
+
+	objects, err := build.Evaluate(built.OrderedResources, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run generated code: %w", err)
+	}
+
+	manifests := make([]interface{}, len(objects))
+	for i, obj := range objects {
+		manifests[i] = obj
+	}
+	return serialize.ToMultiYAML(manifests)
+}
@@ -0,0 +1,64 @@
+package roundtrip_test
+
+import (
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/internal/roundtrip"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	requireGo(t)
+
+	// No TypeMeta, a main function of its own, and a Service that has to
+	// follow the ConfigMap it references
+	goCode := `package main
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var Web = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:   "web",
+		Labels: map[string]string{"config": WebConfig.Name},
+	},
+}
+
+var WebConfig = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "web-config"},
+	Data:       map[string]string{"mode": "production"},
+}
+
+func main() {
+	panic("main should not run")
+}
+`
+	out, err := roundtrip.Evaluate(goCode)
+	require.NoError(t, err)
+
+	docs, err := roundtrip.ParseMultiDocYAML(out)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, "v1", docs[0]["apiVersion"])
+	assert.Equal(t, "ConfigMap", docs[0]["kind"])
+	assert.Equal(t, map[string]interface{}{"mode": "production"}, docs[0]["data"])
+	assert.Equal(t, "Service", docs[1]["kind"])
+	assert.NotContains(t, docs[1], "status", "status is omitted like in build output")
+}
+
+func TestEvaluate_CompileError(t *testing.T) {
+	requireGo(t)
+
+	_, err := roundtrip.Evaluate(`package main
+
+import corev1 "k8s.io/api/core/v1"
+
+var Web = corev1.Service{Replicas: 3}
+`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to run generated code")
+}
@@ -4,10 +4,12 @@ package roundtrip_test
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -18,6 +20,8 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+var updateGolden = flag.Bool("update", false, "update the lost field lists of the round-trip seeds")
+
 // kubernetesExamplesURL is the base URL for kubernetes/examples raw files
 const kubernetesExamplesBaseURL = "https://raw.githubusercontent.com/kubernetes/examples/master"
 
@@ -147,38 +151,26 @@ func TestLocalReferenceFiles(t *testing.T) {
 	}
 }
 
-// TestRoundTripWithLocalFiles performs a full round-trip test with local files.
-// This test is skipped if the wetwire-k8s binary is not available.
-func TestRoundTripWithLocalFiles(t *testing.T) {
+// requireGo skips round-trip tests in short mode or without a go command,
+// which Evaluate needs to run the imported code.
+func requireGo(t *testing.T) {
+	t.Helper()
 	if testing.Short() {
 		t.Skip("skipping round-trip test in short mode")
 	}
-
-	// Check if wetwire-k8s binary exists
-	binaryPaths := []string{
-		"./wetwire-k8s",
-		filepath.Join("..", "..", "wetwire-k8s"),
-	}
-
-	binaryFound := false
-	for _, path := range binaryPaths {
-		if _, err := os.Stat(path); err == nil {
-			binaryFound = true
-			break
-		}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found, skipping round-trip test")
 	}
+}
 
-	if !binaryFound {
-		t.Skip("wetwire-k8s binary not found, skipping round-trip test")
-	}
+// TestRoundTripWithLocalFiles performs a full round-trip test with local files.
+func TestRoundTripWithLocalFiles(t *testing.T) {
+	requireGo(t)
 
 	referenceDir := filepath.Join("..", "..", "testdata", "roundtrip", "examples")
 
 	files, err := os.ReadDir(referenceDir)
-	if err != nil {
-		t.Skipf("reference directory not found: %v", err)
-		return
-	}
+	require.NoError(t, err)
 
 	for _, file := range files {
 		if !strings.HasSuffix(file.Name(), ".yaml") {
@@ -191,22 +183,49 @@ func TestRoundTripWithLocalFiles(t *testing.T) {
 			require.NoError(t, err)
 
 			result, err := roundtrip.RoundTrip(data, roundtrip.DefaultOptions())
-			if err != nil {
-				// Log the error but don't fail - the round-trip may not be fully implemented
-				t.Logf("round-trip failed for %s: %v", file.Name(), err)
-				t.Skip("round-trip not fully implemented")
-				return
-			}
+			require.NoError(t, err)
+			assert.True(t, result.Equivalent, "round trip of %s changed the manifest:\n%s",
+				file.Name(), roundtrip.DiffSummary(result.Differences))
+		})
+	}
+}
 
-			if !result.Equivalent {
-				t.Logf("Round-trip differences for %s:\n%s",
-					file.Name(), roundtrip.DiffSummary(result.Differences))
+// TestRoundTripSeeds round-trips the manifests built from examples/ and
+// compares the fields lost in translation with the .lost file next to each
+// seed. A seed that loses new fields fails; when the importer learns to keep
+// a field, run the test with -update to record the shorter list.
+func TestRoundTripSeeds(t *testing.T) {
+	requireGo(t)
+
+	seeds, err := filepath.Glob(filepath.Join("..", "..", "testdata", "roundtrip", "seeds", "*.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, seeds)
+
+	for _, seed := range seeds {
+		name := strings.TrimSuffix(filepath.Base(seed), ".yaml")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(seed)
+			require.NoError(t, err)
+
+			result, err := roundtrip.RoundTrip(data, roundtrip.DefaultOptions())
+			require.NoError(t, err)
+
+			for _, diff := range result.Differences {
+				assert.Equal(t, roundtrip.DiffTypeMissing, diff.Type,
+					"only lost fields are expected, got %s at %s", diff.Type, diff.Path)
 			}
 
-			// For now, we just log differences instead of failing
-			// This allows us to track progress on round-trip fidelity
-			t.Logf("Round-trip for %s: equivalent=%v, differences=%d",
-				file.Name(), result.Equivalent, len(result.Differences))
+			var lost strings.Builder
+			for _, path := range result.LostFields() {
+				lost.WriteString(path + "\n")
+			}
+			golden := strings.TrimSuffix(seed, ".yaml") + ".lost"
+			if *updateGolden {
+				require.NoError(t, os.WriteFile(golden, []byte(lost.String()), 0644))
+			}
+			expected, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), lost.String(), "fields lost in the round trip of %s changed", name)
 		})
 	}
 }
@@ -3,6 +3,7 @@
 //
 // A round-trip test verifies that:
 // YAML -> Import (Go code) -> Build -> YAML produces semantically equivalent output.
+// Differences show fields the importer or serializer lose in translation.
 package roundtrip
 
 import (
@@ -10,12 +11,12 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
 
+	"github.com/lex00/wetwire-k8s-go/internal/importer"
 	"gopkg.in/yaml.v3"
 )
 
@@ -43,27 +44,41 @@ type Result struct {
 	ResultYAML   []byte       // The YAML produced after building the Go code
 	Equivalent   bool         // Whether the original and result are semantically equivalent
 	Differences  []Difference // List of differences if not equivalent
+	Warnings     []string     // Importer warnings about fields it could not convert
+}
+
+// LostFields returns the paths of fields in the original that are missing
+// from the result.
+func (r *Result) LostFields() []string {
+	var paths []string
+	for _, diff := range r.Differences {
+		if diff.Type == DiffTypeMissing {
+			paths = append(paths, diff.Path)
+		}
+	}
+	return paths
 }
 
 // Options configures the round-trip operation.
 type Options struct {
-	PackageName string // Go package name for generated code (default: "main")
-	Normalize   bool   // Whether to normalize YAML before comparison (default: true)
+	Normalize bool // Whether to normalize YAML before comparison (default: true)
 }
 
 // DefaultOptions returns the default round-trip options.
 func DefaultOptions() Options {
 	return Options{
-		PackageName: "main",
-		Normalize:   true,
+		Normalize: true,
 	}
 }
 
 // RoundTrip performs a complete round-trip conversion:
 // YAML input -> Go code -> YAML output
 //
-// This is a high-level function that coordinates the import and build process.
-// It returns the result along with any semantic differences found.
+// The input is imported as a main package, which is compiled and run with
+// Evaluate to build it back to YAML. Result documents are matched to the
+// original ones by apiVersion, kind, namespace and name, since build may
+// order them differently. It returns the result along with any semantic
+// differences found.
 func RoundTrip(yamlInput []byte, opts Options) (*Result, error) {
 	if len(yamlInput) == 0 {
 		return &Result{
@@ -85,35 +100,14 @@ func RoundTrip(yamlInput []byte, opts Options) (*Result, error) {
 		}, nil
 	}
 
-	// Create a temporary directory for the round-trip
-	tmpDir, err := os.MkdirTemp("", "roundtrip-*")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create temp directory: %w", err)
-	}
-	defer os.RemoveAll(tmpDir)
-
-	// Write input YAML to temp file
-	inputFile := filepath.Join(tmpDir, "input.yaml")
-	if err := os.WriteFile(inputFile, yamlInput, 0644); err != nil {
-		return nil, fmt.Errorf("failed to write input file: %w", err)
-	}
-
-	// Generate Go code using the importer
-	goCode, err := importYAML(inputFile, opts.PackageName)
+	imported, err := importer.ImportBytes(yamlInput, importer.Options{PackageName: "main"})
 	if err != nil {
 		return nil, fmt.Errorf("import failed: %w", err)
 	}
 
-	// Write Go code to temp file
-	goFile := filepath.Join(tmpDir, "resources.go")
-	if err := os.WriteFile(goFile, []byte(goCode), 0644); err != nil {
-		return nil, fmt.Errorf("failed to write Go file: %w", err)
-	}
-
-	// Build YAML from Go code
-	resultYAML, err := buildYAML(tmpDir)
+	resultYAML, err := Evaluate(imported.GoCode)
 	if err != nil {
-		return nil, fmt.Errorf("build failed: %w", err)
+		return nil, err
 	}
 
 	// Parse result YAML
@@ -123,17 +117,47 @@ func RoundTrip(yamlInput []byte, opts Options) (*Result, error) {
 	}
 
 	// Compare documents
-	equivalent, differences := Compare(originalDocs, resultDocs, opts.Normalize)
+	equivalent, differences := Compare(originalDocs, matchDocuments(originalDocs, resultDocs), opts.Normalize)
 
 	return &Result{
 		OriginalYAML: yamlInput,
-		GoCode:       goCode,
+		GoCode:       imported.GoCode,
 		ResultYAML:   resultYAML,
 		Equivalent:   equivalent,
 		Differences:  differences,
+		Warnings:     imported.Warnings,
 	}, nil
 }
 
+// matchDocuments reorders result so that each document is at the index of
+// the original document with the same identity. Unmatched result documents
+// follow in their own order.
+func matchDocuments(original, result []map[string]interface{}) []map[string]interface{} {
+	used := make([]bool, len(result))
+	var matched []map[string]interface{}
+	for _, doc := range original {
+		for i, candidate := range result {
+			if !used[i] && documentKey(candidate) == documentKey(doc) {
+				used[i] = true
+				matched = append(matched, candidate)
+				break
+			}
+		}
+	}
+	for i, doc := range result {
+		if !used[i] {
+			matched = append(matched, doc)
+		}
+	}
+	return matched
+}
+
+// documentKey identifies a document by apiVersion, kind, namespace and name.
+func documentKey(doc map[string]interface{}) string {
+	metadata, _ := doc["metadata"].(map[string]interface{})
+	return fmt.Sprintf("%v/%v/%v/%v", doc["apiVersion"], doc["kind"], metadata["namespace"], metadata["name"])
+}
+
 // ParseMultiDocYAML parses a multi-document YAML byte slice into a slice of documents.
 func ParseMultiDocYAML(data []byte) ([]map[string]interface{}, error) {
 	var docs []map[string]interface{}
@@ -485,71 +509,6 @@ func serializeDocuments(docs []map[string]interface{}) ([]byte, error) {
 	return []byte(strings.Join(documents, "\n---\n")), nil
 }
 
-// importYAML runs the wetwire-k8s import command on a YAML file.
-func importYAML(inputFile, packageName string) (string, error) {
-	// Find the wetwire-k8s binary
-	binary, err := findBinary()
-	if err != nil {
-		return "", err
-	}
-
-	cmd := exec.Command(binary, "import", "-p", packageName, inputFile)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("import command failed: %s\n%s", err, stderr.String())
-	}
-
-	return stdout.String(), nil
-}
-
-// buildYAML runs the wetwire-k8s build command on a directory.
-func buildYAML(dir string) ([]byte, error) {
-	// Find the wetwire-k8s binary
-	binary, err := findBinary()
-	if err != nil {
-		return nil, err
-	}
-
-	cmd := exec.Command(binary, "build", dir)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("build command failed: %s\n%s", err, stderr.String())
-	}
-
-	return stdout.Bytes(), nil
-}
-
-// findBinary finds the wetwire-k8s binary.
-func findBinary() (string, error) {
-	// Check for binary in current directory first
-	if _, err := os.Stat("./wetwire-k8s"); err == nil {
-		return "./wetwire-k8s", nil
-	}
-
-	// Check in PATH
-	binary, err := exec.LookPath("wetwire-k8s")
-	if err == nil {
-		return binary, nil
-	}
-
-	// Check in project root
-	projectRoot := findProjectRoot()
-	if projectRoot != "" {
-		binaryPath := filepath.Join(projectRoot, "wetwire-k8s")
-		if _, err := os.Stat(binaryPath); err == nil {
-			return binaryPath, nil
-		}
-	}
-
-	return "", fmt.Errorf("wetwire-k8s binary not found")
-}
-
 // findProjectRoot attempts to find the project root directory.
 func findProjectRoot() string {
 	dir, err := os.Getwd()
@@ -0,0 +1,65 @@
+package serialize
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// untaggedPaths returns the JSON paths of the struct fields of resource that
+// have no name in a json tag, mapped to their lowerCamelCase name.
+// encoding/json writes such fields under their Go name, which the API server
+// does not accept, so Serialize renames them.
+func untaggedPaths(resource interface{}) map[string]string {
+	paths := make(map[string]string)
+	walkFields(reflect.ValueOf(resource), "", func(_ reflect.Type, field reflect.StructField, _ reflect.Value, path string) {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			paths[path] = lowerCamelCase(field.Name)
+		}
+	})
+	return paths
+}
+
+// lowerCamelCase lowers the leading upper case letters of a Go field name,
+// keeping the last one of an acronym that starts a word: APIVersion becomes
+// apiVersion, TTL ttl and Size size.
+func lowerCamelCase(name string) string {
+	runes := []rune(name)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// renamePaths renames the keys of data at paths. Deeper paths are renamed
+// first, since the paths of nested fields contain the old names of their
+// parents. A key is not renamed over an existing key.
+func renamePaths(data map[string]interface{}, paths map[string]string) {
+	ordered := make([]string, 0, len(paths))
+	for path := range paths {
+		ordered = append(ordered, path)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return strings.Count(ordered[i], pathSep) > strings.Count(ordered[j], pathSep)
+	})
+
+	for _, path := range ordered {
+		segments := strings.Split(strings.TrimPrefix(path, pathSep), pathSep)
+		object, ok := lookupPath(data, segments[:len(segments)-1]).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		old, name := segments[len(segments)-1], paths[path]
+		value, ok := object[old]
+		if _, taken := object[name]; !ok || taken {
+			continue
+		}
+		delete(object, old)
+		object[name] = value
+	}
+}
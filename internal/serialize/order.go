@@ -0,0 +1,137 @@
+package serialize
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// topLevelKeyOrder is the order of well-known top-level resource fields, as
+// kubectl prints them. Other fields follow in alphabetical order.
+var topLevelKeyOrder = []string{
+	"apiVersion",
+	"kind",
+	"metadata",
+	"spec",
+	"data",
+	"stringData",
+	"binaryData",
+	"type",
+	"immutable",
+}
+
+// metadataKeyOrder is the order of well-known ObjectMeta fields.
+var metadataKeyOrder = []string{
+	"name",
+	"generateName",
+	"namespace",
+	"labels",
+	"annotations",
+}
+
+// nestedKeyOrder is the order of well-known fields in other nested objects,
+// such as containers, ports and volumes.
+var nestedKeyOrder = []string{
+	"name",
+}
+
+// freeFormMaps are fields holding user-defined keys, which are sorted
+// alphabetically without any well-known fields first, so that a label
+// called "name" is not moved ahead of the others. A LabelSelector under
+// "selector" has no "name" field and sorts the same either way.
+var freeFormMaps = map[string]bool{
+	"labels":           true,
+	"annotations":      true,
+	"data":             true,
+	"stringData":       true,
+	"binaryData":       true,
+	"matchLabels":      true,
+	"nodeSelector":     true,
+	"selector":         true, // Service and ReplicationController selectors
+	"parameters":       true, // StorageClass and VolumeSnapshotClass parameters
+	"volumeAttributes": true, // CSI volume attributes
+}
+
+// toOrderedNode converts a serialized resource to a YAML node whose mapping
+// keys are in canonical order: well-known fields first, in kubectl order,
+// then the remaining fields alphabetically.
+func toOrderedNode(data map[string]interface{}) (*yaml.Node, error) {
+	return orderedNode(data, topLevelKeyOrder)
+}
+
+// orderedNode converts a value to a YAML node, ordering the keys of a map
+// by keyOrder and the keys of nested maps by the order for their field.
+func orderedNode(value interface{}, keyOrder []string) (*yaml.Node, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range sortKeys(v, keyOrder) {
+			child, err := orderedNode(v[key], childKeyOrder(key))
+			if err != nil {
+				return nil, err
+			}
+			keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+			node.Content = append(node.Content, keyNode, child)
+		}
+		return node, nil
+
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range v {
+			child, err := orderedNode(item, nestedKeyOrder)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		return node, nil
+
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(v); err != nil {
+			return nil, fmt.Errorf("failed to encode value: %w", err)
+		}
+		return node, nil
+	}
+}
+
+// childKeyOrder returns the key order for the value of a field.
+func childKeyOrder(key string) []string {
+	if key == "metadata" {
+		return metadataKeyOrder
+	}
+	if freeFormMaps[key] {
+		return nil
+	}
+	return nestedKeyOrder
+}
+
+// sortKeys returns the keys of data with the keys in keyOrder first, in
+// that order, followed by the rest alphabetically.
+func sortKeys(data map[string]interface{}, keyOrder []string) []string {
+	rank := make(map[string]int, len(keyOrder))
+	for i, key := range keyOrder {
+		rank[key] = i
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		ri, iKnown := rank[keys[i]]
+		rj, jKnown := rank[keys[j]]
+		switch {
+		case iKnown && jKnown:
+			return ri < rj
+		case iKnown != jKnown:
+			return iKnown
+		default:
+			return keys[i] < keys[j]
+		}
+	})
+
+	return keys
+}
@@ -0,0 +1,58 @@
+package serialize
+
+import "reflect"
+
+// clusterScopedKinds are the built-in kinds that are not namespaced. Their
+// manifests must not set metadata.namespace. Custom resources are assumed to
+// be namespaced.
+var clusterScopedKinds = map[string]bool{
+	"Namespace":                        true,
+	"Node":                             true,
+	"PersistentVolume":                 true,
+	"StorageClass":                     true,
+	"CSIDriver":                        true,
+	"CSINode":                          true,
+	"VolumeAttachment":                 true,
+	"ClusterRole":                      true,
+	"ClusterRoleBinding":               true,
+	"PriorityClass":                    true,
+	"RuntimeClass":                     true,
+	"IngressClass":                     true,
+	"CustomResourceDefinition":         true,
+	"APIService":                       true,
+	"MutatingWebhookConfiguration":     true,
+	"ValidatingWebhookConfiguration":   true,
+	"ValidatingAdmissionPolicy":        true,
+	"ValidatingAdmissionPolicyBinding": true,
+	"CertificateSigningRequest":        true,
+	"FlowSchema":                       true,
+	"PriorityLevelConfiguration":       true,
+	"ComponentStatus":                  true,
+}
+
+// IsClusterScoped reports whether objects of the given kind exist outside
+// any namespace.
+func IsClusterScoped(kind string) bool {
+	return clusterScopedKinds[kind]
+}
+
+// stripClusterNamespace removes metadata.namespace from a serialized
+// built-in resource of a cluster-scoped kind, which the API server would
+// reject or ignore. Typed resources without TypeMeta are identified by the
+// name of their Go type.
+func stripClusterNamespace(resource interface{}, data map[string]interface{}) {
+	kind, _ := data["kind"].(string)
+	if kind == "" {
+		t := reflect.TypeOf(resource)
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		kind = t.Name()
+	}
+	if !IsClusterScoped(kind) {
+		return
+	}
+	if metadata, ok := data["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "namespace")
+	}
+}
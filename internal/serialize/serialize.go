@@ -1,22 +1,68 @@
 package serialize
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// Scheme resolves the apiVersion and kind of typed resources that do not set
+// TypeMeta. Structured custom resource types are registered with their
+// AddToScheme function, e.g. widgetsv1.AddToScheme(serialize.Scheme).
+var Scheme = runtime.NewScheme()
+
 // Serialize converts a Go struct (Kubernetes resource) to a map[string]interface{}.
-// It handles field name conversion from Go naming to Kubernetes camelCase conventions,
-// recursively processes nested structs, and omits zero values.
+// Field names are taken from the json struct tags, which the Kubernetes API
+// types define with the exact names of the API (terminationGracePeriodSeconds,
+// hostIPC, clusterIP). Exported fields whose tag gives no name, such as those
+// of hand-written custom resource types, are converted to lowerCamelCase:
+// APIVersion becomes apiVersion and TTL ttl. Zero values are omitted other
+// than those of fields listed in PreserveZeroFields. Null creationTimestamps
+// are dropped with the other zero values, and status is omitted for built-in
+// kinds, as is metadata.namespace for cluster-scoped built-in kinds such as
+// ClusterRole.
+//
+// A metav1.Duration is written as a duration string ("30s"), a
+// resource.Quantity in its canonical form ("512Mi") and a metav1.Time as an
+// RFC 3339 timestamp, never as nested objects. Unset Duration and Quantity
+// fields are omitted like other zero values.
+//
+// An unstructured.Unstructured is serialized from its Object map. Typed
+// resources without apiVersion and kind get them from Scheme if their type
+// is registered there. A Manifest is returned as it is.
 func Serialize(resource interface{}) (map[string]interface{}, error) {
 	if resource == nil {
 		return nil, errors.New("resource cannot be nil")
 	}
+	if manifest, ok := resource.(Manifest); ok {
+		if manifest == nil {
+			return nil, errors.New("manifest cannot be nil")
+		}
+		return manifest, nil
+	}
+
+	// Unstructured only encodes its Object map as JSON through a pointer;
+	// a value would be encoded as {"Object": {...}}
+	switch u := resource.(type) {
+	case unstructured.Unstructured:
+		resource = u.Object
+	case *unstructured.Unstructured:
+		if u == nil {
+			return nil, errors.New("resource cannot be nil")
+		}
+		resource = u.Object
+	}
+	if object, ok := resource.(map[string]interface{}); ok && object == nil {
+		return nil, errors.New("unstructured resource has no object")
+	}
 
 	// Convert to JSON first (which handles k8s types correctly with json tags),
 	// then parse back to map[string]interface{}
@@ -30,25 +76,88 @@ func Serialize(resource interface{}) (map[string]interface{}, error) {
 		return nil, fmt.Errorf("failed to unmarshal JSON to map: %w", err)
 	}
 
+	setTypeMeta(resource, result)
+
+	// Status is populated by the cluster and rejected or ignored on apply,
+	// as is a namespace on a cluster-scoped kind
+	if isBuiltinKind(resource, result) {
+		delete(result, "status")
+		stripClusterNamespace(resource, result)
+	}
+
 	// Clean up the result by removing zero values
-	result = cleanZeroValues(result)
+	keep := preservedPaths(resource)
+	deletePaths(result, unsetScalarPaths(resource), keep)
+	result = cleanZeroValues(result, "", keep)
+
+	renamePaths(result, untaggedPaths(resource))
 
 	return result, nil
 }
 
-// ToYAML converts a Kubernetes resource to YAML format.
+// YAMLOptions controls the layout of YAML output.
+type YAMLOptions struct {
+	// Indent is the number of spaces per nesting level, from 2 to 9. Zero
+	// uses DefaultYAMLIndent.
+	Indent int
+
+	// LeadingSeparator starts the output with a "---" line, which some tools
+	// require even for a single document.
+	LeadingSeparator bool
+}
+
+// DefaultYAMLIndent is the indent used when YAMLOptions.Indent is zero.
+const DefaultYAMLIndent = 2
+
+// ToYAML converts a Kubernetes resource to YAML format. Fields are written in
+// a stable, kubectl-style order: apiVersion, kind, metadata, spec and data
+// first, with name first in nested objects, so output is reproducible.
 func ToYAML(resource interface{}) ([]byte, error) {
+	return ToYAMLWithOptions(resource, YAMLOptions{})
+}
+
+// ToYAMLWithOptions is like ToYAML but formats the output according to opts.
+func ToYAMLWithOptions(resource interface{}, opts YAMLOptions) ([]byte, error) {
+	indent, err := opts.indent()
+	if err != nil {
+		return nil, err
+	}
+
 	data, err := Serialize(resource)
 	if err != nil {
 		return nil, err
 	}
 
-	yamlBytes, err := yaml.Marshal(data)
+	node, err := toOrderedNode(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal to YAML: %w", err)
 	}
 
-	return yamlBytes, nil
+	var buf bytes.Buffer
+	if opts.LeadingSeparator {
+		buf.WriteString("---\n")
+	}
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(indent)
+	if err := encoder.Encode(node); err != nil {
+		return nil, fmt.Errorf("failed to marshal to YAML: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal to YAML: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// indent returns the indent to use, validating that the encoder supports it.
+func (o YAMLOptions) indent() (int, error) {
+	if o.Indent == 0 {
+		return DefaultYAMLIndent, nil
+	}
+	if o.Indent < 2 || o.Indent > 9 {
+		return 0, fmt.Errorf("invalid YAML indent %d: must be between 2 and 9", o.Indent)
+	}
+	return o.Indent, nil
 }
 
 // ToJSON converts a Kubernetes resource to JSON format.
@@ -69,13 +178,20 @@ func ToJSON(resource interface{}) ([]byte, error) {
 // ToMultiYAML converts multiple Kubernetes resources to a multi-document YAML format,
 // separated by "---" delimiters.
 func ToMultiYAML(resources []interface{}) ([]byte, error) {
+	return ToMultiYAMLWithOptions(resources, YAMLOptions{})
+}
+
+// ToMultiYAMLWithOptions is like ToMultiYAML but formats the output according
+// to opts. With LeadingSeparator the first document is also preceded by "---".
+func ToMultiYAMLWithOptions(resources []interface{}, opts YAMLOptions) ([]byte, error) {
 	if len(resources) == 0 {
 		return []byte{}, nil
 	}
 
+	docOpts := YAMLOptions{Indent: opts.Indent}
 	var documents []string
 	for i, resource := range resources {
-		yamlBytes, err := ToYAML(resource)
+		yamlBytes, err := ToYAMLWithOptions(resource, docOpts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to serialize resource %d: %w", i, err)
 		}
@@ -87,21 +203,77 @@ func ToMultiYAML(resources []interface{}) ([]byte, error) {
 
 	// Join with document separator
 	result := strings.Join(documents, "\n---\n")
+	if opts.LeadingSeparator {
+		result = "---\n" + result
+	}
 	return []byte(result), nil
 }
 
-// cleanZeroValues recursively removes zero values from a map
-func cleanZeroValues(data map[string]interface{}) map[string]interface{} {
+// JSONOptions controls the shape of JSON output for several resources.
+type JSONOptions struct {
+	// List wraps the resources in a v1 List object, which kubectl apply,
+	// including with --server-side, accepts, rather than a bare JSON array.
+	List bool
+}
+
+// jsonList is a v1 List object, with its fields in the order kubectl writes
+// them.
+type jsonList struct {
+	APIVersion string        `json:"apiVersion"`
+	Kind       string        `json:"kind"`
+	Items      []interface{} `json:"items"`
+}
+
+// ToMultiJSON converts multiple Kubernetes resources to a single indented
+// JSON document: an array of the resources, or a List object holding them
+// with opts.List. Each resource is serialized as by ToJSON, and the document
+// is marshaled as a whole, so the output is always valid, consistently
+// indented JSON.
+func ToMultiJSON(resources []interface{}, opts JSONOptions) ([]byte, error) {
+	items := make([]interface{}, 0, len(resources))
+	for i, resource := range resources {
+		data, err := Serialize(resource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize resource %d: %w", i, err)
+		}
+		items = append(items, data)
+	}
+
+	var document interface{} = items
+	if opts.List {
+		document = jsonList{APIVersion: "v1", Kind: "List", Items: items}
+	}
+	jsonBytes, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal to JSON: %w", err)
+	}
+	return jsonBytes, nil
+}
+
+// cleanZeroValues recursively removes zero values from a map, except for
+// values at the paths in keep. path is the path of data itself.
+func cleanZeroValues(data map[string]interface{}, path string, keep map[string]bool) map[string]interface{} {
 	result := make(map[string]interface{})
 
 	for key, value := range data {
+		valuePath := childPath(path, key)
+		if keep[valuePath] && value != nil {
+			result[key] = value
+			continue
+		}
+		if path == "" && dataFields[key] {
+			if entries := cleanDataEntries(value); len(entries) > 0 {
+				result[key] = entries
+			}
+			continue
+		}
 		if isZeroValue(value) {
 			continue
 		}
 
 		// Recursively clean nested maps
 		if nestedMap, ok := value.(map[string]interface{}); ok {
-			cleaned := cleanZeroValues(nestedMap)
+			cleaned := cleanZeroValues(nestedMap, valuePath, keep)
 			if len(cleaned) > 0 {
 				result[key] = cleaned
 			}
@@ -110,7 +282,7 @@ func cleanZeroValues(data map[string]interface{}) map[string]interface{} {
 
 		// Recursively clean slices
 		if slice, ok := value.([]interface{}); ok {
-			cleanedSlice := cleanSlice(slice)
+			cleanedSlice := cleanSlice(slice, valuePath, keep)
 			if len(cleanedSlice) > 0 {
 				result[key] = cleanedSlice
 			}
@@ -123,18 +295,47 @@ func cleanZeroValues(data map[string]interface{}) map[string]interface{} {
 	return result
 }
 
-// cleanSlice recursively cleans zero values from a slice
-func cleanSlice(slice []interface{}) []interface{} {
+// dataFields are top-level fields holding Secret and ConfigMap data. Secret
+// data ([]byte values) is base64-encoded by the JSON round trip in Serialize,
+// while stringData is kept as raw strings, as the API server expects.
+var dataFields = map[string]bool{
+	"data":       true,
+	"stringData": true,
+	"binaryData": true,
+}
+
+// cleanDataEntries returns the entries of a data field with only null values
+// removed. Empty strings are valid data and are kept.
+func cleanDataEntries(value interface{}) map[string]interface{} {
+	entries, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(entries))
+	for key, entry := range entries {
+		if entry != nil {
+			result[key] = entry
+		}
+	}
+	return result
+}
+
+// cleanSlice recursively cleans zero values from a slice. Empty scalars,
+// such as the "" core API group in the apiGroups of an RBAC rule, are
+// elements in their own right and are kept.
+func cleanSlice(slice []interface{}, path string, keep map[string]bool) []interface{} {
 	var result []interface{}
 
-	for _, item := range slice {
-		if isZeroValue(item) {
+	for i, item := range slice {
+		if item == nil {
 			continue
 		}
+		itemPath := childPath(path, strconv.Itoa(i))
 
 		// Recursively clean nested maps
 		if nestedMap, ok := item.(map[string]interface{}); ok {
-			cleaned := cleanZeroValues(nestedMap)
+			cleaned := cleanZeroValues(nestedMap, itemPath, keep)
 			if len(cleaned) > 0 {
 				result = append(result, cleaned)
 			}
@@ -143,7 +344,7 @@ func cleanSlice(slice []interface{}) []interface{} {
 
 		// Recursively clean nested slices
 		if nestedSlice, ok := item.([]interface{}); ok {
-			cleaned := cleanSlice(nestedSlice)
+			cleaned := cleanSlice(nestedSlice, itemPath, keep)
 			if len(cleaned) > 0 {
 				result = append(result, cleaned)
 			}
@@ -180,3 +381,115 @@ func isZeroValue(value interface{}) bool {
 
 	return false
 }
+
+// setTypeMeta sets apiVersion and kind in data from the registration of the
+// resource's type in Scheme, unless data already has both.
+func setTypeMeta(resource interface{}, data map[string]interface{}) {
+	apiVersion, _ := data["apiVersion"].(string)
+	kind, _ := data["kind"].(string)
+	if apiVersion != "" && kind != "" {
+		return
+	}
+
+	// Generated types implement runtime.Object on pointers, so look up a
+	// pointer to a copy of struct values
+	obj, ok := resource.(runtime.Object)
+	if !ok {
+		v := reflect.ValueOf(resource)
+		if v.Kind() != reflect.Struct {
+			return
+		}
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		if obj, ok = p.Interface().(runtime.Object); !ok {
+			return
+		}
+	}
+
+	gvks, _, err := Scheme.ObjectKinds(obj)
+	if err != nil || len(gvks) == 0 {
+		return
+	}
+	data["apiVersion"] = gvks[0].GroupVersion().String()
+	data["kind"] = gvks[0].Kind
+}
+
+// builtinPackagePrefix is the import path prefix of the built-in API types.
+const builtinPackagePrefix = "k8s.io/api/"
+
+// isBuiltinKind reports whether a resource is a built-in Kubernetes kind.
+// Typed resources are identified by their package; other resources by the
+// group of their apiVersion, where built-in groups are the core group, groups
+// without a domain (e.g. "apps") and *.k8s.io groups.
+func isBuiltinKind(resource interface{}, data map[string]interface{}) bool {
+	t := reflect.TypeOf(resource)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.PkgPath() != "" {
+		return strings.HasPrefix(t.PkgPath(), builtinPackagePrefix)
+	}
+
+	apiVersion, ok := data["apiVersion"].(string)
+	if !ok || apiVersion == "" {
+		return false
+	}
+	group, _, found := strings.Cut(apiVersion, "/")
+	if !found {
+		return true // Core group, e.g. "v1"
+	}
+	return !strings.Contains(group, ".") || strings.HasSuffix(group, ".k8s.io")
+}
+
+// serverFields are metadata fields populated by the API server that should
+// not take part in comparisons between desired and live objects.
+var serverFields = []string{
+	"uid",
+	"resourceVersion",
+	"generation",
+	"creationTimestamp",
+	"managedFields",
+	"selfLink",
+}
+
+// lastAppliedAnnotation is the annotation kubectl apply stores on live objects.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// Normalize prepares a manifest for comparison. It removes status, server
+// populated metadata and the last-applied annotation, then drops zero values
+// the same way serialization does.
+func Normalize(manifest map[string]interface{}) map[string]interface{} {
+	if manifest == nil {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(manifest))
+	for key, value := range manifest {
+		if key == "status" {
+			continue
+		}
+		result[key] = value
+	}
+
+	if metadata, ok := result["metadata"].(map[string]interface{}); ok {
+		cleaned := make(map[string]interface{}, len(metadata))
+		for key, value := range metadata {
+			cleaned[key] = value
+		}
+		for _, field := range serverFields {
+			delete(cleaned, field)
+		}
+		if annotations, ok := cleaned["annotations"].(map[string]interface{}); ok {
+			kept := make(map[string]interface{}, len(annotations))
+			for key, value := range annotations {
+				if key != lastAppliedAnnotation {
+					kept[key] = value
+				}
+			}
+			cleaned["annotations"] = kept
+		}
+		result["metadata"] = cleaned
+	}
+
+	return cleanZeroValues(result, "", nil)
+}
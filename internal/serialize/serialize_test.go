@@ -4,12 +4,21 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -202,6 +211,96 @@ func TestFieldNameConversion(t *testing.T) {
 	assert.NotContains(t, got, "objectMeta")
 }
 
+// TestFieldNamesFromTags checks that field names come from the json tags of
+// the API types, including acronyms that a case conversion would get wrong.
+func TestFieldNamesFromTags(t *testing.T) {
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: corev1.PodSpec{
+			TerminationGracePeriodSeconds: ptr(int64(30)),
+			HostIPC:                       true,
+			HostPID:                       true,
+			DNSPolicy:                     corev1.DNSClusterFirst,
+			Containers: []corev1.Container{{
+				Name:                   "web",
+				Image:                  "nginx:1.27",
+				TerminationMessagePath: "/dev/termination-log",
+				Ports:                  []corev1.ContainerPort{{ContainerPort: 80, HostIP: "127.0.0.1"}},
+			}},
+		},
+	}
+
+	got, err := Serialize(pod)
+	require.NoError(t, err)
+
+	spec := got["spec"].(map[string]interface{})
+	for _, name := range []string{"terminationGracePeriodSeconds", "hostIPC", "hostPID", "dnsPolicy", "containers"} {
+		assert.Contains(t, spec, name)
+	}
+	container := spec["containers"].([]interface{})[0].(map[string]interface{})
+	assert.Contains(t, container, "terminationMessagePath")
+	port := container["ports"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"containerPort": float64(80), "hostIP": "127.0.0.1"}, port)
+
+	service := &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec:       corev1.ServiceSpec{ClusterIP: "None", ExternalIPs: []string{"203.0.113.10"}},
+	}
+	got, err = Serialize(service)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"clusterIP":   "None",
+		"externalIPs": []interface{}{"203.0.113.10"},
+	}, got["spec"])
+}
+
+// TestFieldNamesWithoutTags checks that fields whose tag gives no name are
+// written in lowerCamelCase rather than under their Go name.
+func TestFieldNamesWithoutTags(t *testing.T) {
+	type widgetItem struct {
+		DisplayName string
+	}
+	type widgetSpec struct {
+		Size    int
+		URLPath string
+		TTL     int `json:",omitempty"`
+		Items   []widgetItem
+		Labels  map[string]string
+		Mode    string `json:"mode"`
+	}
+	type widget struct {
+		metav1.TypeMeta   `json:",inline"`
+		metav1.ObjectMeta `json:"metadata"`
+		Spec              widgetSpec `json:"spec"`
+	}
+
+	got, err := Serialize(widget{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "example.com/v1", Kind: "Widget"},
+		ObjectMeta: metav1.ObjectMeta{Name: "big"},
+		Spec: widgetSpec{
+			Size:    3,
+			URLPath: "/widgets",
+			TTL:     60,
+			Items:   []widgetItem{{DisplayName: "first"}},
+			Labels:  map[string]string{"Size": "large"},
+			Mode:    "fast",
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com/v1", got["apiVersion"])
+	assert.Equal(t, map[string]interface{}{
+		"size":    float64(3),
+		"urlPath": "/widgets",
+		"ttl":     float64(60),
+		"items":   []interface{}{map[string]interface{}{"displayName": "first"}},
+		"labels":  map[string]interface{}{"Size": "large"},
+		"mode":    "fast",
+	}, got["spec"], "map keys are data and are not renamed")
+}
+
 // TestZeroValueOmission tests that zero values are omitted from output
 func TestZeroValueOmission(t *testing.T) {
 	deployment := &appsv1.Deployment{
@@ -359,6 +458,61 @@ func TestToMultiYAMLSingleResource(t *testing.T) {
 	assert.Contains(t, yamlStr, "test-deployment")
 }
 
+func TestToMultiJSON(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test-deployment"},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr(int32(2))},
+	}
+	service := &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test-service"},
+	}
+	resources := []interface{}{deployment, service}
+
+	t.Run("array", func(t *testing.T) {
+		data, err := ToMultiJSON(resources, JSONOptions{})
+		require.NoError(t, err)
+
+		var items []map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &items))
+		require.Len(t, items, 2)
+		assert.Equal(t, "Deployment", items[0]["kind"])
+		assert.Equal(t, float64(2), items[0]["spec"].(map[string]interface{})["replicas"])
+		assert.Equal(t, "Service", items[1]["kind"])
+
+		// The whole document is indented consistently
+		assert.True(t, strings.HasPrefix(string(data), "[\n  {\n    \"apiVersion\": \"apps/v1\""), string(data))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		data, err := ToMultiJSON(resources, JSONOptions{List: true})
+		require.NoError(t, err)
+
+		var list struct {
+			APIVersion string                   `json:"apiVersion"`
+			Kind       string                   `json:"kind"`
+			Items      []map[string]interface{} `json:"items"`
+		}
+		require.NoError(t, json.Unmarshal(data, &list))
+		assert.Equal(t, "v1", list.APIVersion)
+		assert.Equal(t, "List", list.Kind)
+		require.Len(t, list.Items, 2)
+		assert.Equal(t, "test-deployment", list.Items[0]["metadata"].(map[string]interface{})["name"])
+		assert.True(t, strings.HasPrefix(string(data), "{\n  \"apiVersion\": \"v1\",\n  \"kind\": \"List\",\n  \"items\": ["), string(data))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		data, err := ToMultiJSON(nil, JSONOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "[]", string(data))
+
+		data, err = ToMultiJSON(nil, JSONOptions{List: true})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"apiVersion": "v1", "kind": "List", "items": []}`, string(data))
+	})
+}
+
 // TestToMultiYAMLEmpty tests multi-document YAML with no resources
 func TestToMultiYAMLEmpty(t *testing.T) {
 	resources := []interface{}{}
@@ -367,6 +521,183 @@ func TestToMultiYAMLEmpty(t *testing.T) {
 	assert.Empty(t, yaml)
 }
 
+func TestToYAMLWithOptions(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "app",
+			Labels: map[string]string{"app": "web"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		opts     YAMLOptions
+		expected string
+	}{
+		{
+			name: "default",
+			opts: YAMLOptions{},
+			expected: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  labels:
+    app: web
+`,
+		},
+		{
+			name: "four spaces",
+			opts: YAMLOptions{Indent: 4},
+			expected: `apiVersion: v1
+kind: ConfigMap
+metadata:
+    name: app
+    labels:
+        app: web
+`,
+		},
+		{
+			name: "two spaces",
+			opts: YAMLOptions{Indent: 2},
+			expected: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  labels:
+    app: web
+`,
+		},
+		{
+			name: "default with leading separator",
+			opts: YAMLOptions{LeadingSeparator: true},
+			expected: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  labels:
+    app: web
+`,
+		},
+		{
+			name: "four spaces with leading separator",
+			opts: YAMLOptions{Indent: 4, LeadingSeparator: true},
+			expected: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+    name: app
+    labels:
+        app: web
+`,
+		},
+		{
+			name: "two spaces with leading separator",
+			opts: YAMLOptions{Indent: 2, LeadingSeparator: true},
+			expected: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  labels:
+    app: web
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ToYAMLWithOptions(configMap, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+		})
+	}
+
+	// The zero options match ToYAML
+	plain, err := ToYAML(configMap)
+	require.NoError(t, err)
+	assert.Equal(t, tests[0].expected, string(plain))
+}
+
+func TestToMultiYAMLWithOptions(t *testing.T) {
+	typeMeta := metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"}
+	first := &corev1.ConfigMap{TypeMeta: typeMeta, ObjectMeta: metav1.ObjectMeta{Name: "first", Labels: map[string]string{"app": "web"}}}
+	second := &corev1.ConfigMap{TypeMeta: typeMeta, ObjectMeta: metav1.ObjectMeta{Name: "second"}}
+
+	tests := []struct {
+		name      string
+		resources []interface{}
+		opts      YAMLOptions
+		expected  string
+	}{
+		{
+			name:      "single document",
+			resources: []interface{}{second},
+			opts:      YAMLOptions{},
+			expected:  "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: second",
+		},
+		{
+			name:      "single document with leading separator",
+			resources: []interface{}{second},
+			opts:      YAMLOptions{LeadingSeparator: true},
+			expected:  "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: second",
+		},
+		{
+			name:      "single document with four spaces",
+			resources: []interface{}{second},
+			opts:      YAMLOptions{Indent: 4},
+			expected:  "apiVersion: v1\nkind: ConfigMap\nmetadata:\n    name: second",
+		},
+		{
+			name:      "two spaces",
+			resources: []interface{}{first, second},
+			opts:      YAMLOptions{Indent: 2},
+			expected: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: first\n  labels:\n    app: web\n" +
+				"---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: second",
+		},
+		{
+			name:      "two spaces with leading separator",
+			resources: []interface{}{first, second},
+			opts:      YAMLOptions{Indent: 2, LeadingSeparator: true},
+			expected: "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: first\n  labels:\n    app: web\n" +
+				"---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: second",
+		},
+		{
+			name:      "four spaces with leading separator",
+			resources: []interface{}{first, second},
+			opts:      YAMLOptions{Indent: 4, LeadingSeparator: true},
+			expected: "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n    name: first\n    labels:\n        app: web\n" +
+				"---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n    name: second",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ToMultiYAMLWithOptions(tt.resources, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+		})
+	}
+
+	// No resources produce no output, even with a leading separator
+	out, err := ToMultiYAMLWithOptions(nil, YAMLOptions{LeadingSeparator: true})
+	require.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+func TestToYAMLWithOptions_InvalidIndent(t *testing.T) {
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app"}}
+
+	for _, indent := range []int{-1, 1, 10} {
+		_, err := ToYAMLWithOptions(configMap, YAMLOptions{Indent: indent})
+		assert.Error(t, err, "indent %d", indent)
+
+		_, err = ToMultiYAMLWithOptions([]interface{}{configMap}, YAMLOptions{Indent: indent})
+		assert.Error(t, err, "indent %d", indent)
+	}
+}
+
 // TestSerializeNilResource tests handling of nil resources
 func TestSerializeNilResource(t *testing.T) {
 	_, err := Serialize(nil)
@@ -510,6 +841,42 @@ func TestSerializeSecret(t *testing.T) {
 	assert.Equal(t, "Opaque", got["type"])
 }
 
+// TestSerializeSecretData tests that Secret data is base64-encoded and
+// stringData is kept as raw strings
+func TestSerializeSecretData(t *testing.T) {
+	secret := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: "creds"},
+		Type:       corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"token": []byte("s3cr3t"),
+			"empty": {},
+		},
+		StringData: map[string]string{
+			"username": "admin",
+			"note":     "",
+		},
+	}
+
+	got, err := Serialize(secret)
+	require.NoError(t, err)
+
+	data, ok := got["data"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "czNjcjN0", data["token"])
+	assert.Equal(t, "", data["empty"], "empty data entries are kept")
+
+	stringData, ok := got["stringData"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "admin", stringData["username"])
+	assert.Equal(t, "", stringData["note"], "empty stringData entries are kept")
+
+	out, err := ToYAML(secret)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "data:\n  empty: \"\"\n  token: czNjcjN0\n")
+	assert.Contains(t, string(out), "stringData:\n  note: \"\"\n  username: admin\n")
+}
+
 // TestToMultiYAML_OrderPreservation tests that resources are output in order
 func TestToMultiYAML_OrderPreservation(t *testing.T) {
 	resources := []interface{}{
@@ -583,6 +950,92 @@ func TestSerializeStatefulSet(t *testing.T) {
 	assert.Equal(t, "test-service", spec["serviceName"])
 }
 
+// TestSerializeCronJob tests CronJob serialization through the nested
+// Spec.JobTemplate.Spec.Template path, as in examples/cronjob
+func TestSerializeCronJob(t *testing.T) {
+	newCronJob := func(successfulJobs int32) *batchv1.CronJob {
+		return &batchv1.CronJob{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+			ObjectMeta: metav1.ObjectMeta{Name: "session-cleanup", Namespace: "default"},
+			Spec: batchv1.CronJobSpec{
+				Schedule:                   "*/15 * * * *",
+				ConcurrencyPolicy:          batchv1.ReplaceConcurrent,
+				SuccessfulJobsHistoryLimit: ptr(successfulJobs),
+				FailedJobsHistoryLimit:     ptr(int32(1)),
+				JobTemplate: batchv1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{
+						BackoffLimit: ptr(int32(0)),
+						Template: corev1.PodTemplateSpec{
+							ObjectMeta: metav1.ObjectMeta{
+								Labels: map[string]string{"app": "session-cleanup"},
+							},
+							Spec: corev1.PodSpec{
+								RestartPolicy: corev1.RestartPolicyNever,
+								Containers: []corev1.Container{
+									{Name: "cleanup", Image: "myapp/maintenance:v1.4.0", Command: []string{"myapp", "sessions", "expire"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	out, err := ToYAML(newCronJob(3))
+	require.NoError(t, err)
+
+	var got struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string
+		Spec       struct {
+			Schedule                   string
+			ConcurrencyPolicy          string `yaml:"concurrencyPolicy"`
+			SuccessfulJobsHistoryLimit *int32 `yaml:"successfulJobsHistoryLimit"`
+			FailedJobsHistoryLimit     *int32 `yaml:"failedJobsHistoryLimit"`
+			JobTemplate                struct {
+				Spec struct {
+					BackoffLimit *int32 `yaml:"backoffLimit"`
+					Template     struct {
+						Metadata struct{ Labels map[string]string }
+						Spec     struct {
+							RestartPolicy string `yaml:"restartPolicy"`
+							Containers    []struct {
+								Name    string
+								Image   string
+								Command []string
+							}
+						}
+					}
+				}
+			} `yaml:"jobTemplate"`
+		}
+	}
+	require.NoError(t, yaml.Unmarshal(out, &got))
+
+	assert.Equal(t, "batch/v1", got.APIVersion)
+	assert.Equal(t, "CronJob", got.Kind)
+	assert.Equal(t, "*/15 * * * *", got.Spec.Schedule)
+	assert.Equal(t, "Replace", got.Spec.ConcurrencyPolicy)
+	assert.Equal(t, ptr(int32(3)), got.Spec.SuccessfulJobsHistoryLimit)
+	assert.Equal(t, ptr(int32(1)), got.Spec.FailedJobsHistoryLimit)
+
+	job := got.Spec.JobTemplate.Spec
+	assert.Equal(t, ptr(int32(0)), job.BackoffLimit)
+	assert.Equal(t, map[string]string{"app": "session-cleanup"}, job.Template.Metadata.Labels)
+	assert.Equal(t, "Never", job.Template.Spec.RestartPolicy)
+	require.Len(t, job.Template.Spec.Containers, 1)
+	assert.Equal(t, "cleanup", job.Template.Spec.Containers[0].Name)
+	assert.Equal(t, "myapp/maintenance:v1.4.0", job.Template.Spec.Containers[0].Image)
+	assert.Equal(t, []string{"myapp", "sessions", "expire"}, job.Template.Spec.Containers[0].Command)
+
+	t.Run("zero history limit is kept", func(t *testing.T) {
+		out, err := ToYAML(newCronJob(0))
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "\n  successfulJobsHistoryLimit: 0\n")
+	})
+}
+
 // TestSerializeDaemonSet tests DaemonSet serialization
 func TestSerializeDaemonSet(t *testing.T) {
 	ds := &appsv1.DaemonSet{
@@ -686,3 +1139,640 @@ func TestSerializeReplicaSet(t *testing.T) {
 	assert.Equal(t, "apps/v1", got["apiVersion"])
 	assert.Equal(t, "ReplicaSet", got["kind"])
 }
+
+func TestNormalize(t *testing.T) {
+	live := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":              "app-config",
+			"namespace":         "default",
+			"uid":               "3f1c",
+			"resourceVersion":   "12345",
+			"creationTimestamp": "2024-01-01T00:00:00Z",
+			"managedFields":     []interface{}{map[string]interface{}{"manager": "kubectl"}},
+			"annotations": map[string]interface{}{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+			},
+		},
+		"data":   map[string]interface{}{"key": "value"},
+		"status": map[string]interface{}{"phase": "Active"},
+	}
+
+	normalized := Normalize(live)
+
+	assert.Equal(t, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      "app-config",
+			"namespace": "default",
+		},
+		"data": map[string]interface{}{"key": "value"},
+	}, normalized)
+
+	// The input is left untouched
+	assert.Contains(t, live, "status")
+	assert.Contains(t, live["metadata"], "uid")
+}
+
+// TestToYAML_StableOrder tests that fields are written in kubectl order and
+// that output is byte-identical across runs
+func TestToYAML_StableOrder(t *testing.T) {
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web",
+			Namespace:   "prod",
+			Labels:      map[string]string{"tier": "frontend", "app": "web", "name": "web"},
+			Annotations: map[string]string{"owner": "team-a"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "web",
+						Image: "nginx:1.25",
+						Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 80}},
+					}},
+				},
+			},
+		},
+	}
+
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+  labels:
+    app: web
+    name: web
+    tier: frontend
+  annotations:
+    owner: team-a
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: web
+  template:
+    spec:
+      containers:
+        - name: web
+          image: nginx:1.25
+          ports:
+            - name: http
+              containerPort: 80
+`
+
+	first, err := ToYAML(deployment)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(first))
+
+	for i := 0; i < 20; i++ {
+		again, err := ToYAML(deployment)
+		require.NoError(t, err)
+		require.Equal(t, string(first), string(again))
+	}
+}
+
+// TestToYAML_FreeFormMapOrder tests that maps with user-defined keys, such as
+// labels and selectors, are written in alphabetical order, even when a key
+// is a well-known field name such as "name"
+func TestToYAML_FreeFormMapOrder(t *testing.T) {
+	labels := map[string]string{
+		"tier":                       "frontend",
+		"name":                       "web",
+		"app.kubernetes.io/name":     "web",
+		"app.kubernetes.io/instance": "web-prod",
+		"app":                        "web",
+	}
+	service := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web",
+			Labels:      labels,
+			Annotations: map[string]string{"zone": "b", "name": "web", "checksum/config": "abc"},
+		},
+		Spec: corev1.ServiceSpec{Selector: labels},
+	}
+
+	expected := `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  labels:
+    app: web
+    app.kubernetes.io/instance: web-prod
+    app.kubernetes.io/name: web
+    name: web
+    tier: frontend
+  annotations:
+    checksum/config: abc
+    name: web
+    zone: b
+spec:
+  selector:
+    app: web
+    app.kubernetes.io/instance: web-prod
+    app.kubernetes.io/name: web
+    name: web
+    tier: frontend
+`
+
+	first, err := ToYAML(service)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(first))
+
+	second, err := ToYAML(service)
+	require.NoError(t, err)
+	assert.Equal(t, string(first), string(second), "output must be identical across runs")
+
+	t.Run("built manifests", func(t *testing.T) {
+		manifest := map[string]interface{}{
+			"apiVersion": "storage.k8s.io/v1",
+			"kind":       "StorageClass",
+			"metadata":   map[string]interface{}{"name": "fast"},
+			"parameters": map[string]interface{}{"type": "gp3", "name": "fast", "fsType": "ext4"},
+		}
+		out, err := ToYAML(manifest)
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "parameters:\n  fsType: ext4\n  name: fast\n  type: gp3\n")
+	})
+}
+
+// TestToYAML_TopLevelOrder tests the order of data fields after metadata
+func TestToYAML_TopLevelOrder(t *testing.T) {
+	secret := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: "creds"},
+		Type:       corev1.SecretTypeOpaque,
+		StringData: map[string]string{"user": "admin"},
+		Data:       map[string][]byte{"token": []byte("abc")},
+	}
+
+	out, err := ToYAML(secret)
+	require.NoError(t, err)
+
+	var keys []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line != "" && !strings.HasPrefix(line, " ") {
+			keys = append(keys, strings.SplitN(line, ":", 2)[0])
+		}
+	}
+	assert.Equal(t, []string{"apiVersion", "kind", "metadata", "data", "stringData", "type"}, keys)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// TestPreserveZeroFields tests that allowlisted fields keep explicit zero values
+func TestPreserveZeroFields(t *testing.T) {
+	newDeployment := func(replicas *int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+		}
+	}
+
+	t.Run("explicit zero replicas are kept", func(t *testing.T) {
+		out, err := ToYAML(newDeployment(ptr(int32(0))))
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "\n  replicas: 0\n")
+	})
+
+	t.Run("unset replicas are omitted", func(t *testing.T) {
+		out, err := ToYAML(newDeployment(nil))
+		require.NoError(t, err)
+		assert.NotContains(t, string(out), "replicas")
+	})
+
+	t.Run("zero partition is kept", func(t *testing.T) {
+		statefulSet := &appsv1.StatefulSet{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"},
+			ObjectMeta: metav1.ObjectMeta{Name: "db"},
+			Spec: appsv1.StatefulSetSpec{
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+					Type: appsv1.RollingUpdateStatefulSetStrategyType,
+					RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+						Partition: ptr(int32(0)),
+					},
+				},
+			},
+		}
+
+		got, err := Serialize(statefulSet)
+		require.NoError(t, err)
+		spec := got["spec"].(map[string]interface{})
+		strategy := spec["updateStrategy"].(map[string]interface{})
+		rollingUpdate := strategy["rollingUpdate"].(map[string]interface{})
+		assert.Equal(t, float64(0), rollingUpdate["partition"])
+	})
+
+	t.Run("other zero values are still omitted", func(t *testing.T) {
+		got, err := Serialize(newDeployment(ptr(int32(0))))
+		require.NoError(t, err)
+		spec := got["spec"].(map[string]interface{})
+		assert.NotContains(t, spec, "minReadySeconds")
+		assert.NotContains(t, spec, "paused")
+	})
+
+	t.Run("fields can be removed from the allowlist", func(t *testing.T) {
+		delete(PreserveZeroFields, "DeploymentSpec.Replicas")
+		defer func() { PreserveZeroFields["DeploymentSpec.Replicas"] = true }()
+
+		out, err := ToYAML(newDeployment(ptr(int32(0))))
+		require.NoError(t, err)
+		assert.NotContains(t, string(out), "replicas")
+	})
+}
+
+// TestNetworkPolicyEmptySelectors tests that empty label selectors, which
+// select everything, survive serialization
+func TestNetworkPolicyEmptySelectors(t *testing.T) {
+	t.Run("default deny round trip", func(t *testing.T) {
+		// DefaultDenyAll from examples/network-policy
+		policy := &networkingv1.NetworkPolicy{
+			TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "default-deny-all",
+				Namespace: "default",
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{},
+				PolicyTypes: []networkingv1.PolicyType{
+					networkingv1.PolicyTypeIngress,
+					networkingv1.PolicyTypeEgress,
+				},
+			},
+		}
+
+		out, err := ToYAML(policy)
+		require.NoError(t, err)
+		assert.Equal(t, `apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: default-deny-all
+  namespace: default
+spec:
+  podSelector: {}
+  policyTypes:
+    - Ingress
+    - Egress
+`, string(out))
+
+		var decoded map[string]interface{}
+		require.NoError(t, yaml.Unmarshal(out, &decoded))
+		data, err := json.Marshal(decoded)
+		require.NoError(t, err)
+		var roundTripped networkingv1.NetworkPolicy
+		require.NoError(t, json.Unmarshal(data, &roundTripped))
+		assert.Equal(t, *policy, roundTripped)
+	})
+
+	t.Run("empty peer selectors are kept", func(t *testing.T) {
+		policy := &networkingv1.NetworkPolicy{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+			ObjectMeta: metav1.ObjectMeta{Name: "allow-monitoring"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				Ingress: []networkingv1.NetworkPolicyIngressRule{{
+					From: []networkingv1.NetworkPolicyPeer{{
+						NamespaceSelector: &metav1.LabelSelector{},
+						PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "prometheus"}},
+					}},
+				}},
+			},
+		}
+
+		got, err := Serialize(policy)
+		require.NoError(t, err)
+		spec := got["spec"].(map[string]interface{})
+		ingress := spec["ingress"].([]interface{})
+		from := ingress[0].(map[string]interface{})["from"].([]interface{})
+		peer := from[0].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{}, peer["namespaceSelector"])
+		assert.Equal(t, map[string]interface{}{"matchLabels": map[string]interface{}{"app": "prometheus"}}, peer["podSelector"])
+	})
+
+	t.Run("unset peer selectors are omitted", func(t *testing.T) {
+		policy := &networkingv1.NetworkPolicy{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+			ObjectMeta: metav1.ObjectMeta{Name: "allow-web"},
+			Spec: networkingv1.NetworkPolicySpec{
+				Ingress: []networkingv1.NetworkPolicyIngressRule{{
+					From: []networkingv1.NetworkPolicyPeer{{
+						PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+					}},
+				}},
+			},
+		}
+
+		out, err := ToYAML(policy)
+		require.NoError(t, err)
+		assert.NotContains(t, string(out), "namespaceSelector")
+		assert.Contains(t, string(out), "podSelector: {}")
+	})
+}
+
+// TestSerializeDropsStatus tests that status and null creationTimestamps are
+// omitted from built-in kinds
+func TestSerializeDropsStatus(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr(int32(2)),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "web", Image: "nginx:1.25"}},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas:      2,
+			ReadyReplicas: 1,
+		},
+	}
+
+	out, err := ToYAML(deployment)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "status")
+	assert.NotContains(t, string(out), "creationTimestamp")
+	assert.Contains(t, string(out), "replicas: 2")
+
+	t.Run("unstructured built-in kinds", func(t *testing.T) {
+		got, err := Serialize(map[string]interface{}{
+			"apiVersion": "networking.k8s.io/v1",
+			"kind":       "Ingress",
+			"metadata":   map[string]interface{}{"name": "web", "creationTimestamp": nil},
+			"status":     map[string]interface{}{"loadBalancer": map[string]interface{}{"ingress": []interface{}{"1.2.3.4"}}},
+		})
+		require.NoError(t, err)
+		assert.NotContains(t, got, "status")
+		assert.NotContains(t, got["metadata"], "creationTimestamp")
+	})
+
+	t.Run("custom resources keep status", func(t *testing.T) {
+		got, err := Serialize(map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]interface{}{"name": "w"},
+			"status":     map[string]interface{}{"phase": "Ready"},
+		})
+		require.NoError(t, err)
+		assert.Contains(t, got, "status")
+	})
+}
+
+// TestSerializeUnstructured tests that an Unstructured is serialized from its
+// Object map
+func TestSerializeUnstructured(t *testing.T) {
+	certificate := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata":   map[string]interface{}{"name": "web-tls", "namespace": "prod"},
+		"spec": map[string]interface{}{
+			"secretName":           "web-tls",
+			"dnsNames":             []interface{}{"example.com", "www.example.com"},
+			"duration":             "2160h",
+			"revisionHistoryLimit": int64(3),
+			"issuerRef": map[string]interface{}{
+				"name": "letsencrypt",
+				"kind": "ClusterIssuer",
+			},
+		},
+		"status": map[string]interface{}{"conditions": []interface{}{}},
+	}}
+
+	for name, resource := range map[string]interface{}{
+		"value":   certificate,
+		"pointer": &certificate,
+	} {
+		t.Run(name, func(t *testing.T) {
+			out, err := ToYAML(resource)
+			require.NoError(t, err)
+
+			expected := `apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: web-tls
+  namespace: prod
+spec:
+  dnsNames:
+    - example.com
+    - www.example.com
+  duration: 2160h
+  issuerRef:
+    name: letsencrypt
+    kind: ClusterIssuer
+  revisionHistoryLimit: 3
+  secretName: web-tls
+`
+			assert.Equal(t, expected, string(out))
+			assert.NotContains(t, string(out), "Object")
+		})
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		_, err := Serialize(&unstructured.Unstructured{})
+		assert.Error(t, err)
+
+		var u *unstructured.Unstructured
+		_, err = Serialize(u)
+		assert.Error(t, err)
+	})
+}
+
+// widget is a structured custom resource type for the Scheme tests
+type widget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              widgetSpec `json:"spec"`
+}
+
+type widgetSpec struct {
+	Size int `json:"size"`
+}
+
+func (w *widget) DeepCopyObject() runtime.Object {
+	c := *w
+	w.ObjectMeta.DeepCopyInto(&c.ObjectMeta)
+	return &c
+}
+
+// TestSerializeSchemeTypes tests that structured custom resources registered
+// in Scheme get their apiVersion and kind
+func TestSerializeSchemeTypes(t *testing.T) {
+	gv := schema.GroupVersion{Group: "example.com", Version: "v1alpha1"}
+	Scheme.AddKnownTypes(gv, &widget{})
+
+	w := widget{
+		ObjectMeta: metav1.ObjectMeta{Name: "small"},
+		Spec:       widgetSpec{Size: 2},
+	}
+
+	for name, resource := range map[string]interface{}{
+		"value":   w,
+		"pointer": &w,
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := Serialize(resource)
+			require.NoError(t, err)
+			assert.Equal(t, "example.com/v1alpha1", got["apiVersion"])
+			assert.Equal(t, "widget", got["kind"])
+			assert.Equal(t, map[string]interface{}{"size": float64(2)}, got["spec"])
+		})
+	}
+
+	t.Run("explicit TypeMeta wins", func(t *testing.T) {
+		explicit := w
+		explicit.TypeMeta = metav1.TypeMeta{APIVersion: "example.com/v1", Kind: "Widget"}
+		got, err := Serialize(&explicit)
+		require.NoError(t, err)
+		assert.Equal(t, "example.com/v1", got["apiVersion"])
+		assert.Equal(t, "Widget", got["kind"])
+	})
+}
+
+// TestClusterScopedNamespace tests that metadata.namespace is dropped from
+// cluster-scoped kinds, whose manifests would otherwise be invalid
+func TestClusterScopedNamespace(t *testing.T) {
+	t.Run("typed ClusterRole", func(t *testing.T) {
+		role := &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-reader", Namespace: "default"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get"}},
+			},
+		}
+
+		result, err := Serialize(role)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"name": "node-reader"}, result["metadata"])
+
+		// The resource itself is not modified
+		assert.Equal(t, "default", role.Namespace)
+	})
+
+	t.Run("manifest maps", func(t *testing.T) {
+		result, err := Serialize(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata":   map[string]interface{}{"name": "prod", "namespace": "default"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"name": "prod"}, result["metadata"])
+	})
+
+	t.Run("namespaced kinds keep their namespace", func(t *testing.T) {
+		result, err := Serialize(&rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: "reader", Namespace: "default"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "default", result["metadata"].(map[string]interface{})["namespace"])
+	})
+
+	t.Run("custom kinds with the same name are not changed", func(t *testing.T) {
+		result, err := Serialize(map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Node",
+			"metadata":   map[string]interface{}{"name": "edge", "namespace": "fleet"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "fleet", result["metadata"].(map[string]interface{})["namespace"])
+	})
+}
+
+// rolloutSpec is a custom resource spec with the apimachinery types that
+// encode themselves as strings.
+type rolloutSpec struct {
+	Probe         *corev1.Probe     `json:"probe,omitempty"`
+	Interval      metav1.Duration   `json:"interval,omitempty"`
+	Timeout       metav1.Duration   `json:"timeout,omitempty"`
+	MaxMemory     resource.Quantity `json:"maxMemory,omitempty"`
+	MinMemory     resource.Quantity `json:"minMemory,omitempty"`
+	PausedUntil   *metav1.Duration  `json:"pausedUntil,omitempty"`
+	NotBefore     metav1.Time       `json:"notBefore,omitempty"`
+	LastScheduled metav1.Time       `json:"lastScheduled,omitempty"`
+}
+
+type rollout struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              rolloutSpec `json:"spec"`
+}
+
+func TestSerializeStringEncodedTypes(t *testing.T) {
+	t.Run("durations, quantities and timestamps", func(t *testing.T) {
+		result, err := Serialize(&rollout{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "example.com/v1", Kind: "Rollout"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+			Spec: rolloutSpec{
+				Probe: &corev1.Probe{
+					ProbeHandler:     corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz"}},
+					PeriodSeconds:    10,
+					TimeoutSeconds:   30,
+					FailureThreshold: 3,
+				},
+				Interval:    metav1.Duration{Duration: 30 * time.Second},
+				Timeout:     metav1.Duration{Duration: 90 * time.Minute},
+				MaxMemory:   resource.MustParse("512Mi"),
+				PausedUntil: &metav1.Duration{},
+				NotBefore:   metav1.NewTime(time.Date(2024, 3, 1, 9, 30, 0, 0, time.FixedZone("CET", 3600))),
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]interface{}{
+			"probe": map[string]interface{}{
+				"httpGet":          map[string]interface{}{"path": "/healthz"},
+				"periodSeconds":    float64(10),
+				"timeoutSeconds":   float64(30),
+				"failureThreshold": float64(3),
+			},
+			"interval":    "30s",
+			"timeout":     "1h30m0s",
+			"maxMemory":   "512Mi",
+			"pausedUntil": "0s", // Explicit zero through a pointer
+			"notBefore":   "2024-03-01T08:30:00Z",
+		}, result["spec"], "unset minMemory and lastScheduled are omitted")
+	})
+
+	t.Run("resource lists", func(t *testing.T) {
+		// As in examples/namespace-setup
+		quota := &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "compute-quota"},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: corev1.ResourceList{
+					corev1.ResourceRequestsCPU:    resource.MustParse("10"),
+					corev1.ResourceLimitsCPU:      resource.MustParse("500m"),
+					corev1.ResourceRequestsMemory: resource.MustParse("20Gi"),
+					corev1.ResourceLimitsMemory:   resource.MustParse("0.5Gi"),
+					corev1.ResourcePods:           resource.MustParse("0"),
+				},
+			},
+		}
+
+		output, err := ToYAML(quota)
+		require.NoError(t, err)
+
+		var manifest map[string]interface{}
+		require.NoError(t, yaml.Unmarshal(output, &manifest))
+		assert.Equal(t, map[string]interface{}{
+			"requests.cpu":    "10",
+			"limits.cpu":      "500m",
+			"requests.memory": "20Gi",
+			"limits.memory":   "512Mi",
+			"pods":            "0", // Explicit zero in a map
+		}, manifest["spec"].(map[string]interface{})["hard"])
+	})
+}
@@ -0,0 +1,85 @@
+package serialize
+
+import (
+	"bytes"
+	"encoding/json"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	nodev1 "k8s.io/api/node/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// BuiltinScheme registers the kinds of the built-in API groups, so that
+// manifests of those kinds can be decoded into their Go types. Unlike
+// Scheme, it does not set the apiVersion and kind of typed resources.
+var BuiltinScheme = newBuiltinScheme()
+
+func newBuiltinScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{
+		admissionregistrationv1.AddToScheme,
+		appsv1.AddToScheme,
+		autoscalingv1.AddToScheme,
+		autoscalingv2.AddToScheme,
+		batchv1.AddToScheme,
+		certificatesv1.AddToScheme,
+		coordinationv1.AddToScheme,
+		corev1.AddToScheme,
+		discoveryv1.AddToScheme,
+		networkingv1.AddToScheme,
+		nodev1.AddToScheme,
+		policyv1.AddToScheme,
+		rbacv1.AddToScheme,
+		schedulingv1.AddToScheme,
+		storagev1.AddToScheme,
+	} {
+		if err := add(scheme); err != nil {
+			panic(err)
+		}
+	}
+	return scheme
+}
+
+// Typed returns manifest decoded into the Go type of its apiVersion and kind
+// when that is a built-in kind, so that it is serialized like the typed value
+// it encodes: zero values listed in PreserveZeroFields are kept and status is
+// dropped. Manifests of other kinds, and manifests with fields or values that
+// do not fit their type, are returned unchanged.
+func Typed(manifest map[string]interface{}) interface{} {
+	apiVersion, _ := manifest["apiVersion"].(string)
+	kind, _ := manifest["kind"].(string)
+	obj, err := BuiltinScheme.New(schema.FromAPIVersionAndKind(apiVersion, kind))
+	if err != nil {
+		return manifest
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return manifest
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		return manifest
+	}
+	return obj
+}
+
+// Manifest is a manifest that has already been serialized, for example with
+// Serialize(Typed(m)) before being modified. Serialize returns it as it is,
+// keeping zero values that serializing it as a map would drop.
+type Manifest map[string]interface{}
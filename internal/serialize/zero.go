@@ -0,0 +1,223 @@
+package serialize
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PreserveZeroFields lists fields whose zero value is meaningful and is kept
+// in the output when set explicitly, keyed by Go type and field name (e.g.
+// "DeploymentSpec.Replicas"). All other zero values are omitted.
+//
+// A field is only kept if it is present in the JSON encoding of the resource,
+// so optional fields must be set through a pointer: Replicas: ptr(int32(0))
+// serializes to "replicas: 0" while an unset Replicas is still omitted.
+// Callers may add or remove entries before serializing.
+var PreserveZeroFields = map[string]bool{
+	"DeploymentSpec.Replicas":                    true, // Scale to zero
+	"ReplicaSetSpec.Replicas":                    true,
+	"ReplicationControllerSpec.Replicas":         true,
+	"StatefulSetSpec.Replicas":                   true,
+	"RollingUpdateStatefulSetStrategy.Partition": true, // Update all pods
+	"JobSpec.BackoffLimit":                       true, // No retries
+	"CronJobSpec.SuccessfulJobsHistoryLimit":     true, // Keep no finished jobs
+	"CronJobSpec.FailedJobsHistoryLimit":         true,
+	"NetworkPolicySpec.PodSelector":              true, // All pods in the namespace
+	"NetworkPolicyPeer.PodSelector":              true,
+	"NetworkPolicyPeer.NamespaceSelector":        true, // All namespaces
+}
+
+// pathSep separates the segments of a field path. It cannot appear in JSON
+// field names or in label and annotation keys.
+const pathSep = "\x00"
+
+// preservedPaths returns the JSON paths of the fields of resource that are
+// listed in PreserveZeroFields and set.
+func preservedPaths(resource interface{}) map[string]bool {
+	if len(PreserveZeroFields) == 0 {
+		return nil
+	}
+
+	paths := make(map[string]bool)
+	walkFields(reflect.ValueOf(resource), "", func(owner reflect.Type, field reflect.StructField, v reflect.Value, path string) {
+		if PreserveZeroFields[owner.Name()+"."+field.Name] && !isNilValue(v) {
+			paths[path] = true
+		}
+	})
+	return paths
+}
+
+// unsetScalarPaths returns the JSON paths of the struct fields of resource
+// that hold a zero metav1.Duration or resource.Quantity. They are encoded as
+// "0s" and "0", which cannot be told apart from other strings once encoded,
+// so they are found here and removed like other zero values. Set a pointer
+// field to keep an explicit zero.
+func unsetScalarPaths(resource interface{}) map[string]bool {
+	paths := make(map[string]bool)
+	walkFields(reflect.ValueOf(resource), "", func(_ reflect.Type, _ reflect.StructField, v reflect.Value, path string) {
+		if isUnsetScalar(v) {
+			paths[path] = true
+		}
+	})
+	return paths
+}
+
+// walkFields walks a value the way encoding/json encodes it, calling fn with
+// the JSON path of every encoded struct field that is not inlined.
+func walkFields(v reflect.Value, path string, fn func(owner reflect.Type, field reflect.StructField, v reflect.Value, path string)) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if isStringEncoded(v) {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name, inline, skip := jsonFieldName(field)
+			if skip {
+				continue
+			}
+
+			fieldPath := path
+			if !inline {
+				fieldPath = childPath(path, name)
+				fn(t, field, v.Field(i), fieldPath)
+			}
+			walkFields(v.Field(i), fieldPath, fn)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return // Encoded as a base64 string
+		}
+		for i := 0; i < v.Len(); i++ {
+			walkFields(v.Index(i), childPath(path, strconv.Itoa(i)), fn)
+		}
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			walkFields(iter.Value(), childPath(path, iter.Key().String()), fn)
+		}
+	}
+}
+
+// isStringEncoded reports whether v holds one of the apimachinery types that
+// encode themselves as a JSON string rather than an object: a
+// metav1.Duration as "30s", a resource.Quantity in canonical form such as
+// "512Mi", and a metav1.Time or metav1.MicroTime as an RFC 3339 timestamp.
+// Their Go fields never appear in the output.
+func isStringEncoded(v reflect.Value) bool {
+	if v.Kind() != reflect.Struct || !v.CanInterface() {
+		return false
+	}
+	switch v.Interface().(type) {
+	case metav1.Duration, resource.Quantity, metav1.Time, metav1.MicroTime:
+		return true
+	}
+	return false
+}
+
+// isUnsetScalar reports whether v is a zero metav1.Duration or
+// resource.Quantity. A zero metav1.Time already encodes as null.
+func isUnsetScalar(v reflect.Value) bool {
+	if v.Kind() != reflect.Struct || !v.CanInterface() {
+		return false
+	}
+	switch value := v.Interface().(type) {
+	case metav1.Duration:
+		return value.Duration == 0
+	case resource.Quantity:
+		return value.IsZero()
+	}
+	return false
+}
+
+// deletePaths removes the fields at paths from data, leaving any object they
+// empty for cleanZeroValues to remove. Paths in keep are not removed.
+func deletePaths(data map[string]interface{}, paths, keep map[string]bool) {
+	for path := range paths {
+		if keep[path] {
+			continue
+		}
+		segments := strings.Split(strings.TrimPrefix(path, pathSep), pathSep)
+		if object, ok := lookupPath(data, segments[:len(segments)-1]).(map[string]interface{}); ok {
+			delete(object, segments[len(segments)-1])
+		}
+	}
+}
+
+// lookupPath returns the value at the path made of segments in data, or nil
+// if there is none.
+func lookupPath(data map[string]interface{}, segments []string) interface{} {
+	var value interface{} = data
+	for _, segment := range segments {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[segment]
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i >= len(v) {
+				return nil
+			}
+			value = v[i]
+		default:
+			return nil
+		}
+	}
+	return value
+}
+
+// jsonFieldName returns the JSON name of a struct field, whether its fields
+// are inlined into the parent object, and whether it is not encoded at all.
+func jsonFieldName(field reflect.StructField) (name string, inline, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	if field.Anonymous && name == "" {
+		return "", true, false
+	}
+	if strings.Contains(opts, "inline") {
+		return "", true, false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, false, false
+}
+
+// isNilValue reports whether a field holds a nil pointer, map, slice or
+// interface, which encoding/json omits or writes as null.
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
+
+// childPath appends a key or index to a field path.
+func childPath(path, key string) string {
+	return path + pathSep + key
+}
@@ -0,0 +1,149 @@
+package validate
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/lex00/wetwire-k8s-go/internal/serialize"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// knownTypes maps "<import path>.<type name>" to the Go type of every
+// built-in API struct, including the nested types reachable from the kinds.
+var knownTypes = newTypeTable()
+
+func newTypeTable() map[string]reflect.Type {
+	types := make(map[string]reflect.Type)
+	for _, t := range serialize.BuiltinScheme.AllKnownTypes() {
+		collectTypes(t, types)
+	}
+	return types
+}
+
+// collectTypes adds t and every named struct type reachable from its fields
+// to types.
+func collectTypes(t reflect.Type, types map[string]reflect.Type) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t.Name() == "" {
+		return
+	}
+
+	key := typeKey(t)
+	if _, seen := types[key]; seen {
+		return
+	}
+	types[key] = t
+
+	for i := 0; i < t.NumField(); i++ {
+		collectTypes(t.Field(i).Type, types)
+	}
+}
+
+// typeKey returns the key of a named type in knownTypes.
+func typeKey(t reflect.Type) string {
+	return t.PkgPath() + "." + t.Name()
+}
+
+// lookupType returns the built-in type with the given import path and name.
+func lookupType(importPath, name string) (reflect.Type, bool) {
+	t, ok := knownTypes[importPath+"."+name]
+	return t, ok
+}
+
+var jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// isOpaque reports whether a type controls its own JSON encoding, such as
+// resource.Quantity, intstr.IntOrString or metav1.Time. Their fields are not
+// validated.
+func isOpaque(t reflect.Type) bool {
+	return t.Implements(jsonMarshaler) || reflect.PointerTo(t).Implements(jsonMarshaler)
+}
+
+// jsonName returns the JSON name of a struct field and whether the field is
+// optional (tagged omitempty or not encoded at all).
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, strings.Contains(opts, "omitempty") || strings.Contains(opts, "inline")
+}
+
+// isRequired reports whether a field must be set in a composite literal.
+// Fields without omitempty are required by the API, but only those whose
+// zero value is never valid are checked: nil pointers, slices and maps
+// encode as null, and empty strings and zero numbers are rejected. Zero
+// structs and false booleans are valid values.
+func isRequired(field reflect.StructField) bool {
+	if field.Anonymous {
+		return false
+	}
+	if _, optional := jsonName(field); optional {
+		return false
+	}
+
+	switch field.Type.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.String,
+		reflect.Int, reflect.Int32, reflect.Int64, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// enumValues lists the allowed values of closed string enums. Enums that
+// accept custom values, such as SecretType, are not listed.
+var enumValues = map[reflect.Type][]string{}
+
+func init() {
+	enum(corev1.PullAlways, corev1.PullNever, corev1.PullIfNotPresent)
+	enum(corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP)
+	enum(corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeExternalName)
+	enum(corev1.ServiceAffinityClientIP, corev1.ServiceAffinityNone)
+	enum(corev1.ServiceExternalTrafficPolicyCluster, corev1.ServiceExternalTrafficPolicyLocal)
+	enum(corev1.RestartPolicyAlways, corev1.RestartPolicyOnFailure, corev1.RestartPolicyNever)
+	enum(corev1.DNSClusterFirstWithHostNet, corev1.DNSClusterFirst, corev1.DNSDefault, corev1.DNSNone)
+	enum(corev1.TerminationMessageReadFile, corev1.TerminationMessageFallbackToLogsOnError)
+	enum(corev1.ReadWriteOnce, corev1.ReadOnlyMany, corev1.ReadWriteMany, corev1.ReadWriteOncePod)
+	enum(corev1.PersistentVolumeReclaimRecycle, corev1.PersistentVolumeReclaimDelete, corev1.PersistentVolumeReclaimRetain)
+	enum(corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute)
+	enum(corev1.TolerationOpExists, corev1.TolerationOpEqual)
+	enum(corev1.NodeSelectorOpIn, corev1.NodeSelectorOpNotIn, corev1.NodeSelectorOpExists,
+		corev1.NodeSelectorOpDoesNotExist, corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt)
+	enum(corev1.URISchemeHTTP, corev1.URISchemeHTTPS)
+	enum(corev1.DoNotSchedule, corev1.ScheduleAnyway)
+	enum(corev1.MountPropagationNone, corev1.MountPropagationHostToContainer, corev1.MountPropagationBidirectional)
+	enum(appsv1.RecreateDeploymentStrategyType, appsv1.RollingUpdateDeploymentStrategyType)
+	enum(appsv1.RollingUpdateStatefulSetStrategyType, appsv1.OnDeleteStatefulSetStrategyType)
+	enum(appsv1.RollingUpdateDaemonSetStrategyType, appsv1.OnDeleteDaemonSetStrategyType)
+	enum(appsv1.OrderedReadyPodManagement, appsv1.ParallelPodManagement)
+	enum(batchv1.AllowConcurrent, batchv1.ForbidConcurrent, batchv1.ReplaceConcurrent)
+	enum(batchv1.NonIndexedCompletion, batchv1.IndexedCompletion)
+	enum(networkingv1.PathTypeExact, networkingv1.PathTypePrefix, networkingv1.PathTypeImplementationSpecific)
+	enum(networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress)
+	enum(metav1.LabelSelectorOpIn, metav1.LabelSelectorOpNotIn, metav1.LabelSelectorOpExists, metav1.LabelSelectorOpDoesNotExist)
+	enum(autoscalingv2.ObjectMetricSourceType, autoscalingv2.PodsMetricSourceType, autoscalingv2.ResourceMetricSourceType,
+		autoscalingv2.ContainerResourceMetricSourceType, autoscalingv2.ExternalMetricSourceType)
+	enum(autoscalingv2.UtilizationMetricType, autoscalingv2.ValueMetricType, autoscalingv2.AverageValueMetricType)
+	enum(policyv1.IfHealthyBudget, policyv1.AlwaysAllow)
+}
+
+// enum registers the allowed values of a string enum type.
+func enum[T ~string](values ...T) {
+	t := reflect.TypeOf(values[0])
+	for _, v := range values {
+		enumValues[t] = append(enumValues[t], string(v))
+	}
+}
@@ -0,0 +1,30 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var NoSelectorDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "no-selector"},
+	Spec: appsv1.DeploymentSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Image:           "nginx:1.25",
+					ImagePullPolicy: "Sometimes",
+				}},
+			},
+		},
+	},
+}
+
+var BadService = corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "bad"},
+	Spec: corev1.ServiceSpec{
+		Type:  corev1.ServiceType("Internal"),
+		Ports: []corev1.ServicePort{{Port: "http"}},
+	},
+	Metadata: metav1.ObjectMeta{},
+}
@@ -0,0 +1,36 @@
+package testdata
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var WebDeployment = appsv1.Deployment{
+	ObjectMeta: metav1.ObjectMeta{Name: "web"},
+	Spec: appsv1.DeploymentSpec{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		Strategy: appsv1.DeploymentStrategy{Type: "RollingUpdate"},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:            "web",
+					Image:           "nginx:1.25",
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					Ports:           []corev1.ContainerPort{{ContainerPort: 80, Protocol: "TCP"}},
+				}},
+			},
+		},
+	},
+}
+
+var WebService = &corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{Name: "web"},
+	Spec: corev1.ServiceSpec{
+		Type:     corev1.ServiceType("ClusterIP"),
+		Selector: map[string]string{"app": "web"},
+		Ports:    []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromInt32(80)}},
+	},
+}
@@ -0,0 +1,331 @@
+// Package validate checks Kubernetes resources declared in Go source against
+// the built-in API types, without a cluster or any external tools.
+//
+// Each top-level variable initialized with a k8s.io/api composite literal is
+// walked alongside its Go type to report missing required fields, unknown
+// fields, literals of the wrong type and values outside closed enums.
+package validate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	coreast "github.com/lex00/wetwire-core-go/ast"
+)
+
+// Issue is a schema violation in a resource declaration.
+type Issue struct {
+	File     string // Source file
+	Line     int    // Line of the offending expression
+	Column   int    // Column of the offending expression
+	Resource string // Variable the resource is declared in
+	Field    string // JSON path of the field, e.g. "spec.selector"
+	Message  string // Description of the violation
+}
+
+// String formats the issue as "file:line: Resource: message".
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", i.File, i.Line, i.Resource, i.Message)
+}
+
+// ValidateFile validates the resources declared in a Go source file.
+func ValidateFile(filePath string) ([]Issue, error) {
+	file, fset, err := coreast.ParseFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
+	}
+
+	c := &checker{fset: fset, imports: fileImports(file)}
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, name := range valueSpec.Names {
+				if name.Name != "_" && i < len(valueSpec.Values) {
+					c.resource = name.Name
+					c.checkExpr(valueSpec.Values[i], nil, "")
+				}
+			}
+		}
+	}
+
+	return c.issues, nil
+}
+
+// ValidateDirectory validates the resources declared in all Go files within
+// a directory recursively, skipping tests, vendor and hidden directories.
+func ValidateDirectory(dir string) ([]Issue, error) {
+	var issues []Issue
+
+	opts := coreast.ParseOptions{
+		SkipTests:  true,
+		SkipVendor: true,
+		SkipHidden: true,
+	}
+
+	err := coreast.WalkGoFiles(dir, opts, func(path string) error {
+		fileIssues, err := ValidateFile(path)
+		if err != nil {
+			// Files that do not parse are reported by the build
+			return nil
+		}
+		issues = append(issues, fileIssues...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory %s: %w", dir, err)
+	}
+
+	return issues, nil
+}
+
+// fileImports maps the package names used in a file to their import paths.
+func fileImports(file *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = importPath
+	}
+	return imports
+}
+
+// checker walks resource expressions alongside their Go types.
+type checker struct {
+	fset     *token.FileSet
+	imports  map[string]string
+	resource string
+	issues   []Issue
+}
+
+// report records an issue at the position of node.
+func (c *checker) report(node ast.Node, field, format string, args ...any) {
+	pos := c.fset.Position(node.Pos())
+	c.issues = append(c.issues, Issue{
+		File:     pos.Filename,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Resource: c.resource,
+		Field:    field,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// resolveType returns the built-in type named by a type expression such as
+// appsv1.Deployment, or nil if it is not a built-in type.
+func (c *checker) resolveType(expr ast.Expr) reflect.Type {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	importPath, ok := c.imports[pkg.Name]
+	if !ok {
+		return nil
+	}
+	if t, ok := lookupType(importPath, sel.Sel.Name); ok {
+		return t
+	}
+	// Named non-struct types, such as corev1.Protocol, for conversions
+	for t := range enumValues {
+		if t.PkgPath() == importPath && t.Name() == sel.Sel.Name {
+			return t
+		}
+	}
+	return nil
+}
+
+// checkExpr validates expr as a value of type t. A nil t means the type is
+// unknown and is taken from the expression itself, if it names one.
+func (c *checker) checkExpr(expr ast.Expr, t reflect.Type, field string) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch e := expr.(type) {
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			c.checkExpr(e.X, t, field)
+		}
+
+	case *ast.ParenExpr:
+		c.checkExpr(e.X, t, field)
+
+	case *ast.CompositeLit:
+		if e.Type != nil {
+			if named := c.resolveType(e.Type); named != nil {
+				t = named
+			} else if _, isArray := e.Type.(*ast.ArrayType); !isArray {
+				if _, isMap := e.Type.(*ast.MapType); !isMap {
+					return // Not a built-in type
+				}
+			}
+		}
+		if t == nil {
+			return
+		}
+		c.checkCompositeLit(e, t, field)
+
+	case *ast.BasicLit:
+		if t != nil {
+			c.checkLiteral(e, t, field)
+		}
+
+	case *ast.CallExpr:
+		// Conversions such as corev1.Protocol("TCP")
+		if len(e.Args) != 1 {
+			return
+		}
+		if named := c.resolveType(e.Fun); named != nil {
+			if lit, ok := e.Args[0].(*ast.BasicLit); ok {
+				c.checkLiteral(lit, named, field)
+			}
+		}
+	}
+}
+
+// checkCompositeLit validates a composite literal of type t.
+func (c *checker) checkCompositeLit(lit *ast.CompositeLit, t reflect.Type, field string) {
+	switch t.Kind() {
+	case reflect.Struct:
+		if !isOpaque(t) {
+			c.checkStruct(lit, t, field)
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i, elt := range lit.Elts {
+			c.checkExpr(elt, t.Elem(), fmt.Sprintf("%s[%d]", field, i))
+		}
+
+	case reflect.Map:
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key := ""
+			if keyLit, ok := kv.Key.(*ast.BasicLit); ok && keyLit.Kind == token.STRING {
+				key, _ = strconv.Unquote(keyLit.Value)
+			}
+			c.checkExpr(kv.Value, t.Elem(), joinField(field, key))
+		}
+	}
+}
+
+// checkStruct validates the fields of a struct literal and reports required
+// fields that are not set.
+func (c *checker) checkStruct(lit *ast.CompositeLit, t reflect.Type, field string) {
+	set := make(map[string]bool)
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			// Positional fields: required fields cannot be told apart
+			return
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		structField, ok := t.FieldByName(key.Name)
+		if !ok || len(structField.Index) != 1 {
+			c.report(key, field, "unknown field %s in %s", key.Name, t.Name())
+			continue
+		}
+		set[key.Name] = true
+
+		fieldPath := field
+		if !structField.Anonymous {
+			name, _ := jsonName(structField)
+			fieldPath = joinField(field, name)
+		}
+		c.checkExpr(kv.Value, structField.Type, fieldPath)
+	}
+
+	var missing []string
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if structField.IsExported() && !set[structField.Name] && isRequired(structField) {
+			name, _ := jsonName(structField)
+			missing = append(missing, joinField(field, name))
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		c.report(lit, name, "missing required field %s in %s", name, t.Name())
+	}
+}
+
+// checkLiteral validates a basic literal assigned to a value of type t.
+func (c *checker) checkLiteral(lit *ast.BasicLit, t reflect.Type, field string) {
+	if !literalFits(lit.Kind, t.Kind()) {
+		c.report(lit, field, "%s: cannot use %s as %s", field, lit.Value, t)
+		return
+	}
+
+	allowed, isEnum := enumValues[t]
+	if !isEnum || lit.Kind != token.STRING {
+		return
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	c.report(lit, field, "%s: unsupported value %q for %s, must be one of: %s",
+		field, value, t.Name(), strings.Join(allowed, ", "))
+}
+
+// literalFits reports whether a literal of the given kind can hold a value
+// of the given type kind.
+func literalFits(lit token.Token, kind reflect.Kind) bool {
+	switch lit {
+	case token.STRING:
+		return kind == reflect.String || kind == reflect.Interface
+	case token.INT:
+		switch kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64, reflect.Interface:
+			return true
+		}
+		return false
+	case token.FLOAT:
+		return kind == reflect.Float32 || kind == reflect.Float64 || kind == reflect.Interface
+	}
+	return true
+}
+
+// joinField appends a field name to a JSON path.
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
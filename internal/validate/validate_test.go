@@ -0,0 +1,66 @@
+package validate_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/internal/validate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFile_Valid(t *testing.T) {
+	issues, err := validate.ValidateFile(filepath.Join("testdata", "valid.go"))
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func TestValidateFile_Invalid(t *testing.T) {
+	issues, err := validate.ValidateFile(filepath.Join("testdata", "invalid.go"))
+	require.NoError(t, err)
+
+	var got []string
+	for _, issue := range issues {
+		assert.Contains(t, issue.File, "invalid.go")
+		got = append(got, issue.String()[len(issue.File):])
+	}
+
+	assert.Equal(t, []string{
+		`:16: NoSelectorDeployment: spec.template.spec.containers[0].imagePullPolicy: unsupported value "Sometimes" for PullPolicy, must be one of: Always, Never, IfNotPresent`,
+		`:14: NoSelectorDeployment: missing required field spec.template.spec.containers[0].name in Container`,
+		`:11: NoSelectorDeployment: missing required field spec.selector in DeploymentSpec`,
+		`:26: BadService: spec.type: unsupported value "Internal" for ServiceType, must be one of: ClusterIP, NodePort, LoadBalancer, ExternalName`,
+		`:27: BadService: spec.ports[0].port: cannot use "http" as int32`,
+		`:29: BadService: unknown field Metadata in Service`,
+	}, got)
+}
+
+func TestValidateFile_Issue(t *testing.T) {
+	issues, err := validate.ValidateFile(filepath.Join("testdata", "invalid.go"))
+	require.NoError(t, err)
+
+	var selector *validate.Issue
+	for i := range issues {
+		if issues[i].Field == "spec.selector" {
+			selector = &issues[i]
+		}
+	}
+	require.NotNil(t, selector)
+	assert.Equal(t, "NoSelectorDeployment", selector.Resource)
+	assert.Equal(t, 11, selector.Line)
+	assert.Equal(t, 8, selector.Column)
+}
+
+func TestValidateDirectory(t *testing.T) {
+	issues, err := validate.ValidateDirectory("testdata")
+	require.NoError(t, err)
+	assert.Len(t, issues, 6)
+	for _, issue := range issues {
+		assert.Equal(t, "invalid.go", filepath.Base(issue.File))
+	}
+}
+
+func TestValidateFile_NonExistent(t *testing.T) {
+	_, err := validate.ValidateFile("/nonexistent/file.go")
+	assert.Error(t, err)
+}
@@ -0,0 +1,68 @@
+// Package lint checks Go source declaring Kubernetes resources against the
+// wetwire-k8s lint rules, as the lint command does, for use by other Go
+// programs.
+//
+// The functions run the default rule set: every rule except the optional
+// ones, reporting issues of all severities. Use a Config with NewLinter to
+// disable rules, enable optional ones or change severities.
+package lint
+
+import (
+	k8slint "github.com/lex00/wetwire-k8s-go/internal/lint"
+)
+
+// Severity is the severity of an issue.
+type Severity = k8slint.Severity
+
+// Severity levels, from most to least severe.
+const (
+	SeverityError   = k8slint.SeverityError
+	SeverityWarning = k8slint.SeverityWarning
+	SeverityInfo    = k8slint.SeverityInfo
+)
+
+// Issue is a problem reported by a rule, with its position.
+type Issue = k8slint.Issue
+
+// Rule describes a lint rule.
+type Rule = k8slint.Rule
+
+// Config controls which rules run and which issues are reported.
+type Config = k8slint.Config
+
+// Linter lints Go source with a fixed configuration.
+type Linter = k8slint.Linter
+
+// NewLinter returns a linter for config. A nil config runs the default rule
+// set.
+func NewLinter(config *Config) *Linter {
+	return k8slint.NewLinter(config)
+}
+
+// Rules returns every lint rule, including optional rules and custom rules
+// registered by the program.
+func Rules() []Rule {
+	return k8slint.Rules()
+}
+
+// LintFile lints the Go file at path. Rules that compare resources across
+// files also see the other files of its package.
+func LintFile(path string) ([]Issue, error) {
+	return k8slint.NewLinter(nil).LintFile(path)
+}
+
+// LintDir lints the non-test Go files in dir and its subdirectories. A file
+// that cannot be parsed is reported as a WK0001 issue at its first syntax
+// error, and the other files are still linted.
+func LintDir(dir string) ([]Issue, error) {
+	return k8slint.NewLinter(nil).LintDirectory(dir)
+}
+
+// LintSource lints Go source held in memory. Issues are reported in the file
+// "source.go", and the source is treated as a whole package.
+func LintSource(src []byte) ([]Issue, error) {
+	return k8slint.NewLinter(nil).LintSource(sourceFile, src)
+}
+
+// sourceFile is the file name LintSource reports issues in.
+const sourceFile = "source.go"
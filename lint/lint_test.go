@@ -0,0 +1,81 @@
+package lint_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lex00/wetwire-k8s-go/lint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const latestTagSource = `package k8s
+
+import corev1 "k8s.io/api/core/v1"
+
+var WebPod = corev1.Pod{
+	Spec: corev1.PodSpec{
+		Containers: []corev1.Container{
+			corev1.Container{Name: "web", Image: "nginx:latest"},
+		},
+	},
+}
+`
+
+// issuesByRule returns the lines each rule reported issues on.
+func issuesByRule(issues []lint.Issue) map[string][]int {
+	lines := make(map[string][]int)
+	for _, issue := range issues {
+		lines[issue.Rule] = append(lines[issue.Rule], issue.Line)
+	}
+	return lines
+}
+
+func TestLintSource(t *testing.T) {
+	issues, err := lint.LintSource([]byte(latestTagSource))
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{8}, issuesByRule(issues)["WK8006"])
+	for _, issue := range issues {
+		assert.Equal(t, "source.go", issue.File)
+	}
+}
+
+func TestLintSource_ParseError(t *testing.T) {
+	_, err := lint.LintSource([]byte("package k8s\n\nvar = \n"))
+	assert.ErrorContains(t, err, "failed to parse source.go")
+}
+
+func TestLintFileAndDir(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pod.go")
+	require.NoError(t, os.WriteFile(path, []byte(latestTagSource), 0644))
+
+	issues, err := lint.LintFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []int{8}, issuesByRule(issues)["WK8006"])
+
+	issues, err = lint.LintDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []int{8}, issuesByRule(issues)["WK8006"])
+}
+
+func TestNewLinter(t *testing.T) {
+	linter := lint.NewLinter(&lint.Config{
+		DisabledRules: []string{"WK8006"},
+		MinSeverity:   lint.SeverityInfo,
+	})
+	issues, err := linter.LintSource("pod.go", []byte(latestTagSource))
+	require.NoError(t, err)
+	assert.NotContains(t, issuesByRule(issues), "WK8006")
+}
+
+func TestRules(t *testing.T) {
+	ids := make(map[string]lint.Severity)
+	for _, rule := range lint.Rules() {
+		ids[rule.ID] = rule.Severity
+	}
+	assert.Contains(t, ids, "WK8006")
+	assert.Contains(t, ids, "WK8014") // optional rules are listed too
+}
@@ -0,0 +1,143 @@
+// Package wetwirek8s builds Kubernetes manifests from in-memory Go values.
+//
+// The wetwire-k8s CLI discovers resources by parsing Go source files. This
+// package is for programs that already hold their resources as values, such
+// as typed structs from k8s.io/api or unstructured objects, and only need
+// them validated, ordered and serialized:
+//
+//	manifests, err := wetwirek8s.Build([]any{deployment, service})
+package wetwirek8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/lex00/wetwire-k8s-go/internal/build"
+	"github.com/lex00/wetwire-k8s-go/internal/serialize"
+)
+
+// Options configures BuildWithOptions.
+type Options struct {
+	// Format is the output format, "yaml" or "json". Empty means "yaml".
+	Format string
+
+	// Indent is the number of spaces per YAML nesting level, from 2 to 9.
+	// Zero uses the same indent as the CLI. It is ignored for JSON.
+	Indent int
+}
+
+// Build serializes resources to multi-document YAML. It is BuildWithOptions
+// with default options.
+func Build(resources []any) ([]byte, error) {
+	return BuildWithOptions(resources, Options{})
+}
+
+// BuildWithOptions serializes resources to multi-document YAML or, with
+// Format "json", to a JSON array of objects.
+//
+// Each resource must be a Kubernetes object: a typed struct or a pointer to
+// one, an unstructured.Unstructured or a map. Its apiVersion and kind are
+// taken from TypeMeta, or from the serializer's scheme for registered types,
+// and it must have a metadata name or generateName. Resources declaring the
+// same object are collapsed if they are identical and are an error
+// otherwise.
+//
+// Resources are written in the order the CLI applies kinds in, Namespaces
+// first and Services and Ingresses last, keeping their given order within a
+// kind. Dependencies between values cannot be seen, so unlike the CLI no
+// further ordering is done.
+func BuildWithOptions(resources []any, opts Options) ([]byte, error) {
+	format := opts.Format
+	if format == "" {
+		format = "yaml"
+	}
+	if format != "yaml" && format != "json" {
+		return nil, fmt.Errorf("unsupported format: %s (supported: yaml, json)", format)
+	}
+
+	objects, err := manifests(resources)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "json" {
+		if len(objects) == 0 {
+			return []byte("[]"), nil
+		}
+		data, err := json.MarshalIndent(objects, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal to JSON: %w", err)
+		}
+		return data, nil
+	}
+
+	documents := make([]interface{}, len(objects))
+	for i, object := range objects {
+		documents[i] = object
+	}
+	return serialize.ToMultiYAMLWithOptions(documents, serialize.YAMLOptions{Indent: opts.Indent})
+}
+
+// manifest is a serialized resource and its position in the input.
+type manifest struct {
+	index  int
+	kind   string
+	object map[string]interface{}
+}
+
+// manifests serializes and validates resources, drops identical duplicates
+// and returns the remaining objects in apply order.
+func manifests(resources []any) ([]map[string]interface{}, error) {
+	var result []manifest
+	seen := make(map[string]int)
+	for i, resource := range resources {
+		object, err := serialize.Serialize(resource)
+		if err != nil {
+			return nil, fmt.Errorf("resource %d: %w", i, err)
+		}
+
+		apiVersion, _ := object["apiVersion"].(string)
+		kind, _ := object["kind"].(string)
+		if apiVersion == "" || kind == "" {
+			return nil, fmt.Errorf("resource %d (%T): apiVersion and kind are not set and the type is not registered in the scheme", i, resource)
+		}
+		metadata, _ := object["metadata"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+		generateName, _ := metadata["generateName"].(string)
+		if name == "" && generateName == "" {
+			return nil, fmt.Errorf("resource %d (%s): metadata.name is not set", i, kind)
+		}
+
+		m := manifest{index: i, kind: kind, object: object}
+		if name != "" {
+			namespace, _ := metadata["namespace"].(string)
+			path := name
+			if namespace != "" {
+				path = namespace + "/" + name
+			}
+			key := fmt.Sprintf("%s %s %s", apiVersion, kind, path)
+			if j, ok := seen[key]; ok {
+				first := result[j]
+				if reflect.DeepEqual(first.object, object) {
+					continue
+				}
+				return nil, fmt.Errorf("conflicting duplicate resources: %s %s is declared differently by resources %d and %d",
+					kind, path, first.index, i)
+			}
+			seen[key] = len(result)
+		}
+		result = append(result, m)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return build.KindPriority(result[i].kind) < build.KindPriority(result[j].kind)
+	})
+
+	objects := make([]map[string]interface{}, len(result))
+	for i, m := range result {
+		objects[i] = m.object
+	}
+	return objects, nil
+}
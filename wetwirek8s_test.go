@@ -0,0 +1,150 @@
+package wetwirek8s_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	wetwirek8s "github.com/lex00/wetwire-k8s-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+var labels = map[string]string{"app": "web"}
+
+func deployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "prod", Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr(int32(2)),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "web", Image: "nginx:1.27"}},
+				},
+			},
+		},
+	}
+}
+
+func service() corev1.Service {
+	return corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "prod"},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports:    []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromInt32(8080)}},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	// The Service is given first but applied after the Deployment
+	output, err := wetwirek8s.Build([]any{service(), deployment()})
+	require.NoError(t, err)
+
+	documents := strings.Split(string(output), "\n---\n")
+	require.Len(t, documents, 2)
+	assert.True(t, strings.HasPrefix(documents[0], "apiVersion: apps/v1\nkind: Deployment\n"), documents[0])
+	assert.Contains(t, documents[0], "replicas: 2")
+	assert.Contains(t, documents[0], "image: nginx:1.27")
+	assert.True(t, strings.HasPrefix(documents[1], "apiVersion: v1\nkind: Service\n"), documents[1])
+	assert.Contains(t, documents[1], "targetPort: 8080")
+	assert.NotContains(t, string(output), "status")
+	assert.NotContains(t, string(output), "creationTimestamp")
+}
+
+func TestBuildWithOptions_JSON(t *testing.T) {
+	output, err := wetwirek8s.BuildWithOptions([]any{service(), deployment()}, wetwirek8s.Options{Format: "json"})
+	require.NoError(t, err)
+
+	var objects []struct {
+		Kind     string
+		Metadata struct{ Name string }
+	}
+	require.NoError(t, json.Unmarshal(output, &objects))
+	require.Len(t, objects, 2)
+	assert.Equal(t, "Deployment", objects[0].Kind)
+	assert.Equal(t, "Service", objects[1].Kind)
+	assert.Equal(t, "web", objects[1].Metadata.Name)
+}
+
+func TestBuildWithOptions_Indent(t *testing.T) {
+	output, err := wetwirek8s.BuildWithOptions([]any{service()}, wetwirek8s.Options{Indent: 2})
+	require.NoError(t, err)
+	assert.Contains(t, string(output), "\nmetadata:\n  name: web\n")
+
+	_, err = wetwirek8s.BuildWithOptions([]any{service()}, wetwirek8s.Options{Format: "toml"})
+	assert.ErrorContains(t, err, "unsupported format: toml")
+}
+
+func TestBuild_Empty(t *testing.T) {
+	output, err := wetwirek8s.Build(nil)
+	require.NoError(t, err)
+	assert.Empty(t, output)
+
+	output, err = wetwirek8s.BuildWithOptions(nil, wetwirek8s.Options{Format: "json"})
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(output))
+}
+
+func TestBuild_Duplicates(t *testing.T) {
+	output, err := wetwirek8s.Build([]any{deployment(), service(), deployment()})
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(output), "kind: Deployment"))
+
+	changed := deployment()
+	changed.Spec.Replicas = ptr(int32(3))
+	_, err = wetwirek8s.Build([]any{deployment(), service(), changed})
+	assert.EqualError(t, err, "conflicting duplicate resources: Deployment prod/web is declared differently by resources 0 and 2")
+
+	// The same name in another namespace is a different object
+	other := deployment()
+	other.Namespace = "staging"
+	output, err = wetwirek8s.Build([]any{deployment(), other})
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(output), "kind: Deployment"))
+}
+
+func TestBuild_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []any
+		wantErr   string
+	}{
+		{
+			name:      "nil resource",
+			resources: []any{service(), nil},
+			wantErr:   "resource 1: resource cannot be nil",
+		},
+		{
+			name: "missing kind",
+			resources: []any{struct {
+				Metadata map[string]string `json:"metadata"`
+			}{Metadata: map[string]string{"name": "web"}}},
+			wantErr: "apiVersion and kind are not set",
+		},
+		{
+			name: "missing name",
+			resources: []any{&corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			}},
+			wantErr: "resource 0 (ConfigMap): metadata.name is not set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := wetwirek8s.Build(tt.resources)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}